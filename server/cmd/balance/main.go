@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/balance"
+	"github.com/gorbit/orbitalrush/internal/observability"
+)
+
+func main() {
+	logger := observability.NewLogger().WithValues("component", "balance")
+
+	gridSpec := flag.String("grid", "", `parameter grid, e.g. "ThrustDrainRate=0.25,0.5,1;PalletRestoreAmount=10,25"`)
+	policyName := flag.String("policy", "greedy", "input policy: idle, scripted or greedy")
+	runs := flag.Int("runs", balance.DefaultRuns, "seeded runs per grid point")
+	maxTicks := flag.Int("ticks", balance.DefaultMaxTicks, "maximum ticks per run")
+	sampleEvery := flag.Int("sample-every", balance.DefaultSampleEvery, "ticks between energy samples")
+	seed := flag.Int64("seed", 1, "base seed (run i uses seed+i)")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of worker goroutines")
+	format := flag.String("format", "csv", "report format: csv or json")
+	outPath := flag.String("out", "", "report file (default: stdout)")
+	flag.Parse()
+
+	grid, err := balance.ParseGrid(*gridSpec)
+	if err != nil {
+		fail(err)
+	}
+
+	policy, err := balance.PolicyByName(*policyName)
+	if err != nil {
+		fail(err)
+	}
+
+	var write func(io.Writer, balance.SweepReport) error
+	switch *format {
+	case "csv":
+		write = balance.WriteCSV
+	case "json":
+		write = balance.WriteJSON
+	default:
+		fail(fmt.Errorf("unknown format: %s", *format))
+	}
+
+	runner := balance.NewRunner(policy)
+	runner.Runs = *runs
+	runner.MaxTicks = *maxTicks
+	runner.SampleEvery = *sampleEvery
+	runner.Seed = *seed
+	runner.Workers = *workers
+
+	// Cancel the sweep on interrupt
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	logger.Info("Balance sweep starting", "policy", *policyName, "grid", *gridSpec, "runs", *runs, "workers", *workers)
+	start := time.Now()
+
+	report, err := runner.Run(ctx, grid)
+	if err != nil {
+		fail(err)
+	}
+
+	logger.Info("Balance sweep finished", "points", len(report.Points), "duration_seconds", time.Since(start).Seconds())
+
+	out := os.Stdout
+	if *outPath != "" {
+		file, err := os.Create(*outPath)
+		if err != nil {
+			fail(err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := write(out, report); err != nil {
+		fail(err)
+	}
+}
+
+// fail prints err and exits with a non-zero status.
+func fail(err error) {
+	fmt.Fprintf(os.Stderr, "balance: %v\n", err)
+	os.Exit(1)
+}
@@ -6,10 +6,16 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime"
+	"strconv"
 	"syscall"
 	"time"
 
+	"github.com/gorbit/orbitalrush/internal/matchmaking"
 	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/persistence"
+	"github.com/gorbit/orbitalrush/internal/room"
+	"github.com/gorbit/orbitalrush/internal/session"
 	"github.com/gorbit/orbitalrush/internal/transport"
 )
 
@@ -27,6 +33,158 @@ func main() {
 	stopGCMonitor := observability.StartGCMonitor(ctx, gcMonitorInterval, logger)
 	logger.Info("GC monitor started", "interval_seconds", gcMonitorInterval.Seconds())
 	
+	// Room registry shared by the rooms API and /ws?room=
+	maxRooms := room.DefaultMaxRooms
+	if value := os.Getenv("MAX_ROOMS"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			logger.Error(err, "Invalid MAX_ROOMS, using default", "value", value, "default", maxRooms)
+		} else {
+			maxRooms = parsed
+		}
+	}
+
+	// Settings of every private session and room, shared by the transport and the room registry
+	sessionOpts := session.DefaultOptions()
+
+	// Debug mode: check world invariants after every tick and freeze sessions that break them
+	if os.Getenv("DEBUG_INVARIANTS") == "true" {
+		sessionOpts.InvariantMode = session.InvariantsFreeze
+		logger.Info("World invariant checks enabled", "mode", session.InvariantsFreeze.String())
+	}
+
+	// Jitter buffer policy for ticks whose tick-targeted input has not arrived
+	if value := os.Getenv("INPUT_GAP_POLICY"); value != "" {
+		policy, err := session.ParseGapPolicy(value)
+		if err != nil {
+			logger.Error(err, "Invalid INPUT_GAP_POLICY, using default", "value", value, "default", session.GapZero.String())
+		} else {
+			sessionOpts.GapPolicy = policy
+		}
+	}
+
+	// Bound on the input latency of players queuing commands faster than the tick rate
+	if value := os.Getenv("INPUT_BACKLOG_POLICY"); value != "" {
+		policy, err := session.ParseBacklogPolicy(value)
+		if err != nil {
+			logger.Error(err, "Invalid INPUT_BACKLOG_POLICY, using default", "value", value, "default", session.BacklogNone.String())
+		} else {
+			sessionOpts.BacklogPolicy = policy
+		}
+	}
+
+	// Simulation tick rate of private sessions and rooms
+	tickRate := session.DefaultTickRate
+	if value := os.Getenv("TICK_RATE"); value != "" {
+		rate, err := strconv.Atoi(value)
+		if err == nil {
+			err = session.ValidateTickRate(rate)
+		}
+		if err != nil {
+			logger.Error(err, "Invalid TICK_RATE, using default", "value", value, "default", session.DefaultTickRate)
+		} else {
+			tickRate = rate
+		}
+	}
+
+	// One scheduler steps every session at the tick rate, instead of a goroutine per session
+	workers := 0 // One per CPU
+	if value := os.Getenv("SCHEDULER_WORKERS"); value != "" {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			logger.Error(err, "Invalid SCHEDULER_WORKERS, using default", "value", value, "default", runtime.GOMAXPROCS(0))
+		} else {
+			workers = count
+		}
+	}
+	scheduler, err := session.NewScheduler(tickRate, workers)
+	if err != nil {
+		logger.Error(err, "Invalid scheduler config")
+		os.Exit(1)
+	}
+	scheduler.Start()
+	sessionOpts.TickRate = tickRate
+	sessionOpts.Scheduler = scheduler
+	logger.Info("Tick scheduler started", "tick_rate", scheduler.Rate(), "workers", scheduler.Workers())
+
+	// Session lifecycle: countdown before games and reaping of idle, finished or unjoined sessions.
+	// The join timeout cannot be disabled, so that rooms nobody joins never pile up.
+	lifecycle := &sessionOpts.Lifecycle
+	lifecycleSeconds := []struct {
+		name  string
+		value *time.Duration
+		min   int
+	}{
+		{"SESSION_COUNTDOWN_SECONDS", &lifecycle.Countdown, 0},
+		{"SESSION_IDLE_TIMEOUT_SECONDS", &lifecycle.IdleTimeout, 0},
+		{"SESSION_FINISHED_TIMEOUT_SECONDS", &lifecycle.FinishedTimeout, 0},
+		{"SESSION_JOIN_TIMEOUT_SECONDS", &lifecycle.JoinTimeout, 1},
+	}
+	for _, setting := range lifecycleSeconds {
+		if value := os.Getenv(setting.name); value != "" {
+			seconds, err := strconv.Atoi(value)
+			if err != nil || seconds < setting.min {
+				logger.Error(err, "Invalid "+setting.name+", using default", "value", value, "default_seconds", setting.value.Seconds())
+			} else {
+				*setting.value = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	rooms := room.NewManager(session.NewRealClock(), maxRooms, sessionOpts, logger.WithValues("component", "rooms"))
+	transportOpts := transport.Options{Session: sessionOpts}
+
+	// Matchmaking queue for /ws/match; matches get rooms from the shared registry
+	matchmaker, err := matchmaking.NewMatchmaker(session.NewRealClock(), rooms, matchmaking.DefaultConfig(), logger.WithValues("component", "matchmaking"))
+	if err != nil {
+		logger.Error(err, "Invalid matchmaking config")
+		os.Exit(1)
+	}
+	matchmaker.Start(matchmaking.DefaultInterval)
+
+	// Grace period of resumable sessions after a disconnect
+	resumeGrace := transport.DefaultResumeGrace
+	if value := os.Getenv("RESUME_GRACE_SECONDS"); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			logger.Error(err, "Invalid RESUME_GRACE_SECONDS, using default", "value", value, "default_seconds", transport.DefaultResumeGrace.Seconds())
+		} else {
+			resumeGrace = time.Duration(seconds) * time.Second
+		}
+	}
+	transportOpts.Resume = transport.NewResumeStore(resumeGrace)
+
+	// Record replays of private sessions and rooms to REPLAY_DIR, served on /replay/{id}
+	var replayStore *persistence.ReplayStore
+	if dir := os.Getenv("REPLAY_DIR"); dir != "" {
+		var err error
+		replayStore, err = persistence.NewReplayStore(dir)
+		if err != nil {
+			logger.Error(err, "Failed to open replay directory", "dir", dir)
+			os.Exit(1)
+		}
+		transportOpts.Replays = replayStore
+		rooms.SetReplaySink(transport.RoomReplaySink(replayStore, logger.WithValues("component", "replays")))
+		logger.Info("Replay recording enabled", "dir", dir)
+	}
+
+	// Checkpoint resumable sessions to SESSION_DIR so that they survive restarts
+	var checkpoints *persistence.Store
+	if dir := os.Getenv("SESSION_DIR"); dir != "" {
+		store, err := persistence.NewStore(dir)
+		if err != nil {
+			logger.Error(err, "Failed to open session checkpoint directory", "dir", dir)
+			os.Exit(1)
+		}
+		restored, err := transport.RestoreSessions(store, transportOpts, logger)
+		if err != nil {
+			logger.Error(err, "Some session checkpoints could not be restored", "dir", dir)
+		}
+		transport.StartCheckpointing(ctx, store, transportOpts.Resume, transport.DefaultCheckpointInterval, logger)
+		checkpoints = store
+		logger.Info("Session checkpoints enabled", "dir", dir, "restored_sessions", restored, "interval_seconds", transport.DefaultCheckpointInterval.Seconds())
+	}
+
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
@@ -34,7 +192,14 @@ func main() {
 
 	// Create HTTP mux and register handlers
 	mux := http.NewServeMux()
-	mux.HandleFunc("/ws", transport.WebSocketHandler)
+	mux.HandleFunc("/ws", transport.NewWebSocketHandler(rooms, transportOpts))
+	mux.HandleFunc("/ws/match", transport.NewMatchmakingHandler(matchmaker, rooms))
+	roomsHandler := transport.NewRoomsHandler(rooms)
+	mux.Handle("/api/rooms", roomsHandler)
+	mux.Handle("/api/rooms/", roomsHandler)
+	if replayStore != nil {
+		mux.Handle("/replay/", transport.NewReplayHandler(replayStore))
+	}
 	mux.HandleFunc("/healthz", transport.HealthzHandler)
 	mux.HandleFunc("/metrics", observability.MetricsHandler)
 
@@ -47,7 +212,7 @@ func main() {
 
 	// Start server in a goroutine
 	go func() {
-		logger.Info("Server starting", "address", addr, "ws_endpoint", "/ws", "health_endpoint", "/healthz", "metrics_endpoint", "/metrics")
+		logger.Info("Server starting", "address", addr, "ws_endpoint", "/ws", "match_endpoint", "/ws/match", "rooms_endpoint", "/api/rooms", "health_endpoint", "/healthz", "metrics_endpoint", "/metrics")
 
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logger.Error(err, "Server failed to start", "address", addr)
@@ -64,9 +229,24 @@ func main() {
 
 	// Stop GC monitor
 	close(stopGCMonitor)
-	cancel() // Cancel context to stop GC monitor goroutine
+	cancel() // Cancel context to stop GC monitor and checkpoint goroutines
 	logger.Info("GC monitor stopped")
 
+	// Final checkpoint, so that clients can resume after the restart
+	if checkpoints != nil {
+		saved, err := transport.CheckpointSessions(checkpoints, transportOpts.Resume)
+		if err != nil {
+			logger.Error(err, "Failed to checkpoint sessions on shutdown", "dir", checkpoints.Dir())
+		}
+		logger.Info("Sessions checkpointed", "sessions", saved)
+	}
+
+	// Stop the matching loop
+	matchmaker.Stop()
+
+	// Stop stepping sessions
+	scheduler.Stop()
+
 	// Graceful shutdown with timeout
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer shutdownCancel()
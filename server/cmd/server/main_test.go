@@ -48,7 +48,7 @@ var _ = Describe("Server Startup and Shutdown", Label("scope:integration", "loop
 	Describe("Server initialization", func() {
 		It("registers /ws endpoint with transport handler", func() {
 			mux := http.NewServeMux()
-			mux.HandleFunc("/ws", transport.WebSocketHandler)
+			mux.HandleFunc("/ws", transport.WebSocketHandler(transport.DefaultOptions()))
 
 			testServer := httptest.NewServer(mux)
 			defer testServer.Close()
@@ -231,7 +231,7 @@ var _ = Describe("Server Startup and Shutdown", Label("scope:integration", "loop
 			mux := http.NewServeMux()
 			
 			// Register transport handlers (simulating main.go)
-			mux.HandleFunc("/ws", transport.WebSocketHandler)
+			mux.HandleFunc("/ws", transport.WebSocketHandler(transport.DefaultOptions()))
 			mux.HandleFunc("/healthz", transport.HealthzHandler)
 
 			// Verify handlers are registered
@@ -250,7 +250,7 @@ var _ = Describe("Server Startup and Shutdown", Label("scope:integration", "loop
 
 		It("handles concurrent WebSocket connections", func() {
 			mux := http.NewServeMux()
-			mux.HandleFunc("/ws", transport.WebSocketHandler)
+			mux.HandleFunc("/ws", transport.WebSocketHandler(transport.DefaultOptions()))
 
 			testServer := httptest.NewServer(mux)
 			defer testServer.Close()
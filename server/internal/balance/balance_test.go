@@ -0,0 +1,172 @@
+package balance
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestBalance(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Balance Runner Suite")
+}
+
+var _ = Describe("Balance Runner", Label("scope:unit", "loop:g8-balance", "layer:tools", "dep:none", "b:balance-runner", "r:medium"), func() {
+	newRunner := func(policy PolicyFactory) *Runner {
+		runner := NewRunner(policy)
+		runner.Runs = 20
+		runner.MaxTicks = 600
+		runner.SampleEvery = 60
+		runner.Workers = 4
+		return runner
+	}
+
+	Describe("Simulate", func() {
+		It("is deterministic for a seed", func() {
+			runner := newRunner(NewGreedyPolicy(0.05))
+			a := runner.Simulate(rules.DefaultConfig(), 7)
+			b := runner.Simulate(rules.DefaultConfig(), 7)
+			Expect(a).To(Equal(b))
+		})
+
+		It("samples energy at a fixed number of points", func() {
+			runner := newRunner(NewIdlePolicy())
+			result := runner.Simulate(rules.DefaultConfig(), 1)
+			Expect(result.Energy).To(HaveLen(600/60 + 1))
+			Expect(result.Energy[0]).To(Equal(levels.ShipStartEnergy))
+		})
+
+		It("pads the energy curve after the game ends", func() {
+			runner := newRunner(NewIdlePolicy())
+			// An idle ship falls into the sun long before MaxTicks
+			result := runner.Simulate(rules.DefaultConfig(), 1)
+			Expect(result.Done).To(BeTrue())
+			Expect(result.Win).To(BeFalse())
+			Expect(result.Ticks).To(BeNumerically("<", 600))
+			last := result.Energy[len(result.Energy)-1]
+			Expect(result.Energy[len(result.Energy)-2]).To(Equal(last))
+		})
+
+		It("stops at MaxTicks", func() {
+			runner := newRunner(NewIdlePolicy())
+			runner.Level = func(seed int64) entities.World {
+				world := levels.Seeded(seed)
+				world.Sun.Mass = 0 // nothing pulls the ship in
+				return world
+			}
+			result := runner.Simulate(rules.DefaultConfig(), 1)
+			Expect(result.Done).To(BeFalse())
+			Expect(result.Ticks).To(Equal(600))
+		})
+	})
+
+	Describe("Run", func() {
+		It("aggregates every grid point", func() {
+			runner := newRunner(NewGreedyPolicy(0.05))
+			grid := Grid{
+				{Param: "ThrustDrainRate", Values: []float64{0.25, 1.0}},
+				{Param: "PalletRestoreAmount", Values: []float64{10, 25}},
+			}
+
+			report, err := runner.Run(context.Background(), grid)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Points).To(HaveLen(4))
+			Expect(report.Runs).To(Equal(20))
+
+			for _, point := range report.Points {
+				Expect(point.Runs).To(Equal(20))
+				Expect(point.Wins + point.Losses + point.Timeouts).To(Equal(20))
+				Expect(point.WinRate).To(BeNumerically("~", float64(point.Wins)/20.0, 1e-9))
+				Expect(point.EnergyCurve).To(HaveLen(11))
+				Expect(point.EnergyCurve[0].MeanEnergy).To(BeNumerically("~", 100.0, 1e-9))
+				if point.Wins > 0 {
+					Expect(point.MeanSecondsToWin).To(BeNumerically("~", point.MeanTicksToWin*runner.Dt, 1e-9))
+				}
+			}
+		})
+
+		It("produces the same report regardless of worker count", func() {
+			grid := Grid{{Param: "ThrustDrainRate", Values: []float64{0.25, 0.5}}}
+
+			serial := newRunner(NewGreedyPolicy(0.05))
+			serial.Workers = 1
+			parallel := newRunner(NewGreedyPolicy(0.05))
+			parallel.Workers = 8
+
+			a, err := serial.Run(context.Background(), grid)
+			Expect(err).NotTo(HaveOccurred())
+			b, err := parallel.Run(context.Background(), grid)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a).To(Equal(b))
+		})
+
+		It("rewards cheaper thrust", func() {
+			runner := newRunner(NewGreedyPolicy(0.05))
+			runner.MaxTicks = 1800
+			grid := Grid{{Param: "ThrustDrainRate", Values: []float64{0.1, 2.0}}}
+
+			report, err := runner.Run(context.Background(), grid)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(report.Points[0].WinRate).To(BeNumerically(">", report.Points[1].WinRate))
+		})
+
+		It("returns the context error when cancelled", func() {
+			runner := newRunner(NewIdlePolicy())
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := runner.Run(ctx, Grid{})
+			Expect(err).To(MatchError(context.Canceled))
+		})
+
+		It("rejects invalid settings", func() {
+			runner := newRunner(NewIdlePolicy())
+			runner.Runs = 0
+			_, err := runner.Run(context.Background(), Grid{})
+			Expect(err).To(MatchError(ContainSubstring("Runs")))
+		})
+	})
+
+	Describe("Policies", func() {
+		It("scripted policy replays its steps then idles", func() {
+			policy := NewScriptedPolicy([]ScriptStep{
+				{Ticks: 2, Command: rules.InputCommand{Thrust: 1.0}},
+				{Ticks: 1, Command: rules.InputCommand{Turn: -1.0}},
+			})(0)
+			world := levels.Standard()
+
+			Expect(policy.Input(world)).To(Equal(rules.InputCommand{Thrust: 1.0}))
+			Expect(policy.Input(world)).To(Equal(rules.InputCommand{Thrust: 1.0}))
+			Expect(policy.Input(world)).To(Equal(rules.InputCommand{Turn: -1.0}))
+			Expect(policy.Input(world)).To(Equal(rules.InputCommand{}))
+		})
+
+		It("greedy policy produces inputs within range", func() {
+			policy := NewGreedyPolicy(0.1)(3)
+			world := levels.Seeded(3)
+			for i := 0; i < 300 && !world.Done; i++ {
+				input := policy.Input(world)
+				Expect(input.Thrust).To(BeNumerically(">=", 0.0))
+				Expect(input.Thrust).To(BeNumerically("<=", 1.0))
+				Expect(input.Turn).To(BeNumerically(">=", -1.0))
+				Expect(input.Turn).To(BeNumerically("<=", 1.0))
+				world = rules.StepWithConfig(world, input, DefaultDt, rules.DefaultConfig())
+			}
+		})
+
+		It("resolves policies by name", func() {
+			for _, name := range []string{"idle", "scripted", "greedy"} {
+				factory, err := PolicyByName(name)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(factory(1)).NotTo(BeNil())
+			}
+			_, err := PolicyByName("nope")
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,146 @@
+package balance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// Axis is one swept parameter and the values it takes.
+type Axis struct {
+	Param  string
+	Values []float64
+}
+
+// Grid is a parameter sweep. Every combination of axis values is a point.
+type Grid []Axis
+
+// ParamValue is a parameter name with the value it has at a grid point.
+type ParamValue struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Point is one combination of grid values applied to a base configuration.
+type Point struct {
+	Params []ParamValue
+	Config rules.Config
+}
+
+// ParamNames lists the rules.Config fields that can be swept.
+var ParamNames = []string{
+	"ThrustAcceleration",
+	"TurnRate",
+	"MaxEnergy",
+	"ThrustDrainRate",
+	"PalletRestoreAmount",
+	"G",
+	"AMax",
+	"PickupRadius",
+}
+
+// SetParam sets the rules.Config field called name to value.
+// Returns an error if name is not one of ParamNames.
+func SetParam(cfg *rules.Config, name string, value float64) error {
+	switch name {
+	case "ThrustAcceleration":
+		cfg.ThrustAcceleration = value
+	case "TurnRate":
+		cfg.TurnRate = value
+	case "MaxEnergy":
+		cfg.MaxEnergy = float32(value)
+	case "ThrustDrainRate":
+		cfg.ThrustDrainRate = float32(value)
+	case "PalletRestoreAmount":
+		cfg.PalletRestoreAmount = float32(value)
+	case "G":
+		cfg.G = value
+	case "AMax":
+		cfg.AMax = value
+	case "PickupRadius":
+		cfg.PickupRadius = value
+	default:
+		return fmt.Errorf("unknown parameter: %s", name)
+	}
+	return nil
+}
+
+// ParseGrid parses a grid specification of the form
+// "ThrustDrainRate=0.25,0.5,1;PalletRestoreAmount=10,25".
+// An empty specification is an empty grid (a single point: the base configuration).
+func ParseGrid(spec string) (Grid, error) {
+	grid := Grid{}
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return grid, nil
+	}
+
+	seen := make(map[string]bool)
+	for _, axisSpec := range strings.Split(spec, ";") {
+		name, valuesSpec, ok := strings.Cut(strings.TrimSpace(axisSpec), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid axis %q: expected Param=v1,v2,...", axisSpec)
+		}
+		name = strings.TrimSpace(name)
+		if err := SetParam(&rules.Config{}, name, 0); err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate parameter: %s", name)
+		}
+		seen[name] = true
+
+		axis := Axis{Param: name}
+		for _, valueStr := range strings.Split(valuesSpec, ",") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(valueStr), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q for %s: %w", valueStr, name, err)
+			}
+			axis.Values = append(axis.Values, value)
+		}
+		grid = append(grid, axis)
+	}
+
+	return grid, nil
+}
+
+// Points expands the grid into every combination of axis values applied to base.
+// Points are ordered with the last axis varying fastest.
+// Returns an error if an axis is empty, names an unknown parameter, or produces
+// an invalid configuration.
+func (g Grid) Points(base rules.Config) ([]Point, error) {
+	points := []Point{{Params: []ParamValue{}, Config: base}}
+
+	for _, axis := range g {
+		if len(axis.Values) == 0 {
+			return nil, fmt.Errorf("axis %s has no values", axis.Param)
+		}
+
+		expanded := make([]Point, 0, len(points)*len(axis.Values))
+		for _, point := range points {
+			for _, value := range axis.Values {
+				cfg := point.Config
+				if err := SetParam(&cfg, axis.Param, value); err != nil {
+					return nil, err
+				}
+
+				params := make([]ParamValue, len(point.Params), len(point.Params)+1)
+				copy(params, point.Params)
+				params = append(params, ParamValue{Name: axis.Param, Value: value})
+
+				expanded = append(expanded, Point{Params: params, Config: cfg})
+			}
+		}
+		points = expanded
+	}
+
+	for _, point := range points {
+		if err := point.Config.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid grid point %v: %w", point.Params, err)
+		}
+	}
+
+	return points, nil
+}
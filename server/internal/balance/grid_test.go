@@ -0,0 +1,78 @@
+package balance
+
+import (
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Parameter Grid", Label("scope:unit", "loop:g8-balance", "layer:tools", "dep:none", "b:parameter-grid", "r:low"), func() {
+	Describe("ParseGrid", func() {
+		It("parses axes and values", func() {
+			grid, err := ParseGrid("ThrustDrainRate=0.25,0.5; PalletRestoreAmount=10")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grid).To(Equal(Grid{
+				{Param: "ThrustDrainRate", Values: []float64{0.25, 0.5}},
+				{Param: "PalletRestoreAmount", Values: []float64{10}},
+			}))
+		})
+
+		It("treats an empty spec as an empty grid", func() {
+			grid, err := ParseGrid("  ")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(grid).To(BeEmpty())
+		})
+
+		It("rejects unknown parameters", func() {
+			_, err := ParseGrid("Gravity=1")
+			Expect(err).To(MatchError(ContainSubstring("unknown parameter")))
+		})
+
+		It("rejects malformed axes and values", func() {
+			_, err := ParseGrid("ThrustDrainRate")
+			Expect(err).To(HaveOccurred())
+			_, err = ParseGrid("ThrustDrainRate=fast")
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects duplicate parameters", func() {
+			_, err := ParseGrid("G=1;G=2")
+			Expect(err).To(MatchError(ContainSubstring("duplicate")))
+		})
+	})
+
+	Describe("Points", func() {
+		It("expands the cartesian product with the last axis varying fastest", func() {
+			grid := Grid{
+				{Param: "ThrustDrainRate", Values: []float64{0.25, 0.5}},
+				{Param: "PalletRestoreAmount", Values: []float64{10, 25, 40}},
+			}
+
+			points, err := grid.Points(rules.DefaultConfig())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(points).To(HaveLen(6))
+			Expect(points[0].Params).To(Equal([]ParamValue{{"ThrustDrainRate", 0.25}, {"PalletRestoreAmount", 10}}))
+			Expect(points[1].Params).To(Equal([]ParamValue{{"ThrustDrainRate", 0.25}, {"PalletRestoreAmount", 25}}))
+			Expect(points[5].Config.ThrustDrainRate).To(Equal(float32(0.5)))
+			Expect(points[5].Config.PalletRestoreAmount).To(Equal(float32(40)))
+			Expect(points[5].Config.TurnRate).To(Equal(rules.TurnRate))
+		})
+
+		It("yields the base configuration for an empty grid", func() {
+			points, err := Grid{}.Points(rules.DefaultConfig())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(points).To(HaveLen(1))
+			Expect(points[0].Config).To(Equal(rules.DefaultConfig()))
+		})
+
+		It("rejects points that produce invalid configurations", func() {
+			_, err := Grid{{Param: "MaxEnergy", Values: []float64{0}}}.Points(rules.DefaultConfig())
+			Expect(err).To(MatchError(ContainSubstring("MaxEnergy")))
+		})
+
+		It("rejects empty axes", func() {
+			_, err := Grid{{Param: "G"}}.Points(rules.DefaultConfig())
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
@@ -0,0 +1,205 @@
+package balance
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// Policy decides the input for the next tick from the current world state.
+// Balance runs are single-player, so policies steer world.Ships[0].
+type Policy interface {
+	Input(world entities.World) rules.InputCommand
+}
+
+// PolicyFactory creates a fresh Policy for a single run.
+// Policies may keep per-run state, so every run gets its own instance,
+// seeded from the run seed so that runs stay reproducible.
+type PolicyFactory func(seed int64) Policy
+
+// IdlePolicy never thrusts or turns. It measures how a level plays out with no input.
+type IdlePolicy struct{}
+
+// NewIdlePolicy returns a factory for IdlePolicy.
+func NewIdlePolicy() PolicyFactory {
+	return func(seed int64) Policy {
+		return IdlePolicy{}
+	}
+}
+
+// Input returns the zero command.
+func (IdlePolicy) Input(world entities.World) rules.InputCommand {
+	return rules.InputCommand{}
+}
+
+// ScriptStep is a command held for a number of ticks.
+type ScriptStep struct {
+	Ticks   int
+	Command rules.InputCommand
+}
+
+// ScriptedPolicy replays a fixed list of steps and then idles.
+type ScriptedPolicy struct {
+	steps []ScriptStep
+	step  int
+	held  int
+}
+
+// NewScriptedPolicy returns a factory for a ScriptedPolicy replaying steps.
+// The seed is ignored: a script is the same for every run.
+func NewScriptedPolicy(steps []ScriptStep) PolicyFactory {
+	return func(seed int64) Policy {
+		return &ScriptedPolicy{steps: steps}
+	}
+}
+
+// DefaultScript is a short opening that burns towards the inner pallet ring,
+// coasts, and turns around. It is useful as a baseline that ignores world state.
+func DefaultScript() []ScriptStep {
+	return []ScriptStep{
+		{Ticks: 15, Command: rules.InputCommand{Thrust: 1.0, Turn: 0.0}},
+		{Ticks: 30, Command: rules.InputCommand{Thrust: 0.0, Turn: 1.0}},
+		{Ticks: 20, Command: rules.InputCommand{Thrust: 1.0, Turn: 0.0}},
+		{Ticks: 60, Command: rules.InputCommand{Thrust: 0.0, Turn: 0.0}},
+		{Ticks: 30, Command: rules.InputCommand{Thrust: 0.5, Turn: -1.0}},
+	}
+}
+
+// Input returns the command for the current script step.
+func (p *ScriptedPolicy) Input(world entities.World) rules.InputCommand {
+	for p.step < len(p.steps) && p.held >= p.steps[p.step].Ticks {
+		p.step++
+		p.held = 0
+	}
+	if p.step >= len(p.steps) {
+		return rules.InputCommand{}
+	}
+	p.held++
+	return p.steps[p.step].Command
+}
+
+// Greedy bot tuning.
+const (
+	// greedyCruiseSpeed is the speed the bot tries to approach its target at
+	greedyCruiseSpeed = 40.0
+	// greedyAlignTolerance is the heading error (radians) under which the bot thrusts
+	greedyAlignTolerance = 0.35
+	// greedyTurnGain converts heading error (radians) into turn input
+	greedyTurnGain = 2.0
+	// greedySunMargin is the multiple of the sun radius inside which the bot flees the sun
+	greedySunMargin = 1.6
+)
+
+// GreedyPolicy is a simple bot that steers towards the nearest active pallet
+// and flees the sun when it gets too close. Aim is perturbed by seeded noise
+// so that runs on the same level differ the way human players do.
+type GreedyPolicy struct {
+	rng      *rand.Rand
+	aimNoise float64
+}
+
+// NewGreedyPolicy returns a factory for GreedyPolicy.
+// aimNoise is the standard deviation (radians) of the noise added to the desired heading.
+func NewGreedyPolicy(aimNoise float64) PolicyFactory {
+	return func(seed int64) Policy {
+		return &GreedyPolicy{
+			rng:      rand.New(rand.NewSource(seed)),
+			aimNoise: aimNoise,
+		}
+	}
+}
+
+// Input steers the ship's velocity towards the nearest active pallet.
+func (p *GreedyPolicy) Input(world entities.World) rules.InputCommand {
+	ship := world.Ships[0]
+
+	// Desired velocity: towards the target at cruise speed, or away from the sun when too close
+	var desiredDir entities.Vec2
+	toSun := world.Sun.Pos.Sub(ship.Pos)
+	if toSun.Length() < float64(world.Sun.Radius)*greedySunMargin {
+		desiredDir = toSun.Scale(-1).Normalize()
+	} else if target, ok := nearestActivePallet(world); ok {
+		desiredDir = target.Sub(ship.Pos).Normalize()
+	} else {
+		return rules.InputCommand{}
+	}
+
+	// Steering: thrust along the difference between desired and current velocity
+	steer := desiredDir.Scale(greedyCruiseSpeed).Sub(ship.Vel)
+	if steer.LengthSq() == 0 {
+		return rules.InputCommand{}
+	}
+
+	// Thrust direction is (cos θ, -sin θ) (see rules.CalculateThrustAcceleration)
+	heading := math.Atan2(-steer.Y, steer.X)
+	if p.aimNoise > 0 {
+		heading += p.rng.NormFloat64() * p.aimNoise
+	}
+	diff := wrapAngle(heading - ship.Rot)
+
+	turn := clamp(diff*greedyTurnGain, -1.0, 1.0)
+	thrust := 0.0
+	if math.Abs(diff) < greedyAlignTolerance {
+		thrust = 1.0
+	}
+
+	return rules.InputCommand{Thrust: float32(thrust), Turn: float32(turn)}
+}
+
+// PolicyByName resolves the policies available to the balance CLI.
+// Supported names: "idle", "scripted", "greedy".
+func PolicyByName(name string) (PolicyFactory, error) {
+	switch name {
+	case "idle":
+		return NewIdlePolicy(), nil
+	case "scripted":
+		return NewScriptedPolicy(DefaultScript()), nil
+	case "greedy":
+		return NewGreedyPolicy(0.05), nil
+	default:
+		return nil, fmt.Errorf("unknown policy: %s", name)
+	}
+}
+
+// nearestActivePallet returns the position of the active pallet closest to the ship.
+// Returns false if no pallet is active.
+func nearestActivePallet(world entities.World) (entities.Vec2, bool) {
+	best := entities.Zero()
+	bestDistSq := math.Inf(1)
+	found := false
+	for _, pallet := range world.Pallets {
+		if !pallet.Active {
+			continue
+		}
+		distSq := pallet.Pos.Sub(world.Ships[0].Pos).LengthSq()
+		if distSq < bestDistSq {
+			best = pallet.Pos
+			bestDistSq = distSq
+			found = true
+		}
+	}
+	return best, found
+}
+
+// wrapAngle normalizes an angle to [-π, π).
+func wrapAngle(a float64) float64 {
+	a = math.Mod(a+math.Pi, 2*math.Pi)
+	if a < 0 {
+		a += 2 * math.Pi
+	}
+	return a - math.Pi
+}
+
+// clamp limits v to [lo, hi].
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
@@ -0,0 +1,106 @@
+package balance
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SweepReport is the aggregated result of a balance sweep.
+type SweepReport struct {
+	Runs        int           `json:"runs"`
+	MaxTicks    int           `json:"max_ticks"`
+	Dt          float64       `json:"dt"`
+	Seed        int64         `json:"seed"`
+	SampleEvery int           `json:"sample_every"`
+	Points      []PointResult `json:"points"`
+}
+
+// PointResult aggregates the runs of one grid point.
+type PointResult struct {
+	Params           []ParamValue   `json:"params"`
+	Runs             int            `json:"runs"`
+	Wins             int            `json:"wins"`
+	Losses           int            `json:"losses"`
+	Timeouts         int            `json:"timeouts"`
+	WinRate          float64        `json:"win_rate"`
+	MeanTicksToWin   float64        `json:"mean_ticks_to_win"`   // 0 if no run was won
+	MedianTicksToWin float64        `json:"median_ticks_to_win"` // 0 if no run was won
+	MeanSecondsToWin float64        `json:"mean_seconds_to_win"` // 0 if no run was won
+	EnergyCurve      []EnergySample `json:"energy_curve"`
+}
+
+// EnergySample is the mean ship energy across runs at a tick.
+type EnergySample struct {
+	Tick       int     `json:"tick"`
+	MeanEnergy float64 `json:"mean_energy"`
+}
+
+// WriteJSON writes the report as indented JSON.
+func WriteJSON(w io.Writer, report SweepReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
+
+// WriteCSV writes the report as CSV, one row per grid point.
+// Columns are the swept parameters, the outcome statistics, and one
+// energy_t<tick> column per energy sample.
+func WriteCSV(w io.Writer, report SweepReport) error {
+	writer := csv.NewWriter(w)
+
+	if len(report.Points) == 0 {
+		writer.Flush()
+		return writer.Error()
+	}
+
+	// Header (parameter and sample columns are the same for every point)
+	first := report.Points[0]
+	header := make([]string, 0, len(first.Params)+8+len(first.EnergyCurve))
+	for _, param := range first.Params {
+		header = append(header, param.Name)
+	}
+	header = append(header,
+		"runs", "wins", "losses", "timeouts", "win_rate",
+		"mean_ticks_to_win", "median_ticks_to_win", "mean_seconds_to_win",
+	)
+	for _, sample := range first.EnergyCurve {
+		header = append(header, fmt.Sprintf("energy_t%d", sample.Tick))
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, point := range report.Points {
+		row := make([]string, 0, len(header))
+		for _, param := range point.Params {
+			row = append(row, formatFloat(param.Value))
+		}
+		row = append(row,
+			strconv.Itoa(point.Runs),
+			strconv.Itoa(point.Wins),
+			strconv.Itoa(point.Losses),
+			strconv.Itoa(point.Timeouts),
+			formatFloat(point.WinRate),
+			formatFloat(point.MeanTicksToWin),
+			formatFloat(point.MedianTicksToWin),
+			formatFloat(point.MeanSecondsToWin),
+		)
+		for _, sample := range point.EnergyCurve {
+			row = append(row, formatFloat(sample.MeanEnergy))
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatFloat formats a float with the shortest exact representation.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
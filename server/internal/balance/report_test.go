@@ -0,0 +1,64 @@
+package balance
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Balance Report", Label("scope:unit", "loop:g8-balance", "layer:tools", "dep:none", "b:balance-report", "r:low"), func() {
+	report := SweepReport{
+		Runs:        10,
+		MaxTicks:    60,
+		Dt:          DefaultDt,
+		Seed:        1,
+		SampleEvery: 30,
+		Points: []PointResult{
+			{
+				Params:           []ParamValue{{Name: "ThrustDrainRate", Value: 0.5}},
+				Runs:             10,
+				Wins:             4,
+				Losses:           5,
+				Timeouts:         1,
+				WinRate:          0.4,
+				MeanTicksToWin:   45,
+				MedianTicksToWin: 44,
+				MeanSecondsToWin: 1.5,
+				EnergyCurve:      []EnergySample{{Tick: 0, MeanEnergy: 100}, {Tick: 30, MeanEnergy: 90}, {Tick: 60, MeanEnergy: 80.5}},
+			},
+		},
+	}
+
+	It("writes one CSV row per point with parameter and energy columns", func() {
+		var buf bytes.Buffer
+		Expect(WriteCSV(&buf, report)).To(Succeed())
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(records).To(HaveLen(2))
+		Expect(records[0]).To(Equal([]string{
+			"ThrustDrainRate", "runs", "wins", "losses", "timeouts", "win_rate",
+			"mean_ticks_to_win", "median_ticks_to_win", "mean_seconds_to_win",
+			"energy_t0", "energy_t30", "energy_t60",
+		}))
+		Expect(records[1]).To(Equal([]string{"0.5", "10", "4", "5", "1", "0.4", "45", "44", "1.5", "100", "90", "80.5"}))
+	})
+
+	It("writes JSON that round-trips", func() {
+		var buf bytes.Buffer
+		Expect(WriteJSON(&buf, report)).To(Succeed())
+
+		var decoded SweepReport
+		Expect(json.Unmarshal(buf.Bytes(), &decoded)).To(Succeed())
+		Expect(decoded).To(Equal(report))
+	})
+
+	It("writes nothing for an empty report", func() {
+		var buf bytes.Buffer
+		Expect(WriteCSV(&buf, SweepReport{})).To(Succeed())
+		Expect(buf.Len()).To(Equal(0))
+	})
+})
@@ -0,0 +1,267 @@
+package balance
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// Runner defaults
+const (
+	// DefaultRuns is the number of seeded runs per grid point
+	DefaultRuns = 1000
+	// DefaultMaxTicks caps a run at two minutes of game time at 30 Hz
+	DefaultMaxTicks = 3600
+	// DefaultSampleEvery samples energy once per second of game time at 30 Hz
+	DefaultSampleEvery = 30
+	// DefaultDt matches the session tick rate (30 Hz)
+	DefaultDt = 1.0 / 30.0
+)
+
+// Runner sweeps a parameter grid, simulating many seeded runs per point.
+// Runs are independent and are spread across a pool of worker goroutines.
+type Runner struct {
+	// Base is the configuration grid values are applied to
+	Base rules.Config
+	// Policy creates the input policy for each run
+	Policy PolicyFactory
+	// Level builds the initial world for a run seed
+	Level func(seed int64) entities.World
+	// Runs is the number of runs per grid point
+	Runs int
+	// MaxTicks caps the length of a run; runs still going are counted as timeouts
+	MaxTicks int
+	// Dt is the simulation time step in seconds
+	Dt float64
+	// Seed is the base seed; run i uses Seed+i at every grid point so that
+	// points are compared on the same set of levels
+	Seed int64
+	// Workers is the size of the worker pool (0 = runtime.NumCPU())
+	Workers int
+	// SampleEvery is the number of ticks between energy samples
+	SampleEvery int
+}
+
+// NewRunner creates a Runner with default settings for the given policy.
+// Levels are generated with levels.Seeded.
+func NewRunner(policy PolicyFactory) *Runner {
+	return &Runner{
+		Base:        rules.DefaultConfig(),
+		Policy:      policy,
+		Level:       levels.Seeded,
+		Runs:        DefaultRuns,
+		MaxTicks:    DefaultMaxTicks,
+		Dt:          DefaultDt,
+		Workers:     runtime.NumCPU(),
+		SampleEvery: DefaultSampleEvery,
+	}
+}
+
+// RunResult is the outcome of a single simulated run.
+type RunResult struct {
+	Seed   int64
+	Done   bool
+	Win    bool
+	Ticks  int
+	Energy []float32 // Ship energy at ticks 0, SampleEvery, 2*SampleEvery, ..., MaxTicks
+}
+
+// Simulate plays one run with cfg from the level for seed until the game is done
+// or MaxTicks is reached. Energy samples after the game ends repeat the final energy.
+func (r *Runner) Simulate(cfg rules.Config, seed int64) RunResult {
+	world := r.Level(seed)
+	policy := r.Policy(seed)
+
+	sampleCount := r.MaxTicks/r.SampleEvery + 1
+	energy := make([]float32, 0, sampleCount)
+	energy = append(energy, world.Ships[0].Energy)
+
+	ticks := 0
+	for ticks < r.MaxTicks && !world.Done {
+		input := policy.Input(world)
+		world = rules.StepWithConfig(world, input, r.Dt, cfg)
+		ticks++
+
+		if ticks%r.SampleEvery == 0 {
+			energy = append(energy, world.Ships[0].Energy)
+		}
+	}
+
+	// Pad the curve with the final energy so every run has the same number of samples
+	for len(energy) < sampleCount {
+		energy = append(energy, world.Ships[0].Energy)
+	}
+
+	return RunResult{
+		Seed:   seed,
+		Done:   world.Done,
+		Win:    world.Done && world.Win,
+		Ticks:  ticks,
+		Energy: energy,
+	}
+}
+
+// job identifies one run of one grid point.
+type job struct {
+	point int
+	run   int
+}
+
+// Run simulates Runs seeded runs for every point of grid and aggregates them.
+// Returns ctx.Err() if the context is cancelled before all runs finish.
+func (r *Runner) Run(ctx context.Context, grid Grid) (SweepReport, error) {
+	if err := r.validate(); err != nil {
+		return SweepReport{}, err
+	}
+
+	points, err := grid.Points(r.Base)
+	if err != nil {
+		return SweepReport{}, err
+	}
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	// Results are written by index, so no locking is needed
+	results := make([][]RunResult, len(points))
+	for i := range results {
+		results[i] = make([]RunResult, r.Runs)
+	}
+
+	jobs := make(chan job)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				results[j.point][j.run] = r.Simulate(points[j.point].Config, r.Seed+int64(j.run))
+			}
+		}()
+	}
+
+	// Feed jobs until done or cancelled
+feed:
+	for p := range points {
+		for run := 0; run < r.Runs; run++ {
+			select {
+			case <-ctx.Done():
+				break feed
+			case jobs <- job{point: p, run: run}:
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return SweepReport{}, err
+	}
+
+	report := SweepReport{
+		Runs:        r.Runs,
+		MaxTicks:    r.MaxTicks,
+		Dt:          r.Dt,
+		Seed:        r.Seed,
+		SampleEvery: r.SampleEvery,
+		Points:      make([]PointResult, len(points)),
+	}
+	for i, point := range points {
+		report.Points[i] = r.aggregate(point, results[i])
+	}
+
+	return report, nil
+}
+
+// aggregate summarizes the runs of one grid point.
+func (r *Runner) aggregate(point Point, runs []RunResult) PointResult {
+	result := PointResult{
+		Params: point.Params,
+		Runs:   len(runs),
+	}
+
+	sampleCount := r.MaxTicks/r.SampleEvery + 1
+	energySums := make([]float64, sampleCount)
+	winTicks := make([]int, 0, len(runs))
+
+	for _, run := range runs {
+		switch {
+		case run.Win:
+			result.Wins++
+			winTicks = append(winTicks, run.Ticks)
+		case run.Done:
+			result.Losses++
+		default:
+			result.Timeouts++
+		}
+		for i, e := range run.Energy {
+			energySums[i] += float64(e)
+		}
+	}
+
+	if result.Runs > 0 {
+		result.WinRate = float64(result.Wins) / float64(result.Runs)
+	}
+
+	if len(winTicks) > 0 {
+		sort.Ints(winTicks)
+		total := 0
+		for _, t := range winTicks {
+			total += t
+		}
+		result.MeanTicksToWin = float64(total) / float64(len(winTicks))
+		result.MedianTicksToWin = median(winTicks)
+		result.MeanSecondsToWin = result.MeanTicksToWin * r.Dt
+	}
+
+	result.EnergyCurve = make([]EnergySample, sampleCount)
+	for i := range energySums {
+		mean := 0.0
+		if result.Runs > 0 {
+			mean = energySums[i] / float64(result.Runs)
+		}
+		result.EnergyCurve[i] = EnergySample{Tick: i * r.SampleEvery, MeanEnergy: mean}
+	}
+
+	return result
+}
+
+// validate checks the runner settings.
+func (r *Runner) validate() error {
+	if r.Policy == nil {
+		return fmt.Errorf("runner has no policy")
+	}
+	if r.Level == nil {
+		return fmt.Errorf("runner has no level generator")
+	}
+	if r.Runs <= 0 {
+		return fmt.Errorf("invalid Runs: must be > 0, got %d", r.Runs)
+	}
+	if r.MaxTicks <= 0 {
+		return fmt.Errorf("invalid MaxTicks: must be > 0, got %d", r.MaxTicks)
+	}
+	if r.SampleEvery <= 0 {
+		return fmt.Errorf("invalid SampleEvery: must be > 0, got %d", r.SampleEvery)
+	}
+	if r.Dt <= 0 {
+		return fmt.Errorf("invalid Dt: must be > 0, got %f", r.Dt)
+	}
+	return nil
+}
+
+// median returns the median of a sorted slice.
+func median(sorted []int) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return float64(sorted[n/2])
+	}
+	return float64(sorted[n/2-1]+sorted[n/2]) / 2.0
+}
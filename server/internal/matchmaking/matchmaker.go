@@ -0,0 +1,332 @@
+package matchmaking
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/room"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+)
+
+const (
+	// DefaultRating is the rating of players that do not provide one
+	DefaultRating = 1500
+	// DefaultInterval is how often the matchmaker's loop runs a matching pass
+	DefaultInterval = time.Second
+)
+
+// Config holds the matchmaking parameters.
+type Config struct {
+	// MatchSize is the number of players per match, in [2, room.MaxPlayersLimit]
+	MatchSize int
+	// BaseWindow is the largest rating difference accepted for a player who just joined the queue
+	BaseWindow float64
+	// WindowGrowth widens a player's window by this many rating points per second of waiting
+	WindowGrowth float64
+	// MaxWindow caps the window, so very long waits never pair arbitrary ratings
+	MaxWindow float64
+	// Room is the configuration of the rooms created for matches; MaxPlayers is set to MatchSize
+	Room room.Config
+}
+
+// DefaultConfig returns the matchmaking parameters used by the server: pairs of
+// players within 100 rating points, widening by 10 points per second up to 1000.
+func DefaultConfig() Config {
+	return Config{
+		MatchSize:    2,
+		BaseWindow:   100,
+		WindowGrowth: 10,
+		MaxWindow:    1000,
+		Room:         room.DefaultConfig(),
+	}
+}
+
+// Validate checks that the configuration can form matches.
+// Returns an error describing the first invalid field.
+func (c Config) Validate() error {
+	if c.MatchSize < 2 || c.MatchSize > room.MaxPlayersLimit {
+		return fmt.Errorf("invalid MatchSize: must be in [2, %d], got %d", room.MaxPlayersLimit, c.MatchSize)
+	}
+	if c.BaseWindow < 0 {
+		return fmt.Errorf("invalid BaseWindow: must be >= 0, got %f", c.BaseWindow)
+	}
+	if c.WindowGrowth < 0 {
+		return fmt.Errorf("invalid WindowGrowth: must be >= 0, got %f", c.WindowGrowth)
+	}
+	if c.MaxWindow < c.BaseWindow {
+		return fmt.Errorf("invalid MaxWindow: must be >= BaseWindow (%f), got %f", c.BaseWindow, c.MaxWindow)
+	}
+	roomCfg := c.Room
+	roomCfg.MaxPlayers = c.MatchSize
+	if err := roomCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid room config: %w", err)
+	}
+	return nil
+}
+
+// Assignment is the game a matched player was assigned to.
+// The player has already joined Room as PlayerID; they must leave it when they disconnect.
+type Assignment struct {
+	Room     *room.Room
+	PlayerID entities.PlayerID
+	Players  int           // Number of players in the match
+	Waited   time.Duration // Time the player spent in the queue
+}
+
+// Ticket is a player's place in the matchmaking queue.
+type Ticket struct {
+	ID         uint64
+	Rating     int
+	EnqueuedAt time.Time
+	assigned   chan Assignment
+}
+
+// Assigned returns a channel that receives the player's assignment once they are matched.
+// It receives at most one value.
+func (t *Ticket) Assigned() <-chan Assignment {
+	return t.assigned
+}
+
+// Matchmaker queues players and groups them into matches by rating and wait time.
+// Every match gets a new room; its players are joined to it before they are handed
+// their assignment.
+//
+// A player's rating window is BaseWindow + WindowGrowth × seconds waited, capped at
+// MaxWindow. Two players are compatible when their rating difference is within the
+// larger of their two windows, so a long wait makes a player acceptable to everyone
+// nearby. Matching passes run from the oldest ticket: each ticket in turn is grouped
+// with the closest-rated compatible tickets, if there are enough of them.
+//
+// Matchmaker is safe for concurrent use. Time comes from a session.Clock, so tests
+// drive it deterministically with session.FakeClock and call Match directly.
+type Matchmaker struct {
+	mu     sync.Mutex
+	clock  session.Clock
+	rooms  *room.Manager
+	cfg    Config
+	logger logr.Logger
+	queue  []*Ticket // Sorted by enqueue time
+	nextID uint64
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMatchmaker creates a matchmaker that creates match rooms in rooms.
+// Returns an error if cfg is invalid.
+// The logger parameter is optional; a zero logger disables matchmaking logging.
+func NewMatchmaker(clock session.Clock, rooms *room.Manager, cfg Config, logger logr.Logger) (*Matchmaker, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return &Matchmaker{
+		clock:  clock,
+		rooms:  rooms,
+		cfg:    cfg,
+		logger: logger,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Enqueue adds a player with the given rating to the queue.
+func (m *Matchmaker) Enqueue(rating int) *Ticket {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.nextID++
+	ticket := &Ticket{
+		ID:         m.nextID,
+		Rating:     rating,
+		EnqueuedAt: m.clock.Now(),
+		assigned:   make(chan Assignment, 1),
+	}
+	m.queue = append(m.queue, ticket)
+	observability.UpdateMatchmakingQueueSize(len(m.queue))
+	return ticket
+}
+
+// Cancel removes a ticket from the queue.
+// Returns false if the ticket is not queued, e.g. because it was already matched;
+// its assignment is then available from Assigned.
+func (m *Matchmaker) Cancel(ticket *Ticket) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, queued := range m.queue {
+		if queued == ticket {
+			m.queue = append(m.queue[:i], m.queue[i+1:]...)
+			observability.UpdateMatchmakingQueueSize(len(m.queue))
+			return true
+		}
+	}
+	return false
+}
+
+// QueueSize returns the number of players waiting to be matched.
+func (m *Matchmaker) QueueSize() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.queue)
+}
+
+// Match runs one matching pass and returns the number of matches made.
+// If a match room cannot be created (e.g. the room limit is reached), the pass
+// stops and the remaining players stay queued for the next pass.
+func (m *Matchmaker) Match() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.clock.Now()
+	matches := 0
+	for i := 0; i < len(m.queue); {
+		group := m.findGroup(i, now)
+		if group == nil {
+			i++
+			continue
+		}
+		if err := m.assign(group, now); err != nil {
+			if m.logger.Enabled() {
+				m.logger.Error(err, "Failed to create match room", "queue_size", len(m.queue))
+			}
+			break
+		}
+		m.removeGroup(group)
+		matches++
+		// queue[i] was matched, so the next ticket moved to index i
+	}
+
+	observability.UpdateMatchmakingQueueSize(len(m.queue))
+	return matches
+}
+
+// Start runs a matching pass every interval until Stop is called.
+func (m *Matchmaker) Start(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.done:
+				return
+			case <-ticker.C:
+				m.Match()
+			}
+		}
+	}()
+}
+
+// Stop stops the matching loop started by Start. It can be called multiple times.
+func (m *Matchmaker) Stop() {
+	m.stopOnce.Do(func() {
+		close(m.done)
+	})
+}
+
+// window returns the rating window of ticket at time now.
+func (m *Matchmaker) window(ticket *Ticket, now time.Time) float64 {
+	waited := now.Sub(ticket.EnqueuedAt).Seconds()
+	return math.Min(m.cfg.BaseWindow+m.cfg.WindowGrowth*waited, m.cfg.MaxWindow)
+}
+
+// compatible reports whether a and b may play in the same match at time now.
+func (m *Matchmaker) compatible(a, b *Ticket, now time.Time) bool {
+	diff := math.Abs(float64(a.Rating - b.Rating))
+	return diff <= math.Max(m.window(a, now), m.window(b, now))
+}
+
+// findGroup returns a full match anchored on queue[anchor], or nil if there are not
+// enough compatible tickets. Candidates are taken closest rating first (then oldest
+// first) and must be compatible with every ticket already in the group.
+// Callers hold m.mu.
+func (m *Matchmaker) findGroup(anchor int, now time.Time) []*Ticket {
+	first := m.queue[anchor]
+	candidates := make([]*Ticket, 0, len(m.queue)-1)
+	for i, ticket := range m.queue {
+		if i != anchor && m.compatible(first, ticket, now) {
+			candidates = append(candidates, ticket)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		di := math.Abs(float64(candidates[i].Rating - first.Rating))
+		dj := math.Abs(float64(candidates[j].Rating - first.Rating))
+		return di < dj
+	})
+
+	group := []*Ticket{first}
+	for _, candidate := range candidates {
+		fits := true
+		for _, member := range group {
+			if !m.compatible(member, candidate, now) {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			group = append(group, candidate)
+			if len(group) == m.cfg.MatchSize {
+				return group
+			}
+		}
+	}
+	return nil
+}
+
+// assign creates a room for group, joins every player to it and hands them their
+// assignments. Callers hold m.mu.
+func (m *Matchmaker) assign(group []*Ticket, now time.Time) error {
+	cfg := m.cfg.Room
+	cfg.MaxPlayers = len(group)
+	if cfg.Name == "" {
+		cfg.Name = "match"
+	}
+	rm, err := m.rooms.Create(cfg)
+	if err != nil {
+		return err
+	}
+
+	assignments := make([]Assignment, len(group))
+	for i, ticket := range group {
+		_, playerID, err := m.rooms.Join(rm.ID())
+		if err != nil {
+			m.rooms.Close(rm.ID())
+			return err
+		}
+		assignments[i] = Assignment{
+			Room:     rm,
+			PlayerID: playerID,
+			Players:  len(group),
+			Waited:   now.Sub(ticket.EnqueuedAt),
+		}
+	}
+
+	for i, ticket := range group {
+		ticket.assigned <- assignments[i]
+		observability.ObserveMatchmakingQueueTime(assignments[i].Waited)
+	}
+	if m.logger.Enabled() {
+		m.logger.Info("Match created", "room_id", rm.ID(), "players", len(group))
+	}
+	return nil
+}
+
+// removeGroup removes the tickets of group from the queue. Callers hold m.mu.
+func (m *Matchmaker) removeGroup(group []*Ticket) {
+	matched := make(map[*Ticket]bool, len(group))
+	for _, ticket := range group {
+		matched[ticket] = true
+	}
+	queue := m.queue[:0]
+	for _, ticket := range m.queue {
+		if !matched[ticket] {
+			queue = append(queue, ticket)
+		}
+	}
+	m.queue = queue
+}
@@ -0,0 +1,203 @@
+package matchmaking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/room"
+	"github.com/gorbit/orbitalrush/internal/session"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func TestMatchmaking(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Matchmaking Suite")
+}
+
+var _ = Describe("Matchmaker", Label("scope:unit", "loop:g3-orch", "layer:server", "double:fake-io", "b:matchmaking", "r:high"), func() {
+	var (
+		clock      *session.FakeClock
+		rooms      *room.Manager
+		matchmaker *Matchmaker
+	)
+
+	newMatchmaker := func(cfg Config) *Matchmaker {
+		mm, err := NewMatchmaker(clock, rooms, cfg, logr.Discard())
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return mm
+	}
+
+	assignment := func(ticket *Ticket) Assignment {
+		var a Assignment
+		ExpectWithOffset(1, ticket.Assigned()).To(Receive(&a))
+		return a
+	}
+
+	BeforeEach(func() {
+		observability.InitMetrics()
+		clock = session.NewFakeClock()
+		rooms = room.NewManager(clock, 10, session.DefaultOptions(), logr.Discard())
+		matchmaker = newMatchmaker(DefaultConfig())
+	})
+
+	AfterEach(func() {
+		for _, info := range rooms.List() {
+			rooms.Close(info.ID)
+		}
+	})
+
+	Describe("Config", func() {
+		It("accepts the default config", func() {
+			Expect(DefaultConfig().Validate()).To(Succeed())
+		})
+
+		It("rejects invalid match sizes and windows", func() {
+			cfg := DefaultConfig()
+			cfg.MatchSize = 1
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("MatchSize")))
+
+			cfg = DefaultConfig()
+			cfg.MatchSize = room.MaxPlayersLimit + 1
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("MatchSize")))
+
+			cfg = DefaultConfig()
+			cfg.MaxWindow = cfg.BaseWindow - 1
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("MaxWindow")))
+
+			cfg = DefaultConfig()
+			cfg.Room.Level = "maze"
+			_, err := NewMatchmaker(clock, rooms, cfg, logr.Discard())
+			Expect(err).To(MatchError(ContainSubstring("invalid room config")))
+		})
+	})
+
+	It("pairs players with close ratings into a new room", func() {
+		first := matchmaker.Enqueue(1500)
+		second := matchmaker.Enqueue(1550)
+
+		Expect(matchmaker.Match()).To(Equal(1))
+		Expect(matchmaker.QueueSize()).To(Equal(0))
+
+		a, b := assignment(first), assignment(second)
+		Expect(a.Room).To(BeIdenticalTo(b.Room))
+		Expect(a.PlayerID).NotTo(Equal(b.PlayerID))
+		Expect(a.Players).To(Equal(2))
+		Expect(a.Room.Config().MaxPlayers).To(Equal(2))
+		Expect(a.Room.Session().Players()).To(ConsistOf(a.PlayerID, b.PlayerID))
+	})
+
+	It("does not pair players whose ratings are too far apart", func() {
+		first := matchmaker.Enqueue(1000)
+		second := matchmaker.Enqueue(1500)
+
+		Expect(matchmaker.Match()).To(Equal(0))
+		Expect(first.Assigned()).NotTo(Receive())
+		Expect(second.Assigned()).NotTo(Receive())
+		Expect(rooms.List()).To(BeEmpty())
+	})
+
+	It("widens the rating window with wait time", func() {
+		first := matchmaker.Enqueue(1000)
+		second := matchmaker.Enqueue(1500)
+
+		// Window is 100 + 10/s, so a 500 point gap needs 40 seconds of waiting
+		clock.Advance(39 * time.Second)
+		Expect(matchmaker.Match()).To(Equal(0))
+
+		clock.Advance(time.Second)
+		Expect(matchmaker.Match()).To(Equal(1))
+		Expect(assignment(first).Waited).To(Equal(40 * time.Second))
+		Expect(assignment(second).Waited).To(Equal(40 * time.Second))
+	})
+
+	It("lets a long wait widen the window for newcomers too", func() {
+		veteran := matchmaker.Enqueue(1000)
+		clock.Advance(40 * time.Second)
+		newcomer := matchmaker.Enqueue(1500)
+
+		Expect(matchmaker.Match()).To(Equal(1))
+		Expect(assignment(veteran).Room).To(BeIdenticalTo(assignment(newcomer).Room))
+	})
+
+	It("never widens the window beyond MaxWindow", func() {
+		matchmaker.Enqueue(0)
+		matchmaker.Enqueue(1500)
+
+		clock.Advance(time.Hour)
+		Expect(matchmaker.Match()).To(Equal(0))
+	})
+
+	It("pairs the oldest player with the closest rating", func() {
+		oldest := matchmaker.Enqueue(1500)
+		far := matchmaker.Enqueue(1420)
+		nearest := matchmaker.Enqueue(1510)
+
+		Expect(matchmaker.Match()).To(Equal(1))
+		Expect(assignment(oldest).Room).To(BeIdenticalTo(assignment(nearest).Room))
+		Expect(far.Assigned()).NotTo(Receive())
+		Expect(matchmaker.QueueSize()).To(Equal(1))
+	})
+
+	It("forms larger matches only from mutually compatible players", func() {
+		cfg := DefaultConfig()
+		cfg.MatchSize = 3
+		matchmaker = newMatchmaker(cfg)
+
+		anchor := matchmaker.Enqueue(1500)
+		low := matchmaker.Enqueue(1410)
+		high := matchmaker.Enqueue(1590)
+		Expect(matchmaker.Match()).To(Equal(0), "low and high are 180 apart")
+
+		mid := matchmaker.Enqueue(1520)
+		Expect(matchmaker.Match()).To(Equal(1))
+		Expect(assignment(anchor).Players).To(Equal(3))
+		Expect(assignment(mid).Players).To(Equal(3))
+		// low and high are equally close to the anchor, but low is 110 away from mid
+		Expect(assignment(high).Players).To(Equal(3))
+		Expect(low.Assigned()).NotTo(Receive())
+		Expect(matchmaker.QueueSize()).To(Equal(1))
+	})
+
+	It("removes cancelled tickets from the queue", func() {
+		first := matchmaker.Enqueue(1500)
+		Expect(matchmaker.Cancel(first)).To(BeTrue())
+		Expect(matchmaker.Cancel(first)).To(BeFalse())
+
+		matchmaker.Enqueue(1500)
+		Expect(matchmaker.Match()).To(Equal(0))
+		Expect(first.Assigned()).NotTo(Receive())
+	})
+
+	It("keeps players queued when the match room cannot be created", func() {
+		rooms = room.NewManager(clock, 1, session.DefaultOptions(), logr.Discard())
+		matchmaker = newMatchmaker(DefaultConfig())
+		_, err := rooms.Create(room.DefaultConfig())
+		Expect(err).NotTo(HaveOccurred())
+
+		first := matchmaker.Enqueue(1500)
+		matchmaker.Enqueue(1500)
+		Expect(matchmaker.Match()).To(Equal(0))
+		Expect(matchmaker.QueueSize()).To(Equal(2))
+		Expect(first.Assigned()).NotTo(Receive())
+	})
+
+	It("records queue time and queue size metrics", func() {
+		matchmaker.Enqueue(1500)
+		matchmaker.Enqueue(1500)
+		matchmaker.Enqueue(3000)
+		clock.Advance(5 * time.Second)
+		matchmaker.Match()
+
+		var metric dto.Metric
+		Expect(observability.GetMatchmakingQueueHistogram().Write(&metric)).To(Succeed())
+		Expect(metric.Histogram.GetSampleCount()).To(Equal(uint64(2)))
+		Expect(metric.Histogram.GetSampleSum()).To(Equal(10.0))
+
+		Expect(observability.GetMatchmakingQueueSizeGauge().Write(&metric)).To(Succeed())
+		Expect(metric.Gauge.GetValue()).To(Equal(1.0))
+	})
+})
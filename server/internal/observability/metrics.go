@@ -34,6 +34,51 @@ var (
 	// connectionBytesCounter tracks bytes in/out
 	connectionBytesCounter *prometheus.CounterVec
 
+	// activeRoomsGauge tracks current number of open rooms
+	activeRoomsGauge prometheus.Gauge
+
+	// roomPlayersGauge tracks current number of players per room
+	roomPlayersGauge *prometheus.GaugeVec
+
+	// matchmakingQueueHistogram tracks how long players wait in the matchmaking queue
+	matchmakingQueueHistogram prometheus.Histogram
+
+	// matchmakingQueueSizeGauge tracks current number of players in the matchmaking queue
+	matchmakingQueueSizeGauge prometheus.Gauge
+
+	// lateInputsCounter tracks inputs that arrived after later inputs of their player were applied
+	lateInputsCounter *prometheus.CounterVec
+
+	// rollbackTicksHistogram tracks how many ticks each rollback re-simulated
+	rollbackTicksHistogram prometheus.Histogram
+
+	// inputTimingCounter tracks tick-targeted inputs by arrival timing
+	inputTimingCounter *prometheus.CounterVec
+
+	// inputMarginHistogram tracks how many ticks before being played tick-targeted inputs arrive
+	inputMarginHistogram prometheus.Histogram
+
+	// inputBacklogCounter tracks queued commands not applied on their own to bound input latency
+	inputBacklogCounter *prometheus.CounterVec
+
+	// inputRedundancyCounter tracks redundant input copies and whether they were still needed
+	inputRedundancyCounter *prometheus.CounterVec
+
+	// droppedTicksCounter tracks ticks sessions skipped because they fell behind
+	droppedTicksCounter prometheus.Counter
+
+	// sessionTransitionsCounter tracks session lifecycle state transitions
+	sessionTransitionsCounter *prometheus.CounterVec
+
+	// scheduledSessionsGauge tracks sessions stepped by the tick scheduler
+	scheduledSessionsGauge prometheus.Gauge
+
+	// schedulerLagHistogram tracks how late after their tick boundary scheduled sessions are stepped
+	schedulerLagHistogram prometheus.Histogram
+
+	// schedulerOverrunsCounter tracks tick boundaries scheduled sessions missed because their previous step was still running
+	schedulerOverrunsCounter prometheus.Counter
+
 	// metricsInitialized tracks whether metrics have been initialized
 	metricsInitialized bool
 
@@ -71,6 +116,51 @@ func InitMetrics() {
 		if connectionBytesCounter != nil {
 			prometheus.Unregister(connectionBytesCounter)
 		}
+		if activeRoomsGauge != nil {
+			prometheus.Unregister(activeRoomsGauge)
+		}
+		if roomPlayersGauge != nil {
+			prometheus.Unregister(roomPlayersGauge)
+		}
+		if matchmakingQueueHistogram != nil {
+			prometheus.Unregister(matchmakingQueueHistogram)
+		}
+		if matchmakingQueueSizeGauge != nil {
+			prometheus.Unregister(matchmakingQueueSizeGauge)
+		}
+		if lateInputsCounter != nil {
+			prometheus.Unregister(lateInputsCounter)
+		}
+		if rollbackTicksHistogram != nil {
+			prometheus.Unregister(rollbackTicksHistogram)
+		}
+		if inputTimingCounter != nil {
+			prometheus.Unregister(inputTimingCounter)
+		}
+		if inputMarginHistogram != nil {
+			prometheus.Unregister(inputMarginHistogram)
+		}
+		if inputBacklogCounter != nil {
+			prometheus.Unregister(inputBacklogCounter)
+		}
+		if inputRedundancyCounter != nil {
+			prometheus.Unregister(inputRedundancyCounter)
+		}
+		if droppedTicksCounter != nil {
+			prometheus.Unregister(droppedTicksCounter)
+		}
+		if sessionTransitionsCounter != nil {
+			prometheus.Unregister(sessionTransitionsCounter)
+		}
+		if scheduledSessionsGauge != nil {
+			prometheus.Unregister(scheduledSessionsGauge)
+		}
+		if schedulerLagHistogram != nil {
+			prometheus.Unregister(schedulerLagHistogram)
+		}
+		if schedulerOverrunsCounter != nil {
+			prometheus.Unregister(schedulerOverrunsCounter)
+		}
 	}
 
 	// Connection events counter
@@ -145,6 +235,140 @@ func InitMetrics() {
 		[]string{"direction"}, // direction: in, out
 	)
 
+	// Active rooms gauge
+	activeRoomsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "active_rooms",
+			Help: "Current number of open rooms",
+		},
+	)
+
+	// Room players gauge
+	roomPlayersGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "room_players",
+			Help: "Current number of players in a room",
+		},
+		[]string{"room"}, // room: room ID
+	)
+
+	// Matchmaking queue time histogram
+	// Buckets: 1s, 5s, 10s, 30s, 1m, 2m, 5m
+	matchmakingQueueHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "matchmaking_queue_seconds",
+			Help:    "Time players spend in the matchmaking queue before being matched",
+			Buckets: []float64{1, 5, 10, 30, 60, 120, 300}, // 1s, 5s, 10s, 30s, 1m, 2m, 5m
+		},
+	)
+
+	// Matchmaking queue size gauge
+	matchmakingQueueSizeGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "matchmaking_queue_size",
+			Help: "Current number of players in the matchmaking queue",
+		},
+	)
+
+	// Late inputs counter
+	lateInputsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "late_inputs_total",
+			Help: "Total number of inputs that arrived after later inputs of their player were applied",
+		},
+		[]string{"result"}, // result: rolled_back, dropped
+	)
+
+	// Rollback ticks histogram
+	// Buckets: 1, 2, 4, 8, 16, 32 ticks
+	rollbackTicksHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "rollback_ticks",
+			Help:    "Number of ticks re-simulated by a rollback",
+			Buckets: []float64{1, 2, 4, 8, 16, 32},
+		},
+	)
+
+	// Input timing counter
+	inputTimingCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "input_timing_total",
+			Help: "Total number of tick-targeted inputs by arrival timing",
+		},
+		[]string{"timing"}, // timing: on_time, late, early
+	)
+
+	// Input margin histogram
+	// Buckets: -8 to 32 ticks; negative margins are late inputs
+	inputMarginHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "input_margin_ticks",
+			Help:    "Ticks between the arrival of a tick-targeted input and the tick it is played at",
+			Buckets: []float64{-8, -4, -2, -1, 0, 1, 2, 4, 8, 16, 32},
+		},
+	)
+
+	// Input backlog counter
+	inputBacklogCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "input_backlog_total",
+			Help: "Total number of queued commands dropped or coalesced to bound input latency",
+		},
+		[]string{"action"}, // action: dropped, coalesced
+	)
+
+	// Input redundancy counter
+	inputRedundancyCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "input_redundancy_total",
+			Help: "Total number of redundant input copies received, by whether they added an input that was missing",
+		},
+		[]string{"result"}, // result: useful, duplicate
+	)
+
+	// Dropped ticks counter
+	droppedTicksCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "ticks_dropped_total",
+			Help: "Total number of due ticks sessions skipped because they fell behind",
+		},
+	)
+
+	// Session transitions counter
+	sessionTransitionsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "session_transitions_total",
+			Help: "Total number of session lifecycle state transitions",
+		},
+		[]string{"from", "to"}, // states: waiting, countdown, running, finished, expired
+	)
+
+	// Scheduled sessions gauge
+	scheduledSessionsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "scheduler_sessions",
+			Help: "Number of sessions stepped by the tick scheduler",
+		},
+	)
+
+	// Scheduler lag histogram
+	// Buckets: 0.1ms, 0.5ms, 1ms, 5ms, 10ms, 33ms, 100ms
+	schedulerLagHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    "scheduler_lag_seconds",
+			Help:    "Time between a tick boundary and the start of a scheduled session's step",
+			Buckets: []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.033, 0.1}, // 0.1ms, 0.5ms, 1ms, 5ms, 10ms, 33ms, 100ms
+		},
+	)
+
+	// Scheduler overruns counter
+	schedulerOverrunsCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "scheduler_overruns_total",
+			Help: "Total number of tick boundaries scheduled sessions missed because their previous step was still running",
+		},
+	)
+
 	// Register all metrics
 	prometheus.MustRegister(connectionEventsCounter)
 	prometheus.MustRegister(messagesCounter)
@@ -154,6 +378,21 @@ func InitMetrics() {
 	prometheus.MustRegister(gcPauseHistogram)
 	prometheus.MustRegister(connectionDurationHistogram)
 	prometheus.MustRegister(connectionBytesCounter)
+	prometheus.MustRegister(activeRoomsGauge)
+	prometheus.MustRegister(roomPlayersGauge)
+	prometheus.MustRegister(matchmakingQueueHistogram)
+	prometheus.MustRegister(matchmakingQueueSizeGauge)
+	prometheus.MustRegister(lateInputsCounter)
+	prometheus.MustRegister(rollbackTicksHistogram)
+	prometheus.MustRegister(inputTimingCounter)
+	prometheus.MustRegister(inputMarginHistogram)
+	prometheus.MustRegister(inputBacklogCounter)
+	prometheus.MustRegister(inputRedundancyCounter)
+	prometheus.MustRegister(droppedTicksCounter)
+	prometheus.MustRegister(sessionTransitionsCounter)
+	prometheus.MustRegister(scheduledSessionsGauge)
+	prometheus.MustRegister(schedulerLagHistogram)
+	prometheus.MustRegister(schedulerOverrunsCounter)
 
 	// Record server start time
 	serverStartTime = time.Now()
@@ -208,6 +447,136 @@ func UpdateQueueDepth(size int) {
 	}
 }
 
+// GetActiveRoomsGauge returns the active rooms gauge metric.
+func GetActiveRoomsGauge() prometheus.Gauge {
+	return activeRoomsGauge
+}
+
+// GetRoomPlayersGauge returns the room players gauge metric.
+func GetRoomPlayersGauge() *prometheus.GaugeVec {
+	return roomPlayersGauge
+}
+
+// UpdateActiveRooms updates the active rooms gauge metric with the current number of rooms.
+func UpdateActiveRooms(count int) {
+	if activeRoomsGauge != nil {
+		activeRoomsGauge.Set(float64(count))
+	}
+}
+
+// UpdateRoomPlayers updates the player count of room roomID.
+func UpdateRoomPlayers(roomID string, players int) {
+	if roomPlayersGauge != nil {
+		roomPlayersGauge.WithLabelValues(roomID).Set(float64(players))
+	}
+}
+
+// DeleteRoomMetrics removes the per-room series of a closed room, so closed
+// rooms do not accumulate in the exported metrics.
+func DeleteRoomMetrics(roomID string) {
+	if roomPlayersGauge != nil {
+		roomPlayersGauge.DeleteLabelValues(roomID)
+	}
+}
+
+// GetMatchmakingQueueHistogram returns the matchmaking queue time histogram metric.
+func GetMatchmakingQueueHistogram() prometheus.Histogram {
+	return matchmakingQueueHistogram
+}
+
+// GetMatchmakingQueueSizeGauge returns the matchmaking queue size gauge metric.
+func GetMatchmakingQueueSizeGauge() prometheus.Gauge {
+	return matchmakingQueueSizeGauge
+}
+
+// ObserveMatchmakingQueueTime records the time a matched player spent in the queue.
+func ObserveMatchmakingQueueTime(wait time.Duration) {
+	if matchmakingQueueHistogram != nil {
+		matchmakingQueueHistogram.Observe(wait.Seconds())
+	}
+}
+
+// UpdateMatchmakingQueueSize updates the matchmaking queue size gauge metric.
+func UpdateMatchmakingQueueSize(size int) {
+	if matchmakingQueueSizeGauge != nil {
+		matchmakingQueueSizeGauge.Set(float64(size))
+	}
+}
+
+// GetLateInputsCounter returns the late inputs counter metric.
+func GetLateInputsCounter() *prometheus.CounterVec {
+	return lateInputsCounter
+}
+
+// GetRollbackTicksHistogram returns the rollback ticks histogram metric.
+func GetRollbackTicksHistogram() prometheus.Histogram {
+	return rollbackTicksHistogram
+}
+
+// RecordLateInput counts a late input with its result ("rolled_back" or "dropped").
+func RecordLateInput(result string) {
+	if lateInputsCounter != nil {
+		lateInputsCounter.WithLabelValues(result).Inc()
+	}
+}
+
+// ObserveRollbackTicks records the number of ticks a rollback re-simulated.
+func ObserveRollbackTicks(ticks int) {
+	if rollbackTicksHistogram != nil {
+		rollbackTicksHistogram.Observe(float64(ticks))
+	}
+}
+
+// GetInputTimingCounter returns the input timing counter metric.
+func GetInputTimingCounter() *prometheus.CounterVec {
+	return inputTimingCounter
+}
+
+// GetInputMarginHistogram returns the input margin histogram metric.
+func GetInputMarginHistogram() prometheus.Histogram {
+	return inputMarginHistogram
+}
+
+// RecordInputTiming counts a tick-targeted input by timing ("on_time", "late" or "early").
+func RecordInputTiming(timing string) {
+	if inputTimingCounter != nil {
+		inputTimingCounter.WithLabelValues(timing).Inc()
+	}
+}
+
+// ObserveInputMargin records the margin of a tick-targeted input in ticks.
+func ObserveInputMargin(ticks int64) {
+	if inputMarginHistogram != nil {
+		inputMarginHistogram.Observe(float64(ticks))
+	}
+}
+
+// GetInputBacklogCounter returns the input backlog counter metric.
+func GetInputBacklogCounter() *prometheus.CounterVec {
+	return inputBacklogCounter
+}
+
+// RecordInputBacklog counts commands handled by backlog control ("dropped" or "coalesced").
+func RecordInputBacklog(action string, commands int) {
+	if inputBacklogCounter != nil {
+		inputBacklogCounter.WithLabelValues(action).Add(float64(commands))
+	}
+}
+
+// GetInputRedundancyCounter returns the input redundancy counter metric.
+func GetInputRedundancyCounter() *prometheus.CounterVec {
+	return inputRedundancyCounter
+}
+
+// RecordRedundantInputs counts the redundant input copies of a packet: useful
+// ones added an input the server had not received yet, duplicate ones had arrived.
+func RecordRedundantInputs(useful, duplicate int) {
+	if inputRedundancyCounter != nil {
+		inputRedundancyCounter.WithLabelValues("useful").Add(float64(useful))
+		inputRedundancyCounter.WithLabelValues("duplicate").Add(float64(duplicate))
+	}
+}
+
 // MetricsHandler handles HTTP requests to the /metrics endpoint.
 // It returns Prometheus-formatted metrics.
 func MetricsHandler(w http.ResponseWriter, r *http.Request) {
@@ -291,3 +660,62 @@ func GetHealthMetrics() HealthMetrics {
 	return metrics
 }
 
+// GetDroppedTicksCounter returns the dropped ticks counter metric.
+func GetDroppedTicksCounter() prometheus.Counter {
+	return droppedTicksCounter
+}
+
+// RecordDroppedTicks counts ticks a session skipped because more were due than it could run.
+func RecordDroppedTicks(ticks int) {
+	if droppedTicksCounter != nil {
+		droppedTicksCounter.Add(float64(ticks))
+	}
+}
+
+// GetSessionTransitionsCounter returns the session transitions counter metric.
+func GetSessionTransitionsCounter() *prometheus.CounterVec {
+	return sessionTransitionsCounter
+}
+
+// RecordSessionTransition counts a session lifecycle transition between two states.
+func RecordSessionTransition(from, to string) {
+	if sessionTransitionsCounter != nil {
+		sessionTransitionsCounter.WithLabelValues(from, to).Inc()
+	}
+}
+
+// GetScheduledSessionsGauge returns the scheduled sessions gauge metric.
+func GetScheduledSessionsGauge() prometheus.Gauge {
+	return scheduledSessionsGauge
+}
+
+// GetSchedulerLagHistogram returns the scheduler lag histogram metric.
+func GetSchedulerLagHistogram() prometheus.Histogram {
+	return schedulerLagHistogram
+}
+
+// GetSchedulerOverrunsCounter returns the scheduler overruns counter metric.
+func GetSchedulerOverrunsCounter() prometheus.Counter {
+	return schedulerOverrunsCounter
+}
+
+// UpdateScheduledSessions updates the number of sessions stepped by the tick scheduler.
+func UpdateScheduledSessions(count int) {
+	if scheduledSessionsGauge != nil {
+		scheduledSessionsGauge.Set(float64(count))
+	}
+}
+
+// ObserveSchedulerLag records how late after its tick boundary a scheduled session was stepped.
+func ObserveSchedulerLag(lag time.Duration) {
+	if schedulerLagHistogram != nil {
+		schedulerLagHistogram.Observe(lag.Seconds())
+	}
+}
+
+// RecordSchedulerOverrun counts a tick boundary a scheduled session missed.
+func RecordSchedulerOverrun() {
+	if schedulerOverrunsCounter != nil {
+		schedulerOverrunsCounter.Inc()
+	}
+}
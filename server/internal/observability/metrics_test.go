@@ -4,10 +4,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	dto "github.com/prometheus/client_model/go"
 )
 
@@ -99,6 +101,131 @@ var _ = Describe("Metrics", Label("scope:integration", "loop:g7-ops", "layer:ser
 		})
 	})
 
+	Describe("Room Metrics", func() {
+		It("tracks open rooms and players per room", func() {
+			UpdateActiveRooms(2)
+			UpdateRoomPlayers("abc", 3)
+
+			var metric dto.Metric
+			Expect(GetActiveRoomsGauge().Write(&metric)).To(Succeed())
+			Expect(metric.Gauge.GetValue()).To(Equal(2.0))
+
+			Expect(GetRoomPlayersGauge().WithLabelValues("abc").Write(&metric)).To(Succeed())
+			Expect(metric.Gauge.GetValue()).To(Equal(3.0))
+		})
+
+		It("removes the series of a closed room", func() {
+			UpdateRoomPlayers("abc", 1)
+			Expect(testutil.CollectAndCount(GetRoomPlayersGauge())).To(Equal(1))
+
+			DeleteRoomMetrics("abc")
+			Expect(testutil.CollectAndCount(GetRoomPlayersGauge())).To(Equal(0))
+		})
+	})
+
+	Describe("Matchmaking Metrics", func() {
+		It("records queue times in seconds and the queue size", func() {
+			ObserveMatchmakingQueueTime(1500 * time.Millisecond)
+			UpdateMatchmakingQueueSize(4)
+
+			var metric dto.Metric
+			Expect(GetMatchmakingQueueHistogram().Write(&metric)).To(Succeed())
+			Expect(metric.Histogram.GetSampleCount()).To(Equal(uint64(1)))
+			Expect(metric.Histogram.GetSampleSum()).To(Equal(1.5))
+
+			Expect(testutil.ToFloat64(GetMatchmakingQueueSizeGauge())).To(Equal(4.0))
+		})
+	})
+
+	Describe("Rollback Metrics", func() {
+		It("counts late inputs by result and records rollback lengths", func() {
+			RecordLateInput("rolled_back")
+			RecordLateInput("rolled_back")
+			RecordLateInput("dropped")
+			ObserveRollbackTicks(3)
+
+			Expect(testutil.ToFloat64(GetLateInputsCounter().WithLabelValues("rolled_back"))).To(Equal(2.0))
+			Expect(testutil.ToFloat64(GetLateInputsCounter().WithLabelValues("dropped"))).To(Equal(1.0))
+
+			var metric dto.Metric
+			Expect(GetRollbackTicksHistogram().Write(&metric)).To(Succeed())
+			Expect(metric.Histogram.GetSampleCount()).To(Equal(uint64(1)))
+			Expect(metric.Histogram.GetSampleSum()).To(Equal(3.0))
+		})
+	})
+
+	Describe("Input Timing Metrics", func() {
+		It("counts inputs by timing and records their margins", func() {
+			RecordInputTiming("on_time")
+			RecordInputTiming("late")
+			ObserveInputMargin(2)
+			ObserveInputMargin(-1)
+
+			Expect(testutil.ToFloat64(GetInputTimingCounter().WithLabelValues("on_time"))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(GetInputTimingCounter().WithLabelValues("late"))).To(Equal(1.0))
+
+			var metric dto.Metric
+			Expect(GetInputMarginHistogram().Write(&metric)).To(Succeed())
+			Expect(metric.Histogram.GetSampleCount()).To(Equal(uint64(2)))
+			Expect(metric.Histogram.GetSampleSum()).To(Equal(1.0))
+		})
+	})
+
+	Describe("Input Backlog Metrics", func() {
+		It("counts dropped and coalesced commands", func() {
+			RecordInputBacklog("dropped", 3)
+			RecordInputBacklog("coalesced", 2)
+			RecordInputBacklog("coalesced", 1)
+
+			Expect(testutil.ToFloat64(GetInputBacklogCounter().WithLabelValues("dropped"))).To(Equal(3.0))
+			Expect(testutil.ToFloat64(GetInputBacklogCounter().WithLabelValues("coalesced"))).To(Equal(3.0))
+		})
+	})
+
+	Describe("Input Redundancy Metrics", func() {
+		It("counts useful and duplicate redundant inputs", func() {
+			RecordRedundantInputs(1, 3)
+			RecordRedundantInputs(0, 4)
+
+			Expect(testutil.ToFloat64(GetInputRedundancyCounter().WithLabelValues("useful"))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(GetInputRedundancyCounter().WithLabelValues("duplicate"))).To(Equal(7.0))
+		})
+	})
+
+	Describe("Dropped Ticks Metrics", func() {
+		It("counts dropped ticks", func() {
+			RecordDroppedTicks(4)
+			RecordDroppedTicks(1)
+
+			Expect(testutil.ToFloat64(GetDroppedTicksCounter())).To(Equal(5.0))
+		})
+	})
+
+	Describe("Scheduler Metrics", func() {
+		It("tracks scheduled sessions, lag and overruns", func() {
+			UpdateScheduledSessions(3)
+			ObserveSchedulerLag(2 * time.Millisecond)
+			RecordSchedulerOverrun()
+
+			Expect(testutil.ToFloat64(GetScheduledSessionsGauge())).To(Equal(3.0))
+			Expect(testutil.ToFloat64(GetSchedulerOverrunsCounter())).To(Equal(1.0))
+			var metric dto.Metric
+			Expect(GetSchedulerLagHistogram().Write(&metric)).To(Succeed())
+			Expect(metric.Histogram.GetSampleCount()).To(Equal(uint64(1)))
+		})
+	})
+
+	Describe("Session Transition Metrics", func() {
+		It("counts transitions by states", func() {
+			RecordSessionTransition("running", "finished")
+			RecordSessionTransition("finished", "expired")
+			RecordSessionTransition("running", "finished")
+
+			Expect(testutil.ToFloat64(GetSessionTransitionsCounter().WithLabelValues("running", "finished"))).To(Equal(2.0))
+			Expect(testutil.ToFloat64(GetSessionTransitionsCounter().WithLabelValues("finished", "expired"))).To(Equal(1.0))
+		})
+	})
+
 	Describe("Tick Duration Histogram", func() {
 		It("can record tick durations", func() {
 			histogram := GetTickDurationHistogram()
@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gorbit/orbitalrush/internal/session"
+)
+
+// replayExt is the file extension of replay files (gzip-compressed JSON).
+const replayExt = ".json.gz"
+
+// ErrInvalidReplayID is returned for replay IDs that cannot name a replay file.
+var ErrInvalidReplayID = errors.New("invalid replay ID")
+
+// ReplayStore keeps session replays in a local directory, one gzip-compressed
+// JSON file per replay ID. Like Store, files are renamed into place once written.
+//
+// ReplayStore is safe for concurrent use.
+type ReplayStore struct {
+	dir string
+}
+
+// NewReplayStore creates a replay store in dir, creating the directory if needed.
+func NewReplayStore(dir string) (*ReplayStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create replay directory: %w", err)
+	}
+	return &ReplayStore{dir: dir}, nil
+}
+
+// Dir returns the directory of the store.
+func (s *ReplayStore) Dir() string {
+	return s.dir
+}
+
+// Save writes replay id, replacing any previous replay with that ID.
+func (s *ReplayStore) Save(id string, replay session.Replay) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("%w %q", ErrInvalidReplayID, id)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(replay); err != nil {
+		return fmt.Errorf("failed to encode replay %s: %w", id, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to encode replay %s: %w", id, err)
+	}
+
+	if err := writeFile(s.dir, id+replayExt, buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write replay %s: %w", id, err)
+	}
+	return nil
+}
+
+// Load reads replay id. Returns an error wrapping os.ErrNotExist if there is no such
+// replay; errors for invalid IDs also wrap ErrInvalidReplayID.
+func (s *ReplayStore) Load(id string) (session.Replay, error) {
+	if !validID.MatchString(id) {
+		return session.Replay{}, fmt.Errorf("%w %q: %w", ErrInvalidReplayID, id, os.ErrNotExist)
+	}
+
+	file, err := os.Open(filepath.Join(s.dir, id+replayExt))
+	if err != nil {
+		return session.Replay{}, fmt.Errorf("failed to read replay %s: %w", id, err)
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return session.Replay{}, fmt.Errorf("failed to decode replay %s: %w", id, err)
+	}
+	defer zr.Close()
+
+	var replay session.Replay
+	if err := json.NewDecoder(zr).Decode(&replay); err != nil {
+		return session.Replay{}, fmt.Errorf("failed to decode replay %s: %w", id, err)
+	}
+	if replay.Version != session.ReplayVersion {
+		return session.Replay{}, fmt.Errorf("unsupported replay version %d in %s (expected %d)", replay.Version, id, session.ReplayVersion)
+	}
+	return replay, nil
+}
+
+// IDs returns the IDs of the stored replays, sorted.
+func (s *ReplayStore) IDs() ([]string, error) {
+	ids, err := listIDs(s.dir, replayExt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replays: %w", err)
+	}
+	return ids, nil
+}
+
+// Delete removes replay id. Deleting a missing replay is not an error.
+func (s *ReplayStore) Delete(id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("%w %q", ErrInvalidReplayID, id)
+	}
+	if err := os.Remove(filepath.Join(s.dir, id+replayExt)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete replay %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package persistence
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replay Store", Label("scope:unit", "loop:g3-orch", "layer:server", "dep:fs", "b:replay-recording", "r:medium"), func() {
+	var (
+		dir   string
+		store *ReplayStore
+	)
+
+	// recorded returns the replay of a short session with some thrust
+	recorded := func() session.Replay {
+		clock := session.NewFakeClock()
+		sess := session.NewSession(clock, levels.Standard(), 10)
+		sess.StartRecording("standard", 0)
+		for seq := uint32(1); seq <= 5; seq++ {
+			sess.EnqueueCommand(seq, rules.InputCommand{Thrust: 1.0})
+		}
+		for i := 0; i < 20; i++ {
+			clock.Advance(session.DefaultTickInterval)
+			Expect(sess.Run(1)).To(Succeed())
+		}
+		replay, _ := sess.StopRecording()
+		return replay
+	}
+
+	BeforeEach(func() {
+		dir = filepath.Join(GinkgoT().TempDir(), "replays")
+		var err error
+		store, err = NewReplayStore(dir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("saves and loads gzip-compressed replays by ID", func() {
+		replay := recorded()
+		Expect(store.Save("abc123", replay)).To(Succeed())
+
+		loaded, err := store.Load("abc123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded).To(Equal(replay))
+		Expect(store.IDs()).To(Equal([]string{"abc123"}))
+
+		file, err := os.Open(filepath.Join(dir, "abc123.json.gz"))
+		Expect(err).NotTo(HaveOccurred())
+		defer file.Close()
+		_, err = gzip.NewReader(file)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports missing replays and invalid IDs as not found", func() {
+		_, err := store.Load("missing")
+		Expect(err).To(MatchError(os.ErrNotExist))
+		_, err = store.Load("../escape")
+		Expect(err).To(MatchError(os.ErrNotExist))
+		Expect(err).To(MatchError(ErrInvalidReplayID))
+		Expect(store.Save("../escape", recorded())).To(MatchError(ContainSubstring("invalid replay ID")))
+	})
+
+	It("deletes replays", func() {
+		Expect(store.Save("abc123", recorded())).To(Succeed())
+		Expect(store.Delete("abc123")).To(Succeed())
+		Expect(store.Delete("abc123")).To(Succeed())
+		Expect(store.IDs()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,188 @@
+package persistence
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/session"
+)
+
+// FormatVersion is the version of the checkpoint file format.
+// Files with another version are not loaded.
+const FormatVersion = 1
+
+// checkpointExt is the file extension of checkpoint files.
+const checkpointExt = ".json"
+
+// validID matches the IDs a checkpoint can be stored under. IDs become file
+// names, so path separators and dots are not allowed.
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// checkpointFile is the on-disk format of a checkpoint.
+type checkpointFile struct {
+	Version    int                `json:"version"`
+	SavedAt    time.Time          `json:"saved_at"`
+	Checkpoint session.Checkpoint `json:"checkpoint"`
+}
+
+// Store keeps session checkpoints in a local directory, one JSON file per session ID.
+// Files are written to a temporary file and renamed into place, so a crash while
+// saving never leaves a truncated checkpoint behind.
+//
+// Store is safe for concurrent use: concurrent saves of the same ID leave one of
+// the saved checkpoints.
+type Store struct {
+	dir string
+}
+
+// NewStore creates a store in dir, creating the directory if needed.
+// The directory is only accessible by the server's user, since session IDs are
+// resume tokens.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Dir returns the directory of the store.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Save writes the checkpoint of session id, replacing any previous one.
+func (s *Store) Save(id string, cp session.Checkpoint) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("invalid checkpoint ID %q", id)
+	}
+
+	data, err := json.Marshal(checkpointFile{
+		Version:    FormatVersion,
+		SavedAt:    time.Now().UTC(),
+		Checkpoint: cp,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint %s: %w", id, err)
+	}
+
+	if err := writeFile(s.dir, id+checkpointExt, data); err != nil {
+		return fmt.Errorf("failed to write checkpoint %s: %w", id, err)
+	}
+	return nil
+}
+
+// Load reads the checkpoint of session id.
+func (s *Store) Load(id string) (session.Checkpoint, error) {
+	if !validID.MatchString(id) {
+		return session.Checkpoint{}, fmt.Errorf("invalid checkpoint ID %q", id)
+	}
+
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return session.Checkpoint{}, fmt.Errorf("failed to read checkpoint %s: %w", id, err)
+	}
+	var file checkpointFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return session.Checkpoint{}, fmt.Errorf("failed to decode checkpoint %s: %w", id, err)
+	}
+	if file.Version != FormatVersion {
+		return session.Checkpoint{}, fmt.Errorf("unsupported checkpoint version %d in %s (expected %d)", file.Version, id, FormatVersion)
+	}
+	return file.Checkpoint, nil
+}
+
+// LoadAll reads every checkpoint in the store.
+// Checkpoints that cannot be read are skipped and reported in the returned
+// error, so one corrupt file does not prevent restoring the others.
+func (s *Store) LoadAll() (map[string]session.Checkpoint, error) {
+	ids, err := s.IDs()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make(map[string]session.Checkpoint, len(ids))
+	var errs []error
+	for _, id := range ids {
+		cp, err := s.Load(id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		checkpoints[id] = cp
+	}
+	return checkpoints, errors.Join(errs...)
+}
+
+// IDs returns the IDs of the stored checkpoints, sorted.
+func (s *Store) IDs() ([]string, error) {
+	ids, err := listIDs(s.dir, checkpointExt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+	return ids, nil
+}
+
+// Delete removes the checkpoint of session id. Deleting a missing checkpoint is not an error.
+func (s *Store) Delete(id string) error {
+	if !validID.MatchString(id) {
+		return fmt.Errorf("invalid checkpoint ID %q", id)
+	}
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete checkpoint %s: %w", id, err)
+	}
+	return nil
+}
+
+// path returns the file of the checkpoint of session id.
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+checkpointExt)
+}
+
+// writeFile writes data to dir/name through a synced temporary file that is
+// renamed into place, so readers never see a partially written file.
+func writeFile(dir, name string, data []byte) error {
+	tmp, err := os.CreateTemp(dir, "."+name+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+// listIDs returns the valid IDs of the files in dir with extension ext, sorted.
+func listIDs(dir, ext string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		if id := strings.TrimSuffix(name, ext); validID.MatchString(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,98 @@
+package persistence
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPersistence(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Persistence Suite")
+}
+
+var _ = Describe("Checkpoint Store", Label("scope:unit", "loop:g3-orch", "layer:server", "dep:fs", "b:session-persistence", "r:high"), func() {
+	var (
+		dir   string
+		store *Store
+	)
+
+	checkpoint := func(tick uint32) session.Checkpoint {
+		world := levels.Standard()
+		world.Tick = tick
+		return session.Checkpoint{
+			World:         world,
+			Rules:         rules.DefaultConfig(),
+			NextSequences: map[entities.PlayerID]uint32{entities.DefaultPlayerID: tick + 1},
+		}
+	}
+
+	BeforeEach(func() {
+		dir = filepath.Join(GinkgoT().TempDir(), "sessions")
+		var err error
+		store, err = NewStore(dir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("creates the directory with owner-only permissions", func() {
+		info, err := os.Stat(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.IsDir()).To(BeTrue())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o700)))
+	})
+
+	It("saves and loads checkpoints by ID", func() {
+		Expect(store.Save("abc123", checkpoint(10))).To(Succeed())
+		Expect(store.Save("abc123", checkpoint(20))).To(Succeed())
+
+		cp, err := store.Load("abc123")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cp).To(Equal(checkpoint(20)))
+		Expect(store.IDs()).To(Equal([]string{"abc123"}))
+	})
+
+	It("leaves no temporary files behind", func() {
+		Expect(store.Save("abc123", checkpoint(1))).To(Succeed())
+
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+		Expect(entries[0].Name()).To(Equal("abc123.json"))
+	})
+
+	It("loads every readable checkpoint and reports the others", func() {
+		Expect(store.Save("first", checkpoint(1))).To(Succeed())
+		Expect(store.Save("second", checkpoint(2))).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "broken.json"), []byte(`{"version":`), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "future.json"), []byte(`{"version":99}`), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600)).To(Succeed())
+
+		checkpoints, err := store.LoadAll()
+		Expect(err).To(MatchError(ContainSubstring("broken")))
+		Expect(err).To(MatchError(ContainSubstring("unsupported checkpoint version 99")))
+		Expect(checkpoints).To(HaveLen(2))
+		Expect(checkpoints["first"]).To(Equal(checkpoint(1)))
+		Expect(checkpoints["second"]).To(Equal(checkpoint(2)))
+	})
+
+	It("deletes checkpoints", func() {
+		Expect(store.Save("abc123", checkpoint(1))).To(Succeed())
+		Expect(store.Delete("abc123")).To(Succeed())
+		Expect(store.Delete("abc123")).To(Succeed())
+		Expect(store.IDs()).To(BeEmpty())
+	})
+
+	It("rejects IDs that are not plain file names", func() {
+		Expect(store.Save("../escape", checkpoint(1))).To(MatchError(ContainSubstring("invalid checkpoint ID")))
+		_, err := store.Load("a/b")
+		Expect(err).To(MatchError(ContainSubstring("invalid checkpoint ID")))
+		Expect(store.Delete("")).To(MatchError(ContainSubstring("invalid checkpoint ID")))
+	})
+})
@@ -0,0 +1,711 @@
+package proto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Binary encoding of the messages, a compact alternative to JSON for clients that
+// negotiate it (see the transport). A message is one tag byte identifying its type
+// followed by its fields in declaration order, without names:
+//   - unsigned integers (uint32 and counts) are unsigned varints, signed ones
+//     zigzag varints (encoding/binary)
+//   - float32 and float64 are 4 and 8 bytes of IEEE 754, little-endian
+//   - bools are one byte, 0 or 1
+//   - strings are their length as an unsigned varint followed by their bytes
+//   - slices are their length as an unsigned varint followed by their elements
+//
+// The Type field is implied by the tag; optional snapshot fields are announced by
+// a flags byte. See SPEC.md for the layout of every message.
+
+// Binary message tags.
+const (
+	tagInput          byte = 0x01
+	tagInputs         byte = 0x02
+	tagRestart        byte = 0x03
+	tagSessionControl byte = 0x04
+	tagReplayControl  byte = 0x05
+	tagSnapshotAck    byte = 0x06
+	tagKeyframe       byte = 0x07
+	tagHello          byte = 0x08
+	tagSnapshot       byte = 0x10
+	tagSession        byte = 0x11
+	tagQueued         byte = 0x12
+	tagMatch          byte = 0x13
+	tagReplayState    byte = 0x14
+	tagWarning        byte = 0x15
+	tagError          byte = 0x16
+	tagDelta          byte = 0x17
+	tagWelcome        byte = 0x18
+)
+
+// Snapshot flags.
+const (
+	snapshotDone      byte = 1 << 0
+	snapshotWin       byte = 1 << 1
+	snapshotPaused    byte = 1 << 2
+	snapshotTimeScale byte = 1 << 3 // TimeScale follows the pallets
+	snapshotAck       byte = 1 << 4 // Ack follows TimeScale
+	deltaShip         byte = 1 << 5 // Ship follows the flags, in a delta
+	deltaSun          byte = 1 << 6 // Sun follows RemovedShips, in a delta
+)
+
+// Ship delta fields, set in the mask preceding them.
+const (
+	shipDestroyed byte = 1 << iota
+	shipPos
+	shipVel
+	shipRot
+	shipEnergy
+)
+
+// ErrBinaryTruncated is returned when a binary message ends before its last field.
+var ErrBinaryTruncated = errors.New("binary message truncated")
+
+// MarshalBinary encodes msg, one of the message structs (not a pointer to it), in
+// the binary encoding. Returns an error for other types.
+func MarshalBinary(msg any) ([]byte, error) {
+	w := binaryWriter{buf: make([]byte, 0, 64)}
+	switch m := msg.(type) {
+	case InputMessage:
+		w.byte(tagInput)
+		w.uvarint(m.Seq)
+		w.float32(m.Thrust)
+		w.float32(m.Turn)
+		w.uvarint(m.Tick)
+	case InputsMessage:
+		w.byte(tagInputs)
+		w.count(len(m.Inputs))
+		for _, input := range m.Inputs {
+			w.uvarint(input.Seq)
+			w.float32(input.Thrust)
+			w.float32(input.Turn)
+		}
+	case RestartMessage:
+		w.byte(tagRestart)
+	case SessionControlMessage:
+		w.byte(tagSessionControl)
+		w.string(m.Action)
+		w.float64(m.Scale)
+	case ReplayControlMessage:
+		w.byte(tagReplayControl)
+		w.string(m.Action)
+		w.float64(m.Speed)
+		w.uvarint(m.Tick)
+	case SnapshotAckMessage:
+		w.byte(tagSnapshotAck)
+		w.uvarint(m.Tick)
+	case KeyframeRequestMessage:
+		w.byte(tagKeyframe)
+	case HelloMessage:
+		w.byte(tagHello)
+		w.versions(m.Versions)
+	case SnapshotMessage:
+		w.byte(tagSnapshot)
+		w.snapshot(m)
+	case DeltaSnapshotMessage:
+		w.byte(tagDelta)
+		w.delta(m)
+	case SessionMessage:
+		w.byte(tagSession)
+		w.string(m.Token)
+		w.bool(m.Resumed)
+	case QueuedMessage:
+		w.byte(tagQueued)
+		w.varint(m.Rating)
+	case MatchMessage:
+		w.byte(tagMatch)
+		w.string(m.Room)
+		w.string(m.You)
+		w.varint(m.Players)
+	case ReplayStateMessage:
+		w.byte(tagReplayState)
+		w.string(m.ID)
+		w.uvarint(m.Tick)
+		w.uvarint(m.StartTick)
+		w.uvarint(m.EndTick)
+		w.bool(m.Paused)
+		w.float64(m.Speed)
+	case WarningMessage:
+		w.byte(tagWarning)
+		w.string(m.Code)
+		w.string(m.Message)
+		w.varint(m.Dropped)
+		w.varint(m.Coalesced)
+	case ErrorMessage:
+		w.byte(tagError)
+		w.string(m.Message)
+		w.string(m.Code)
+		w.versions(m.Versions)
+	case WelcomeMessage:
+		w.byte(tagWelcome)
+		w.string(string(m.Version))
+	default:
+		return nil, fmt.Errorf("unsupported message type for binary encoding: %T", msg)
+	}
+	return w.buf, nil
+}
+
+// UnmarshalBinary decodes a binary message and returns a pointer to the message
+// struct of its tag, with Type set. It does not validate the message.
+// Returns an error for unknown tags, truncated messages and trailing bytes.
+func UnmarshalBinary(data []byte) (any, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty message")
+	}
+	r := binaryReader{data: data[1:]}
+	var msg any
+	switch data[0] {
+	case tagInput:
+		m := &InputMessage{Type: "input"}
+		m.Seq = r.uvarint()
+		m.Thrust = r.float32()
+		m.Turn = r.float32()
+		m.Tick = r.uvarint()
+		msg = m
+	case tagInputs:
+		m := &InputsMessage{Type: "inputs"}
+		n := r.count()
+		m.Inputs = make([]InputEntry, n)
+		for i := range m.Inputs {
+			m.Inputs[i].Seq = r.uvarint()
+			m.Inputs[i].Thrust = r.float32()
+			m.Inputs[i].Turn = r.float32()
+		}
+		msg = m
+	case tagRestart:
+		msg = &RestartMessage{Type: "restart"}
+	case tagSessionControl:
+		m := &SessionControlMessage{Type: "session_control"}
+		m.Action = r.string()
+		m.Scale = r.float64()
+		msg = m
+	case tagReplayControl:
+		m := &ReplayControlMessage{Type: "replay_control"}
+		m.Action = r.string()
+		m.Speed = r.float64()
+		m.Tick = r.uvarint()
+		msg = m
+	case tagSnapshotAck:
+		m := &SnapshotAckMessage{Type: "snapshot_ack"}
+		m.Tick = r.uvarint()
+		msg = m
+	case tagKeyframe:
+		msg = &KeyframeRequestMessage{Type: "keyframe_request"}
+	case tagHello:
+		m := &HelloMessage{Type: "hello"}
+		m.Versions = r.versions()
+		msg = m
+	case tagSnapshot:
+		m := &SnapshotMessage{Type: "snapshot"}
+		r.snapshot(m)
+		msg = m
+	case tagDelta:
+		m := &DeltaSnapshotMessage{Type: "delta"}
+		r.delta(m)
+		msg = m
+	case tagSession:
+		m := &SessionMessage{Type: "session"}
+		m.Token = r.string()
+		m.Resumed = r.bool()
+		msg = m
+	case tagQueued:
+		m := &QueuedMessage{Type: "queued"}
+		m.Rating = r.varint()
+		msg = m
+	case tagMatch:
+		m := &MatchMessage{Type: "match"}
+		m.Room = r.string()
+		m.You = r.string()
+		m.Players = r.varint()
+		msg = m
+	case tagReplayState:
+		m := &ReplayStateMessage{Type: "replay"}
+		m.ID = r.string()
+		m.Tick = r.uvarint()
+		m.StartTick = r.uvarint()
+		m.EndTick = r.uvarint()
+		m.Paused = r.bool()
+		m.Speed = r.float64()
+		msg = m
+	case tagWarning:
+		m := &WarningMessage{Type: "warning"}
+		m.Code = r.string()
+		m.Message = r.string()
+		m.Dropped = r.varint()
+		m.Coalesced = r.varint()
+		msg = m
+	case tagError:
+		m := &ErrorMessage{Type: "error"}
+		m.Message = r.string()
+		m.Code = r.string()
+		m.Versions = r.versions()
+		msg = m
+	case tagWelcome:
+		m := &WelcomeMessage{Type: "welcome"}
+		m.Version = ProtocolVersion(r.string())
+		msg = m
+	default:
+		return nil, fmt.Errorf("unknown binary message tag: 0x%02x", data[0])
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.data) > 0 {
+		return nil, fmt.Errorf("binary message has %d trailing bytes", len(r.data))
+	}
+	return msg, nil
+}
+
+// binaryWriter appends fields to buf.
+type binaryWriter struct {
+	buf []byte
+}
+
+func (w *binaryWriter) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *binaryWriter) uvarint(v uint32) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(v))
+}
+
+func (w *binaryWriter) count(n int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(n))
+}
+
+func (w *binaryWriter) varint(v int) {
+	w.buf = binary.AppendVarint(w.buf, int64(v))
+}
+
+func (w *binaryWriter) float32(v float32) {
+	w.buf = binary.LittleEndian.AppendUint32(w.buf, math.Float32bits(v))
+}
+
+func (w *binaryWriter) float64(v float64) {
+	w.buf = binary.LittleEndian.AppendUint64(w.buf, math.Float64bits(v))
+}
+
+func (w *binaryWriter) bool(v bool) {
+	if v {
+		w.byte(1)
+	} else {
+		w.byte(0)
+	}
+}
+
+func (w *binaryWriter) string(s string) {
+	w.count(len(s))
+	w.buf = append(w.buf, s...)
+}
+
+func (w *binaryWriter) versions(versions []ProtocolVersion) {
+	w.count(len(versions))
+	for _, version := range versions {
+		w.string(string(version))
+	}
+}
+
+func (w *binaryWriter) vec2(v Vec2Snapshot) {
+	w.float64(v.X)
+	w.float64(v.Y)
+}
+
+func (w *binaryWriter) ship(s ShipSnapshot) {
+	w.string(s.ID)
+	w.bool(s.Destroyed)
+	w.vec2(s.Pos)
+	w.vec2(s.Vel)
+	w.float64(s.Rot)
+	w.float32(s.Energy)
+}
+
+func (w *binaryWriter) snapshot(m SnapshotMessage) {
+	w.uvarint(m.Tick)
+	w.byte(snapshotFlags(m.Done, m.Win, m.Paused, m.TimeScale, m.Ack))
+	w.ship(m.Ship)
+	w.count(len(m.Ships))
+	for _, ship := range m.Ships {
+		w.ship(ship)
+	}
+	w.string(m.You)
+	w.vec2(m.Sun.Pos)
+	w.float32(m.Sun.Radius)
+	w.count(len(m.Pallets))
+	for _, pallet := range m.Pallets {
+		w.uvarint(pallet.ID)
+		w.vec2(pallet.Pos)
+		w.bool(pallet.Active)
+	}
+	if m.TimeScale != 0 {
+		w.float64(m.TimeScale)
+	}
+	if m.Ack != nil {
+		w.uvarint(m.Ack.Seq)
+		w.uvarint(m.Ack.Tick)
+	}
+}
+
+// snapshotFlags returns the flags of the fields snapshots and deltas share.
+func snapshotFlags(done, win, paused bool, timeScale float64, ack *InputAckSnapshot) byte {
+	var flags byte
+	if done {
+		flags |= snapshotDone
+	}
+	if win {
+		flags |= snapshotWin
+	}
+	if paused {
+		flags |= snapshotPaused
+	}
+	if timeScale != 0 {
+		flags |= snapshotTimeScale
+	}
+	if ack != nil {
+		flags |= snapshotAck
+	}
+	return flags
+}
+
+func (w *binaryWriter) shipDelta(s ShipDelta) {
+	var mask byte
+	if s.Destroyed != nil {
+		mask |= shipDestroyed
+	}
+	if s.Pos != nil {
+		mask |= shipPos
+	}
+	if s.Vel != nil {
+		mask |= shipVel
+	}
+	if s.Rot != nil {
+		mask |= shipRot
+	}
+	if s.Energy != nil {
+		mask |= shipEnergy
+	}
+	w.byte(mask)
+	if s.Destroyed != nil {
+		w.bool(*s.Destroyed)
+	}
+	if s.Pos != nil {
+		w.vec2(*s.Pos)
+	}
+	if s.Vel != nil {
+		w.vec2(*s.Vel)
+	}
+	if s.Rot != nil {
+		w.float64(*s.Rot)
+	}
+	if s.Energy != nil {
+		w.float32(*s.Energy)
+	}
+}
+
+func (w *binaryWriter) delta(m DeltaSnapshotMessage) {
+	w.uvarint(m.Tick)
+	w.uvarint(m.Base)
+	flags := snapshotFlags(m.Done, m.Win, m.Paused, m.TimeScale, m.Ack)
+	if m.Ship != nil {
+		flags |= deltaShip
+	}
+	if m.Sun != nil {
+		flags |= deltaSun
+	}
+	w.byte(flags)
+	if m.Ship != nil {
+		w.shipDelta(*m.Ship)
+	}
+	w.count(len(m.Ships))
+	for _, ship := range m.Ships {
+		w.string(ship.ID)
+		w.shipDelta(ship)
+	}
+	w.count(len(m.RemovedShips))
+	for _, id := range m.RemovedShips {
+		w.string(id)
+	}
+	if m.Sun != nil {
+		w.vec2(m.Sun.Pos)
+		w.float32(m.Sun.Radius)
+	}
+	w.count(len(m.Pallets))
+	for _, pallet := range m.Pallets {
+		w.uvarint(pallet.ID)
+		w.vec2(pallet.Pos)
+		w.bool(pallet.Active)
+	}
+	w.count(len(m.RemovedPallets))
+	for _, id := range m.RemovedPallets {
+		w.uvarint(id)
+	}
+	if m.TimeScale != 0 {
+		w.float64(m.TimeScale)
+	}
+	if m.Ack != nil {
+		w.uvarint(m.Ack.Seq)
+		w.uvarint(m.Ack.Tick)
+	}
+}
+
+// binaryReader reads fields from data. The first error is kept in err; reads
+// after it return zero values.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+// next returns the next n bytes, or nil once the data is exhausted.
+func (r *binaryReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if len(r.data) < n {
+		r.err = ErrBinaryTruncated
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *binaryReader) byte() byte {
+	if b := r.next(1); b != nil {
+		return b[0]
+	}
+	return 0
+}
+
+func (r *binaryReader) uvarint64() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.err = ErrBinaryTruncated
+		if n < 0 {
+			r.err = fmt.Errorf("binary message has an invalid varint")
+		}
+		return 0
+	}
+	r.data = r.data[n:]
+	return v
+}
+
+func (r *binaryReader) uvarint() uint32 {
+	v := r.uvarint64()
+	if v > math.MaxUint32 {
+		r.fail(fmt.Errorf("binary message has a uint32 out of range: %d", v))
+		return 0
+	}
+	return uint32(v)
+}
+
+// count reads a length; as every element takes at least one byte, lengths beyond
+// the remaining data are rejected before anything is allocated.
+func (r *binaryReader) count() int {
+	n := r.uvarint64()
+	if n > uint64(len(r.data)) {
+		r.fail(ErrBinaryTruncated)
+		return 0
+	}
+	return int(n)
+}
+
+func (r *binaryReader) varint() int {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		r.err = ErrBinaryTruncated
+		if n < 0 {
+			r.err = fmt.Errorf("binary message has an invalid varint")
+		}
+		return 0
+	}
+	r.data = r.data[n:]
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		r.fail(fmt.Errorf("binary message has an int out of range: %d", v))
+		return 0
+	}
+	return int(v)
+}
+
+func (r *binaryReader) float32() float32 {
+	if b := r.next(4); b != nil {
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	}
+	return 0
+}
+
+func (r *binaryReader) float64() float64 {
+	if b := r.next(8); b != nil {
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return 0
+}
+
+func (r *binaryReader) bool() bool {
+	switch b := r.byte(); b {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		r.fail(fmt.Errorf("binary message has an invalid bool: %d", b))
+		return false
+	}
+}
+
+func (r *binaryReader) string() string {
+	n := r.count()
+	if b := r.next(n); b != nil {
+		return string(b)
+	}
+	return ""
+}
+
+// versions reads a list of versions, nil if it is empty.
+func (r *binaryReader) versions() []ProtocolVersion {
+	n := r.count()
+	if n == 0 {
+		return nil
+	}
+	versions := make([]ProtocolVersion, n)
+	for i := range versions {
+		versions[i] = ProtocolVersion(r.string())
+	}
+	return versions
+}
+
+func (r *binaryReader) vec2() Vec2Snapshot {
+	return Vec2Snapshot{X: r.float64(), Y: r.float64()}
+}
+
+func (r *binaryReader) ship() ShipSnapshot {
+	var s ShipSnapshot
+	s.ID = r.string()
+	s.Destroyed = r.bool()
+	s.Pos = r.vec2()
+	s.Vel = r.vec2()
+	s.Rot = r.float64()
+	s.Energy = r.float32()
+	return s
+}
+
+func (r *binaryReader) snapshot(m *SnapshotMessage) {
+	m.Tick = r.uvarint()
+	flags := r.byte()
+	m.Done = flags&snapshotDone != 0
+	m.Win = flags&snapshotWin != 0
+	m.Paused = flags&snapshotPaused != 0
+	m.Ship = r.ship()
+	if n := r.count(); n > 0 {
+		m.Ships = make([]ShipSnapshot, n)
+		for i := range m.Ships {
+			m.Ships[i] = r.ship()
+		}
+	}
+	m.You = r.string()
+	m.Sun.Pos = r.vec2()
+	m.Sun.Radius = r.float32()
+	m.Pallets = make([]PalletSnapshot, r.count())
+	for i := range m.Pallets {
+		m.Pallets[i].ID = r.uvarint()
+		m.Pallets[i].Pos = r.vec2()
+		m.Pallets[i].Active = r.bool()
+	}
+	if flags&snapshotTimeScale != 0 {
+		m.TimeScale = r.float64()
+	}
+	if flags&snapshotAck != 0 {
+		m.Ack = &InputAckSnapshot{Seq: r.uvarint(), Tick: r.uvarint()}
+	}
+}
+
+func (r *binaryReader) shipDelta(s *ShipDelta) {
+	mask := r.byte()
+	if mask&^(shipDestroyed|shipPos|shipVel|shipRot|shipEnergy) != 0 {
+		r.fail(fmt.Errorf("binary message has an invalid ship field mask: 0x%02x", mask))
+		return
+	}
+	if mask&shipDestroyed != 0 {
+		destroyed := r.bool()
+		s.Destroyed = &destroyed
+	}
+	if mask&shipPos != 0 {
+		pos := r.vec2()
+		s.Pos = &pos
+	}
+	if mask&shipVel != 0 {
+		vel := r.vec2()
+		s.Vel = &vel
+	}
+	if mask&shipRot != 0 {
+		rot := r.float64()
+		s.Rot = &rot
+	}
+	if mask&shipEnergy != 0 {
+		energy := r.float32()
+		s.Energy = &energy
+	}
+}
+
+func (r *binaryReader) delta(m *DeltaSnapshotMessage) {
+	m.Tick = r.uvarint()
+	m.Base = r.uvarint()
+	flags := r.byte()
+	m.Done = flags&snapshotDone != 0
+	m.Win = flags&snapshotWin != 0
+	m.Paused = flags&snapshotPaused != 0
+	if flags&deltaShip != 0 {
+		m.Ship = &ShipDelta{}
+		r.shipDelta(m.Ship)
+	}
+	if n := r.count(); n > 0 {
+		m.Ships = make([]ShipDelta, n)
+		for i := range m.Ships {
+			m.Ships[i].ID = r.string()
+			r.shipDelta(&m.Ships[i])
+		}
+	}
+	if n := r.count(); n > 0 {
+		m.RemovedShips = make([]string, n)
+		for i := range m.RemovedShips {
+			m.RemovedShips[i] = r.string()
+		}
+	}
+	if flags&deltaSun != 0 {
+		m.Sun = &SunSnapshot{Pos: r.vec2(), Radius: r.float32()}
+	}
+	if n := r.count(); n > 0 {
+		m.Pallets = make([]PalletSnapshot, n)
+		for i := range m.Pallets {
+			m.Pallets[i].ID = r.uvarint()
+			m.Pallets[i].Pos = r.vec2()
+			m.Pallets[i].Active = r.bool()
+		}
+	}
+	if n := r.count(); n > 0 {
+		m.RemovedPallets = make([]uint32, n)
+		for i := range m.RemovedPallets {
+			m.RemovedPallets[i] = r.uvarint()
+		}
+	}
+	if flags&snapshotTimeScale != 0 {
+		m.TimeScale = r.float64()
+	}
+	if flags&snapshotAck != 0 {
+		m.Ack = &InputAckSnapshot{Seq: r.uvarint(), Tick: r.uvarint()}
+	}
+}
+
+// fail keeps err as the reader's error unless it already has one.
+func (r *binaryReader) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
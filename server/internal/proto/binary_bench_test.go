@@ -0,0 +1,85 @@
+package proto
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// benchmarkSnapshot returns a two-player snapshot with ten pallets.
+func benchmarkSnapshot() SnapshotMessage {
+	ship := ShipSnapshot{ID: "p1", Pos: Vec2Snapshot{X: 123.456, Y: -78.9}, Vel: Vec2Snapshot{X: 1.5, Y: -0.25}, Rot: 2.0944, Energy: 87.5}
+	snapshot := SnapshotMessage{
+		Type:  "snapshot",
+		Tick:  4321,
+		Ship:  ship,
+		Ships: []ShipSnapshot{ship, {ID: "p2", Pos: Vec2Snapshot{X: -200, Y: 150}, Vel: Vec2Snapshot{X: -0.5, Y: 2}, Rot: 0.5, Energy: 64}},
+		You:   "p1",
+		Sun:   SunSnapshot{Radius: 50},
+		Ack:   &InputAckSnapshot{Seq: 812, Tick: 4320},
+	}
+	for i := 0; i < 10; i++ {
+		snapshot.Pallets = append(snapshot.Pallets, PalletSnapshot{
+			ID:     uint32(i + 1),
+			Pos:    Vec2Snapshot{X: float64(i)*31.7 - 150, Y: float64(i)*-17.3 + 90},
+			Active: i%3 != 0,
+		})
+	}
+	return snapshot
+}
+
+func BenchmarkSnapshotMarshalJSON(b *testing.B) {
+	snapshot := benchmarkSnapshot()
+	b.ReportAllocs()
+	var data []byte
+	for i := 0; i < b.N; i++ {
+		data, _ = json.Marshal(snapshot)
+	}
+	b.ReportMetric(float64(len(data)), "bytes/msg")
+}
+
+func BenchmarkSnapshotMarshalBinary(b *testing.B) {
+	snapshot := benchmarkSnapshot()
+	b.ReportAllocs()
+	var data []byte
+	for i := 0; i < b.N; i++ {
+		data, _ = MarshalBinary(snapshot)
+	}
+	b.ReportMetric(float64(len(data)), "bytes/msg")
+}
+
+func BenchmarkSnapshotUnmarshalJSON(b *testing.B) {
+	data, _ := json.Marshal(benchmarkSnapshot())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var snapshot SnapshotMessage
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSnapshotUnmarshalBinary(b *testing.B) {
+	data, _ := MarshalBinary(benchmarkSnapshot())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSnapshotDeltaBinary(b *testing.B) {
+	base := benchmarkSnapshot()
+	current := benchmarkSnapshot()
+	current.Tick++
+	current.Ship.Pos.X += current.Ship.Vel.X
+	current.Ship.Pos.Y += current.Ship.Vel.Y
+	current.Ships[0] = current.Ship
+	current.Pallets[4].Active = false
+	b.ReportAllocs()
+	var data []byte
+	for i := 0; i < b.N; i++ {
+		data, _ = MarshalBinary(DiffSnapshot(base, current))
+	}
+	b.ReportMetric(float64(len(data)), "bytes/msg")
+}
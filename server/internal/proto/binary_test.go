@@ -0,0 +1,209 @@
+package proto
+
+import (
+	"encoding/hex"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// unhex decodes a hex string, ignoring spaces.
+func unhex(s string) []byte {
+	data, err := hex.DecodeString(strings.ReplaceAll(s, " ", ""))
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	return data
+}
+
+var _ = Describe("Binary Encoding", Label("scope:contract", "loop:g4-proto", "layer:contract", "b:binary-encoding"), func() {
+	Describe("Golden Bytes", func() {
+		It("encodes an InputMessage", func() {
+			data, err := MarshalBinary(InputMessage{Type: "input", Seq: 5, Thrust: 1.0, Turn: -0.5})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex("01 05 0000803f 000000bf 00")))
+		})
+
+		It("encodes an InputsMessage", func() {
+			data, err := MarshalBinary(InputsMessage{Type: "inputs", Inputs: []InputEntry{
+				{Seq: 300, Thrust: 0, Turn: 1.0},
+				{Seq: 301, Thrust: 1.0, Turn: 0},
+			}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex("02 02 ac02 00000000 0000803f ad02 0000803f 00000000")))
+		})
+
+		It("encodes a SnapshotMessage", func() {
+			data, err := MarshalBinary(SnapshotMessage{
+				Type: "snapshot",
+				Tick: 3,
+				Ship: ShipSnapshot{Pos: Vec2Snapshot{X: 1}, Energy: 100},
+				Sun:  SunSnapshot{Radius: 50},
+				Pallets: []PalletSnapshot{
+					{ID: 1, Pos: Vec2Snapshot{Y: 2}, Active: true},
+				},
+				Done: true,
+				Ack:  &InputAckSnapshot{Seq: 7, Tick: 2},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex(
+				"10 03 11" + // tag, tick, flags (done, ack)
+					" 00 00 000000000000f03f 0000000000000000" + // ship: id, destroyed, pos
+					" 0000000000000000 0000000000000000 0000000000000000 0000c842" + // vel, rot, energy
+					" 00 00" + // ships, you
+					" 0000000000000000 0000000000000000 00004842" + // sun
+					" 01 01 0000000000000000 0000000000000040 01" + // pallets
+					" 07 02", // ack
+			)))
+		})
+
+		It("encodes an ErrorMessage", func() {
+			data, err := MarshalBinary(ErrorMessage{Type: "error", Message: "bad"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex("16 03 626164 00 00")))
+		})
+
+		It("encodes an ErrorMessage with a code and versions", func() {
+			data, err := MarshalBinary(ErrorMessage{Type: "error", Message: "no", Code: "x", Versions: []ProtocolVersion{"v1"}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex("16 02 6e6f 01 78 01 02 7631")))
+		})
+
+		It("encodes negative integers as zigzag varints", func() {
+			data, err := MarshalBinary(QueuedMessage{Type: "queued", Rating: -1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex("12 01")))
+		})
+
+		It("decodes golden bytes", func() {
+			msg, err := UnmarshalBinary(unhex("01 05 0000803f 000000bf 00"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(msg).To(Equal(&InputMessage{Type: "input", Seq: 5, Thrust: 1.0, Turn: -0.5}))
+		})
+	})
+
+	Describe("Round Trip", func() {
+		DescribeTable("decodes what it encodes",
+			func(msg any, decoded any) {
+				data, err := MarshalBinary(msg)
+				Expect(err).NotTo(HaveOccurred())
+				got, err := UnmarshalBinary(data)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(got).To(Equal(decoded))
+			},
+			Entry("input", InputMessage{Type: "input", Seq: 1 << 31, Thrust: 0.25, Turn: -1, Tick: 90},
+				&InputMessage{Type: "input", Seq: 1 << 31, Thrust: 0.25, Turn: -1, Tick: 90}),
+			Entry("restart", RestartMessage{Type: "restart"}, &RestartMessage{Type: "restart"}),
+			Entry("session_control", SessionControlMessage{Type: "session_control", Action: "scale", Scale: 2.5},
+				&SessionControlMessage{Type: "session_control", Action: "scale", Scale: 2.5}),
+			Entry("replay_control", ReplayControlMessage{Type: "replay_control", Action: "seek", Tick: 1200},
+				&ReplayControlMessage{Type: "replay_control", Action: "seek", Tick: 1200}),
+			Entry("session", SessionMessage{Type: "session", Token: "abc123", Resumed: true},
+				&SessionMessage{Type: "session", Token: "abc123", Resumed: true}),
+			Entry("match", MatchMessage{Type: "match", Room: "r1", You: "p2", Players: 4},
+				&MatchMessage{Type: "match", Room: "r1", You: "p2", Players: 4}),
+			Entry("replay", ReplayStateMessage{Type: "replay", ID: "x", Tick: 5, StartTick: 1, EndTick: 9, Paused: true, Speed: 0.5},
+				&ReplayStateMessage{Type: "replay", ID: "x", Tick: 5, StartTick: 1, EndTick: 9, Paused: true, Speed: 0.5}),
+			Entry("hello", HelloMessage{Type: "hello", Versions: []ProtocolVersion{"v1", "v2"}},
+				&HelloMessage{Type: "hello", Versions: []ProtocolVersion{"v1", "v2"}}),
+			Entry("welcome", WelcomeMessage{Type: "welcome", Version: "v1"}, &WelcomeMessage{Type: "welcome", Version: "v1"}),
+			Entry("error", ErrorMessage{Type: "error", Message: "no", Code: "unsupported_version", Versions: []ProtocolVersion{"v1"}},
+				&ErrorMessage{Type: "error", Message: "no", Code: "unsupported_version", Versions: []ProtocolVersion{"v1"}}),
+			Entry("warning", WarningMessage{Type: "warning", Code: "input_backlog", Message: "slow", Dropped: 3, Coalesced: 2},
+				&WarningMessage{Type: "warning", Code: "input_backlog", Message: "slow", Dropped: 3, Coalesced: 2}),
+		)
+
+		It("round-trips a multiplayer snapshot", func() {
+			snapshot := SnapshotMessage{
+				Type: "snapshot",
+				Tick: 1000,
+				Ship: ShipSnapshot{ID: "p1", Pos: Vec2Snapshot{X: -12.5, Y: 300}, Vel: Vec2Snapshot{X: 1, Y: -2}, Rot: 3.14, Energy: 42.5},
+				Ships: []ShipSnapshot{
+					{ID: "p1", Pos: Vec2Snapshot{X: -12.5, Y: 300}, Vel: Vec2Snapshot{X: 1, Y: -2}, Rot: 3.14, Energy: 42.5},
+					{ID: "p2", Destroyed: true},
+				},
+				You:       "p1",
+				Sun:       SunSnapshot{Pos: Vec2Snapshot{X: 0, Y: 0}, Radius: 50},
+				Pallets:   []PalletSnapshot{{ID: 1, Pos: Vec2Snapshot{X: 10, Y: 20}, Active: true}, {ID: 2}},
+				Win:       true,
+				Paused:    true,
+				TimeScale: 0.5,
+			}
+			data, err := MarshalBinary(snapshot)
+			Expect(err).NotTo(HaveOccurred())
+			got, err := UnmarshalBinary(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(&snapshot))
+		})
+
+		It("decodes an empty pallet list as empty, not nil", func() {
+			data, err := MarshalBinary(SnapshotMessage{Type: "snapshot", Pallets: []PalletSnapshot{}})
+			Expect(err).NotTo(HaveOccurred())
+			got, err := UnmarshalBinary(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got.(*SnapshotMessage).Pallets).To(BeEmpty())
+			Expect(got.(*SnapshotMessage).Pallets).NotTo(BeNil())
+		})
+	})
+
+	Describe("Errors", func() {
+		It("rejects unsupported types", func() {
+			_, err := MarshalBinary(&InputMessage{Type: "input"})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("rejects empty messages and unknown tags", func() {
+			_, err := UnmarshalBinary(nil)
+			Expect(err).To(HaveOccurred())
+			_, err = UnmarshalBinary([]byte{0x7f})
+			Expect(err).To(MatchError(ContainSubstring("unknown binary message tag")))
+		})
+
+		It("rejects every truncation of a message", func() {
+			data, err := MarshalBinary(InputMessage{Type: "input", Seq: 300, Thrust: 1, Turn: 1, Tick: 2})
+			Expect(err).NotTo(HaveOccurred())
+			for n := 1; n < len(data); n++ {
+				_, err := UnmarshalBinary(data[:n])
+				Expect(err).To(MatchError(ErrBinaryTruncated), "truncated to %d bytes", n)
+			}
+		})
+
+		It("rejects trailing bytes", func() {
+			_, err := UnmarshalBinary(unhex("03 00"))
+			Expect(err).To(MatchError(ContainSubstring("trailing bytes")))
+		})
+
+		It("rejects counts beyond the message length without allocating them", func() {
+			_, err := UnmarshalBinary(unhex("02 ffffffff0f"))
+			Expect(err).To(MatchError(ErrBinaryTruncated))
+		})
+
+		It("rejects invalid bools", func() {
+			_, err := UnmarshalBinary(unhex("11 00 02"))
+			Expect(err).To(MatchError(ContainSubstring("invalid bool")))
+		})
+
+		It("rejects sequence numbers beyond uint32", func() {
+			_, err := UnmarshalBinary(unhex("01 8080808010 00000000 00000000 00"))
+			Expect(err).To(MatchError(ContainSubstring("out of range")))
+		})
+
+		DescribeTable("decodes non-finite inputs that validation rejects",
+			func(thrust, turn, field string) {
+				decoded, err := UnmarshalBinary(unhex("01 05 " + thrust + " " + turn + " 00"))
+				Expect(err).NotTo(HaveOccurred())
+				input := decoded.(*InputMessage)
+				Expect(ValidateInputMessage(input)).To(MatchError(ContainSubstring("invalid " + field)))
+
+				decoded, err = UnmarshalBinary(unhex("02 01 05 " + thrust + " " + turn))
+				Expect(err).NotTo(HaveOccurred())
+				inputs := decoded.(*InputsMessage)
+				Expect(ValidateInputsMessage(inputs)).To(MatchError(ContainSubstring(field + " must be in range")))
+			},
+			Entry("NaN thrust", "0000c07f", "00000000", "thrust"),
+			Entry("+Inf thrust", "0000807f", "00000000", "thrust"),
+			Entry("NaN turn", "0000803f", "0000c07f", "turn"),
+			Entry("+Inf turn", "0000803f", "0000807f", "turn"),
+			Entry("-Inf turn", "0000803f", "000080ff", "turn"),
+		)
+	})
+})
@@ -0,0 +1,209 @@
+package proto
+
+import (
+	"fmt"
+	"sort"
+)
+
+// DiffSnapshot returns current as a delta against base: the fields that differ,
+// ships and pallets that changed, appeared or disappeared (matched by ID), and
+// current's Done, Win, Paused, TimeScale and Ack. ApplyDelta(base, delta) returns
+// current, with pallets that are new in current after those of base.
+func DiffSnapshot(base, current SnapshotMessage) DeltaSnapshotMessage {
+	delta := DeltaSnapshotMessage{
+		Type:      "delta",
+		Tick:      current.Tick,
+		Base:      base.Tick,
+		Done:      current.Done,
+		Win:       current.Win,
+		Paused:    current.Paused,
+		TimeScale: current.TimeScale,
+		Ack:       current.Ack,
+	}
+
+	if ship, changed := diffShip(base.Ship, current.Ship); changed {
+		delta.Ship = &ship
+	}
+
+	baseShips := make(map[string]ShipSnapshot, len(base.Ships))
+	for _, ship := range base.Ships {
+		baseShips[ship.ID] = ship
+	}
+	for _, ship := range current.Ships {
+		old, ok := baseShips[ship.ID]
+		if !ok {
+			old = ShipSnapshot{ID: ship.ID}
+		}
+		delete(baseShips, ship.ID)
+		if shipDelta, changed := diffShip(old, ship); changed || !ok {
+			shipDelta.ID = ship.ID
+			delta.Ships = append(delta.Ships, shipDelta)
+		}
+	}
+	for _, ship := range base.Ships {
+		if _, removed := baseShips[ship.ID]; removed {
+			delta.RemovedShips = append(delta.RemovedShips, ship.ID)
+		}
+	}
+
+	if current.Sun != base.Sun {
+		sun := current.Sun
+		delta.Sun = &sun
+	}
+
+	basePallets := make(map[uint32]PalletSnapshot, len(base.Pallets))
+	for _, pallet := range base.Pallets {
+		basePallets[pallet.ID] = pallet
+	}
+	for _, pallet := range current.Pallets {
+		old, ok := basePallets[pallet.ID]
+		delete(basePallets, pallet.ID)
+		if !ok || old != pallet {
+			delta.Pallets = append(delta.Pallets, pallet)
+		}
+	}
+	for _, pallet := range base.Pallets {
+		if _, removed := basePallets[pallet.ID]; removed {
+			delta.RemovedPallets = append(delta.RemovedPallets, pallet.ID)
+		}
+	}
+
+	return delta
+}
+
+// diffShip returns the fields of current that differ from base, and whether any does.
+// The ID is left empty.
+func diffShip(base, current ShipSnapshot) (ShipDelta, bool) {
+	var delta ShipDelta
+	changed := false
+	if current.Destroyed != base.Destroyed {
+		destroyed := current.Destroyed
+		delta.Destroyed = &destroyed
+		changed = true
+	}
+	if current.Pos != base.Pos {
+		pos := current.Pos
+		delta.Pos = &pos
+		changed = true
+	}
+	if current.Vel != base.Vel {
+		vel := current.Vel
+		delta.Vel = &vel
+		changed = true
+	}
+	if current.Rot != base.Rot {
+		rot := current.Rot
+		delta.Rot = &rot
+		changed = true
+	}
+	if current.Energy != base.Energy {
+		energy := current.Energy
+		delta.Energy = &energy
+		changed = true
+	}
+	return delta, changed
+}
+
+// ApplyDelta returns the snapshot delta encodes against base. Ships are sorted by ID
+// and new pallets follow those of base. Returns an error if delta is not against
+// base's tick, or removes a ship or pallet base does not have; clients then request
+// a keyframe (see KeyframeRequestMessage).
+func ApplyDelta(base SnapshotMessage, delta DeltaSnapshotMessage) (SnapshotMessage, error) {
+	if delta.Base != base.Tick {
+		return SnapshotMessage{}, fmt.Errorf("delta base tick %d does not match snapshot tick %d", delta.Base, base.Tick)
+	}
+
+	snapshot := SnapshotMessage{
+		Type:      "snapshot",
+		Tick:      delta.Tick,
+		Ship:      base.Ship,
+		You:       base.You,
+		Sun:       base.Sun,
+		Done:      delta.Done,
+		Win:       delta.Win,
+		Paused:    delta.Paused,
+		TimeScale: delta.TimeScale,
+		Ack:       delta.Ack,
+	}
+
+	if delta.Ship != nil {
+		applyShip(&snapshot.Ship, *delta.Ship)
+	}
+
+	ships := make(map[string]ShipSnapshot, len(base.Ships)+len(delta.Ships))
+	for _, ship := range base.Ships {
+		ships[ship.ID] = ship
+	}
+	for _, id := range delta.RemovedShips {
+		if _, ok := ships[id]; !ok {
+			return SnapshotMessage{}, fmt.Errorf("delta removes unknown ship %q", id)
+		}
+		delete(ships, id)
+	}
+	for _, shipDelta := range delta.Ships {
+		ship, ok := ships[shipDelta.ID]
+		if !ok {
+			ship = ShipSnapshot{ID: shipDelta.ID}
+		}
+		applyShip(&ship, shipDelta)
+		ships[ship.ID] = ship
+	}
+	for _, ship := range ships {
+		snapshot.Ships = append(snapshot.Ships, ship)
+	}
+	sort.Slice(snapshot.Ships, func(i, j int) bool { return snapshot.Ships[i].ID < snapshot.Ships[j].ID })
+
+	if delta.Sun != nil {
+		snapshot.Sun = *delta.Sun
+	}
+
+	removed := make(map[uint32]bool, len(delta.RemovedPallets))
+	for _, id := range delta.RemovedPallets {
+		removed[id] = true
+	}
+	changed := make(map[uint32]PalletSnapshot, len(delta.Pallets))
+	for _, pallet := range delta.Pallets {
+		changed[pallet.ID] = pallet
+	}
+	snapshot.Pallets = make([]PalletSnapshot, 0, len(base.Pallets)+len(delta.Pallets))
+	for _, pallet := range base.Pallets {
+		if removed[pallet.ID] {
+			delete(removed, pallet.ID)
+			continue
+		}
+		if update, ok := changed[pallet.ID]; ok {
+			pallet = update
+			delete(changed, pallet.ID)
+		}
+		snapshot.Pallets = append(snapshot.Pallets, pallet)
+	}
+	for id := range removed {
+		return SnapshotMessage{}, fmt.Errorf("delta removes unknown pallet %d", id)
+	}
+	for _, pallet := range delta.Pallets {
+		if _, added := changed[pallet.ID]; added {
+			snapshot.Pallets = append(snapshot.Pallets, pallet)
+		}
+	}
+
+	return snapshot, nil
+}
+
+// applyShip sets the fields of ship that delta changed.
+func applyShip(ship *ShipSnapshot, delta ShipDelta) {
+	if delta.Destroyed != nil {
+		ship.Destroyed = *delta.Destroyed
+	}
+	if delta.Pos != nil {
+		ship.Pos = *delta.Pos
+	}
+	if delta.Vel != nil {
+		ship.Vel = *delta.Vel
+	}
+	if delta.Rot != nil {
+		ship.Rot = *delta.Rot
+	}
+	if delta.Energy != nil {
+		ship.Energy = *delta.Energy
+	}
+}
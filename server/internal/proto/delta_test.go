@@ -0,0 +1,207 @@
+package proto
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// deltaTestSnapshot returns a two-player snapshot at tick with three pallets.
+func deltaTestSnapshot(tick uint32) SnapshotMessage {
+	p1 := ShipSnapshot{ID: "p1", Pos: Vec2Snapshot{X: 100, Y: 0}, Vel: Vec2Snapshot{X: 0, Y: 5}, Rot: 1.5, Energy: 80}
+	return SnapshotMessage{
+		Type:  "snapshot",
+		Tick:  tick,
+		Ship:  p1,
+		Ships: []ShipSnapshot{p1, {ID: "p2", Pos: Vec2Snapshot{X: -100, Y: 0}, Energy: 100}},
+		You:   "p1",
+		Sun:   SunSnapshot{Radius: 50},
+		Pallets: []PalletSnapshot{
+			{ID: 1, Pos: Vec2Snapshot{X: 10, Y: 10}, Active: true},
+			{ID: 2, Pos: Vec2Snapshot{X: 20, Y: 20}, Active: true},
+			{ID: 3, Pos: Vec2Snapshot{X: 30, Y: 30}, Active: true},
+		},
+	}
+}
+
+var _ = Describe("Delta Snapshots", Label("scope:contract", "loop:g4-proto", "layer:contract", "b:delta-snapshots"), func() {
+	Describe("DiffSnapshot", func() {
+		It("sends only the tick and flags of an unchanged world", func() {
+			base := deltaTestSnapshot(10)
+			current := deltaTestSnapshot(11)
+			current.Ack = &InputAckSnapshot{Seq: 4, Tick: 10}
+
+			delta := DiffSnapshot(base, current)
+			Expect(delta).To(Equal(DeltaSnapshotMessage{Type: "delta", Tick: 11, Base: 10, Ack: &InputAckSnapshot{Seq: 4, Tick: 10}}))
+
+			data, err := json.Marshal(delta)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"t":"delta","tick":11,"base":10,"done":false,"win":false,"ack":{"seq":4,"tick":10}}`))
+		})
+
+		It("sends only the changed fields of ships", func() {
+			base := deltaTestSnapshot(10)
+			current := deltaTestSnapshot(11)
+			current.Ship.Pos = Vec2Snapshot{X: 100, Y: 0.5}
+			current.Ships[0] = current.Ship
+			current.Ships[1].Energy = 99
+
+			delta := DiffSnapshot(base, current)
+			Expect(delta.Ship).To(Equal(&ShipDelta{Pos: &Vec2Snapshot{X: 100, Y: 0.5}}))
+			energy := float32(99)
+			Expect(delta.Ships).To(Equal([]ShipDelta{
+				{ID: "p1", Pos: &Vec2Snapshot{X: 100, Y: 0.5}},
+				{ID: "p2", Energy: &energy},
+			}))
+			Expect(delta.Sun).To(BeNil())
+			Expect(delta.Pallets).To(BeEmpty())
+		})
+
+		It("lists changed, new and removed pallets and ships", func() {
+			base := deltaTestSnapshot(10)
+			current := deltaTestSnapshot(11)
+			current.Pallets = []PalletSnapshot{
+				{ID: 1, Pos: Vec2Snapshot{X: 10, Y: 10}, Active: false},
+				{ID: 2, Pos: Vec2Snapshot{X: 20, Y: 20}, Active: true},
+				{ID: 4, Pos: Vec2Snapshot{X: 40, Y: 40}, Active: true},
+			}
+			current.Ships = current.Ships[:1]
+			current.Sun.Radius = 60
+
+			delta := DiffSnapshot(base, current)
+			Expect(delta.Pallets).To(Equal([]PalletSnapshot{
+				{ID: 1, Pos: Vec2Snapshot{X: 10, Y: 10}, Active: false},
+				{ID: 4, Pos: Vec2Snapshot{X: 40, Y: 40}, Active: true},
+			}))
+			Expect(delta.RemovedPallets).To(Equal([]uint32{3}))
+			Expect(delta.RemovedShips).To(Equal([]string{"p2"}))
+			Expect(delta.Sun).To(Equal(&SunSnapshot{Radius: 60}))
+		})
+	})
+
+	Describe("ApplyDelta", func() {
+		It("reconstructs the current snapshot", func() {
+			base := deltaTestSnapshot(10)
+			current := deltaTestSnapshot(12)
+			current.Ship.Energy = 75
+			current.Ship.Rot = -0.5
+			current.Ships[0] = current.Ship
+			current.Ships = append(current.Ships, ShipSnapshot{ID: "p3", Pos: Vec2Snapshot{X: 5}, Energy: 100})
+			current.Pallets[2].Active = false
+			current.Pallets = append(current.Pallets[1:], PalletSnapshot{ID: 9, Active: true})
+			current.Paused = true
+			current.TimeScale = 2
+
+			got, err := ApplyDelta(base, DiffSnapshot(base, current))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(current))
+		})
+
+		It("reconstructs a snapshot through the binary encoding", func() {
+			base := deltaTestSnapshot(10)
+			current := deltaTestSnapshot(11)
+			current.Ships[1].Destroyed = true
+			current.Ships = current.Ships[1:]
+			current.Done = true
+
+			data, err := MarshalBinary(DiffSnapshot(base, current))
+			Expect(err).NotTo(HaveOccurred())
+			msg, err := UnmarshalBinary(data)
+			Expect(err).NotTo(HaveOccurred())
+			delta := msg.(*DeltaSnapshotMessage)
+			Expect(ValidateDeltaSnapshotMessage(delta)).To(Succeed())
+
+			got, err := ApplyDelta(base, *delta)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(current))
+		})
+
+		It("rejects a delta against another tick", func() {
+			base := deltaTestSnapshot(10)
+			delta := DiffSnapshot(deltaTestSnapshot(9), deltaTestSnapshot(11))
+			_, err := ApplyDelta(base, delta)
+			Expect(err).To(MatchError(ContainSubstring("does not match")))
+		})
+
+		It("rejects removals of ships and pallets the base does not have", func() {
+			base := deltaTestSnapshot(10)
+			_, err := ApplyDelta(base, DeltaSnapshotMessage{Type: "delta", Tick: 11, Base: 10, RemovedShips: []string{"p9"}})
+			Expect(err).To(MatchError(ContainSubstring("unknown ship")))
+			_, err = ApplyDelta(base, DeltaSnapshotMessage{Type: "delta", Tick: 11, Base: 10, RemovedPallets: []uint32{9}})
+			Expect(err).To(MatchError(ContainSubstring("unknown pallet")))
+		})
+	})
+
+	Describe("Binary Encoding", func() {
+		It("encodes a delta with golden bytes", func() {
+			rot := 0.0
+			data, err := MarshalBinary(DeltaSnapshotMessage{
+				Type:           "delta",
+				Tick:           11,
+				Base:           10,
+				Ship:           &ShipDelta{Rot: &rot},
+				RemovedPallets: []uint32{3},
+				Win:            true,
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex(
+				"17 0b 0a 22" + // tag, tick, base, flags (win, ship)
+					" 08 0000000000000000" + // ship: mask (rot), rot
+					" 00 00 00" + // ships, removed ships, pallets
+					" 01 03", // removed pallets
+			)))
+		})
+
+		It("encodes snapshot acks and keyframe requests", func() {
+			data, err := MarshalBinary(SnapshotAckMessage{Type: "snapshot_ack", Tick: 300})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex("06 ac02")))
+			data, err = MarshalBinary(KeyframeRequestMessage{Type: "keyframe_request"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(data).To(Equal(unhex("07")))
+		})
+
+		It("rejects unknown ship fields", func() {
+			_, err := UnmarshalBinary(unhex("17 0b 0a 20 40"))
+			Expect(err).To(MatchError(ContainSubstring("field mask")))
+		})
+
+		It("is smaller than a full snapshot of a mostly unchanged world", func() {
+			base := deltaTestSnapshot(10)
+			current := deltaTestSnapshot(11)
+			current.Ship.Pos.Y = 0.5
+			current.Ships[0] = current.Ship
+
+			full, err := MarshalBinary(current)
+			Expect(err).NotTo(HaveOccurred())
+			delta, err := MarshalBinary(DiffSnapshot(base, current))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(delta)).To(BeNumerically("<", len(full)/4))
+		})
+	})
+
+	Describe("Validation", func() {
+		It("accepts snapshot acks and keyframe requests", func() {
+			Expect(ValidateSnapshotAckMessage(&SnapshotAckMessage{Type: "snapshot_ack", Tick: 0})).To(Succeed())
+			Expect(ValidateKeyframeRequestMessage(&KeyframeRequestMessage{Type: "keyframe_request"})).To(Succeed())
+			Expect(ValidateSnapshotAckMessage(&SnapshotAckMessage{Type: "ack"})).NotTo(Succeed())
+			Expect(ValidateKeyframeRequestMessage(nil)).NotTo(Succeed())
+		})
+
+		It("accepts a delta against the same tick, as sent while paused", func() {
+			Expect(ValidateDeltaSnapshotMessage(&DeltaSnapshotMessage{Type: "delta", Tick: 10, Base: 10, Paused: true})).To(Succeed())
+		})
+
+		It("rejects a base after the tick", func() {
+			Expect(ValidateDeltaSnapshotMessage(&DeltaSnapshotMessage{Type: "delta", Tick: 10, Base: 11})).To(MatchError(ContainSubstring("invalid base")))
+		})
+
+		It("rejects ships without an ID and invalid fields", func() {
+			Expect(ValidateDeltaSnapshotMessage(&DeltaSnapshotMessage{Type: "delta", Ships: []ShipDelta{{}}})).To(MatchError(ContainSubstring("id must not be empty")))
+			energy := float32(-1)
+			Expect(ValidateDeltaSnapshotMessage(&DeltaSnapshotMessage{Type: "delta", Ship: &ShipDelta{Energy: &energy}})).To(MatchError(ContainSubstring("invalid energy")))
+			Expect(ValidateDeltaSnapshotMessage(&DeltaSnapshotMessage{Type: "delta", Sun: &SunSnapshot{}})).To(MatchError(ContainSubstring("invalid sun")))
+		})
+	})
+})
@@ -1,38 +1,226 @@
 package proto
 
 // InputMessage represents a client input command message.
-// Client → Server message format: {"t":"input","seq":u32,"thrust":0..1,"turn":-1..1}
+// Client → Server message format: {"t":"input","seq":u32,"thrust":0..1,"turn":-1..1,"tick":u32}
+// where tick is optional (see session.Session.EnqueuePlayerCommandAt).
 type InputMessage struct {
-	Type   string  `json:"t"`     // Message type: "input"
-	Seq    uint32  `json:"seq"`   // Sequence number
+	Type   string  `json:"t"`              // Message type: "input"
+	Seq    uint32  `json:"seq"`            // Sequence number
+	Thrust float32 `json:"thrust"`         // Thrust input [0.0, 1.0]
+	Turn   float32 `json:"turn"`           // Turn input [-1.0, 1.0]
+	Tick   uint32  `json:"tick,omitempty"` // Tick the input is intended for, 0 (omitted) if untargeted
+}
+
+// MaxInputsPerMessage is the largest number of inputs an InputsMessage may carry.
+const MaxInputsPerMessage = 8
+
+// InputsMessage carries the client's newest input and redundant copies of the inputs
+// before it, so that inputs of lost or delayed messages still reach the server.
+// Client → Server message format: {"t":"inputs","inputs":[{"seq":u32,"thrust":0..1,"turn":-1..1},...]}
+// Inputs are oldest first with increasing sequence numbers; the last one is the newest.
+type InputsMessage struct {
+	Type   string       `json:"t"`      // Message type: "inputs"
+	Inputs []InputEntry `json:"inputs"` // 1 to MaxInputsPerMessage inputs, oldest first
+}
+
+// InputEntry is one input of an InputsMessage.
+type InputEntry struct {
+	Seq    uint32  `json:"seq"`    // Sequence number
 	Thrust float32 `json:"thrust"` // Thrust input [0.0, 1.0]
 	Turn   float32 `json:"turn"`   // Turn input [-1.0, 1.0]
 }
 
+// MaxHelloVersions is the maximum number of versions a HelloMessage may announce.
+const MaxHelloVersions = 8
+
+// HelloMessage announces the protocol versions a client supports. It is optional
+// and must be the first message; the server answers with a WelcomeMessage, or an
+// ErrorMessage with code "unsupported_version" before closing the connection.
+// Clients that do not send it use ProtocolVersionV1.
+// Client → Server message format: {"t":"hello","versions":["v1",...]}
+type HelloMessage struct {
+	Type     string            `json:"t"`        // Message type: "hello"
+	Versions []ProtocolVersion `json:"versions"` // Supported versions, 1 to MaxHelloVersions
+}
+
 // RestartMessage represents a client restart request message.
 // Client → Server message format: {"t":"restart"}
 type RestartMessage struct {
 	Type string `json:"t"` // Message type: "restart"
 }
 
+// SessionControlMessage pauses, resumes or changes the time scale of the sender's session.
+// Client → Server message format: {"t":"session_control","action":"pause"|"resume"|"scale","scale":f64}
+// Scale is only read by the "scale" action; the session limits its range.
+type SessionControlMessage struct {
+	Type   string  `json:"t"`               // Message type: "session_control"
+	Action string  `json:"action"`          // "pause", "resume" or "scale"
+	Scale  float64 `json:"scale,omitempty"` // New time scale (multiple of real time)
+}
+
+// SnapshotAckMessage acknowledges the snapshot of a tick; the server may send later
+// snapshots as deltas against it (see DeltaSnapshotMessage).
+// Client → Server message format: {"t":"snapshot_ack","tick":u32}
+// Tick is the tick of a full snapshot, or of a delta the client applied.
+type SnapshotAckMessage struct {
+	Type string `json:"t"`    // Message type: "snapshot_ack"
+	Tick uint32 `json:"tick"` // Tick of the acknowledged snapshot
+}
+
+// KeyframeRequestMessage asks the server for a full snapshot, e.g. after receiving a
+// delta against a snapshot the client does not have. Deltas resume once the client
+// acknowledges a snapshot again.
+// Client → Server message format: {"t":"keyframe_request"}
+type KeyframeRequestMessage struct {
+	Type string `json:"t"` // Message type: "keyframe_request"
+}
+
 // SnapshotMessage represents a server state snapshot message.
-// Server → Client message format with tick, ship, sun, pallets, done, win
+// Server → Client message format with tick, ship, ships, you, sun, pallets, done, win,
+// paused, time_scale and ack.
+// Ship is the receiving player's own ship, kept for single-player clients;
+// Ships lists every ship in the world and You is the receiver's player ID.
 type SnapshotMessage struct {
-	Type    string          `json:"t"`      // Message type: "snapshot"
-	Tick    uint32          `json:"tick"`   // Current simulation tick
-	Ship    ShipSnapshot    `json:"ship"`   // Ship state
-	Sun     SunSnapshot     `json:"sun"`    // Sun state
-	Pallets []PalletSnapshot `json:"pallets"` // List of pallets
-	Done    bool            `json:"done"`   // Whether the game is finished
-	Win     bool            `json:"win"`    // Whether the player won (only valid if Done is true)
+	Type      string            `json:"t"`                    // Message type: "snapshot"
+	Tick      uint32            `json:"tick"`                 // Current simulation tick
+	Ship      ShipSnapshot      `json:"ship"`                 // Receiving player's ship state
+	Ships     []ShipSnapshot    `json:"ships,omitempty"`      // All ships, sorted by ID
+	You       string            `json:"you,omitempty"`        // Receiving player's ID (empty for observers)
+	Sun       SunSnapshot       `json:"sun"`                  // Sun state
+	Pallets   []PalletSnapshot  `json:"pallets"`              // List of pallets
+	Done      bool              `json:"done"`                 // Whether the game is finished
+	Win       bool              `json:"win"`                  // Whether the player won (only valid if Done is true)
+	Paused    bool              `json:"paused,omitempty"`     // Whether the session is paused
+	TimeScale float64           `json:"time_scale,omitempty"` // Simulation speed relative to real time, omitted at 1
+	Ack       *InputAckSnapshot `json:"ack,omitempty"`        // Receiving player's last applied input, omitted before the first
+}
+
+// DeltaSnapshotMessage is a snapshot encoded as the changes from an earlier snapshot
+// the client acknowledged, its base (see DiffSnapshot and ApplyDelta).
+// Server → Client message format with tick, base, ship, ships, removed_ships, sun,
+// pallets, removed_pallets, done, win, paused, time_scale and ack.
+// Done, Win, Paused, TimeScale and Ack are sent as in SnapshotMessage; the other
+// fields only list what changed, and You is the base's.
+type DeltaSnapshotMessage struct {
+	Type           string            `json:"t"`                         // Message type: "delta"
+	Tick           uint32            `json:"tick"`                      // Current simulation tick
+	Base           uint32            `json:"base"`                      // Tick of the snapshot the delta applies to
+	Ship           *ShipDelta        `json:"ship,omitempty"`            // Changed fields of the receiving player's ship, omitted if none changed
+	Ships          []ShipDelta       `json:"ships,omitempty"`           // Changed fields of ships, by ID; ships not in the base start from zero values
+	RemovedShips   []string          `json:"removed_ships,omitempty"`   // IDs of ships no longer in the world
+	Sun            *SunSnapshot      `json:"sun,omitempty"`             // Sun state, omitted if unchanged
+	Pallets        []PalletSnapshot  `json:"pallets,omitempty"`         // Changed or new pallets
+	RemovedPallets []uint32          `json:"removed_pallets,omitempty"` // IDs of pallets no longer in the world
+	Done           bool              `json:"done"`                      // Whether the game is finished
+	Win            bool              `json:"win"`                       // Whether the player won (only valid if Done is true)
+	Paused         bool              `json:"paused,omitempty"`          // Whether the session is paused
+	TimeScale      float64           `json:"time_scale,omitempty"`      // Simulation speed relative to real time, omitted at 1
+	Ack            *InputAckSnapshot `json:"ack,omitempty"`             // Receiving player's last applied input, omitted before the first
+}
+
+// WelcomeMessage answers a HelloMessage with the version the connection uses.
+// Server → Client message format: {"t":"welcome","version":"v1"}
+type WelcomeMessage struct {
+	Type    string          `json:"t"`       // Message type: "welcome"
+	Version ProtocolVersion `json:"version"` // Negotiated version (see NegotiateVersion)
+}
+
+// SessionMessage is the first message on a /ws connection with a private session.
+// Server → Client message format: {"t":"session","token":string,"resumed":bool}
+// Reconnecting with the token within the grace period resumes the session.
+type SessionMessage struct {
+	Type    string `json:"t"`       // Message type: "session"
+	Token   string `json:"token"`   // Resume token of the session
+	Resumed bool   `json:"resumed"` // Whether the connection resumed an existing session
+}
+
+// QueuedMessage tells a player that they are waiting in the matchmaking queue.
+// Server → Client message format: {"t":"queued","rating":i32}
+type QueuedMessage struct {
+	Type   string `json:"t"`      // Message type: "queued"
+	Rating int    `json:"rating"` // Rating the player is matched by
+}
+
+// MatchMessage hands a matched player their game assignment.
+// Server → Client message format: {"t":"match","room":string,"you":string,"players":i32}
+// Snapshots of the room follow on the same connection.
+type MatchMessage struct {
+	Type    string `json:"t"`       // Message type: "match"
+	Room    string `json:"room"`    // ID of the room created for the match
+	You     string `json:"you"`     // Player ID assigned to the receiver in the room
+	Players int    `json:"players"` // Number of players in the match
+}
+
+// Replay playback speed limits (multiples of real time).
+const (
+	MinReplaySpeed = 0.25
+	MaxReplaySpeed = 16.0
+)
+
+// ReplayControlMessage controls the playback of a replay connection.
+// Client → Server message format: {"t":"replay_control","action":"play"|"pause"|"speed"|"seek","speed":f64,"tick":u32}
+// Speed is only read by the "speed" action, tick only by "seek".
+type ReplayControlMessage struct {
+	Type   string  `json:"t"`               // Message type: "replay_control"
+	Action string  `json:"action"`          // "play", "pause", "speed" or "seek"
+	Speed  float64 `json:"speed,omitempty"` // New playback speed, in [MinReplaySpeed, MaxReplaySpeed]
+	Tick   uint32  `json:"tick,omitempty"`  // Tick to seek to (clamped to the replay)
+}
+
+// ReplayStateMessage describes the playback state of a replay connection.
+// Server → Client message format: {"t":"replay","id":string,"tick":u32,"start_tick":u32,"end_tick":u32,"paused":bool,"speed":f64}
+// It is sent when the connection opens, after every control message and when playback reaches the end.
+type ReplayStateMessage struct {
+	Type      string  `json:"t"`          // Message type: "replay"
+	ID        string  `json:"id"`         // Replay ID
+	Tick      uint32  `json:"tick"`       // Current tick
+	StartTick uint32  `json:"start_tick"` // First tick of the replay
+	EndTick   uint32  `json:"end_tick"`   // Last tick of the replay
+	Paused    bool    `json:"paused"`     // Whether playback is paused
+	Speed     float64 `json:"speed"`      // Playback speed
+}
+
+// WarningMessage tells a player about a recoverable problem with their connection.
+// Server → Client message format: {"t":"warning","code":string,"message":string,"dropped":i32,"coalesced":i32}
+// Code "input_backlog" reports inputs the server dropped or merged to keep input latency bounded.
+type WarningMessage struct {
+	Type      string `json:"t"`                   // Message type: "warning"
+	Code      string `json:"code"`                // Machine-readable warning code, e.g. "input_backlog"
+	Message   string `json:"message"`             // Human-readable description
+	Dropped   int    `json:"dropped,omitempty"`   // Inputs discarded since the last warning
+	Coalesced int    `json:"coalesced,omitempty"` // Inputs merged into others since the last warning
+}
+
+// ErrorMessage reports a message the server rejected or could not handle.
+// Server → Client message format: {"t":"error","message":string,"code":string,"versions":[string]}
+// Code "unsupported_version" rejects a HelloMessage; Versions then lists the
+// versions the server supports.
+type ErrorMessage struct {
+	Type     string            `json:"t"`                  // Message type: "error"
+	Message  string            `json:"message"`            // Human-readable description
+	Code     string            `json:"code,omitempty"`     // Machine-readable error code, omitted for errors without one
+	Versions []ProtocolVersion `json:"versions,omitempty"` // Versions the server supports, for "unsupported_version"
 }
 
 // ShipSnapshot represents ship state in a snapshot.
 type ShipSnapshot struct {
-	Pos    Vec2Snapshot `json:"pos"`    // Position
-	Vel    Vec2Snapshot `json:"vel"`    // Velocity
-	Rot    float64      `json:"rot"`    // Rotation angle in radians
-	Energy float32      `json:"energy"`  // Current energy level
+	ID        string       `json:"id,omitempty"`        // Owning player ID
+	Destroyed bool         `json:"destroyed,omitempty"` // Whether the ship has hit the sun
+	Pos       Vec2Snapshot `json:"pos"`                 // Position
+	Vel       Vec2Snapshot `json:"vel"`                 // Velocity
+	Rot       float64      `json:"rot"`                 // Rotation angle in radians
+	Energy    float32      `json:"energy"`              // Current energy level
+}
+
+// ShipDelta holds the fields of a ship that changed since the base snapshot;
+// unchanged fields are nil.
+type ShipDelta struct {
+	ID        string        `json:"id,omitempty"`        // Owning player ID
+	Destroyed *bool         `json:"destroyed,omitempty"` // Whether the ship has hit the sun
+	Pos       *Vec2Snapshot `json:"pos,omitempty"`       // Position
+	Vel       *Vec2Snapshot `json:"vel,omitempty"`       // Velocity
+	Rot       *float64      `json:"rot,omitempty"`       // Rotation angle in radians
+	Energy    *float32      `json:"energy,omitempty"`    // Current energy level
 }
 
 // SunSnapshot represents sun state in a snapshot.
@@ -48,9 +236,16 @@ type PalletSnapshot struct {
 	Active bool         `json:"active"` // Whether the pallet is active/collectible
 }
 
+// InputAckSnapshot acknowledges the last input the server applied for the receiving
+// player. Clients predicting ahead replay their inputs with a higher sequence number
+// on top of the snapshot.
+type InputAckSnapshot struct {
+	Seq  uint32 `json:"seq"`  // Sequence number of the input
+	Tick uint32 `json:"tick"` // Tick the input was applied at; snapshots from tick+1 on include it
+}
+
 // Vec2Snapshot represents a 2D vector in a snapshot.
 type Vec2Snapshot struct {
 	X float64 `json:"x"` // X coordinate
 	Y float64 `json:"y"` // Y coordinate
 }
-
@@ -61,6 +61,16 @@ var _ = Describe("Protocol Messages", Label("scope:contract", "loop:g4-proto", "
 			Expect(roundTripped).To(Equal(original))
 		})
 
+		It("carries the intended tick only when set", func() {
+			data, err := json.Marshal(InputMessage{Type: "input", Seq: 7, Thrust: 1.0, Tick: 120})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"t":"input","seq":7,"thrust":1,"turn":0,"tick":120}`))
+
+			var msg InputMessage
+			Expect(json.Unmarshal([]byte(`{"t":"input","seq":7,"thrust":1,"turn":0}`), &msg)).To(Succeed())
+			Expect(msg.Tick).To(Equal(uint32(0)))
+		})
+
 		It("handles edge case values", func() {
 			msg := InputMessage{
 				Type:   "input",
@@ -117,6 +127,65 @@ var _ = Describe("Protocol Messages", Label("scope:contract", "loop:g4-proto", "
 		})
 	})
 
+	Describe("SessionControlMessage", func() {
+		It("deserializes from the documented format", func() {
+			var msg SessionControlMessage
+			Expect(json.Unmarshal([]byte(`{"t":"session_control","action":"scale","scale":0.5}`), &msg)).To(Succeed())
+			Expect(msg).To(Equal(SessionControlMessage{Type: "session_control", Action: "scale", Scale: 0.5}))
+		})
+
+		It("reports the paused state and time scale in snapshots, omitted when running at real time", func() {
+			data, err := json.Marshal(SnapshotMessage{Type: "snapshot", Paused: true, TimeScale: 0.5})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"paused":true,"time_scale":0.5`))
+
+			data, err = json.Marshal(SnapshotMessage{Type: "snapshot"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("paused"))
+			Expect(string(data)).NotTo(ContainSubstring("time_scale"))
+		})
+
+		It("acknowledges the last applied input in snapshots, omitted before the first", func() {
+			data, err := json.Marshal(SnapshotMessage{Type: "snapshot", Ack: &InputAckSnapshot{Seq: 12, Tick: 40}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"ack":{"seq":12,"tick":40}`))
+
+			data, err = json.Marshal(SnapshotMessage{Type: "snapshot"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("ack"))
+		})
+	})
+
+	Describe("SessionMessage", func() {
+		It("serializes to the documented format", func() {
+			data, err := json.Marshal(SessionMessage{Type: "session", Token: "9f86d081884c7d65", Resumed: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"t":"session","token":"9f86d081884c7d65","resumed":true}`))
+		})
+	})
+
+	Describe("Matchmaking Messages", func() {
+		It("serializes QueuedMessage to the documented format", func() {
+			data, err := json.Marshal(QueuedMessage{Type: "queued", Rating: 1500})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"t":"queued","rating":1500}`))
+		})
+
+		It("serializes MatchMessage to the documented format", func() {
+			data, err := json.Marshal(MatchMessage{Type: "match", Room: "0a1b2c3d", You: "p2", Players: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"t":"match","room":"0a1b2c3d","you":"p2","players":2}`))
+		})
+	})
+
+	Describe("Warning Messages", func() {
+		It("serializes WarningMessage to the documented format", func() {
+			data, err := json.Marshal(WarningMessage{Type: "warning", Code: "input_backlog", Message: "inputs merged", Coalesced: 4})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"t":"warning","code":"input_backlog","message":"inputs merged","coalesced":4}`))
+		})
+	})
+
 	Describe("SnapshotMessage", func() {
 		It("serializes to JSON matching TDD spec format", func() {
 			msg := SnapshotMessage{
@@ -380,6 +449,46 @@ var _ = Describe("Protocol Messages", Label("scope:contract", "loop:g4-proto", "
 			})
 		})
 
+		Describe("ValidateInputsMessage", func() {
+			It("accepts up to MaxInputsPerMessage inputs with increasing sequence numbers", func() {
+				msg := &InputsMessage{Type: "inputs", Inputs: []InputEntry{{Seq: 4, Thrust: 1.0}, {Seq: 5, Turn: -1.0}}}
+				Expect(ValidateInputsMessage(msg)).To(Succeed())
+			})
+
+			It("rejects empty and oversized messages", func() {
+				msg := &InputsMessage{Type: "inputs"}
+				Expect(ValidateInputsMessage(msg)).To(MatchError(ContainSubstring("must carry 1 to 8 inputs")))
+
+				for seq := uint32(1); seq <= MaxInputsPerMessage+1; seq++ {
+					msg.Inputs = append(msg.Inputs, InputEntry{Seq: seq})
+				}
+				Expect(ValidateInputsMessage(msg)).To(MatchError(ContainSubstring("got 9")))
+			})
+
+			It("rejects sequence numbers out of order and invalid inputs", func() {
+				msg := &InputsMessage{Type: "inputs", Inputs: []InputEntry{{Seq: 5}, {Seq: 5}}}
+				Expect(ValidateInputsMessage(msg)).To(MatchError(ContainSubstring("index 1: seq must be greater than the previous one")))
+
+				msg.Inputs = []InputEntry{{Seq: 0}}
+				Expect(ValidateInputsMessage(msg)).To(MatchError(ContainSubstring("seq must be greater than 0")))
+
+				msg.Inputs = []InputEntry{{Seq: 1}, {Seq: 2, Thrust: 1.5}}
+				Expect(ValidateInputsMessage(msg)).To(MatchError(ContainSubstring("index 1: thrust")))
+
+				msg.Inputs = []InputEntry{{Seq: 1, Turn: -2}}
+				Expect(ValidateInputsMessage(msg)).To(MatchError(ContainSubstring("index 0: turn")))
+
+				msg = &InputsMessage{Type: "input", Inputs: []InputEntry{{Seq: 1}}}
+				Expect(ValidateInputsMessage(msg)).To(MatchError(ContainSubstring("type")))
+			})
+
+			It("deserializes from the documented format", func() {
+				var msg InputsMessage
+				Expect(json.Unmarshal([]byte(`{"t":"inputs","inputs":[{"seq":1,"thrust":0.5,"turn":0},{"seq":2,"thrust":1,"turn":-0.5}]}`), &msg)).To(Succeed())
+				Expect(msg.Inputs).To(Equal([]InputEntry{{Seq: 1, Thrust: 0.5}, {Seq: 2, Thrust: 1, Turn: -0.5}}))
+			})
+		})
+
 		Describe("ValidateRestartMessage", func() {
 			It("accepts valid messages", func() {
 				msg := &RestartMessage{Type: "restart"}
@@ -395,6 +504,113 @@ var _ = Describe("Protocol Messages", Label("scope:contract", "loop:g4-proto", "
 			})
 		})
 
+		Describe("ValidateSessionMessage", func() {
+			It("accepts valid messages", func() {
+				Expect(ValidateSessionMessage(&SessionMessage{Type: "session", Token: "9f86d081884c7d65"})).To(Succeed())
+			})
+
+			It("rejects invalid type and empty tokens", func() {
+				Expect(ValidateSessionMessage(&SessionMessage{Type: "snapshot", Token: "9f86d081884c7d65"})).To(MatchError(ContainSubstring("type")))
+				Expect(ValidateSessionMessage(&SessionMessage{Type: "session"})).To(MatchError(ContainSubstring("token")))
+			})
+		})
+
+		Describe("ValidateQueuedMessage", func() {
+			It("accepts valid messages", func() {
+				Expect(ValidateQueuedMessage(&QueuedMessage{Type: "queued", Rating: 1500})).To(Succeed())
+			})
+
+			It("rejects invalid type", func() {
+				err := ValidateQueuedMessage(&QueuedMessage{Type: "match"})
+				Expect(err).To(MatchError(ContainSubstring("type")))
+			})
+		})
+
+		Describe("ValidateMatchMessage", func() {
+			It("accepts valid messages", func() {
+				msg := &MatchMessage{Type: "match", Room: "0a1b2c3d", You: "p1", Players: 2}
+				Expect(ValidateMatchMessage(msg)).To(Succeed())
+			})
+
+			It("rejects missing room, player and player count", func() {
+				msg := &MatchMessage{Type: "match", You: "p1", Players: 2}
+				Expect(ValidateMatchMessage(msg)).To(MatchError(ContainSubstring("room")))
+
+				msg = &MatchMessage{Type: "match", Room: "0a1b2c3d", Players: 2}
+				Expect(ValidateMatchMessage(msg)).To(MatchError(ContainSubstring("you")))
+
+				msg = &MatchMessage{Type: "match", Room: "0a1b2c3d", You: "p1"}
+				Expect(ValidateMatchMessage(msg)).To(MatchError(ContainSubstring("players")))
+			})
+		})
+
+		Describe("ValidateReplayControlMessage", func() {
+			It("accepts every action", func() {
+				for _, action := range []string{"play", "pause", "seek"} {
+					Expect(ValidateReplayControlMessage(&ReplayControlMessage{Type: "replay_control", Action: action})).To(Succeed())
+				}
+				msg := &ReplayControlMessage{Type: "replay_control", Action: "speed", Speed: 2.0}
+				Expect(ValidateReplayControlMessage(msg)).To(Succeed())
+			})
+
+			It("rejects unknown actions and speeds out of range", func() {
+				msg := &ReplayControlMessage{Type: "replay_control", Action: "rewind"}
+				Expect(ValidateReplayControlMessage(msg)).To(MatchError(ContainSubstring("action")))
+
+				for _, speed := range []float64{0, MinReplaySpeed / 2, MaxReplaySpeed * 2} {
+					msg = &ReplayControlMessage{Type: "replay_control", Action: "speed", Speed: speed}
+					Expect(ValidateReplayControlMessage(msg)).To(MatchError(ContainSubstring("speed")))
+				}
+			})
+		})
+
+		Describe("ValidateReplayStateMessage", func() {
+			It("accepts valid messages", func() {
+				msg := &ReplayStateMessage{Type: "replay", ID: "0a1b2c3d4e5f6a7b", Tick: 30, EndTick: 90, Speed: 1.0}
+				Expect(ValidateReplayStateMessage(msg)).To(Succeed())
+			})
+
+			It("rejects missing IDs, ticks outside the replay and invalid speeds", func() {
+				msg := &ReplayStateMessage{Type: "replay", Tick: 30, EndTick: 90, Speed: 1.0}
+				Expect(ValidateReplayStateMessage(msg)).To(MatchError(ContainSubstring("id")))
+
+				msg = &ReplayStateMessage{Type: "replay", ID: "abc", Tick: 5, StartTick: 10, EndTick: 90, Speed: 1.0}
+				Expect(ValidateReplayStateMessage(msg)).To(MatchError(ContainSubstring("tick")))
+
+				msg = &ReplayStateMessage{Type: "replay", ID: "abc", Tick: 30, EndTick: 90}
+				Expect(ValidateReplayStateMessage(msg)).To(MatchError(ContainSubstring("speed")))
+			})
+		})
+
+		Describe("ValidateWarningMessage", func() {
+			It("accepts valid messages", func() {
+				msg := &WarningMessage{Type: "warning", Code: "input_backlog", Message: "inputs dropped", Dropped: 3}
+				Expect(ValidateWarningMessage(msg)).To(Succeed())
+			})
+
+			It("rejects missing codes and negative counts", func() {
+				Expect(ValidateWarningMessage(&WarningMessage{Type: "warning"})).To(MatchError(ContainSubstring("code")))
+				msg := &WarningMessage{Type: "warning", Code: "input_backlog", Coalesced: -1}
+				Expect(ValidateWarningMessage(msg)).To(MatchError(ContainSubstring("counts")))
+			})
+		})
+
+		Describe("ValidateSessionControlMessage", func() {
+			It("accepts valid messages", func() {
+				for _, action := range []string{"pause", "resume"} {
+					Expect(ValidateSessionControlMessage(&SessionControlMessage{Type: "session_control", Action: action})).To(Succeed())
+				}
+				Expect(ValidateSessionControlMessage(&SessionControlMessage{Type: "session_control", Action: "scale", Scale: 2})).To(Succeed())
+			})
+
+			It("rejects unknown actions and non-positive scales", func() {
+				err := ValidateSessionControlMessage(&SessionControlMessage{Type: "session_control", Action: "stop"})
+				Expect(err).To(MatchError(ContainSubstring("action")))
+				err = ValidateSessionControlMessage(&SessionControlMessage{Type: "session_control", Action: "scale"})
+				Expect(err).To(MatchError(ContainSubstring("scale")))
+			})
+		})
+
 		Describe("ValidateSnapshotMessage", func() {
 			It("accepts valid messages", func() {
 				msg := &SnapshotMessage{
@@ -445,6 +661,36 @@ var _ = Describe("Protocol Messages", Label("scope:contract", "loop:g4-proto", "
 				Expect(err).To(HaveOccurred())
 			})
 
+			It("validates the ships array", func() {
+				msg := &SnapshotMessage{
+					Type: "snapshot",
+					Tick:  1,
+					Ship: ShipSnapshot{
+						ID:     "p1",
+						Pos:    Vec2Snapshot{X: 0.0, Y: 0.0},
+						Vel:    Vec2Snapshot{X: 0.0, Y: 0.0},
+						Rot:    0.0,
+						Energy: 100.0,
+					},
+					Ships: []ShipSnapshot{
+						{ID: "p1", Energy: 100.0},
+						{ID: "p2", Pos: Vec2Snapshot{X: math.Inf(1), Y: 0.0}, Energy: 100.0}, // Invalid: position must be finite
+					},
+					You: "p1",
+					Sun: SunSnapshot{
+						Pos:    Vec2Snapshot{X: 0.0, Y: 0.0},
+						Radius: 5.0,
+					},
+					Pallets: []PalletSnapshot{},
+				}
+				err := ValidateSnapshotMessage(msg)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("ship at index 1"))
+
+				msg.Ships[1].Pos.X = 0.0
+				Expect(ValidateSnapshotMessage(msg)).To(Succeed())
+			})
+
 			It("validates nested sun structure", func() {
 				msg := &SnapshotMessage{
 					Type: "snapshot",
@@ -781,6 +1027,54 @@ var _ = Describe("Protocol Messages", Label("scope:contract", "loop:g4-proto", "
 				Expect(CompareVersion("v10", "v5")).To(Equal(1))
 			})
 		})
+
+		Describe("NegotiateVersion", func() {
+			It("picks the highest version both sides support", func() {
+				version, ok := NegotiateVersion([]ProtocolVersion{"v1", "v3", "v2"}, []ProtocolVersion{"v1", "v2"})
+				Expect(ok).To(BeTrue())
+				Expect(version).To(Equal(ProtocolVersion("v2")))
+			})
+
+			It("fails without a common version", func() {
+				_, ok := NegotiateVersion([]ProtocolVersion{"v2"}, SupportedVersions())
+				Expect(ok).To(BeFalse())
+			})
+
+			It("supports v1", func() {
+				Expect(SupportedVersions()).To(ContainElement(ProtocolVersionV1))
+			})
+		})
+
+		Describe("Version Handshake Messages", func() {
+			It("serializes hello and welcome messages", func() {
+				data, err := json.Marshal(HelloMessage{Type: "hello", Versions: []ProtocolVersion{"v1", "v2"}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(MatchJSON(`{"t":"hello","versions":["v1","v2"]}`))
+
+				data, err = json.Marshal(WelcomeMessage{Type: "welcome", Version: "v1"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(MatchJSON(`{"t":"welcome","version":"v1"}`))
+			})
+
+			It("serializes a version rejection", func() {
+				data, err := json.Marshal(ErrorMessage{Type: "error", Message: "unsupported", Code: "unsupported_version", Versions: []ProtocolVersion{"v1"}})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(string(data)).To(MatchJSON(`{"t":"error","message":"unsupported","code":"unsupported_version","versions":["v1"]}`))
+			})
+
+			It("validates hello messages", func() {
+				Expect(ValidateHelloMessage(&HelloMessage{Type: "hello", Versions: []ProtocolVersion{"v1"}})).To(Succeed())
+				Expect(ValidateHelloMessage(&HelloMessage{Type: "hello"})).To(MatchError(ContainSubstring("invalid versions")))
+				Expect(ValidateHelloMessage(&HelloMessage{Type: "hello", Versions: make([]ProtocolVersion, MaxHelloVersions+1)})).To(MatchError(ContainSubstring("invalid versions")))
+				Expect(ValidateHelloMessage(&HelloMessage{Type: "hello", Versions: []ProtocolVersion{"v1", "1"}})).To(MatchError(ContainSubstring("invalid version at index 1")))
+				Expect(ValidateHelloMessage(nil)).NotTo(Succeed())
+			})
+
+			It("validates welcome messages", func() {
+				Expect(ValidateWelcomeMessage(&WelcomeMessage{Type: "welcome", Version: "v1"})).To(Succeed())
+				Expect(ValidateWelcomeMessage(&WelcomeMessage{Type: "welcome"})).To(MatchError(ContainSubstring("invalid version")))
+			})
+		})
 	})
 
 	Describe("Schema Compatibility", Label("scope:contract", "loop:g4-proto", "layer:contract"), func() {
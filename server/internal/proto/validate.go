@@ -20,17 +20,56 @@ func ValidateInputMessage(msg *InputMessage) error {
 		return fmt.Errorf("invalid seq: must be greater than 0")
 	}
 
-	if msg.Thrust < 0.0 || msg.Thrust > 1.0 {
+	// NaN fails every comparison, so it is rejected explicitly
+	if isNaNOrInf(msg.Thrust) || msg.Thrust < 0.0 || msg.Thrust > 1.0 {
 		return fmt.Errorf("invalid thrust: must be in range [0.0, 1.0], got %f", msg.Thrust)
 	}
 
-	if msg.Turn < -1.0 || msg.Turn > 1.0 {
+	if isNaNOrInf(msg.Turn) || msg.Turn < -1.0 || msg.Turn > 1.0 {
 		return fmt.Errorf("invalid turn: must be in range [-1.0, 1.0], got %f", msg.Turn)
 	}
 
 	return nil
 }
 
+// isNaNOrInf reports whether v is NaN or an infinity.
+func isNaNOrInf(v float32) bool {
+	return math.IsNaN(float64(v)) || math.IsInf(float64(v), 0)
+}
+
+// ValidateInputsMessage validates an InputsMessage.
+// Returns an error if the message is invalid.
+func ValidateInputsMessage(msg *InputsMessage) error {
+	if msg == nil {
+		return fmt.Errorf("inputs message is nil")
+	}
+
+	if msg.Type != "inputs" {
+		return fmt.Errorf("invalid type: expected 'inputs', got '%s'", msg.Type)
+	}
+
+	if len(msg.Inputs) == 0 || len(msg.Inputs) > MaxInputsPerMessage {
+		return fmt.Errorf("invalid inputs: must carry 1 to %d inputs, got %d", MaxInputsPerMessage, len(msg.Inputs))
+	}
+
+	for i, input := range msg.Inputs {
+		if input.Seq == 0 {
+			return fmt.Errorf("invalid input at index %d: seq must be greater than 0", i)
+		}
+		if i > 0 && input.Seq <= msg.Inputs[i-1].Seq {
+			return fmt.Errorf("invalid input at index %d: seq must be greater than the previous one, got %d after %d", i, input.Seq, msg.Inputs[i-1].Seq)
+		}
+		if isNaNOrInf(input.Thrust) || input.Thrust < 0.0 || input.Thrust > 1.0 {
+			return fmt.Errorf("invalid input at index %d: thrust must be in range [0.0, 1.0], got %f", i, input.Thrust)
+		}
+		if isNaNOrInf(input.Turn) || input.Turn < -1.0 || input.Turn > 1.0 {
+			return fmt.Errorf("invalid input at index %d: turn must be in range [-1.0, 1.0], got %f", i, input.Turn)
+		}
+	}
+
+	return nil
+}
+
 // ValidateRestartMessage validates a RestartMessage.
 // Returns an error if the message is invalid.
 func ValidateRestartMessage(msg *RestartMessage) error {
@@ -45,6 +84,30 @@ func ValidateRestartMessage(msg *RestartMessage) error {
 	return nil
 }
 
+// ValidateSessionControlMessage validates a SessionControlMessage.
+// Returns an error if the message is invalid.
+func ValidateSessionControlMessage(msg *SessionControlMessage) error {
+	if msg == nil {
+		return fmt.Errorf("session control message is nil")
+	}
+
+	if msg.Type != "session_control" {
+		return fmt.Errorf("invalid type: expected 'session_control', got '%s'", msg.Type)
+	}
+
+	switch msg.Action {
+	case "pause", "resume":
+	case "scale":
+		if !(msg.Scale > 0) || math.IsInf(msg.Scale, 0) {
+			return fmt.Errorf("invalid scale: must be a positive finite number, got %g", msg.Scale)
+		}
+	default:
+		return fmt.Errorf("invalid action: expected 'pause', 'resume' or 'scale', got '%s'", msg.Action)
+	}
+
+	return nil
+}
+
 // ValidateSnapshotMessage validates a SnapshotMessage.
 // Returns an error if the message is invalid.
 func ValidateSnapshotMessage(msg *SnapshotMessage) error {
@@ -60,6 +123,12 @@ func ValidateSnapshotMessage(msg *SnapshotMessage) error {
 		return fmt.Errorf("invalid ship: %w", err)
 	}
 
+	for i, ship := range msg.Ships {
+		if err := ValidateShipSnapshot(&ship); err != nil {
+			return fmt.Errorf("invalid ship at index %d: %w", i, err)
+		}
+	}
+
 	if err := ValidateSunSnapshot(&msg.Sun); err != nil {
 		return fmt.Errorf("invalid sun: %w", err)
 	}
@@ -70,6 +139,286 @@ func ValidateSnapshotMessage(msg *SnapshotMessage) error {
 		}
 	}
 
+	if msg.TimeScale < 0 || math.IsNaN(msg.TimeScale) || math.IsInf(msg.TimeScale, 0) {
+		return fmt.Errorf("invalid time_scale: must be >= 0 and finite, got %g", msg.TimeScale)
+	}
+
+	return nil
+}
+
+// ValidateHelloMessage validates a HelloMessage.
+// Returns an error if the message is invalid.
+func ValidateHelloMessage(msg *HelloMessage) error {
+	if msg == nil {
+		return fmt.Errorf("hello message is nil")
+	}
+
+	if msg.Type != "hello" {
+		return fmt.Errorf("invalid type: expected 'hello', got '%s'", msg.Type)
+	}
+
+	if len(msg.Versions) == 0 || len(msg.Versions) > MaxHelloVersions {
+		return fmt.Errorf("invalid versions: must announce 1 to %d versions, got %d", MaxHelloVersions, len(msg.Versions))
+	}
+
+	for i, version := range msg.Versions {
+		if _, err := ParseVersion(string(version)); err != nil {
+			return fmt.Errorf("invalid version at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateWelcomeMessage validates a WelcomeMessage.
+// Returns an error if the message is invalid.
+func ValidateWelcomeMessage(msg *WelcomeMessage) error {
+	if msg == nil {
+		return fmt.Errorf("welcome message is nil")
+	}
+
+	if msg.Type != "welcome" {
+		return fmt.Errorf("invalid type: expected 'welcome', got '%s'", msg.Type)
+	}
+
+	if _, err := ParseVersion(string(msg.Version)); err != nil {
+		return fmt.Errorf("invalid version: %w", err)
+	}
+
+	return nil
+}
+
+// ValidateSnapshotAckMessage validates a SnapshotAckMessage.
+// Returns an error if the message is invalid.
+func ValidateSnapshotAckMessage(msg *SnapshotAckMessage) error {
+	if msg == nil {
+		return fmt.Errorf("snapshot ack message is nil")
+	}
+
+	if msg.Type != "snapshot_ack" {
+		return fmt.Errorf("invalid type: expected 'snapshot_ack', got '%s'", msg.Type)
+	}
+
+	return nil
+}
+
+// ValidateKeyframeRequestMessage validates a KeyframeRequestMessage.
+// Returns an error if the message is invalid.
+func ValidateKeyframeRequestMessage(msg *KeyframeRequestMessage) error {
+	if msg == nil {
+		return fmt.Errorf("keyframe request message is nil")
+	}
+
+	if msg.Type != "keyframe_request" {
+		return fmt.Errorf("invalid type: expected 'keyframe_request', got '%s'", msg.Type)
+	}
+
+	return nil
+}
+
+// ValidateDeltaSnapshotMessage validates a DeltaSnapshotMessage.
+// Returns an error if the message is invalid.
+func ValidateDeltaSnapshotMessage(msg *DeltaSnapshotMessage) error {
+	if msg == nil {
+		return fmt.Errorf("delta snapshot message is nil")
+	}
+
+	if msg.Type != "delta" {
+		return fmt.Errorf("invalid type: expected 'delta', got '%s'", msg.Type)
+	}
+
+	// The base is equal to the tick while the session is paused
+	if msg.Base > msg.Tick {
+		return fmt.Errorf("invalid base: must be <= tick %d, got %d", msg.Tick, msg.Base)
+	}
+
+	if msg.Ship != nil {
+		if err := ValidateShipDelta(msg.Ship); err != nil {
+			return fmt.Errorf("invalid ship: %w", err)
+		}
+	}
+
+	for i := range msg.Ships {
+		if msg.Ships[i].ID == "" {
+			return fmt.Errorf("invalid ship at index %d: id must not be empty", i)
+		}
+		if err := ValidateShipDelta(&msg.Ships[i]); err != nil {
+			return fmt.Errorf("invalid ship at index %d: %w", i, err)
+		}
+	}
+
+	if msg.Sun != nil {
+		if err := ValidateSunSnapshot(msg.Sun); err != nil {
+			return fmt.Errorf("invalid sun: %w", err)
+		}
+	}
+
+	for i, pallet := range msg.Pallets {
+		if err := ValidatePalletSnapshot(&pallet); err != nil {
+			return fmt.Errorf("invalid pallet at index %d: %w", i, err)
+		}
+	}
+
+	if msg.TimeScale < 0 || math.IsNaN(msg.TimeScale) || math.IsInf(msg.TimeScale, 0) {
+		return fmt.Errorf("invalid time_scale: must be >= 0 and finite, got %g", msg.TimeScale)
+	}
+
+	return nil
+}
+
+// ValidateShipDelta validates the fields a ShipDelta sets.
+// Returns an error if the delta is invalid.
+func ValidateShipDelta(ship *ShipDelta) error {
+	if ship == nil {
+		return fmt.Errorf("ship delta is nil")
+	}
+
+	if ship.Pos != nil {
+		if err := ValidateVec2Snapshot(ship.Pos); err != nil {
+			return fmt.Errorf("invalid pos: %w", err)
+		}
+	}
+
+	if ship.Vel != nil {
+		if err := ValidateVec2Snapshot(ship.Vel); err != nil {
+			return fmt.Errorf("invalid vel: %w", err)
+		}
+	}
+
+	if ship.Energy != nil && *ship.Energy < 0.0 {
+		return fmt.Errorf("invalid energy: must be >= 0.0, got %f", *ship.Energy)
+	}
+
+	return nil
+}
+
+// ValidateSessionMessage validates a SessionMessage.
+// Returns an error if the message is invalid.
+func ValidateSessionMessage(msg *SessionMessage) error {
+	if msg == nil {
+		return fmt.Errorf("session message is nil")
+	}
+
+	if msg.Type != "session" {
+		return fmt.Errorf("invalid type: expected 'session', got '%s'", msg.Type)
+	}
+
+	if msg.Token == "" {
+		return fmt.Errorf("invalid token: must not be empty")
+	}
+
+	return nil
+}
+
+// ValidateQueuedMessage validates a QueuedMessage.
+// Returns an error if the message is invalid.
+func ValidateQueuedMessage(msg *QueuedMessage) error {
+	if msg == nil {
+		return fmt.Errorf("queued message is nil")
+	}
+
+	if msg.Type != "queued" {
+		return fmt.Errorf("invalid type: expected 'queued', got '%s'", msg.Type)
+	}
+
+	return nil
+}
+
+// ValidateMatchMessage validates a MatchMessage.
+// Returns an error if the message is invalid.
+func ValidateMatchMessage(msg *MatchMessage) error {
+	if msg == nil {
+		return fmt.Errorf("match message is nil")
+	}
+
+	if msg.Type != "match" {
+		return fmt.Errorf("invalid type: expected 'match', got '%s'", msg.Type)
+	}
+
+	if msg.Room == "" {
+		return fmt.Errorf("invalid room: must not be empty")
+	}
+
+	if msg.You == "" {
+		return fmt.Errorf("invalid you: must not be empty")
+	}
+
+	if msg.Players < 1 {
+		return fmt.Errorf("invalid players: must be >= 1, got %d", msg.Players)
+	}
+
+	return nil
+}
+
+// ValidateReplayControlMessage validates a ReplayControlMessage.
+// Returns an error if the message is invalid.
+func ValidateReplayControlMessage(msg *ReplayControlMessage) error {
+	if msg == nil {
+		return fmt.Errorf("replay control message is nil")
+	}
+
+	if msg.Type != "replay_control" {
+		return fmt.Errorf("invalid type: expected 'replay_control', got '%s'", msg.Type)
+	}
+
+	switch msg.Action {
+	case "play", "pause", "seek":
+	case "speed":
+		if msg.Speed < MinReplaySpeed || msg.Speed > MaxReplaySpeed {
+			return fmt.Errorf("invalid speed: must be in [%g, %g], got %g", MinReplaySpeed, MaxReplaySpeed, msg.Speed)
+		}
+	default:
+		return fmt.Errorf("invalid action: expected 'play', 'pause', 'speed' or 'seek', got '%s'", msg.Action)
+	}
+
+	return nil
+}
+
+// ValidateReplayStateMessage validates a ReplayStateMessage.
+// Returns an error if the message is invalid.
+func ValidateReplayStateMessage(msg *ReplayStateMessage) error {
+	if msg == nil {
+		return fmt.Errorf("replay state message is nil")
+	}
+
+	if msg.Type != "replay" {
+		return fmt.Errorf("invalid type: expected 'replay', got '%s'", msg.Type)
+	}
+
+	if msg.ID == "" {
+		return fmt.Errorf("invalid id: must not be empty")
+	}
+
+	if msg.Tick < msg.StartTick || msg.Tick > msg.EndTick {
+		return fmt.Errorf("invalid tick: must be in [%d, %d], got %d", msg.StartTick, msg.EndTick, msg.Tick)
+	}
+
+	if msg.Speed < MinReplaySpeed || msg.Speed > MaxReplaySpeed {
+		return fmt.Errorf("invalid speed: must be in [%g, %g], got %g", MinReplaySpeed, MaxReplaySpeed, msg.Speed)
+	}
+
+	return nil
+}
+
+// ValidateWarningMessage validates a WarningMessage.
+// Returns an error if the message is invalid.
+func ValidateWarningMessage(msg *WarningMessage) error {
+	if msg == nil {
+		return fmt.Errorf("warning message is nil")
+	}
+
+	if msg.Type != "warning" {
+		return fmt.Errorf("invalid type: expected 'warning', got '%s'", msg.Type)
+	}
+
+	if msg.Code == "" {
+		return fmt.Errorf("invalid code: must not be empty")
+	}
+
+	if msg.Dropped < 0 || msg.Coalesced < 0 {
+		return fmt.Errorf("invalid counts: must be >= 0, got dropped %d, coalesced %d", msg.Dropped, msg.Coalesced)
+	}
+
 	return nil
 }
 
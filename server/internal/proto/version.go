@@ -17,6 +17,27 @@ type ProtocolVersion string
 // RestartMessage, and SnapshotMessage types.
 const ProtocolVersionV1 ProtocolVersion = "v1"
 
+// SupportedVersions returns the protocol versions the server speaks, oldest first.
+func SupportedVersions() []ProtocolVersion {
+	return []ProtocolVersion{ProtocolVersionV1}
+}
+
+// NegotiateVersion returns the highest of the client's versions that is compatible
+// with one of the server's, or false if none is.
+func NegotiateVersion(clientVersions, serverVersions []ProtocolVersion) (ProtocolVersion, bool) {
+	var best ProtocolVersion
+	found := false
+	for _, client := range clientVersions {
+		for _, server := range serverVersions {
+			if IsCompatible(client, server) && (!found || CompareVersion(client, best) > 0) {
+				best = client
+				found = true
+			}
+		}
+	}
+	return best, found
+}
+
 // ParseVersion parses a version string and returns a ProtocolVersion.
 // Valid format: "v" followed by a positive integer (e.g., "v1", "v2").
 // Returns an error if the version string is invalid.
@@ -0,0 +1,298 @@
+package room
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+)
+
+// DefaultMaxRooms is the number of rooms a server hosts when not configured otherwise.
+const DefaultMaxRooms = 100
+
+var (
+	// ErrRoomNotFound is returned when no open room has the requested ID
+	ErrRoomNotFound = errors.New("room not found")
+	// ErrRoomFull is returned when joining a room that is at capacity
+	ErrRoomFull = errors.New("room is full")
+	// ErrTooManyRooms is returned when creating a room would exceed the manager's limit
+	ErrTooManyRooms = errors.New("too many rooms")
+)
+
+// Manager is the registry of open rooms. It creates rooms, lets players join and
+// leave them, and closes a room as soon as its last player leaves.
+//
+// Manager is safe for concurrent use. Joining and leaving happen under the manager
+// lock, so a player can never join a room that is being closed.
+type Manager struct {
+	mu         sync.Mutex
+	clock      session.Clock
+	maxRooms   int
+	opts       session.Options // Settings of the rooms' sessions
+	rooms      map[string]*Room
+	logger     logr.Logger
+	replaySink ReplaySink // Receives the replays of closed rooms, nil if rooms are not recorded
+}
+
+// ReplaySink receives the replay of room id when the room closes (see Manager.SetReplaySink).
+type ReplaySink func(id string, replay session.Replay)
+
+// NewManager creates a room manager whose sessions use clock and opts; rooms
+// whose session expires or freezes are closed, and with opts.Scheduler rooms are
+// stepped by it instead of a run loop per room (the caller starts and stops it).
+// maxRooms limits the number of open rooms (DefaultMaxRooms if <= 0).
+// The logger parameter is optional; a zero logger disables room logging.
+func NewManager(clock session.Clock, maxRooms int, opts session.Options, logger logr.Logger) *Manager {
+	if maxRooms <= 0 {
+		maxRooms = DefaultMaxRooms
+	}
+	return &Manager{
+		clock:    clock,
+		maxRooms: maxRooms,
+		opts:     opts,
+		rooms:    make(map[string]*Room),
+		logger:   logger,
+	}
+}
+
+// SetReplaySink enables replay recording for rooms created after the call: every
+// such room records its session from creation, and sink receives the replay when
+// the room closes. sink is called on its own goroutine; nil disables recording.
+func (m *Manager) SetReplaySink(sink ReplaySink) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.replaySink = sink
+}
+
+// Create validates cfg, creates an empty room and starts its run loop.
+// Start from DefaultConfig so that unset fields get usable values.
+// Returns ErrTooManyRooms if the manager is at its room limit, and an error if the
+// manager's session options are invalid.
+func (m *Manager) Create(cfg Config) (*Room, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.rooms) >= m.maxRooms {
+		return nil, ErrTooManyRooms
+	}
+
+	id, err := m.newRoomID()
+	if err != nil {
+		return nil, err
+	}
+	r, err := newRoom(id, cfg, m.clock)
+	if err != nil {
+		return nil, err
+	}
+	if m.logger.Enabled() {
+		r.session.SetLogger(m.logger.WithValues("room_id", id))
+	}
+	if err := m.opts.Apply(r.session); err != nil {
+		return nil, err
+	}
+	r.session.Wait() // Until the first player joins
+	r.onStop = func(err error) { m.end(r, err) }
+	r.scheduler = m.opts.Scheduler
+	if m.replaySink != nil {
+		r.replaySink = m.replaySink
+		r.session.StartRecording(cfg.Level, cfg.Seed)
+	}
+
+	m.rooms[id] = r
+	r.start()
+
+	observability.UpdateActiveRooms(len(m.rooms))
+	observability.UpdateRoomPlayers(id, 0)
+	if m.logger.Enabled() {
+		m.logger.Info("Room created", "room_id", id, "name", cfg.Name, "level", cfg.Level, "max_players", cfg.MaxPlayers)
+	}
+
+	return r, nil
+}
+
+// Get returns the open room with the given ID.
+func (m *Manager) Get(id string) (*Room, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rooms[id]
+	return r, ok
+}
+
+// List returns the open rooms, sorted by creation time and then ID.
+func (m *Manager) List() []Info {
+	m.mu.Lock()
+	rooms := make([]*Room, 0, len(m.rooms))
+	for _, r := range m.rooms {
+		rooms = append(rooms, r)
+	}
+	m.mu.Unlock()
+
+	infos := make([]Info, len(rooms))
+	for i, r := range rooms {
+		infos[i] = r.Info()
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		if !infos[i].CreatedAt.Equal(infos[j].CreatedAt) {
+			return infos[i].CreatedAt.Before(infos[j].CreatedAt)
+		}
+		return infos[i].ID < infos[j].ID
+	})
+	return infos
+}
+
+// Join adds a new player to room id and returns the room and the player's ID.
+// Returns ErrRoomNotFound if the room does not exist and ErrRoomFull if it is at capacity.
+func (m *Manager) Join(id string) (*Room, entities.PlayerID, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rooms[id]
+	if !ok {
+		return nil, "", ErrRoomNotFound
+	}
+	playerID, err := r.join()
+	if err != nil {
+		return nil, "", err
+	}
+
+	players := r.PlayerCount()
+	observability.UpdateRoomPlayers(id, players)
+	if m.logger.Enabled() {
+		m.logger.Info("Player joined room", "room_id", id, "player_id", playerID, "players", players)
+	}
+
+	return r, playerID, nil
+}
+
+// Leave removes player playerID from room id. The room is closed when its last player leaves.
+// Leaving a room that no longer exists, or that the player is not in, does nothing.
+func (m *Manager) Leave(id string, playerID entities.PlayerID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rooms[id]
+	if !ok {
+		return
+	}
+	players, ok := r.leave(playerID)
+	if !ok {
+		return
+	}
+
+	observability.UpdateRoomPlayers(id, players)
+	if m.logger.Enabled() {
+		m.logger.Info("Player left room", "room_id", id, "player_id", playerID, "players", players)
+	}
+
+	if players == 0 {
+		m.closeLocked(id)
+	}
+}
+
+// Spectate attaches a spectator to room id and returns the room.
+// Spectators receive the room's snapshots but do not control a ship; they neither
+// take a player slot nor keep the room open.
+// Returns ErrRoomNotFound if the room does not exist.
+func (m *Manager) Spectate(id string) (*Room, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rooms[id]
+	if !ok {
+		return nil, ErrRoomNotFound
+	}
+	spectators := r.addSpectator()
+	if m.logger.Enabled() {
+		m.logger.Info("Spectator attached to room", "room_id", id, "spectators", spectators)
+	}
+	return r, nil
+}
+
+// StopSpectating detaches a spectator from room id.
+// Detaching from a room that no longer exists does nothing.
+func (m *Manager) StopSpectating(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, ok := m.rooms[id]
+	if !ok {
+		return
+	}
+	spectators := r.removeSpectator()
+	if m.logger.Enabled() {
+		m.logger.Info("Spectator left room", "room_id", id, "spectators", spectators)
+	}
+}
+
+// Close closes room id regardless of its players, stopping its run loop.
+// Returns false if the room does not exist.
+func (m *Manager) Close(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.rooms[id]; !ok {
+		return false
+	}
+	m.closeLocked(id)
+	return true
+}
+
+// end closes room r after its run loop stopped on err, unless it was already
+// closed: err is session.ErrSessionExpired, or the error of a frozen session.
+// It is called by the room's run loop.
+func (m *Manager) end(r *Room, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.rooms[r.id] != r {
+		return
+	}
+	if m.logger.Enabled() {
+		if errors.Is(err, session.ErrSessionExpired) {
+			m.logger.Info("Room expired", "room_id", r.id, "players", r.PlayerCount())
+		} else {
+			m.logger.Error(err, "Room session stopped", "room_id", r.id, "players", r.PlayerCount())
+		}
+	}
+	m.closeLocked(r.id)
+}
+
+// closeLocked stops room id and removes it from the registry. Callers hold m.mu.
+func (m *Manager) closeLocked(id string) {
+	m.rooms[id].stop()
+	delete(m.rooms, id)
+
+	observability.UpdateActiveRooms(len(m.rooms))
+	observability.DeleteRoomMetrics(id)
+	if m.logger.Enabled() {
+		m.logger.Info("Room closed", "room_id", id)
+	}
+}
+
+// newRoomID returns a random room ID that is not in use. Callers hold m.mu.
+// IDs are random so that rooms cannot be joined by guessing sequential IDs.
+func (m *Manager) newRoomID() (string, error) {
+	buf := make([]byte, 4)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("failed to generate room ID: %w", err)
+		}
+		id := hex.EncodeToString(buf)
+		if _, taken := m.rooms[id]; !taken {
+			return id, nil
+		}
+	}
+}
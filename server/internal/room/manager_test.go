@@ -0,0 +1,365 @@
+package room
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Manager", Label("scope:unit", "loop:g3-orch", "layer:server", "double:fake-io", "b:rooms", "r:high"), func() {
+	var (
+		clock   *session.FakeClock
+		manager *Manager
+	)
+
+	BeforeEach(func() {
+		observability.InitMetrics()
+		clock = session.NewFakeClock()
+		manager = NewManager(clock, 2, session.DefaultOptions(), logr.Discard())
+	})
+
+	// withOptions replaces the manager with one whose sessions use opts
+	withOptions := func(configure func(opts *session.Options)) {
+		opts := session.DefaultOptions()
+		configure(&opts)
+		manager = NewManager(clock, 2, opts, logr.Discard())
+	}
+
+	AfterEach(func() {
+		for _, info := range manager.List() {
+			manager.Close(info.ID)
+		}
+	})
+
+	Describe("Create", func() {
+		It("registers the room under a unique ID", func() {
+			first, err := manager.Create(DefaultConfig())
+			Expect(err).NotTo(HaveOccurred())
+			second, err := manager.Create(DefaultConfig())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(first.ID()).NotTo(Equal(second.ID()))
+			room, ok := manager.Get(first.ID())
+			Expect(ok).To(BeTrue())
+			Expect(room).To(BeIdenticalTo(first))
+		})
+
+		It("enforces the room limit", func() {
+			for i := 0; i < 2; i++ {
+				_, err := manager.Create(DefaultConfig())
+				Expect(err).NotTo(HaveOccurred())
+			}
+			_, err := manager.Create(DefaultConfig())
+			Expect(err).To(MatchError(ErrTooManyRooms))
+		})
+
+		It("rejects invalid configs", func() {
+			cfg := DefaultConfig()
+			cfg.Level = "maze"
+			_, err := manager.Create(cfg)
+			Expect(err).To(HaveOccurred())
+			Expect(manager.List()).To(BeEmpty())
+		})
+
+		It("uses DefaultMaxRooms when the limit is not positive", func() {
+			Expect(NewManager(clock, 0, session.DefaultOptions(), logr.Discard()).maxRooms).To(Equal(DefaultMaxRooms))
+		})
+
+		It("runs sessions of new rooms at the configured tick rate", func() {
+			withOptions(func(opts *session.Options) { opts.TickRate = 50 })
+			_, err := manager.Create(DefaultConfig())
+			Expect(err).To(MatchError(ContainSubstring("unsupported tick rate")))
+			Expect(manager.List()).To(BeEmpty())
+
+			withOptions(func(opts *session.Options) { opts.TickRate = session.TickRate60 })
+			room, err := manager.Create(DefaultConfig())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(room.Session().TickRate()).To(Equal(60))
+		})
+	})
+
+	Describe("List", func() {
+		It("lists open rooms with their player counts in creation order", func() {
+			first, _ := manager.Create(DefaultConfig())
+			clock.Advance(time.Second)
+			second, _ := manager.Create(DefaultConfig())
+			_, _, err := manager.Join(second.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			infos := manager.List()
+			Expect(infos).To(HaveLen(2))
+			Expect(infos[0].ID).To(Equal(first.ID()))
+			Expect(infos[0].Players).To(Equal(0))
+			Expect(infos[1].ID).To(Equal(second.ID()))
+			Expect(infos[1].Players).To(Equal(1))
+		})
+	})
+
+	Describe("Join and Leave", func() {
+		It("reports unknown and full rooms", func() {
+			_, _, err := manager.Join("missing")
+			Expect(err).To(MatchError(ErrRoomNotFound))
+
+			cfg := DefaultConfig()
+			cfg.MaxPlayers = 1
+			room, _ := manager.Create(cfg)
+			_, _, err = manager.Join(room.ID())
+			Expect(err).NotTo(HaveOccurred())
+			_, _, err = manager.Join(room.ID())
+			Expect(err).To(MatchError(ErrRoomFull))
+		})
+
+		It("closes a room when its last player leaves", func() {
+			room, _ := manager.Create(DefaultConfig())
+			_, first, _ := manager.Join(room.ID())
+			_, second, _ := manager.Join(room.ID())
+
+			manager.Leave(room.ID(), first)
+			_, ok := manager.Get(room.ID())
+			Expect(ok).To(BeTrue())
+
+			manager.Leave(room.ID(), second)
+			_, ok = manager.Get(room.ID())
+			Expect(ok).To(BeFalse())
+
+			_, _, err := manager.Join(room.ID())
+			Expect(err).To(MatchError(ErrRoomNotFound))
+		})
+
+		It("ignores players that are not in the room", func() {
+			room, _ := manager.Create(DefaultConfig())
+			_, _, _ = manager.Join(room.ID())
+
+			manager.Leave(room.ID(), "p9")
+			manager.Leave("missing", "p1")
+
+			Expect(room.PlayerCount()).To(Equal(1))
+		})
+	})
+
+	Describe("Lifecycle", Label("b:session-lifecycle"), func() {
+		It("waits for the first player, then counts down", func() {
+			withOptions(func(opts *session.Options) { opts.Lifecycle = session.LifecycleConfig{Countdown: 3 * time.Second} })
+			room, _ := manager.Create(DefaultConfig())
+			Expect(room.Session().State()).To(Equal(session.StateWaiting))
+
+			_, _, err := manager.Join(room.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(room.Session().State()).To(Equal(session.StateCountdown))
+
+			clock.Advance(3 * time.Second)
+			Eventually(room.Session().State).Should(Equal(session.StateRunning))
+		})
+
+		It("closes rooms whose session expired", func() {
+			withOptions(func(opts *session.Options) { opts.Lifecycle = session.LifecycleConfig{IdleTimeout: time.Minute} })
+			room, _ := manager.Create(DefaultConfig())
+			_, _, err := manager.Join(room.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.Advance(time.Minute)
+			Eventually(room.Done()).Should(BeClosed())
+			Expect(room.Session().State()).To(Equal(session.StateExpired))
+			_, ok := manager.Get(room.ID())
+			Expect(ok).To(BeFalse())
+			Expect(testutil.ToFloat64(observability.GetActiveRoomsGauge())).To(Equal(0.0))
+		})
+
+		It("closes rooms nobody joined after the join timeout, without an idle timeout", func() {
+			withOptions(func(opts *session.Options) { opts.Lifecycle = session.LifecycleConfig{JoinTimeout: time.Minute} })
+			empty, _ := manager.Create(DefaultConfig())
+			joined, _ := manager.Create(DefaultConfig())
+			_, _, err := manager.Join(joined.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.Advance(time.Minute)
+			Eventually(empty.Done()).Should(BeClosed())
+			_, ok := manager.Get(empty.ID())
+			Expect(ok).To(BeFalse())
+			Consistently(joined.Done(), 100*time.Millisecond).ShouldNot(BeClosed())
+		})
+	})
+
+	It("closes rooms whose session froze on an invariant violation", func() {
+		withOptions(func(opts *session.Options) { opts.InvariantMode = session.InvariantsFreeze })
+		room, _ := manager.Create(DefaultConfig())
+		_, _, err := manager.Join(room.ID())
+		Expect(err).NotTo(HaveOccurred())
+		// A ship with more energy than MaxEnergy breaks the invariants on the next tick
+		ship := levels.SpawnShip("p9", 1)
+		ship.Energy = 2 * DefaultConfig().Rules.MaxEnergy
+		Expect(room.Session().AddPlayer(ship)).To(Succeed())
+
+		clock.Advance(session.DefaultTickInterval)
+		Eventually(room.Done()).Should(BeClosed())
+		Expect(room.Session().Violation()).To(HaveOccurred())
+		_, ok := manager.Get(room.ID())
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("Scheduler", Label("b:tick-scheduler"), func() {
+		It("steps sessions of new rooms through the scheduler until they close", func() {
+			sched, err := session.NewScheduler(session.DefaultTickRate, 1)
+			Expect(err).NotTo(HaveOccurred())
+			sched.Start()
+			defer sched.Stop()
+			withOptions(func(opts *session.Options) { opts.Scheduler = sched })
+
+			room, _ := manager.Create(DefaultConfig())
+			_, player, _ := manager.Join(room.ID())
+			Expect(sched.Stats().Sessions).To(Equal(1))
+
+			clock.Advance(session.DefaultTickInterval)
+			Eventually(func() uint32 { return room.Session().GetWorld().Tick }).Should(Equal(uint32(1)))
+
+			manager.Leave(room.ID(), player)
+			Expect(sched.Stats().Sessions).To(Equal(0))
+		})
+
+		It("closes rooms whose scheduled session expired", func() {
+			sched, err := session.NewScheduler(session.DefaultTickRate, 1)
+			Expect(err).NotTo(HaveOccurred())
+			sched.Start()
+			defer sched.Stop()
+			withOptions(func(opts *session.Options) {
+				opts.Scheduler = sched
+				opts.Lifecycle = session.LifecycleConfig{IdleTimeout: time.Minute}
+			})
+
+			room, _ := manager.Create(DefaultConfig())
+			clock.Advance(time.Minute)
+			Eventually(room.Done()).Should(BeClosed())
+			_, ok := manager.Get(room.ID())
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Spectate", func() {
+		It("counts spectators separately from players", func() {
+			cfg := DefaultConfig()
+			cfg.MaxPlayers = 1
+			room, _ := manager.Create(cfg)
+			_, _, err := manager.Join(room.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			spectated, err := manager.Spectate(room.ID())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(spectated).To(BeIdenticalTo(room))
+			_, err = manager.Spectate(room.ID())
+			Expect(err).NotTo(HaveOccurred(), "spectators do not take player slots")
+
+			info := room.Info()
+			Expect(info.Players).To(Equal(1))
+			Expect(info.Spectators).To(Equal(2))
+			Expect(room.Session().Players()).To(HaveLen(1))
+
+			manager.StopSpectating(room.ID())
+			Expect(room.SpectatorCount()).To(Equal(1))
+		})
+
+		It("reports unknown rooms", func() {
+			_, err := manager.Spectate("missing")
+			Expect(err).To(MatchError(ErrRoomNotFound))
+			manager.StopSpectating("missing")
+		})
+
+		It("does not keep a room open after its last player leaves", func() {
+			room, _ := manager.Create(DefaultConfig())
+			_, player, _ := manager.Join(room.ID())
+			_, err := manager.Spectate(room.ID())
+			Expect(err).NotTo(HaveOccurred())
+
+			manager.Leave(room.ID(), player)
+			_, ok := manager.Get(room.ID())
+			Expect(ok).To(BeFalse())
+			Expect(room.Done()).To(BeClosed())
+		})
+	})
+
+	Describe("SetReplaySink", Label("b:replay-recording"), func() {
+		It("hands the replay of a closed room to the sink", func() {
+			type saved struct {
+				id     string
+				replay session.Replay
+			}
+			replays := make(chan saved, 1)
+			manager.SetReplaySink(func(id string, replay session.Replay) {
+				replays <- saved{id, replay}
+			})
+
+			room, _ := manager.Create(DefaultConfig())
+			_, player, _ := manager.Join(room.ID())
+			Expect(room.Session().EnqueuePlayerCommand(player, 1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+			clock.Advance(5 * session.DefaultTickInterval)
+			Expect(room.Session().Run(5)).To(Succeed())
+			world := room.Session().GetWorld()
+
+			manager.Leave(room.ID(), player)
+			var got saved
+			Eventually(replays).Should(Receive(&got))
+			Expect(got.id).To(Equal(room.ID()))
+			Expect(got.replay.Level).To(Equal(DefaultLevel))
+
+			// The replay ends after the player left
+			world, _ = world.RemoveShip(player)
+			Expect(got.replay.Play()).To(Equal(world))
+		})
+
+		It("does not record rooms without a sink", func() {
+			room, _ := manager.Create(DefaultConfig())
+			_, ok := room.Session().Recording()
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Metrics", func() {
+		It("tracks open rooms and players per room", func() {
+			room, _ := manager.Create(DefaultConfig())
+			_, player, _ := manager.Join(room.ID())
+
+			Expect(testutil.ToFloat64(observability.GetActiveRoomsGauge())).To(Equal(1.0))
+			Expect(testutil.ToFloat64(observability.GetRoomPlayersGauge().WithLabelValues(room.ID()))).To(Equal(1.0))
+
+			manager.Leave(room.ID(), player)
+			Expect(testutil.ToFloat64(observability.GetActiveRoomsGauge())).To(Equal(0.0))
+			Expect(testutil.CollectAndCount(observability.GetRoomPlayersGauge())).To(Equal(0))
+		})
+	})
+
+	It("allows concurrent joins without exceeding capacity", Label("b:concurrent-access"), func() {
+		cfg := DefaultConfig()
+		cfg.MaxPlayers = MaxPlayersLimit
+		room, _ := manager.Create(cfg)
+
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			joined []entities.PlayerID
+		)
+		for i := 0; i < 2*MaxPlayersLimit; i++ {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				if _, id, err := manager.Join(room.ID()); err == nil {
+					mu.Lock()
+					joined = append(joined, id)
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		Expect(joined).To(HaveLen(MaxPlayersLimit))
+		Expect(room.Session().Players()).To(ConsistOf(joined))
+	})
+})
@@ -0,0 +1,346 @@
+package room
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+const (
+	// DefaultMaxPlayers is the room capacity used when Config.MaxPlayers is 0
+	DefaultMaxPlayers = 4
+	// MaxPlayersLimit is the largest allowed room capacity (one spawn slot per player)
+	MaxPlayersLimit = levels.SpawnSlots
+	// DefaultLevel is the level used when Config.Level is empty
+	DefaultLevel = "standard"
+	// maxQueueSize is the command queue size of each player
+	maxQueueSize = 100
+	// MaxSpectatorDelayMs is the largest allowed spectator broadcast delay (one minute)
+	MaxSpectatorDelayMs = 60000
+)
+
+// Config describes a room to create.
+type Config struct {
+	// Name is a display name for room lists; it does not need to be unique
+	Name string `json:"name"`
+	// Level is the level name (see levels.ByName)
+	Level string `json:"level"`
+	// Seed is the level seed (used by seeded levels)
+	Seed int64 `json:"seed"`
+	// MaxPlayers is the room capacity, in [1, MaxPlayersLimit]
+	MaxPlayers int `json:"max_players"`
+	// Rules are the rule parameters the room's session runs with
+	Rules rules.Config `json:"rules"`
+	// SpectatorDelayMs delays the snapshots sent to spectators, in [0, MaxSpectatorDelayMs]
+	SpectatorDelayMs int `json:"spectator_delay_ms"`
+}
+
+// DefaultConfig returns the configuration of a room on the standard level
+// with DefaultMaxPlayers players and the shipped rules.
+func DefaultConfig() Config {
+	return Config{
+		Level:      DefaultLevel,
+		MaxPlayers: DefaultMaxPlayers,
+		Rules:      rules.DefaultConfig(),
+	}
+}
+
+// Validate checks that the configuration describes a room that can be created.
+// Returns an error describing the first invalid field.
+func (c Config) Validate() error {
+	if c.MaxPlayers < 1 || c.MaxPlayers > MaxPlayersLimit {
+		return fmt.Errorf("invalid MaxPlayers: must be in [1, %d], got %d", MaxPlayersLimit, c.MaxPlayers)
+	}
+	if _, err := levels.ByName(c.Level, c.Seed); err != nil {
+		return err
+	}
+	if err := c.Rules.Validate(); err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+	if c.SpectatorDelayMs < 0 || c.SpectatorDelayMs > MaxSpectatorDelayMs {
+		return fmt.Errorf("invalid SpectatorDelayMs: must be in [0, %d], got %d", MaxSpectatorDelayMs, c.SpectatorDelayMs)
+	}
+	return nil
+}
+
+// Info is the public description of a room, as listed by the rooms API.
+type Info struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Level      string    `json:"level"`
+	Players    int       `json:"players"`
+	MaxPlayers int       `json:"max_players"`
+	Spectators int       `json:"spectators"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Room is a named game shared by up to Config.MaxPlayers players.
+// It owns a session and runs its tick loop; connections only enqueue input and
+// read snapshots. Rooms are created, joined and closed through a Manager.
+//
+// Room is safe for concurrent use.
+type Room struct {
+	id           string
+	cfg          Config
+	initialWorld entities.World // Level layout without ships
+	session      *session.Session
+	createdAt    time.Time
+
+	mu         sync.Mutex                // Guards players, joined and spectators
+	players    map[entities.PlayerID]int // Spawn slot of each player
+	joined     []entities.PlayerID       // Players in join order; the first one hosts the room
+	spectators int                       // Number of attached spectators
+
+	done       chan struct{}
+	stopOnce   sync.Once
+	replaySink ReplaySink  // Receives the room's replay on stop, nil if not recorded
+	onStop     func(error) // Called with the error of Run that stopped the run loop, nil to only stop the loop
+
+	scheduler *session.Scheduler        // Steps the session instead of a run loop, nil for a run loop
+	scheduled *session.ScheduledSession // Scheduler entry of the session, nil while not scheduled
+}
+
+// newRoom creates a room from a validated configuration. The room starts empty
+// and its run loop is not started.
+func newRoom(id string, cfg Config, clock session.Clock) (*Room, error) {
+	world, err := levels.ByName(cfg.Level, cfg.Seed)
+	if err != nil {
+		return nil, err
+	}
+	// Players bring their own ships when they join
+	world.Ships = nil
+
+	sess := session.NewSession(clock, world, maxQueueSize)
+	if err := sess.SetRulesConfig(cfg.Rules); err != nil {
+		return nil, err
+	}
+	sess.SetRollbackWindow(session.DefaultRollbackWindow)
+
+	return &Room{
+		id:           id,
+		cfg:          cfg,
+		initialWorld: world,
+		session:      sess,
+		createdAt:    clock.Now(),
+		players:      make(map[entities.PlayerID]int),
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// ID returns the room ID.
+func (r *Room) ID() string {
+	return r.id
+}
+
+// Config returns the configuration the room was created with.
+func (r *Room) Config() Config {
+	return r.cfg
+}
+
+// Session returns the session shared by the room's players.
+func (r *Room) Session() *session.Session {
+	return r.session
+}
+
+// Scheduled returns the scheduler entry of the room's session, nil if the room
+// has a run loop of its own.
+func (r *Room) Scheduled() *session.ScheduledSession {
+	return r.scheduled
+}
+
+// SpectatorDelay returns the delay of the snapshots sent to spectators.
+func (r *Room) SpectatorDelay() time.Duration {
+	return time.Duration(r.cfg.SpectatorDelayMs) * time.Millisecond
+}
+
+// Done returns a channel that is closed when the room is closed.
+func (r *Room) Done() <-chan struct{} {
+	return r.done
+}
+
+// Info returns the public description of the room.
+func (r *Room) Info() Info {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Info{
+		ID:         r.id,
+		Name:       r.cfg.Name,
+		Level:      r.cfg.Level,
+		Players:    len(r.players),
+		MaxPlayers: r.cfg.MaxPlayers,
+		Spectators: r.spectators,
+		CreatedAt:  r.createdAt,
+	}
+}
+
+// PlayerCount returns the number of players in the room.
+func (r *Room) PlayerCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.players)
+}
+
+// Host returns the player hosting the room, the one that has been in it the
+// longest, and false if the room is empty. When the host leaves, the next player
+// in join order becomes the host.
+func (r *Room) Host() (entities.PlayerID, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.joined) == 0 {
+		return "", false
+	}
+	return r.joined[0], true
+}
+
+// SpectatorCount returns the number of spectators attached to the room.
+func (r *Room) SpectatorCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.spectators
+}
+
+// join adds a player to the room in the lowest free spawn slot.
+// Player IDs are derived from the slot ("p1" for slot 0, "p2" for slot 1, ...).
+// Returns ErrRoomFull if the room is at capacity.
+func (r *Room) join() (entities.PlayerID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.players) >= r.cfg.MaxPlayers {
+		return "", ErrRoomFull
+	}
+
+	taken := make(map[int]bool, len(r.players))
+	for _, slot := range r.players {
+		taken[slot] = true
+	}
+	slot := 0
+	for taken[slot] {
+		slot++
+	}
+
+	id := playerIDForSlot(slot)
+	if err := r.session.AddPlayer(levels.SpawnShip(id, slot)); err != nil {
+		return "", err
+	}
+	r.players[id] = slot
+	r.joined = append(r.joined, id)
+	r.session.Start() // Counts down or runs a room that was waiting for players
+	return id, nil
+}
+
+// leave removes a player from the room.
+// Returns the number of players left, and false if the player was not in the room.
+func (r *Room) leave(id entities.PlayerID) (int, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.players[id]; !ok {
+		return len(r.players), false
+	}
+	delete(r.players, id)
+	for i, joined := range r.joined {
+		if joined == id {
+			r.joined = append(r.joined[:i], r.joined[i+1:]...)
+			break
+		}
+	}
+	r.session.RemovePlayer(id)
+	return len(r.players), true
+}
+
+// addSpectator attaches a spectator to the room and returns the new spectator count.
+// Spectators do not take a spawn slot and do not count toward MaxPlayers.
+func (r *Room) addSpectator() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.spectators++
+	return r.spectators
+}
+
+// removeSpectator detaches a spectator from the room and returns the new spectator count.
+func (r *Room) removeSpectator() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.spectators > 0 {
+		r.spectators--
+	}
+	return r.spectators
+}
+
+// Restart resets the room to its level, respawning every current player in their slot.
+func (r *Room) Restart() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ships := make([]entities.Ship, 0, len(r.players))
+	for id, slot := range r.players {
+		ships = append(ships, levels.SpawnShip(id, slot))
+	}
+	// Reset copies the pallets, so initialWorld stays untouched
+	r.session.Reset(entities.NewMultiplayerWorld(ships, r.initialWorld.Sun, r.initialWorld.Pallets))
+}
+
+// start starts the room's run loop, which advances the session at its tick rate
+// (30 Hz by default) until stop is called or Run returns an error: the session
+// expired or froze on an invariant violation. With a scheduler the session is
+// added to it instead.
+func (r *Room) start() {
+	if r.scheduler != nil {
+		r.scheduled = r.scheduler.Add(r.session, func(err error) {
+			if r.onStop != nil {
+				r.onStop(err)
+			}
+		})
+		return
+	}
+
+	ticker := time.NewTicker(r.session.TickInterval())
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				// Limit to 10 ticks per call to prevent lag (see transport.SessionHandler)
+				if err := r.session.Run(10); err != nil {
+					if r.onStop != nil {
+						r.onStop(err)
+					}
+					return
+				}
+			}
+		}
+	}()
+}
+
+// stop stops the room's run loop, so its session is no longer run, and hands the
+// room's replay to its replay sink. It can be called multiple times.
+func (r *Room) stop() {
+	r.stopOnce.Do(func() {
+		close(r.done)
+		if r.scheduled != nil {
+			r.scheduled.Remove()
+		}
+		if replay, ok := r.session.StopRecording(); ok && r.replaySink != nil {
+			go r.replaySink(r.id, replay)
+		}
+	})
+}
+
+// playerIDForSlot returns the player ID of spawn slot slot.
+func playerIDForSlot(slot int) entities.PlayerID {
+	return entities.PlayerID(fmt.Sprintf("p%d", slot+1))
+}
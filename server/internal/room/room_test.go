@@ -0,0 +1,191 @@
+package room
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestRoom(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Room Suite")
+}
+
+var _ = Describe("Room", Label("scope:unit", "loop:g3-orch", "layer:server", "double:fake-io", "b:rooms", "r:high"), func() {
+	var (
+		clock   *session.FakeClock
+		manager *Manager
+		rm      *Room
+	)
+
+	BeforeEach(func() {
+		clock = session.NewFakeClock()
+		manager = NewManager(clock, 10, session.DefaultOptions(), logr.Discard())
+
+		cfg := DefaultConfig()
+		cfg.Name = "test"
+		cfg.MaxPlayers = 3
+		var err error
+		rm, err = manager.Create(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		DeferCleanup(func() { manager.Close(rm.ID()) })
+	})
+
+	Describe("Config", func() {
+		It("accepts the default config", func() {
+			Expect(DefaultConfig().Validate()).To(Succeed())
+		})
+
+		It("rejects capacities outside [1, MaxPlayersLimit]", func() {
+			cfg := DefaultConfig()
+			cfg.MaxPlayers = 0
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("MaxPlayers")))
+			cfg.MaxPlayers = MaxPlayersLimit + 1
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("MaxPlayers")))
+		})
+
+		It("rejects unknown levels and invalid rules", func() {
+			cfg := DefaultConfig()
+			cfg.Level = "maze"
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("unknown level")))
+
+			cfg = DefaultConfig()
+			cfg.Rules.MaxEnergy = 0
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("invalid rules")))
+		})
+
+		It("rejects spectator delays outside [0, MaxSpectatorDelayMs]", func() {
+			cfg := DefaultConfig()
+			cfg.SpectatorDelayMs = -1
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("SpectatorDelayMs")))
+			cfg.SpectatorDelayMs = MaxSpectatorDelayMs + 1
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("SpectatorDelayMs")))
+			cfg.SpectatorDelayMs = 1500
+			Expect(cfg.Validate()).To(Succeed())
+		})
+	})
+
+	It("starts empty on its level", func() {
+		world := rm.Session().GetWorld()
+		Expect(world.Ships).To(BeEmpty())
+		Expect(world.Pallets).To(Equal(levels.Standard().Pallets))
+		info := rm.Info()
+		Expect(info.ID).To(Equal(rm.ID()))
+		Expect(info.Name).To(Equal("test"))
+		Expect(info.Level).To(Equal("standard"))
+		Expect(info.Players).To(Equal(0))
+		Expect(info.MaxPlayers).To(Equal(3))
+	})
+
+	Describe("join", func() {
+		It("spawns players in the lowest free slot", func() {
+			first, err := rm.join()
+			Expect(err).NotTo(HaveOccurred())
+			second, err := rm.join()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first).To(Equal(entities.PlayerID("p1")))
+			Expect(second).To(Equal(entities.PlayerID("p2")))
+
+			_, ok := rm.leave(first)
+			Expect(ok).To(BeTrue())
+			third, err := rm.join()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(third).To(Equal(entities.PlayerID("p1")))
+
+			ship, ok := rm.Session().GetWorld().ShipByID(second)
+			Expect(ok).To(BeTrue())
+			Expect(ship).To(Equal(levels.SpawnShip(second, 1)))
+		})
+
+		It("rejects players beyond capacity", func() {
+			for i := 0; i < 3; i++ {
+				_, err := rm.join()
+				Expect(err).NotTo(HaveOccurred())
+			}
+			_, err := rm.join()
+			Expect(err).To(MatchError(ErrRoomFull))
+			Expect(rm.PlayerCount()).To(Equal(3))
+		})
+	})
+
+	Describe("leave", func() {
+		It("removes the player's ship", func() {
+			id, err := rm.join()
+			Expect(err).NotTo(HaveOccurred())
+
+			players, ok := rm.leave(id)
+			Expect(ok).To(BeTrue())
+			Expect(players).To(Equal(0))
+			Expect(rm.Session().Players()).To(BeEmpty())
+
+			_, ok = rm.leave(id)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("Host", func() {
+		It("is the player that has been in the room the longest", func() {
+			_, ok := rm.Host()
+			Expect(ok).To(BeFalse())
+
+			first, _ := rm.join()
+			second, _ := rm.join()
+			host, ok := rm.Host()
+			Expect(ok).To(BeTrue())
+			Expect(host).To(Equal(first))
+
+			// The new p1 joined after p2, which hosts the room now
+			rm.leave(first)
+			third, _ := rm.join()
+			Expect(third).To(Equal(first))
+			host, _ = rm.Host()
+			Expect(host).To(Equal(second))
+
+			rm.leave(second)
+			host, _ = rm.Host()
+			Expect(host).To(Equal(third))
+		})
+	})
+
+	Describe("Restart", func() {
+		It("resets the level and respawns every player", func() {
+			first, _ := rm.join()
+			second, _ := rm.join()
+			rm.Session().EnqueuePlayerCommand(first, 1, rules.InputCommand{Thrust: 1.0})
+			clock.Advance(5 * session.DefaultTickInterval)
+			Expect(rm.Session().Run(5)).To(Succeed())
+
+			rm.Restart()
+
+			world := rm.Session().GetWorld()
+			Expect(world.Tick).To(Equal(uint32(0)))
+			Expect(world.Ships).To(Equal([]entities.Ship{levels.SpawnShip(first, 0), levels.SpawnShip(second, 1)}))
+			Expect(world.Pallets).To(Equal(levels.Standard().Pallets))
+		})
+	})
+
+	It("runs its session with the configured rules", func() {
+		cfg := DefaultConfig()
+		cfg.Rules.ThrustDrainRate = 5.0
+		// The spawn point is within pickup range of an inner-ring pallet
+		cfg.Rules.PalletRestoreAmount = 0
+		custom, err := manager.Create(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		defer manager.Close(custom.ID())
+
+		id, err := custom.join()
+		Expect(err).NotTo(HaveOccurred())
+		custom.Session().EnqueuePlayerCommand(id, 1, rules.InputCommand{Thrust: 1.0})
+		clock.Advance(session.DefaultTickInterval)
+		Expect(custom.Session().Run(1)).To(Succeed())
+
+		ship, _ := custom.Session().GetWorld().ShipByID(id)
+		Expect(ship.Energy).To(Equal(levels.ShipStartEnergy - 5.0))
+	})
+})
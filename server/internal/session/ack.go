@@ -0,0 +1,33 @@
+package session
+
+import "github.com/gorbit/orbitalrush/internal/sim/entities"
+
+// InputAck acknowledges the last input the session applied for a player, so that
+// a client predicting ahead knows which of its inputs to replay on a snapshot:
+// those with a sequence number above Sequence.
+type InputAck struct {
+	Sequence uint32 `json:"sequence"` // Sequence number of the last applied input
+	Tick     uint32 `json:"tick"`     // Tick the input was applied at; the world of tick Tick+1 is the first to include it
+}
+
+// LastInputAck returns the last input applied for player id. Returns false if
+// no input of the player was applied since they joined or the session was reset.
+func (s *Session) LastInputAck(id entities.PlayerID) (InputAck, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ack, ok := s.acks[id]
+	return ack, ok
+}
+
+// recordAcks acknowledges the inputs of seqs applied at tick. Run calls it with
+// the lock held. Acknowledgements never go back: a late input applied by rollback
+// has a lower sequence number than the input acknowledged after it.
+func (s *Session) recordAcks(tick uint32, seqs map[entities.PlayerID]uint32) {
+	for id, seq := range seqs {
+		if ack, ok := s.acks[id]; ok && seq <= ack.Sequence {
+			continue
+		}
+		s.acks[id] = InputAck{Sequence: seq, Tick: tick}
+	}
+}
@@ -0,0 +1,93 @@
+package session
+
+import (
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Input Acknowledgement", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:input-ack", "r:high"), func() {
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	enqueue := func(seq uint32) {
+		ExpectWithOffset(1, session.EnqueueCommand(seq, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+	}
+
+	runTicks := func(n int) {
+		clock.Advance(DefaultTickInterval * time.Duration(n))
+		ExpectWithOffset(1, session.Run(n)).To(Succeed())
+	}
+
+	ack := func() InputAck {
+		ack, ok := session.LastInputAck(entities.DefaultPlayerID)
+		ExpectWithOffset(1, ok).To(BeTrue())
+		return ack
+	}
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+	})
+
+	It("acknowledges the last applied input and the tick it was applied at", func() {
+		_, ok := session.LastInputAck(entities.DefaultPlayerID)
+		Expect(ok).To(BeFalse())
+
+		enqueue(1)
+		enqueue(2)
+		runTicks(1)
+		Expect(ack()).To(Equal(InputAck{Sequence: 1, Tick: 0}))
+
+		// Ticks without input keep the acknowledgement
+		runTicks(3)
+		Expect(ack()).To(Equal(InputAck{Sequence: 2, Tick: 1}))
+
+		_, ok = session.LastInputAck("p9")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("acknowledges the last of coalesced inputs", func() {
+		session.SetBacklogPolicy(BacklogCoalesce, 1)
+		for seq := uint32(1); seq <= 4; seq++ {
+			enqueue(seq)
+		}
+		runTicks(1)
+		Expect(ack()).To(Equal(InputAck{Sequence: 3, Tick: 0}))
+	})
+
+	It("does not go back for late inputs applied by rollback", func() {
+		session.SetRollbackWindow(10)
+		enqueue(1)
+		runTicks(2)
+		enqueue(3)
+		runTicks(1)
+		Expect(ack()).To(Equal(InputAck{Sequence: 3, Tick: 2}))
+
+		// Sequence 2 is applied at tick 1, before 3
+		enqueue(2)
+		Expect(ack()).To(Equal(InputAck{Sequence: 3, Tick: 2}))
+		runTicks(1)
+		Expect(ack()).To(Equal(InputAck{Sequence: 3, Tick: 2}))
+	})
+
+	It("forgets acknowledgements on reset and when players leave", func() {
+		enqueue(1)
+		runTicks(1)
+		session.Reset(levels.Seeded(7))
+		_, ok := session.LastInputAck(entities.DefaultPlayerID)
+		Expect(ok).To(BeFalse())
+
+		enqueue(1)
+		runTicks(1)
+		Expect(session.RemovePlayer(entities.DefaultPlayerID)).To(BeTrue())
+		_, ok = session.LastInputAck(entities.DefaultPlayerID)
+		Expect(ok).To(BeFalse())
+	})
+})
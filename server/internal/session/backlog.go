@@ -0,0 +1,142 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// BacklogPolicy controls what happens to a player's queued commands when they
+// are queued faster than one per tick, so input latency cannot grow unbounded.
+type BacklogPolicy int
+
+const (
+	// BacklogNone applies one command per tick however many are queued (default)
+	BacklogNone BacklogPolicy = iota
+	// BacklogCoalesce merges the commands beyond the limit into the command applied
+	// at the tick (thrust and turn averaged)
+	BacklogCoalesce
+	// BacklogDropOldest discards the oldest commands beyond the limit
+	BacklogDropOldest
+	// BacklogSpeedApply merges two commands per tick while the backlog is over the
+	// limit, so it drains at twice the normal rate
+	BacklogSpeedApply
+)
+
+// DefaultMaxBacklog is the backlog limit the server uses: 3 commands (100ms at 30Hz).
+const DefaultMaxBacklog = 3
+
+// String returns the policy name.
+func (p BacklogPolicy) String() string {
+	switch p {
+	case BacklogNone:
+		return "none"
+	case BacklogCoalesce:
+		return "coalesce"
+	case BacklogDropOldest:
+		return "drop_oldest"
+	case BacklogSpeedApply:
+		return "speed"
+	default:
+		return fmt.Sprintf("BacklogPolicy(%d)", int(p))
+	}
+}
+
+// ParseBacklogPolicy returns the policy named name ("none", "coalesce", "drop_oldest" or "speed").
+func ParseBacklogPolicy(name string) (BacklogPolicy, error) {
+	for _, p := range []BacklogPolicy{BacklogNone, BacklogCoalesce, BacklogDropOldest, BacklogSpeedApply} {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+	return BacklogNone, fmt.Errorf("unknown backlog policy: %q", name)
+}
+
+// BacklogStats counts the commands of a player that backlog control did not
+// apply on their own.
+type BacklogStats struct {
+	Dropped   int // Commands discarded
+	Coalesced int // Commands merged into another command
+}
+
+// SetBacklogPolicy sets how each player's command backlog is bounded: after a
+// tick at most maxBacklog commands (at least 0) stay queued per player, the rest
+// being handled according to policy. Players sending tick-targeted commands are
+// bounded by their jitter buffer instead.
+func (s *Session) SetBacklogPolicy(policy BacklogPolicy, maxBacklog int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if maxBacklog < 0 {
+		maxBacklog = 0
+	}
+	s.backlogPolicy = policy
+	s.maxBacklog = maxBacklog
+}
+
+// TakeBacklogStats returns the commands of player id dropped or coalesced since
+// the last call, so the player can be warned, and resets them.
+func (s *Session) TakeBacklogStats(id entities.PlayerID) BacklogStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := s.backlog[id]
+	delete(s.backlog, id)
+	return stats
+}
+
+// dequeue returns the command player id applies at the current tick, bounding
+// the backlog left in queue. Run calls it with the lock held.
+func (s *Session) dequeue(id entities.PlayerID, queue *CommandQueue) (*QueuedCommand, bool) {
+	excess := queue.Size() - 1 - s.maxBacklog
+	if s.backlogPolicy == BacklogNone || excess <= 0 {
+		return queue.Dequeue()
+	}
+
+	stats := s.backlog[id]
+	var merged []*QueuedCommand
+	switch s.backlogPolicy {
+	case BacklogDropOldest:
+		for i := 0; i < excess; i++ {
+			queue.Dequeue()
+		}
+		stats.Dropped += excess
+		observability.RecordInputBacklog("dropped", excess)
+		s.backlog[id] = stats
+		return queue.Dequeue()
+	case BacklogCoalesce:
+		merged = make([]*QueuedCommand, 0, excess+1)
+		for i := 0; i <= excess; i++ {
+			cmd, _ := queue.Dequeue()
+			merged = append(merged, cmd)
+		}
+	case BacklogSpeedApply:
+		first, _ := queue.Dequeue()
+		second, _ := queue.Dequeue()
+		merged = []*QueuedCommand{first, second}
+	}
+
+	stats.Coalesced += len(merged) - 1
+	observability.RecordInputBacklog("coalesced", len(merged)-1)
+	s.backlog[id] = stats
+	return coalesce(merged), true
+}
+
+// coalesce merges commands into one with their average thrust and turn and the
+// sequence number and tick of the last.
+func coalesce(commands []*QueuedCommand) *QueuedCommand {
+	var thrust, turn float32
+	for _, cmd := range commands {
+		thrust += cmd.Command.Thrust
+		turn += cmd.Command.Turn
+	}
+	n := float32(len(commands))
+	last := commands[len(commands)-1]
+	return &QueuedCommand{
+		Sequence: last.Sequence,
+		Tick:     last.Tick,
+		Command:  rules.InputCommand{Thrust: thrust / n, Turn: turn / n},
+	}
+}
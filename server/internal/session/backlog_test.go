@@ -0,0 +1,111 @@
+package session
+
+import (
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Input Backlog", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:input-backlog", "r:high"), func() {
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	input := func(seq uint32) rules.InputCommand {
+		return rules.InputCommand{Thrust: float32(seq) / 10, Turn: 0.5}
+	}
+
+	// burst queues seqs 1 to n at once
+	burst := func(n uint32) {
+		for seq := uint32(1); seq <= n; seq++ {
+			ExpectWithOffset(1, session.EnqueueCommand(seq, input(seq))).To(BeTrue())
+		}
+	}
+
+	runTick := func() {
+		clock.Advance(DefaultTickInterval)
+		ExpectWithOffset(1, session.Run(1)).To(Succeed())
+	}
+
+	// applied returns the command the recording applied at tick
+	applied := func(tick uint32) rules.InputCommand {
+		replay, _ := session.Recording()
+		for _, frame := range replay.Frames {
+			if frame.Tick == tick {
+				return frame.Inputs[entities.DefaultPlayerID]
+			}
+		}
+		return rules.InputCommand{}
+	}
+
+	queued := func() int {
+		return session.queues[entities.DefaultPlayerID].Size()
+	}
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+		session.StartRecording("seeded", 7)
+	})
+
+	It("applies one command per tick without a policy", func() {
+		burst(6)
+		runTick()
+		Expect(applied(0)).To(Equal(input(1)))
+		Expect(queued()).To(Equal(5))
+		Expect(session.TakeBacklogStats(entities.DefaultPlayerID)).To(Equal(BacklogStats{}))
+	})
+
+	It("drops the oldest commands beyond the limit", func() {
+		session.SetBacklogPolicy(BacklogDropOldest, 2)
+		burst(6)
+		runTick()
+
+		Expect(applied(0)).To(Equal(input(4)))
+		Expect(queued()).To(Equal(2))
+		Expect(session.TakeBacklogStats(entities.DefaultPlayerID)).To(Equal(BacklogStats{Dropped: 3}))
+		Expect(session.TakeBacklogStats(entities.DefaultPlayerID)).To(Equal(BacklogStats{}))
+	})
+
+	It("coalesces the commands beyond the limit into one", func() {
+		session.SetBacklogPolicy(BacklogCoalesce, 2)
+		burst(6)
+		runTick()
+
+		// Average of seqs 1 to 4
+		Expect(applied(0).Thrust).To(BeNumerically("~", 0.25, 1e-6))
+		Expect(applied(0).Turn).To(BeNumerically("~", 0.5, 1e-6))
+		Expect(queued()).To(Equal(2))
+		Expect(session.TakeBacklogStats(entities.DefaultPlayerID)).To(Equal(BacklogStats{Coalesced: 3}))
+
+		// The sequence continues after the last merged command
+		Expect(session.EnqueueCommand(4, input(4))).To(BeFalse())
+	})
+
+	It("drains the backlog at two commands per tick with speed-apply", func() {
+		session.SetBacklogPolicy(BacklogSpeedApply, 2)
+		burst(6)
+		runTick()
+		Expect(queued()).To(Equal(4))
+		runTick()
+		Expect(queued()).To(Equal(2))
+		runTick()
+		Expect(queued()).To(Equal(1))
+
+		Expect(applied(0).Thrust).To(BeNumerically("~", 0.15, 1e-6))
+		Expect(applied(1).Thrust).To(BeNumerically("~", 0.35, 1e-6))
+		Expect(applied(2)).To(Equal(input(5)))
+		Expect(session.TakeBacklogStats(entities.DefaultPlayerID)).To(Equal(BacklogStats{Coalesced: 2}))
+	})
+
+	It("parses backlog policies", func() {
+		for _, policy := range []BacklogPolicy{BacklogNone, BacklogCoalesce, BacklogDropOldest, BacklogSpeedApply} {
+			Expect(ParseBacklogPolicy(policy.String())).To(Equal(policy))
+		}
+		_, err := ParseBacklogPolicy("drop")
+		Expect(err).To(HaveOccurred())
+	})
+})
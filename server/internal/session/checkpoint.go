@@ -0,0 +1,68 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// Checkpoint is the state a session needs to continue after a server restart:
+// the world, the rule parameters and the sequence state of every player's queue.
+// Queued commands that were not applied yet are not part of it; clients resend
+// input continuously, so they are replaced within a few ticks.
+type Checkpoint struct {
+	World entities.World `json:"world"`
+	Rules rules.Config   `json:"rules"`
+	// NextSequences is the lowest sequence number each player's queue still accepts
+	NextSequences map[entities.PlayerID]uint32 `json:"next_sequences"`
+	// Acks is the last input applied for each player that has one
+	Acks map[entities.PlayerID]InputAck `json:"acks,omitempty"`
+}
+
+// Checkpoint returns the session's current checkpoint.
+func (s *Session) Checkpoint() Checkpoint {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sequences := make(map[entities.PlayerID]uint32, len(s.queues))
+	for id, queue := range s.queues {
+		sequences[id] = queue.NextSequence()
+	}
+	acks := make(map[entities.PlayerID]InputAck, len(s.acks))
+	for id, ack := range s.acks {
+		acks[id] = ack
+	}
+	return Checkpoint{
+		World:         copyWorld(s.world),
+		Rules:         s.rulesConfig(),
+		NextSequences: sequences,
+		Acks:          acks,
+	}
+}
+
+// RestoreSession creates a session that continues from cp. The ticker starts
+// from the current time, so the time the session was down is not simulated.
+// Returns an error if the rules are invalid or a sequence number or acknowledgement
+// refers to a player without a ship.
+func RestoreSession(clock Clock, cp Checkpoint, maxQueueSize int) (*Session, error) {
+	s := NewSession(clock, cp.World, maxQueueSize)
+	if err := s.SetRulesConfig(cp.Rules); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint rules: %w", err)
+	}
+	for id, seq := range cp.NextSequences {
+		queue, ok := s.queues[id]
+		if !ok {
+			return nil, fmt.Errorf("invalid checkpoint: sequence state for unknown player %q", id)
+		}
+		queue.nextSequence = seq
+	}
+	s.clearHistory() // Inputs applied before the checkpoint cannot be rolled back
+	for id, ack := range cp.Acks {
+		if _, ok := s.queues[id]; !ok {
+			return nil, fmt.Errorf("invalid checkpoint: input acknowledgement for unknown player %q", id)
+		}
+		s.acks[id] = ack
+	}
+	return s, nil
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Checkpoint", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:session-persistence", "r:high"), func() {
+	const tickInterval = DefaultTickInterval
+
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Standard(), 10)
+		cfg := rules.DefaultConfig()
+		cfg.ThrustDrainRate = 2.0
+		Expect(session.SetRulesConfig(cfg)).To(Succeed())
+
+		for seq := uint32(1); seq <= 3; seq++ {
+			Expect(session.EnqueueCommand(seq, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		}
+		clock.Advance(3 * tickInterval)
+		Expect(session.Run(3)).To(Succeed())
+	})
+
+	It("captures the world, rules and next sequence numbers", func() {
+		cp := session.Checkpoint()
+		Expect(cp.World).To(Equal(session.GetWorld()))
+		Expect(cp.World.Tick).To(Equal(uint32(3)))
+		Expect(cp.Rules.ThrustDrainRate).To(Equal(float32(2.0)))
+		Expect(cp.NextSequences).To(Equal(map[entities.PlayerID]uint32{entities.DefaultPlayerID: 4}))
+		Expect(cp.Acks).To(Equal(map[entities.PlayerID]InputAck{entities.DefaultPlayerID: {Sequence: 3, Tick: 2}}))
+	})
+
+	It("restores a session that continues from the checkpoint after a JSON round trip", func() {
+		data, err := json.Marshal(session.Checkpoint())
+		Expect(err).NotTo(HaveOccurred())
+		var cp Checkpoint
+		Expect(json.Unmarshal(data, &cp)).To(Succeed())
+
+		restoredClock := NewFakeClock()
+		restored, err := RestoreSession(restoredClock, cp, 10)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored.GetWorld()).To(Equal(session.GetWorld()))
+		Expect(restored.Checkpoint()).To(Equal(session.Checkpoint()))
+
+		// Processed sequence numbers stay rejected, later ones are accepted
+		Expect(restored.EnqueueCommand(3, rules.InputCommand{Thrust: 1.0})).To(BeFalse())
+		Expect(restored.EnqueueCommand(4, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+
+		// Both sessions step identically from here
+		Expect(session.EnqueueCommand(4, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		clock.Advance(tickInterval)
+		restoredClock.Advance(tickInterval)
+		Expect(session.Run(1)).To(Succeed())
+		Expect(restored.Run(1)).To(Succeed())
+		Expect(restored.GetWorld()).To(Equal(session.GetWorld()))
+	})
+
+	It("does not simulate the time the session was down", func() {
+		restoredClock := NewFakeClock()
+		restoredClock.Advance(time.Hour)
+		restored, err := RestoreSession(restoredClock, session.Checkpoint(), 10)
+		Expect(err).NotTo(HaveOccurred())
+
+		restoredClock.Advance(tickInterval)
+		Expect(restored.Run(10)).To(Succeed())
+		Expect(restored.GetWorld().Tick).To(Equal(uint32(4)))
+	})
+
+	It("rejects invalid rules and unknown players", func() {
+		cp := session.Checkpoint()
+		cp.Rules.MaxEnergy = 0
+		_, err := RestoreSession(clock, cp, 10)
+		Expect(err).To(MatchError(ContainSubstring("invalid checkpoint rules")))
+
+		cp = session.Checkpoint()
+		cp.NextSequences["p9"] = 1
+		_, err = RestoreSession(clock, cp, 10)
+		Expect(err).To(MatchError(ContainSubstring("unknown player")))
+
+		cp = session.Checkpoint()
+		cp.Acks["p9"] = InputAck{Sequence: 1}
+		_, err = RestoreSession(clock, cp, 10)
+		Expect(err).To(MatchError(ContainSubstring("unknown player")))
+	})
+})
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"math"
+	"sync"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// These specs are meant to be run with the race detector (go test -race).
+var _ = Describe("Session Concurrency", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:concurrent-access", "r:high"), func() {
+	const tickInterval = DefaultTickInterval
+	const iterations = 200
+
+	var (
+		clock   *FakeClock
+		world   entities.World
+		session *Session
+	)
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		ship := entities.NewShip(entities.NewVec2(200.0, 0.0), entities.NewVec2(0.0, 10.0), 0.0, 100.0)
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		pallets := []entities.Pallet{
+			entities.NewPallet(1, entities.NewVec2(200.0, 5.0), true),
+			entities.NewPallet(2, entities.NewVec2(-300.0, 0.0), true),
+		}
+		world = entities.NewWorld(ship, sun, pallets)
+		session = NewSession(clock, world, 100)
+	})
+
+	It("allows Run, EnqueueCommand, GetWorld and Reset from different goroutines", func() {
+		session.SetInvariantMode(InvariantsFreeze)
+
+		var wg sync.WaitGroup
+		wg.Add(4)
+
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				clock.Advance(tickInterval)
+				Expect(session.Run(10)).To(Succeed())
+			}
+		}()
+
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				session.EnqueueCommand(uint32(i+1), rules.InputCommand{Thrust: 0.5, Turn: 0.1})
+			}
+		}()
+
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				snapshot := session.GetWorld()
+				Expect(snapshot.Pallets).To(HaveLen(2))
+				_ = session.State()
+			}
+		}()
+
+		go func() {
+			defer GinkgoRecover()
+			defer wg.Done()
+			for i := 0; i < iterations/10; i++ {
+				session.Reset(world)
+			}
+		}()
+
+		wg.Wait()
+	})
+
+	Describe("GetWorld", func() {
+		It("returns a copy that later ticks do not modify", func() {
+			snapshot := session.GetWorld()
+
+			clock.Advance(tickInterval)
+			Expect(session.Run(1)).To(Succeed())
+
+			Expect(session.GetWorld().Pallets[0].Active).To(BeFalse())
+			Expect(snapshot.Pallets[0].Active).To(BeTrue())
+			Expect(snapshot.Tick).To(Equal(uint32(0)))
+		})
+	})
+
+	Describe("NewSession", func() {
+		It("does not modify the caller's world", func() {
+			clock.Advance(tickInterval)
+			Expect(session.Run(1)).To(Succeed())
+
+			Expect(world.Pallets[0].Active).To(BeTrue())
+		})
+	})
+
+	Describe("Reset", func() {
+		It("restarts from the given world with an empty queue", func() {
+			session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})
+			clock.Advance(tickInterval * 3)
+			Expect(session.Run(3)).To(Succeed())
+			session.EnqueueCommand(5, rules.InputCommand{Thrust: 1.0})
+
+			session.Reset(world)
+
+			reset := session.GetWorld()
+			Expect(reset.Tick).To(Equal(uint32(0)))
+			Expect(reset.Ships[0]).To(Equal(world.Ships[0]))
+			Expect(reset.Pallets[0].Active).To(BeTrue())
+			Expect(session.queues[entities.DefaultPlayerID].IsEmpty()).To(BeTrue())
+			Expect(session.EnqueueCommand(1, rules.InputCommand{})).To(BeTrue())
+		})
+
+		It("restarts the ticker from the current time", func() {
+			clock.Advance(tickInterval * 5)
+			session.Reset(world)
+
+			Expect(session.Run(10)).To(Succeed())
+			Expect(session.GetWorld().Tick).To(Equal(uint32(0)))
+		})
+
+		It("clears an invariant violation", func() {
+			session.SetInvariantMode(InvariantsFreeze)
+			broken := copyWorld(world)
+			broken.Ships[0].Pos.X = math.NaN()
+			session.Reset(broken)
+
+			clock.Advance(tickInterval)
+			Expect(session.Run(1)).NotTo(Succeed())
+
+			session.Reset(world)
+			Expect(session.Violation()).To(BeNil())
+			clock.Advance(tickInterval)
+			Expect(session.Run(1)).To(Succeed())
+		})
+	})
+})
@@ -0,0 +1,136 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// InvariantMode controls whether a session checks world invariants after every step,
+// and what it does when one is violated.
+type InvariantMode int
+
+const (
+	// InvariantsOff skips invariant checks (production default)
+	InvariantsOff InvariantMode = iota
+	// InvariantsFreeze freezes the session, logs a state dump and returns an error from Run
+	InvariantsFreeze
+	// InvariantsPanic panics with the state dump so tests fail loudly
+	InvariantsPanic
+)
+
+// String returns the mode name.
+func (m InvariantMode) String() string {
+	switch m {
+	case InvariantsOff:
+		return "off"
+	case InvariantsFreeze:
+		return "freeze"
+	case InvariantsPanic:
+		return "panic"
+	default:
+		return fmt.Sprintf("InvariantMode(%d)", int(m))
+	}
+}
+
+// StateDump captures the session state around the step that violated an invariant.
+type StateDump struct {
+	Before        entities.World                           // World before the offending step
+	After         entities.World                           // World produced by the offending step
+	Inputs        map[entities.PlayerID]rules.InputCommand // Inputs applied by the offending step (absent = zero command)
+	QueueSizes    map[entities.PlayerID]int                // Commands still queued per player
+	NextSequences map[entities.PlayerID]uint32             // Next sequence number each player's queue expects
+}
+
+// String formats the dump for logs and panics. Plain formatting is used rather
+// than JSON because the worlds being dumped may contain NaN or Inf.
+func (d StateDump) String() string {
+	return fmt.Sprintf("before=%+v after=%+v inputs=%+v queue_sizes=%v next_sequences=%v",
+		d.Before, d.After, d.Inputs, d.QueueSizes, d.NextSequences)
+}
+
+// InvariantViolationError is returned by Run once a session has frozen on an invariant violation.
+type InvariantViolationError struct {
+	Err  *rules.InvariantError
+	Dump StateDump
+}
+
+// Error implements the error interface.
+func (e *InvariantViolationError) Error() string {
+	return fmt.Sprintf("session frozen: %v", e.Err)
+}
+
+// Unwrap returns the underlying *rules.InvariantError.
+func (e *InvariantViolationError) Unwrap() error {
+	return e.Err
+}
+
+// SetInvariantMode enables or disables invariant checking after every step.
+// Checks cost a world copy per tick, so they are meant for debug runs and tests.
+func (s *Session) SetInvariantMode(mode InvariantMode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.invariantMode = mode
+}
+
+// Violation returns the error the session froze on, or nil if it is not frozen.
+func (s *Session) Violation() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.violation == nil {
+		return nil
+	}
+	return s.violation
+}
+
+// rulesConfig returns the rule configuration the session steps the world with.
+func (s *Session) rulesConfig() rules.Config {
+	cfg := s.rulesCfg
+	cfg.G = s.G
+	cfg.AMax = s.aMax
+	cfg.PickupRadius = s.pickupRadius
+	return cfg
+}
+
+// checkStep checks the invariants between before and after. On violation the session
+// freezes (the world stays at before) and the violation is logged, returned or
+// panicked depending on the invariant mode. Run calls it with the lock held.
+func (s *Session) checkStep(before, after entities.World, inputs map[entities.PlayerID]rules.InputCommand) error {
+	err := rules.CheckInvariants(before, after, s.rulesConfig())
+	if err == nil {
+		return nil
+	}
+
+	violation := &InvariantViolationError{
+		Err: err.(*rules.InvariantError),
+		Dump: StateDump{
+			Before:        before,
+			After:         after,
+			Inputs:        inputs,
+			QueueSizes:    make(map[entities.PlayerID]int, len(s.queues)),
+			NextSequences: make(map[entities.PlayerID]uint32, len(s.queues)),
+		},
+	}
+	for id, queue := range s.queues {
+		violation.Dump.QueueSizes[id] = queue.Size()
+		violation.Dump.NextSequences[id] = queue.NextSequence()
+	}
+	s.violation = violation
+
+	if s.logger.Enabled() {
+		s.logger.WithValues(
+			"component", "session",
+			"tick", after.Tick,
+			"violations", violation.Err.Violations,
+			"dump", violation.Dump.String(),
+		).Error(violation, "World invariant violated, session frozen")
+	}
+
+	if s.invariantMode == InvariantsPanic {
+		panic(fmt.Sprintf("%v\nstate dump: %s", violation, violation.Dump.String()))
+	}
+	return violation
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"errors"
+	"math"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Invariant Checks", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:world-invariants", "r:high"), func() {
+	const tickInterval = DefaultTickInterval
+
+	var clock *FakeClock
+
+	newWorld := func(shipX float64, energy float32) entities.World {
+		ship := entities.NewShip(entities.NewVec2(shipX, 0.0), entities.NewVec2(0.0, 0.0), 0.0, energy)
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		pallets := []entities.Pallet{entities.NewPallet(1, entities.NewVec2(-300.0, 0.0), true)}
+		return entities.NewWorld(ship, sun, pallets)
+	}
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+	})
+
+	It("is off by default", func() {
+		session := NewSession(clock, newWorld(math.NaN(), 100.0), 100)
+
+		clock.Advance(tickInterval * 3)
+		Expect(session.Run(3)).To(Succeed())
+		Expect(session.GetWorld().Tick).To(Equal(uint32(3)))
+		Expect(session.Violation()).To(BeNil())
+	})
+
+	It("runs normally while invariants hold", func() {
+		session := NewSession(clock, newWorld(200.0, 100.0), 100)
+		session.SetInvariantMode(InvariantsFreeze)
+		session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 1.0})
+
+		clock.Advance(tickInterval * 10)
+		Expect(session.Run(10)).To(Succeed())
+		Expect(session.GetWorld().Tick).To(Equal(uint32(10)))
+		Expect(session.Violation()).To(BeNil())
+	})
+
+	Describe("InvariantsFreeze", func() {
+		It("freezes on the first violation and returns a state dump", func() {
+			session := NewSession(clock, newWorld(200.0, 150.0), 100)
+			session.SetInvariantMode(InvariantsFreeze)
+			session.EnqueueCommand(1, rules.InputCommand{Turn: 0.5})
+			session.EnqueueCommand(2, rules.InputCommand{Turn: 0.5})
+
+			clock.Advance(tickInterval * 3)
+			err := session.Run(3)
+
+			var violation *InvariantViolationError
+			Expect(errors.As(err, &violation)).To(BeTrue())
+			Expect(violation.Err.Violations).To(ConsistOf(ContainSubstring("ship[p1].energy")))
+			Expect(violation.Dump.Before.Tick).To(Equal(uint32(0)))
+			Expect(violation.Dump.After.Tick).To(Equal(uint32(1)))
+			Expect(violation.Dump.Inputs).To(Equal(map[entities.PlayerID]rules.InputCommand{entities.DefaultPlayerID: {Turn: 0.5}}))
+			Expect(violation.Dump.QueueSizes).To(Equal(map[entities.PlayerID]int{entities.DefaultPlayerID: 1}))
+			Expect(violation.Dump.NextSequences).To(Equal(map[entities.PlayerID]uint32{entities.DefaultPlayerID: 2}))
+			Expect(violation.Dump.String()).To(ContainSubstring("queue_sizes=map[p1:1]"))
+
+			var invErr *rules.InvariantError
+			Expect(errors.As(err, &invErr)).To(BeTrue())
+		})
+
+		It("keeps the last valid world and refuses to run again", func() {
+			session := NewSession(clock, newWorld(200.0, 150.0), 100)
+			session.SetInvariantMode(InvariantsFreeze)
+
+			clock.Advance(tickInterval)
+			first := session.Run(1)
+			Expect(first).To(HaveOccurred())
+			Expect(session.GetWorld().Tick).To(Equal(uint32(0)))
+
+			clock.Advance(tickInterval * 5)
+			Expect(session.Run(5)).To(BeIdenticalTo(first))
+			Expect(session.GetWorld().Tick).To(Equal(uint32(0)))
+			Expect(session.Violation()).To(BeIdenticalTo(first))
+		})
+
+		It("accepts a legitimate pallet pickup", func() {
+			world := newWorld(200.0, 50.0)
+			world.Pallets[0].Pos = entities.NewVec2(200.0, 0.0)
+			session := NewSession(clock, world, 100)
+			session.SetInvariantMode(InvariantsFreeze)
+
+			clock.Advance(tickInterval)
+			Expect(session.Run(1)).To(Succeed())
+			Expect(session.GetWorld().Pallets[0].Active).To(BeFalse())
+		})
+	})
+
+	Describe("InvariantsPanic", func() {
+		It("panics with the violation and state dump", func() {
+			session := NewSession(clock, newWorld(math.Inf(1), 100.0), 100)
+			session.SetInvariantMode(InvariantsPanic)
+
+			clock.Advance(tickInterval)
+			Expect(func() { _ = session.Run(1) }).To(PanicWith(And(
+				ContainSubstring("ship[p1].pos.x is not finite"),
+				ContainSubstring("state dump"),
+			)))
+		})
+	})
+
+	It("names the modes", func() {
+		Expect(InvariantsOff.String()).To(Equal("off"))
+		Expect(InvariantsFreeze.String()).To(Equal("freeze"))
+		Expect(InvariantsPanic.String()).To(Equal("panic"))
+	})
+})
@@ -0,0 +1,217 @@
+package session
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// GapPolicy controls what a player's jitter buffer plays at a tick whose input
+// has not arrived.
+type GapPolicy int
+
+const (
+	// GapZero plays the zero command, like an empty queue (default)
+	GapZero GapPolicy = iota
+	// GapRepeatLast plays the player's last input again
+	GapRepeatLast
+	// GapWait holds the player's inputs back for up to jitterWaitTicks ticks, so a
+	// delayed input is played late rather than skipped; the extra delay is kept
+	// until the buffer shrinks again
+	GapWait
+)
+
+// String returns the policy name.
+func (p GapPolicy) String() string {
+	switch p {
+	case GapZero:
+		return "zero"
+	case GapRepeatLast:
+		return "repeat"
+	case GapWait:
+		return "wait"
+	default:
+		return fmt.Sprintf("GapPolicy(%d)", int(p))
+	}
+}
+
+// ParseGapPolicy returns the policy named name ("zero", "repeat" or "wait").
+func ParseGapPolicy(name string) (GapPolicy, error) {
+	for _, p := range []GapPolicy{GapZero, GapRepeatLast, GapWait} {
+		if p.String() == name {
+			return p, nil
+		}
+	}
+	return GapZero, fmt.Errorf("unknown gap policy: %q", name)
+}
+
+const (
+	// JitterBufferTicks is the margin, in ticks, a jitter buffer keeps between the
+	// arrival of an input and the tick it is played at
+	JitterBufferTicks = 2
+	// MaxJitterBufferTicks is how far ahead of the tick it would be played at an
+	// input may arrive; later ones are early and rejected
+	MaxJitterBufferTicks = 30
+	// jitterWaitTicks is how many ticks GapWait waits for a missing input
+	jitterWaitTicks = 2
+	// jitterAdaptWindow is the number of arrivals after which a margin larger than
+	// JitterBufferTicks is reduced by a tick
+	jitterAdaptWindow = 30
+)
+
+// jitterBuffer plays the tick-targeted inputs of one player a fixed number of
+// ticks (the offset) after the tick they were intended for. The offset absorbs
+// the player's latency and jitter: late inputs and waits grow it by a tick, and
+// a window of inputs that all arrived with spare margin shrinks it by a tick.
+// The offset grows by at most MaxJitterBufferTicks - 2*JitterBufferTicks ticks,
+// so inputs arriving like the first keep JitterBufferTicks of margin below the
+// early limit.
+type jitterBuffer struct {
+	offset    int64              // World tick minus the intended tick of the input it plays
+	base      int64              // Offset the buffer was created with
+	last      rules.InputCommand // Last input played, for GapRepeatLast
+	played    bool               // Whether an input was played; there are no gaps before the first
+	waited    int                // Ticks waited for the missing input since the last input played
+	arrivals  int                // Arrivals in the current adaptation window
+	minMargin int64              // Smallest margin in the current adaptation window
+}
+
+// newJitterBuffer creates the buffer of a player whose first tick-targeted input,
+// for tick, arrives before step worldTick: it is played JitterBufferTicks later.
+func newJitterBuffer(worldTick, tick uint32) *jitterBuffer {
+	offset := int64(worldTick) - int64(tick) + JitterBufferTicks
+	return &jitterBuffer{
+		offset:    offset,
+		base:      offset,
+		minMargin: math.MaxInt64,
+	}
+}
+
+// grow delays the buffer's inputs by another tick, unless it reached its limit.
+func (b *jitterBuffer) grow() {
+	if b.offset < b.base+MaxJitterBufferTicks-2*JitterBufferTicks {
+		b.offset++
+	}
+}
+
+// inflated returns whether the buffer grew since it was created.
+func (b *jitterBuffer) inflated() bool {
+	return b.offset > b.base
+}
+
+// adapt records the margin of an input that arrived in time and shrinks the
+// buffer at the end of a window in which every input had spare margin.
+func (b *jitterBuffer) adapt(margin int64) {
+	b.arrivals++
+	if margin < b.minMargin {
+		b.minMargin = margin
+	}
+	if b.arrivals < jitterAdaptWindow {
+		return
+	}
+	if b.minMargin > JitterBufferTicks {
+		b.offset--
+	}
+	b.arrivals = 0
+	b.minMargin = math.MaxInt64
+}
+
+// SetGapPolicy sets what jitter buffers play at ticks whose input has not arrived
+// (see EnqueuePlayerCommandAt). It defaults to GapZero.
+func (s *Session) SetGapPolicy(policy GapPolicy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.gapPolicy = policy
+}
+
+// EnqueuePlayerCommandAt adds a command that the client of player id intended for
+// tick. Tick 0 means untargeted, the same as EnqueuePlayerCommand.
+//
+// From their first targeted command on, a player's commands are played through a
+// jitter buffer: the command for tick is applied at tick plus the player's offset,
+// which adapts to the player's latency and jitter, so commands keep their spacing
+// however unevenly they arrive. Commands arriving after their tick was played are
+// late, and more than MaxJitterBufferTicks ahead early; both are rejected and
+// counted, except that an early command restarts a buffer that grew since it was
+// created, so a buffer grown by losses never locks the player out. Ticks without
+// a command are filled according to the gap policy.
+// Returns true if the command was successfully enqueued, false otherwise.
+func (s *Session) EnqueuePlayerCommandAt(id entities.PlayerID, seq, tick uint32, cmd rules.InputCommand) bool {
+	if tick == 0 {
+		return s.EnqueuePlayerCommand(id, seq, cmd)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue, ok := s.queues[id]
+	if !ok {
+		return false
+	}
+	buffer, ok := s.jitter[id]
+	if !ok {
+		buffer = newJitterBuffer(s.world.Tick, tick)
+		s.jitter[id] = buffer
+	}
+
+	// Ticks until the command is played; the next step plays world.Tick - offset
+	margin := int64(tick) + buffer.offset - int64(s.world.Tick)
+	observability.ObserveInputMargin(margin)
+	switch {
+	case margin < 0:
+		observability.RecordInputTiming("late")
+		buffer.grow()
+		return false
+	case margin > MaxJitterBufferTicks && buffer.inflated():
+		// The player's clock moved on since the buffer grew: start over from this command
+		buffer = newJitterBuffer(s.world.Tick, tick)
+		s.jitter[id] = buffer
+		margin = JitterBufferTicks
+	case margin > MaxJitterBufferTicks:
+		observability.RecordInputTiming("early")
+		return false
+	}
+	observability.RecordInputTiming("on_time")
+	buffer.adapt(margin)
+
+	success := queue.EnqueueAt(seq, tick, cmd)
+	if success {
+		s.touch()
+	}
+	s.observeQueue(id, queue)
+	return success
+}
+
+// playout returns the command buffer plays at the current tick and its sequence
+// number, 0 for a repeated command. Returns false if the player has no command
+// for the tick. Run calls it with the lock held.
+func (s *Session) playout(buffer *jitterBuffer, queue *CommandQueue) (rules.InputCommand, uint32, bool) {
+	intended := int64(s.world.Tick) - buffer.offset
+
+	// Drop commands whose tick was skipped when the buffer shrank
+	for next, ok := queue.Peek(); ok && next.Tick != 0 && int64(next.Tick) < intended; next, ok = queue.Peek() {
+		queue.Dequeue()
+	}
+	if next, ok := queue.Peek(); ok && (next.Tick == 0 || int64(next.Tick) == intended) {
+		next, _ = queue.Dequeue()
+		buffer.last = next.Command
+		buffer.played = true
+		buffer.waited = 0
+		return next.Command, next.Sequence, true
+	}
+
+	switch s.gapPolicy {
+	case GapRepeatLast:
+		return buffer.last, 0, true
+	case GapWait:
+		if buffer.played && buffer.waited < jitterWaitTicks {
+			buffer.waited++
+			buffer.grow() // Play the intended tick at the next tick instead
+		}
+	}
+	return rules.InputCommand{}, 0, false
+}
@@ -0,0 +1,203 @@
+package session
+
+import (
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Jitter Buffer", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:jitter-buffer", "r:high"), func() {
+	const tickInterval = DefaultTickInterval
+
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	input := func(seq uint32) rules.InputCommand {
+		return rules.InputCommand{Thrust: float32(seq) / 10, Turn: float32(seq%3) - 1}
+	}
+
+	runTicks := func(s *Session, c *FakeClock, n int) {
+		for i := 0; i < n; i++ {
+			c.Advance(tickInterval)
+			ExpectWithOffset(1, s.Run(1)).To(Succeed())
+		}
+	}
+
+	// enqueue queues the input of seq for tick seq
+	enqueue := func(seqs ...uint32) {
+		for _, seq := range seqs {
+			ExpectWithOffset(1, session.EnqueuePlayerCommandAt(entities.DefaultPlayerID, seq, seq, input(seq))).To(BeTrue(), "seq %d", seq)
+		}
+	}
+
+	// playedAt returns the input the recording applied at tick, if not zero
+	playedAt := func(tick uint32) (rules.InputCommand, bool) {
+		replay, _ := session.Recording()
+		for _, frame := range replay.Frames {
+			if frame.Tick == tick {
+				cmd, ok := frame.Inputs[entities.DefaultPlayerID]
+				return cmd, ok
+			}
+		}
+		return rules.InputCommand{}, false
+	}
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+		session.StartRecording("seeded", 7)
+	})
+
+	It("plays inputs a constant offset after their tick however they arrive", func() {
+		enqueue(1) // Played JitterBufferTicks later, at tick 2
+		runTicks(session, clock, 2)
+		enqueue(2, 3, 4)
+		runTicks(session, clock, 3)
+		enqueue(5, 6)
+		runTicks(session, clock, 3)
+
+		// The same inputs, one per tick from tick 2
+		evenClock := NewFakeClock()
+		even := NewSession(evenClock, levels.Seeded(7), 100)
+		runTicks(even, evenClock, 2)
+		for seq := uint32(1); seq <= 6; seq++ {
+			Expect(even.EnqueueCommand(seq, input(seq))).To(BeTrue())
+		}
+		runTicks(even, evenClock, 6)
+
+		Expect(session.GetWorld()).To(Equal(even.GetWorld()))
+	})
+
+	It("rejects late and early inputs and grows the buffer on late ones", func() {
+		enqueue(1)
+		runTicks(session, clock, 5)
+
+		Expect(session.EnqueuePlayerCommandAt(entities.DefaultPlayerID, 4, 5+MaxJitterBufferTicks, input(4))).To(BeFalse())
+		// Tick 3 would have been played at tick 4
+		Expect(session.EnqueuePlayerCommandAt(entities.DefaultPlayerID, 3, 3, input(3))).To(BeFalse())
+		Expect(session.jitter[entities.DefaultPlayerID].offset).To(Equal(int64(2)))
+
+		// Tick 4 is now played at tick 6
+		Expect(session.EnqueuePlayerCommandAt(entities.DefaultPlayerID, 5, 4, input(4))).To(BeTrue())
+		runTicks(session, clock, 2)
+		cmd, ok := playedAt(6)
+		Expect(ok).To(BeTrue())
+		Expect(cmd).To(Equal(input(4)))
+	})
+
+	It("treats tick 0 as untargeted", func() {
+		Expect(session.EnqueuePlayerCommandAt(entities.DefaultPlayerID, 1, 0, input(1))).To(BeTrue())
+		runTicks(session, clock, 1)
+		Expect(session.jitter).To(BeEmpty())
+		cmd, _ := playedAt(0)
+		Expect(cmd).To(Equal(input(1)))
+	})
+
+	Describe("gap policies", func() {
+		// Tick 3 is missing: ticks 1, 2 and 4 are played at ticks 2, 3 and 5
+		BeforeEach(func() {
+			enqueue(1, 2, 4)
+		})
+
+		It("plays nothing with GapZero", func() {
+			runTicks(session, clock, 6)
+			_, ok := playedAt(4)
+			Expect(ok).To(BeFalse())
+			cmd, _ := playedAt(5)
+			Expect(cmd).To(Equal(input(4)))
+		})
+
+		It("repeats the last input with GapRepeatLast", func() {
+			session.SetGapPolicy(GapRepeatLast)
+			runTicks(session, clock, 6)
+			cmd, _ := playedAt(4)
+			Expect(cmd).To(Equal(input(2)))
+			cmd, _ = playedAt(5)
+			Expect(cmd).To(Equal(input(4)))
+		})
+
+		It("waits for the missing input with GapWait", func() {
+			session.SetGapPolicy(GapWait)
+			runTicks(session, clock, 5)
+			enqueue(3)
+			runTicks(session, clock, 2)
+
+			_, ok := playedAt(4)
+			Expect(ok).To(BeFalse())
+			cmd, _ := playedAt(5)
+			Expect(cmd).To(Equal(input(3)))
+			cmd, _ = playedAt(6)
+			Expect(cmd).To(Equal(input(4)))
+		})
+
+		It("gives up waiting for a lost input", func() {
+			session.SetGapPolicy(GapWait)
+			runTicks(session, clock, 8)
+
+			// Waits at ticks 4 and 5, then skips tick 3
+			cmd, _ := playedAt(7)
+			Expect(cmd).To(Equal(input(4)))
+			Expect(session.jitter[entities.DefaultPlayerID].offset).To(Equal(int64(1 + jitterWaitTicks)))
+		})
+	})
+
+	It("keeps accepting on-time inputs under sustained loss with GapWait", func() {
+		session.SetGapPolicy(GapWait)
+		maxOffset := int64(1 + MaxJitterBufferTicks - 2*JitterBufferTicks)
+
+		// The input of every 4th tick is lost; every loss makes the buffer wait
+		for seq := uint32(1); seq <= 300; seq++ {
+			if seq%4 != 0 {
+				enqueue(seq)
+			}
+			runTicks(session, clock, 1)
+			Expect(session.jitter[entities.DefaultPlayerID].offset).To(BeNumerically("<=", maxOffset))
+		}
+		Expect(session.jitter[entities.DefaultPlayerID].offset).To(Equal(maxOffset))
+	})
+
+	It("restarts a grown buffer on an early input", func() {
+		enqueue(1)
+		runTicks(session, clock, 80)
+
+		// 30 stale inputs grow the buffer to its limit
+		for seq := uint32(2); seq <= 31; seq++ {
+			Expect(session.EnqueuePlayerCommandAt(entities.DefaultPlayerID, seq, seq, input(seq))).To(BeFalse())
+		}
+		Expect(session.jitter[entities.DefaultPlayerID].offset).To(Equal(int64(1 + MaxJitterBufferTicks - 2*JitterBufferTicks)))
+
+		// The client catches up: its input is played JitterBufferTicks after it arrived
+		Expect(session.EnqueuePlayerCommandAt(entities.DefaultPlayerID, 32, 120, input(32))).To(BeTrue())
+		runTicks(session, clock, JitterBufferTicks+1)
+		cmd, ok := playedAt(80 + JitterBufferTicks)
+		Expect(ok).To(BeTrue())
+		Expect(cmd).To(Equal(input(32)))
+	})
+
+	It("shrinks a buffer whose inputs all arrive with spare margin", func() {
+		buffer := newJitterBuffer(10, 10)
+		Expect(buffer.offset).To(Equal(int64(JitterBufferTicks)))
+
+		for i := 0; i < jitterAdaptWindow; i++ {
+			buffer.adapt(JitterBufferTicks + 3)
+		}
+		Expect(buffer.offset).To(Equal(int64(JitterBufferTicks - 1)))
+
+		for i := 0; i < jitterAdaptWindow; i++ {
+			buffer.adapt(JitterBufferTicks + 3 - int64(i%4))
+		}
+		Expect(buffer.offset).To(Equal(int64(JitterBufferTicks - 1)))
+	})
+
+	It("parses gap policies", func() {
+		for _, policy := range []GapPolicy{GapZero, GapRepeatLast, GapWait} {
+			Expect(ParseGapPolicy(policy.String())).To(Equal(policy))
+		}
+		_, err := ParseGapPolicy("skip")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,195 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/observability"
+)
+
+// State is a session's lifecycle state.
+type State int
+
+const (
+	// StateWaiting is a session waiting for players; Run processes no ticks
+	StateWaiting State = iota
+	// StateCountdown is a session counting down to the start; Run processes no ticks
+	StateCountdown
+	// StateRunning is a session being simulated
+	StateRunning
+	// StateFinished is a session whose world is done; Run processes no ticks
+	StateFinished
+	// StateExpired is a session reaped for being idle; it is never simulated again
+	StateExpired
+)
+
+// String returns the state's name, as used in logs and metrics.
+func (s State) String() string {
+	switch s {
+	case StateWaiting:
+		return "waiting"
+	case StateCountdown:
+		return "countdown"
+	case StateRunning:
+		return "running"
+	case StateFinished:
+		return "finished"
+	case StateExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// Lifecycle defaults used by the server.
+const (
+	DefaultIdleTimeout     = 5 * time.Minute
+	DefaultFinishedTimeout = time.Minute
+	DefaultJoinTimeout     = time.Minute
+)
+
+// ErrSessionExpired is returned by Run once the session expired.
+var ErrSessionExpired = errors.New("session expired")
+
+// LifecycleConfig configures a session's countdown and idle timeouts.
+// Zero durations disable the countdown or the timeout.
+type LifecycleConfig struct {
+	// Countdown is the time between Start (or Reset) and the first tick
+	Countdown time.Duration
+	// IdleTimeout expires sessions that are not finished and got no input for this long
+	IdleTimeout time.Duration
+	// FinishedTimeout expires finished sessions after this long
+	FinishedTimeout time.Duration
+	// JoinTimeout expires sessions waiting for players for this long, whatever IdleTimeout
+	JoinTimeout time.Duration
+}
+
+// DefaultLifecycleConfig returns the lifecycle the server runs sessions with:
+// no countdown, DefaultIdleTimeout, DefaultFinishedTimeout and DefaultJoinTimeout.
+func DefaultLifecycleConfig() LifecycleConfig {
+	return LifecycleConfig{
+		IdleTimeout:     DefaultIdleTimeout,
+		FinishedTimeout: DefaultFinishedTimeout,
+		JoinTimeout:     DefaultJoinTimeout,
+	}
+}
+
+// TransitionHook is called with the states of every lifecycle transition.
+type TransitionHook func(from, to State)
+
+// SetLifecycle sets the session's countdown and timeouts. New sessions have
+// neither, so they never expire.
+func (s *Session) SetLifecycle(cfg LifecycleConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lifecycle = cfg
+}
+
+// OnTransition registers hook to be called after every lifecycle transition.
+// Hooks are called with the session lock held, so they must not call the session.
+func (s *Session) OnTransition(hook TransitionHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.transitionHooks = append(s.transitionHooks, hook)
+}
+
+// State returns the session's lifecycle state. Timeouts are applied by Run, so
+// an idle session reads as expired from the first Run after its timeout.
+func (s *Session) State() State {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.state
+}
+
+// Wait puts a session that is counting down or running back to waiting, e.g. a
+// room whose players all left. It has no effect in other states.
+func (s *Session) Wait() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == StateCountdown || s.state == StateRunning {
+		s.transition(StateWaiting)
+	}
+}
+
+// Start starts a waiting session: it counts down if the lifecycle has a
+// countdown and runs otherwise. It has no effect in other states.
+func (s *Session) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state == StateWaiting {
+		s.start()
+	}
+}
+
+// start counts down or runs the session. Callers hold s.mu.
+func (s *Session) start() {
+	if s.lifecycle.Countdown > 0 {
+		s.transition(StateCountdown)
+	} else {
+		s.transition(StateRunning)
+	}
+}
+
+// touch records input activity for the idle timeout. Callers hold s.mu.
+func (s *Session) touch() {
+	s.lastActivity = s.clock.Now()
+}
+
+// transition moves the session to state to, restarting the ticker when it starts
+// running, and logs, counts and reports the transition. Callers hold s.mu.
+func (s *Session) transition(to State) {
+	from := s.state
+	if from == to {
+		return
+	}
+	s.state = to
+	s.stateSince = s.clock.Now()
+	s.lastActivity = s.stateSince
+	if to == StateRunning {
+		s.ticker.Reset() // The time spent in other states is not simulated
+	}
+
+	observability.RecordSessionTransition(from.String(), to.String())
+	if s.logger.Enabled() {
+		s.logger.Info("Session state changed", "component", "session", "from", from.String(), "to", to.String(), "tick", s.world.Tick)
+	}
+	for _, hook := range s.transitionHooks {
+		hook(from, to)
+	}
+}
+
+// advanceLifecycle applies the countdown and the idle timeouts at the current
+// time. Returns ErrSessionExpired if the session expired. Run calls it with the
+// lock held.
+func (s *Session) advanceLifecycle() error {
+	now := s.clock.Now()
+	switch s.state {
+	case StateExpired:
+		return ErrSessionExpired
+	case StateFinished:
+		if s.lifecycle.FinishedTimeout > 0 && now.Sub(s.stateSince) >= s.lifecycle.FinishedTimeout {
+			s.transition(StateExpired)
+			return ErrSessionExpired
+		}
+		return nil
+	case StateWaiting:
+		if s.lifecycle.JoinTimeout > 0 && now.Sub(s.stateSince) >= s.lifecycle.JoinTimeout {
+			s.transition(StateExpired)
+			return ErrSessionExpired
+		}
+	}
+
+	if s.lifecycle.IdleTimeout > 0 && now.Sub(s.lastActivity) >= s.lifecycle.IdleTimeout {
+		s.transition(StateExpired)
+		return ErrSessionExpired
+	}
+	if s.state == StateCountdown && now.Sub(s.stateSince) >= s.lifecycle.Countdown {
+		s.transition(StateRunning)
+	}
+	return nil
+}
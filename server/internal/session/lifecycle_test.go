@@ -0,0 +1,153 @@
+package session
+
+import (
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Session Lifecycle", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:session-lifecycle", "r:high"), func() {
+	var (
+		clock       *FakeClock
+		session     *Session
+		transitions [][2]State
+	)
+
+	tick := func() uint32 {
+		return session.GetWorld().Tick
+	}
+
+	// lostWorld returns a world whose only ship is inside the sun, so it is lost at the first tick
+	lostWorld := func() entities.World {
+		ship := entities.NewShip(entities.NewVec2(10.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 100.0)
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		return entities.NewWorld(ship, sun, nil)
+	}
+
+	BeforeEach(func() {
+		observability.InitMetrics()
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+		transitions = nil
+		session.OnTransition(func(from, to State) {
+			transitions = append(transitions, [2]State{from, to})
+		})
+	})
+
+	It("starts running and never expires without a lifecycle", func() {
+		Expect(session.State()).To(Equal(StateRunning))
+		clock.Advance(time.Hour)
+		Expect(session.Run(10)).To(Succeed())
+		Expect(session.State()).To(Equal(StateRunning))
+		Expect(tick()).To(Equal(uint32(10)))
+	})
+
+	It("counts down from Start before running, without simulating the wait", func() {
+		session.SetLifecycle(LifecycleConfig{Countdown: 3 * time.Second})
+		session.Wait()
+		clock.Advance(time.Second)
+		Expect(session.Run(100)).To(Succeed())
+		Expect(tick()).To(Equal(uint32(0)))
+
+		session.Start()
+		Expect(session.State()).To(Equal(StateCountdown))
+		clock.Advance(2 * time.Second)
+		Expect(session.Run(100)).To(Succeed())
+		Expect(tick()).To(Equal(uint32(0)))
+
+		clock.Advance(time.Second)
+		Expect(session.Run(100)).To(Succeed())
+		Expect(session.State()).To(Equal(StateRunning))
+		clock.Advance(2 * DefaultTickInterval)
+		Expect(session.Run(100)).To(Succeed())
+		Expect(tick()).To(Equal(uint32(2)))
+
+		Expect(transitions).To(Equal([][2]State{
+			{StateRunning, StateWaiting},
+			{StateWaiting, StateCountdown},
+			{StateCountdown, StateRunning},
+		}))
+		Expect(testutil.ToFloat64(observability.GetSessionTransitionsCounter().WithLabelValues("countdown", "running"))).To(Equal(1.0))
+	})
+
+	It("finishes when the world is done and stops ticking", func() {
+		session.Reset(lostWorld())
+
+		clock.Advance(5 * DefaultTickInterval)
+		Expect(session.Run(100)).To(Succeed())
+		Expect(session.State()).To(Equal(StateFinished))
+		Expect(session.GetWorld().Done).To(BeTrue())
+		Expect(transitions).To(Equal([][2]State{{StateRunning, StateFinished}}))
+
+		// Reset starts the game again
+		session.Reset(levels.Seeded(7))
+		Expect(session.State()).To(Equal(StateRunning))
+	})
+
+	It("expires sessions without input for the idle timeout", func() {
+		session.SetLifecycle(LifecycleConfig{IdleTimeout: time.Minute})
+
+		clock.Advance(50 * time.Second)
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		clock.Advance(50 * time.Second)
+		Expect(session.Run(10)).To(Succeed(), "input restarts the idle timeout")
+
+		clock.Advance(10 * time.Second)
+		Expect(session.Run(10)).To(MatchError(ErrSessionExpired))
+		Expect(session.State()).To(Equal(StateExpired))
+
+		// Expired sessions stay expired
+		session.Reset(levels.Seeded(7))
+		Expect(session.Run(10)).To(MatchError(ErrSessionExpired))
+		Expect(transitions).To(Equal([][2]State{{StateRunning, StateExpired}}))
+		Expect(testutil.ToFloat64(observability.GetSessionTransitionsCounter().WithLabelValues("running", "expired"))).To(Equal(1.0))
+	})
+
+	It("expires sessions waiting for players after the join timeout", func() {
+		session.SetLifecycle(LifecycleConfig{JoinTimeout: time.Minute})
+		session.Wait()
+
+		clock.Advance(59 * time.Second)
+		Expect(session.Run(10)).To(Succeed())
+		clock.Advance(time.Second)
+		Expect(session.Run(10)).To(MatchError(ErrSessionExpired), "without an idle timeout")
+		Expect(transitions).To(Equal([][2]State{
+			{StateRunning, StateWaiting},
+			{StateWaiting, StateExpired},
+		}))
+	})
+
+	It("does not expire running sessions after the join timeout", func() {
+		session.SetLifecycle(LifecycleConfig{JoinTimeout: time.Minute})
+		session.Wait()
+		clock.Advance(30 * time.Second)
+		session.Start()
+
+		clock.Advance(time.Minute)
+		Expect(session.Run(10)).To(Succeed())
+		Expect(session.State()).To(Equal(StateRunning))
+	})
+
+	It("expires finished sessions after the finished timeout", func() {
+		session.SetLifecycle(LifecycleConfig{IdleTimeout: time.Hour, FinishedTimeout: 30 * time.Second})
+		session.Reset(lostWorld())
+		clock.Advance(DefaultTickInterval)
+		Expect(session.Run(10)).To(Succeed())
+		Expect(session.State()).To(Equal(StateFinished))
+
+		clock.Advance(29 * time.Second)
+		Expect(session.Run(10)).To(Succeed())
+		clock.Advance(time.Second)
+		Expect(session.Run(10)).To(MatchError(ErrSessionExpired))
+		Expect(transitions).To(Equal([][2]State{
+			{StateRunning, StateFinished},
+			{StateFinished, StateExpired},
+		}))
+	})
+})
@@ -0,0 +1,44 @@
+package session
+
+// Options are the settings a server gives every session it creates, shared by
+// private sessions and rooms. Start from DefaultOptions so that unset fields get
+// usable values.
+type Options struct {
+	InvariantMode InvariantMode   // See SetInvariantMode
+	GapPolicy     GapPolicy       // See SetGapPolicy
+	BacklogPolicy BacklogPolicy   // See SetBacklogPolicy; bounds backlogs at DefaultMaxBacklog commands
+	TickRate      int             // See SetTickRate; 0 for DefaultTickRate
+	Lifecycle     LifecycleConfig // See SetLifecycle
+	Scheduler     *Scheduler      // Steps the sessions once started, nil for a run loop per session
+}
+
+// DefaultOptions returns the options of sessions with invariant checks off, the
+// GapZero and BacklogNone policies, DefaultTickRate, DefaultLifecycleConfig and a
+// run loop per session.
+func DefaultOptions() Options {
+	return Options{
+		Lifecycle: DefaultLifecycleConfig(),
+	}
+}
+
+// Validate checks that the options can be applied to sessions.
+func (o Options) Validate() error {
+	if o.TickRate != 0 {
+		return ValidateTickRate(o.TickRate)
+	}
+	return nil
+}
+
+// Apply sets the options on sess, which must not be recording yet. The scheduler
+// is left to the owner of the session, which adds it once it starts.
+// Returns an error, and leaves the tick rate unchanged, if the rate is not supported.
+func (o Options) Apply(sess *Session) error {
+	sess.SetInvariantMode(o.InvariantMode)
+	sess.SetGapPolicy(o.GapPolicy)
+	sess.SetBacklogPolicy(o.BacklogPolicy, DefaultMaxBacklog)
+	sess.SetLifecycle(o.Lifecycle)
+	if o.TickRate != 0 {
+		return sess.SetTickRate(o.TickRate)
+	}
+	return nil
+}
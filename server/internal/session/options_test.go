@@ -0,0 +1,50 @@
+package session
+
+import (
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Options", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:session-options", "r:medium"), func() {
+	It("defaults to the session defaults", func() {
+		opts := DefaultOptions()
+		Expect(opts.Validate()).To(Succeed())
+		Expect(opts.Lifecycle).To(Equal(DefaultLifecycleConfig()))
+		Expect(opts.Scheduler).To(BeNil())
+
+		sess := NewSession(NewFakeClock(), levels.Seeded(7), 100)
+		Expect(opts.Apply(sess)).To(Succeed())
+		Expect(sess.TickRate()).To(Equal(DefaultTickRate))
+	})
+
+	It("applies every setting to the session", func() {
+		opts := Options{
+			InvariantMode: InvariantsFreeze,
+			GapPolicy:     GapWait,
+			BacklogPolicy: BacklogCoalesce,
+			TickRate:      TickRate60,
+			Lifecycle:     LifecycleConfig{IdleTimeout: time.Minute},
+		}
+		sess := NewSession(NewFakeClock(), levels.Seeded(7), 100)
+		Expect(opts.Apply(sess)).To(Succeed())
+
+		Expect(sess.invariantMode).To(Equal(InvariantsFreeze))
+		Expect(sess.gapPolicy).To(Equal(GapWait))
+		Expect(sess.backlogPolicy).To(Equal(BacklogCoalesce))
+		Expect(sess.TickRate()).To(Equal(TickRate60))
+		Expect(sess.lifecycle).To(Equal(opts.Lifecycle))
+	})
+
+	It("rejects unsupported tick rates", func() {
+		opts := DefaultOptions()
+		opts.TickRate = 45
+		Expect(opts.Validate()).To(MatchError(ContainSubstring("unsupported tick rate")))
+
+		sess := NewSession(NewFakeClock(), levels.Seeded(7), 100)
+		Expect(opts.Apply(sess)).To(MatchError(ContainSubstring("unsupported tick rate")))
+		Expect(sess.TickRate()).To(Equal(DefaultTickRate))
+	})
+})
@@ -0,0 +1,92 @@
+package session
+
+import (
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Players", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:multiplayer-session", "r:high"), func() {
+	const tickInterval = DefaultTickInterval
+
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	shipAt := func(id entities.PlayerID, x float64) entities.Ship {
+		ship := entities.NewShip(entities.NewVec2(x, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 50.0)
+		ship.ID = id
+		return ship
+	}
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		pallets := []entities.Pallet{entities.NewPallet(1, entities.NewVec2(-300.0, 300.0), true)}
+		session = NewSession(clock, entities.NewWorld(shipAt("", 200), sun, pallets), 10)
+	})
+
+	It("starts with the default player", func() {
+		Expect(session.Players()).To(Equal([]entities.PlayerID{entities.DefaultPlayerID}))
+	})
+
+	Describe("AddPlayer", func() {
+		It("adds a ship and a command queue for the player", func() {
+			Expect(session.AddPlayer(shipAt("p2", -200))).To(Succeed())
+
+			Expect(session.Players()).To(Equal([]entities.PlayerID{"p1", "p2"}))
+			Expect(session.EnqueuePlayerCommand("p2", 1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		})
+
+		It("rejects a player that is already in the session", func() {
+			Expect(session.AddPlayer(shipAt(entities.DefaultPlayerID, -200))).NotTo(Succeed())
+			Expect(session.Players()).To(HaveLen(1))
+		})
+	})
+
+	Describe("RemovePlayer", func() {
+		It("removes the ship and drops queued commands", func() {
+			Expect(session.AddPlayer(shipAt("p2", -200))).To(Succeed())
+			Expect(session.EnqueuePlayerCommand("p2", 1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+
+			Expect(session.RemovePlayer("p2")).To(BeTrue())
+			Expect(session.Players()).To(Equal([]entities.PlayerID{entities.DefaultPlayerID}))
+			Expect(session.EnqueuePlayerCommand("p2", 2, rules.InputCommand{})).To(BeFalse())
+		})
+
+		It("reports unknown players", func() {
+			Expect(session.RemovePlayer("nobody")).To(BeFalse())
+		})
+	})
+
+	Describe("EnqueuePlayerCommand", func() {
+		It("rejects commands for unknown players", func() {
+			Expect(session.EnqueuePlayerCommand("nobody", 1, rules.InputCommand{})).To(BeFalse())
+		})
+
+		It("keeps sequence numbers separate per player", func() {
+			Expect(session.AddPlayer(shipAt("p2", -200))).To(Succeed())
+
+			Expect(session.EnqueueCommand(1, rules.InputCommand{})).To(BeTrue())
+			Expect(session.EnqueuePlayerCommand("p2", 1, rules.InputCommand{})).To(BeTrue())
+		})
+	})
+
+	Describe("Run", func() {
+		It("applies one command per player per tick", func() {
+			Expect(session.AddPlayer(shipAt("p2", -200))).To(Succeed())
+			session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})
+			session.EnqueueCommand(2, rules.InputCommand{Thrust: 1.0})
+
+			clock.Advance(tickInterval)
+			Expect(session.Run(1)).To(Succeed())
+
+			world := session.GetWorld()
+			Expect(world.Ships[0].Energy).To(BeNumerically("<", 50.0))
+			Expect(world.Ships[1].Energy).To(Equal(float32(50.0)))
+			Expect(session.queues[entities.DefaultPlayerID].Size()).To(Equal(1))
+		})
+	})
+})
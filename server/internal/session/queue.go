@@ -1,31 +1,36 @@
 package session
 
 import (
-	"sort"
-
 	"github.com/gorbit/orbitalrush/internal/sim/rules"
 )
 
 // QueuedCommand represents a command with its sequence number.
 type QueuedCommand struct {
 	Sequence uint32
+	Tick     uint32 // Tick the client intended the command for, 0 if untargeted
 	Command  rules.InputCommand
 }
 
 // CommandQueue is a queue that stores input commands with sequence numbers,
 // maintains ordering, and deduplicates by sequence.
+//
+// Commands are kept in a binary min-heap ordered by sequence, in a slice
+// allocated once at maxSize, so enqueueing and dequeueing never allocate.
 type CommandQueue struct {
-	commands     map[uint32]*QueuedCommand // O(1) lookup by sequence
-	ordered      []uint32                   // Sorted sequence numbers for ordering
-	maxSize      int                        // Maximum queue size
-	nextSequence uint32                    // Next expected sequence number
+	heap         []QueuedCommand // Min-heap by Sequence
+	maxSize      int             // Maximum queue size
+	maxQueued    uint32          // Highest sequence in the heap, valid while it is not empty
+	nextSequence uint32          // Next expected sequence number
 }
 
 // NewCommandQueue creates a new command queue with the specified maximum size.
+// A negative maxSize is treated as 0: the queue accepts no commands.
 func NewCommandQueue(maxSize int) *CommandQueue {
+	if maxSize < 0 {
+		maxSize = 0
+	}
 	return &CommandQueue{
-		commands:     make(map[uint32]*QueuedCommand),
-		ordered:      make([]uint32, 0),
+		heap:         make([]QueuedCommand, 0, maxSize),
 		maxSize:      maxSize,
 		nextSequence: 1, // Start at sequence 1
 	}
@@ -39,86 +44,145 @@ func NewCommandQueue(maxSize int) *CommandQueue {
 //
 // Returns true on success.
 func (q *CommandQueue) Enqueue(seq uint32, cmd rules.InputCommand) bool {
+	return q.EnqueueAt(seq, 0, cmd)
+}
+
+// EnqueueAt is Enqueue for a command the client intended for tick (see Session.EnqueuePlayerCommandAt).
+func (q *CommandQueue) EnqueueAt(seq, tick uint32, cmd rules.InputCommand) bool {
+	return q.push(QueuedCommand{Sequence: seq, Tick: tick, Command: cmd})
+}
+
+// EnqueueBatch adds commands the way Enqueue adds each of them, e.g. the
+// redundant copies of earlier commands a client repeats in every input packet.
+// Commands already processed, duplicates and commands beyond the queue's capacity
+// are skipped. Returns the number of commands added.
+func (q *CommandQueue) EnqueueBatch(cmds []QueuedCommand) int {
+	added := 0
+	for _, cmd := range cmds {
+		if q.push(cmd) {
+			added++
+		}
+	}
+	return added
+}
+
+// push adds cmd to the heap unless it was processed, is a duplicate or the queue is full.
+func (q *CommandQueue) push(cmd QueuedCommand) bool {
 	// Reject if sequence is less than nextSequence (already processed)
-	if seq < q.nextSequence {
+	if cmd.Sequence < q.nextSequence {
 		return false
 	}
 
-	// Reject if sequence already exists (duplicate)
-	if _, exists := q.commands[seq]; exists {
+	// Reject if sequence already exists (duplicate); commands arriving in order
+	// are above every queued sequence and skip the scan
+	if len(q.heap) > 0 && cmd.Sequence <= q.maxQueued && q.contains(cmd.Sequence) {
 		return false
 	}
 
 	// Reject if queue is full
-	if len(q.commands) >= q.maxSize {
+	if len(q.heap) >= q.maxSize {
 		return false
 	}
 
-	// Add command
-	queuedCmd := &QueuedCommand{
-		Sequence: seq,
-		Command:  cmd,
+	if len(q.heap) == 0 || cmd.Sequence > q.maxQueued {
+		q.maxQueued = cmd.Sequence
 	}
-	q.commands[seq] = queuedCmd
-
-	// Insert sequence into ordered slice (maintain sorted order)
-	q.ordered = append(q.ordered, seq)
-	sort.Slice(q.ordered, func(i, j int) bool {
-		return q.ordered[i] < q.ordered[j]
-	})
-
+	q.heap = append(q.heap, cmd)
+	q.siftUp(len(q.heap) - 1)
 	return true
 }
 
+// contains reports whether seq is queued.
+func (q *CommandQueue) contains(seq uint32) bool {
+	for i := range q.heap {
+		if q.heap[i].Sequence == seq {
+			return true
+		}
+	}
+	return false
+}
+
 // Dequeue removes and returns the next command in sequence order (lowest sequence first).
 // Returns false if the queue is empty.
+//
+// The command is moved to the heap slot the removal frees, so dequeueing does not
+// allocate; it stays valid until the next Enqueue, EnqueueAt or EnqueueBatch.
 func (q *CommandQueue) Dequeue() (*QueuedCommand, bool) {
-	if len(q.ordered) == 0 {
+	if len(q.heap) == 0 {
 		return nil, false
 	}
 
-	// Get the lowest sequence number
-	seq := q.ordered[0]
-
-	// Remove from ordered slice
-	q.ordered = q.ordered[1:]
-
-	// Remove from map
-	cmd := q.commands[seq]
-	delete(q.commands, seq)
+	last := len(q.heap) - 1
+	q.heap[0], q.heap[last] = q.heap[last], q.heap[0]
+	q.heap = q.heap[:last]
+	if last > 0 {
+		q.siftDown(0)
+	}
+	cmd := &q.heap[:last+1][last]
 
 	// Update nextSequence to be one more than the dequeued sequence
-	q.nextSequence = seq + 1
+	q.nextSequence = cmd.Sequence + 1
 
 	return cmd, true
 }
 
 // Peek returns the next command without removing it.
-// Returns false if the queue is empty.
+// Returns false if the queue is empty. The command stays valid until the queue changes.
 func (q *CommandQueue) Peek() (*QueuedCommand, bool) {
-	if len(q.ordered) == 0 {
+	if len(q.heap) == 0 {
 		return nil, false
 	}
+	return &q.heap[0], true
+}
 
-	seq := q.ordered[0]
-	cmd := q.commands[seq]
-	return cmd, true
+// siftUp moves the command at i up until its parent has a lower sequence.
+func (q *CommandQueue) siftUp(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if q.heap[parent].Sequence <= q.heap[i].Sequence {
+			return
+		}
+		q.heap[parent], q.heap[i] = q.heap[i], q.heap[parent]
+		i = parent
+	}
+}
+
+// siftDown moves the command at i down until its children have higher sequences.
+func (q *CommandQueue) siftDown(i int) {
+	n := len(q.heap)
+	for {
+		smallest := i
+		if left := 2*i + 1; left < n && q.heap[left].Sequence < q.heap[smallest].Sequence {
+			smallest = left
+		}
+		if right := 2*i + 2; right < n && q.heap[right].Sequence < q.heap[smallest].Sequence {
+			smallest = right
+		}
+		if smallest == i {
+			return
+		}
+		q.heap[smallest], q.heap[i] = q.heap[i], q.heap[smallest]
+		i = smallest
+	}
 }
 
 // Size returns the current number of commands in the queue.
 func (q *CommandQueue) Size() int {
-	return len(q.commands)
+	return len(q.heap)
+}
+
+// NextSequence returns the lowest sequence number the queue will still accept.
+func (q *CommandQueue) NextSequence() uint32 {
+	return q.nextSequence
 }
 
 // IsEmpty returns true if the queue is empty.
 func (q *CommandQueue) IsEmpty() bool {
-	return len(q.commands) == 0
+	return len(q.heap) == 0
 }
 
 // Clear removes all commands from the queue.
 func (q *CommandQueue) Clear() {
-	q.commands = make(map[uint32]*QueuedCommand)
-	q.ordered = make([]uint32, 0)
+	q.heap = q.heap[:0]
 	// Note: nextSequence is not reset, as it tracks what has been processed
 }
-
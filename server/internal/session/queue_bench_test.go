@@ -0,0 +1,65 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// Benchmarks of the CommandQueue in steady state: one op enqueues and dequeues
+// commands of a queue holding a few commands, as a session's queue does between
+// ticks. Run with `make bench`.
+
+// queueBenchDepth is the number of commands kept queued while benchmarking.
+const queueBenchDepth = 8
+
+// BenchmarkCommandQueueInOrder enqueues one command per op in sequence order.
+func BenchmarkCommandQueueInOrder(b *testing.B) {
+	queue := NewCommandQueue(100)
+	for seq := uint32(1); seq <= queueBenchDepth; seq++ {
+		queue.Enqueue(seq, rules.InputCommand{})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		queue.Enqueue(uint32(i)+queueBenchDepth+1, rules.InputCommand{Thrust: 1.0})
+		queue.Dequeue()
+	}
+}
+
+// BenchmarkCommandQueueOutOfOrder enqueues pairs of commands swapped.
+func BenchmarkCommandQueueOutOfOrder(b *testing.B) {
+	queue := NewCommandQueue(100)
+	for seq := uint32(1); seq <= queueBenchDepth; seq++ {
+		queue.Enqueue(seq, rules.InputCommand{})
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		seq := uint32(2*i) + queueBenchDepth + 1
+		queue.Enqueue(seq+1, rules.InputCommand{Thrust: 1.0})
+		queue.Enqueue(seq, rules.InputCommand{Thrust: 1.0})
+		queue.Dequeue()
+		queue.Dequeue()
+	}
+}
+
+// BenchmarkCommandQueueRedundant enqueues packets repeating the last 4 commands,
+// of which one is new.
+func BenchmarkCommandQueueRedundant(b *testing.B) {
+	queue := NewCommandQueue(100)
+	for seq := uint32(1); seq <= queueBenchDepth; seq++ {
+		queue.Enqueue(seq, rules.InputCommand{})
+	}
+	packet := make([]QueuedCommand, 4)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		last := uint32(i) + queueBenchDepth + 1
+		for j := range packet {
+			packet[j] = QueuedCommand{Sequence: last - uint32(j), Command: rules.InputCommand{Thrust: 1.0}}
+		}
+		queue.EnqueueBatch(packet)
+		queue.Dequeue()
+	}
+}
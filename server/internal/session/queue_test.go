@@ -279,5 +279,91 @@ var _ = Describe("Command Queue", Label("scope:unit", "loop:g3-orch", "layer:sim
 			Expect(success3).To(BeFalse())
 		})
 	})
-})
 
+	Describe("Bulk Enqueue", func() {
+		It("adds new commands and skips processed and duplicate ones", func() {
+			queue := NewCommandQueue(10)
+			queue.Enqueue(1, rules.InputCommand{Thrust: 0.1})
+			queue.Enqueue(2, rules.InputCommand{Thrust: 0.2})
+			queue.Dequeue()
+
+			// A redundant packet repeating sequences 1 and 2 along with the new 3 and 4
+			added := queue.EnqueueBatch([]QueuedCommand{
+				{Sequence: 1, Command: rules.InputCommand{Thrust: 0.1}},
+				{Sequence: 2, Command: rules.InputCommand{Thrust: 0.2}},
+				{Sequence: 4, Tick: 9, Command: rules.InputCommand{Thrust: 0.4}},
+				{Sequence: 3, Tick: 8, Command: rules.InputCommand{Thrust: 0.3}},
+			})
+			Expect(added).To(Equal(2))
+			Expect(queue.Size()).To(Equal(3))
+
+			for _, seq := range []uint32{2, 3, 4} {
+				cmd, ok := queue.Dequeue()
+				Expect(ok).To(BeTrue())
+				Expect(cmd.Sequence).To(Equal(seq))
+			}
+			Expect(queue.IsEmpty()).To(BeTrue())
+		})
+
+		It("stops adding once the queue is full", func() {
+			queue := NewCommandQueue(2)
+			added := queue.EnqueueBatch([]QueuedCommand{{Sequence: 1}, {Sequence: 2}, {Sequence: 3}})
+			Expect(added).To(Equal(2))
+			Expect(queue.Size()).To(Equal(2))
+		})
+	})
+
+	Describe("Heap Ordering", func() {
+		It("dequeues any insertion order by sequence", func() {
+			queue := NewCommandQueue(64)
+			// 37 is coprime with 64, so this visits every sequence 1..64 out of order
+			for i := uint32(0); i < 64; i++ {
+				Expect(queue.Enqueue(i*37%64+1, rules.InputCommand{})).To(BeTrue())
+			}
+			for seq := uint32(1); seq <= 64; seq++ {
+				peeked, _ := queue.Peek()
+				head := *peeked
+				cmd, ok := queue.Dequeue()
+				Expect(ok).To(BeTrue())
+				Expect(cmd.Sequence).To(Equal(seq))
+				Expect(*cmd).To(Equal(head))
+			}
+		})
+
+		It("keeps dequeued commands valid until the next enqueue", func() {
+			queue := NewCommandQueue(10)
+			for seq := uint32(1); seq <= 3; seq++ {
+				queue.Enqueue(seq, rules.InputCommand{Thrust: float32(seq) / 10})
+			}
+			first, _ := queue.Dequeue()
+			second, _ := queue.Dequeue()
+			third, _ := queue.Dequeue()
+			Expect([]uint32{first.Sequence, second.Sequence, third.Sequence}).To(Equal([]uint32{1, 2, 3}))
+			Expect(first.Command.Thrust).To(Equal(float32(0.1)))
+		})
+
+		It("treats a negative maximum size as an empty capacity", func() {
+			queue := NewCommandQueue(-1)
+			Expect(queue.Enqueue(1, rules.InputCommand{})).To(BeFalse())
+			Expect(queue.IsEmpty()).To(BeTrue())
+		})
+
+		It("does not allocate once created", func() {
+			queue := NewCommandQueue(100)
+			batch := []QueuedCommand{{Sequence: 1}, {Sequence: 2}, {Sequence: 3}}
+			seq := uint32(0)
+			allocs := testing.AllocsPerRun(100, func() {
+				seq += 3
+				queue.Enqueue(seq+2, rules.InputCommand{})
+				queue.Enqueue(seq+1, rules.InputCommand{})
+				queue.Enqueue(seq+1, rules.InputCommand{})
+				batch[0].Sequence, batch[1].Sequence, batch[2].Sequence = seq+1, seq+2, seq+3
+				queue.EnqueueBatch(batch)
+				for !queue.IsEmpty() {
+					queue.Dequeue()
+				}
+			})
+			Expect(allocs).To(BeZero())
+		})
+	})
+})
@@ -0,0 +1,52 @@
+package session
+
+import (
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+)
+
+// EnqueuePlayerCommands adds the commands of one input packet to the queue of
+// player id. The last command is the newest input; the commands before it are
+// redundant copies of inputs the client sent before, oldest first, so that an
+// input whose own packet was lost or delayed still arrives in time. Each command
+// is deduplicated like EnqueuePlayerCommand: copies of inputs already queued or
+// applied (also before the rollback history) are skipped, and late ones that
+// were never applied are rolled back if rollback is enabled.
+//
+// Returns whether the newest command was added and how many of the redundant
+// copies were (the useful ones), counted in input_redundancy_total.
+// Unknown players add nothing.
+func (s *Session) EnqueuePlayerCommands(id entities.PlayerID, cmds []QueuedCommand) (bool, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue, ok := s.queues[id]
+	if !ok || len(cmds) == 0 {
+		return false, 0
+	}
+	redundant, newest := cmds[:len(cmds)-1], cmds[len(cmds)-1]
+
+	// Copies of processed inputs can only be rolled back; the queue rejects them.
+	// Most were applied, and only the ones that never were are late.
+	useful := 0
+	if s.rollbackWindow > 0 {
+		for _, cmd := range redundant {
+			if cmd.Sequence < queue.NextSequence() && !s.applied(id, cmd.Sequence) && s.rollback(id, cmd.Sequence, cmd.Command) {
+				useful++
+			}
+		}
+	}
+	useful += queue.EnqueueBatch(redundant)
+
+	success := queue.EnqueueAt(newest.Sequence, newest.Tick, newest.Command)
+	if !success && newest.Sequence < queue.NextSequence() && s.rollbackWindow > 0 {
+		success = s.rollback(id, newest.Sequence, newest.Command)
+	}
+
+	observability.RecordRedundantInputs(useful, len(redundant)-useful)
+	if success || useful > 0 {
+		s.touch()
+	}
+	s.observeQueue(id, queue)
+	return success, useful
+}
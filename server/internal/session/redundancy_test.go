@@ -0,0 +1,120 @@
+package session
+
+import (
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Input Redundancy", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:input-redundancy", "r:high"), func() {
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	input := func(seq uint32) rules.InputCommand {
+		return rules.InputCommand{Thrust: float32(seq) / 10, Turn: 0.5}
+	}
+
+	// packet returns the commands of an input packet carrying seqs from to last
+	packet := func(from, last uint32) []QueuedCommand {
+		var cmds []QueuedCommand
+		for seq := from; seq <= last; seq++ {
+			cmds = append(cmds, QueuedCommand{Sequence: seq, Command: input(seq)})
+		}
+		return cmds
+	}
+
+	redundancy := func(result string) float64 {
+		return testutil.ToFloat64(observability.GetInputRedundancyCounter().WithLabelValues(result))
+	}
+
+	runTick := func() {
+		clock.Advance(DefaultTickInterval)
+		ExpectWithOffset(1, session.Run(1)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		observability.InitMetrics()
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+	})
+
+	It("adds the newest input and the copies of inputs that were lost", func() {
+		Expect(session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(1, 1))).To(BeTrue())
+		newest, useful := session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(1, 2))
+		Expect(newest).To(BeTrue())
+		Expect(useful).To(Equal(0))
+
+		// The packet with sequence 3 was lost; the next one repeats it
+		newest, useful = session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(2, 4))
+		Expect(newest).To(BeTrue())
+		Expect(useful).To(Equal(1))
+		Expect(session.queues[entities.DefaultPlayerID].Size()).To(Equal(4))
+
+		// A packet that arrives twice adds nothing
+		newest, useful = session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(2, 4))
+		Expect(newest).To(BeFalse())
+		Expect(useful).To(Equal(0))
+
+		Expect(redundancy("useful")).To(Equal(1.0))
+		Expect(redundancy("duplicate")).To(Equal(4.0))
+	})
+
+	It("rolls back late copies into the ticks they missed", func() {
+		session.SetRollbackWindow(DefaultRollbackWindow)
+		session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(1, 1))
+		runTick()
+		// Sequence 2 is delayed, so tick 1 runs without input and 3 overtakes it
+		runTick()
+		session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(3, 3))
+		runTick()
+
+		newest, useful := session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(2, 4))
+		Expect(newest).To(BeTrue())
+		Expect(useful).To(Equal(1))
+		runTick()
+
+		referenceClock := NewFakeClock()
+		reference := NewSession(referenceClock, levels.Seeded(7), 100)
+		for seq := uint32(1); seq <= 4; seq++ {
+			Expect(reference.EnqueueCommand(seq, input(seq))).To(BeTrue())
+		}
+		referenceClock.Advance(4 * DefaultTickInterval)
+		Expect(reference.Run(4)).To(Succeed())
+		Expect(session.GetWorld()).To(Equal(reference.GetWorld()))
+		Expect(redundancy("useful")).To(Equal(1.0))
+		Expect(redundancy("duplicate")).To(Equal(1.0))
+	})
+
+	It("does not roll back copies of inputs applied before the window", func() {
+		session.SetRollbackWindow(2)
+		session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(1, 1))
+		runTick()
+		runTick()
+		runTick()
+		session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(1, 2))
+		runTick()
+		world := session.GetWorld()
+
+		// Sequence 1 left the window, which still has an idle tick before sequence 2
+		newest, useful := session.EnqueuePlayerCommands(entities.DefaultPlayerID, packet(1, 3))
+		Expect(newest).To(BeTrue())
+		Expect(useful).To(Equal(0))
+		Expect(session.GetWorld()).To(Equal(world))
+		Expect(redundancy("useful")).To(Equal(0.0))
+		Expect(redundancy("duplicate")).To(Equal(3.0))
+	})
+
+	It("adds nothing for unknown players and empty packets", func() {
+		newest, useful := session.EnqueuePlayerCommands("p9", packet(1, 2))
+		Expect(newest).To(BeFalse())
+		Expect(useful).To(Equal(0))
+		newest, _ = session.EnqueuePlayerCommands(entities.DefaultPlayerID, nil)
+		Expect(newest).To(BeFalse())
+	})
+})
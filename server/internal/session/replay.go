@@ -0,0 +1,409 @@
+package session
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+)
+
+// ReplayVersion is the version of the replay format. Replays of another version are not played.
+const ReplayVersion = 1
+
+// Replay is the recording of a session: the world and rules it started from and
+// every input its run loop applied. The simulation is deterministic, so playing
+// the replay through rules.StepPlayers reproduces the session's world exactly.
+//
+// Ticks without events or non-zero inputs have no frame, so idle stretches cost nothing.
+type Replay struct {
+	Version int `json:"version"`
+	// Level and Seed describe the level the world came from (informational)
+	Level string `json:"level,omitempty"`
+	Seed  int64  `json:"seed,omitempty"`
+	// Dt is the time step of every tick, in seconds
+	Dt    float64        `json:"dt"`
+	Rules rules.Config   `json:"rules"`
+	World entities.World `json:"world"`
+	// Frames are the ticks with events or inputs, in tick order
+	Frames []ReplayFrame `json:"frames"`
+	// EndTick is the world tick when the recording ended
+	EndTick uint32 `json:"end_tick"`
+}
+
+// ReplayFrame is what happened at one tick: the changes made to the session
+// before the tick was stepped (rules, then players leaving, then players joining),
+// then the inputs the step applied. Players without an input got the zero command.
+type ReplayFrame struct {
+	Tick   uint32                                   `json:"tick"`
+	Rules  *rules.Config                            `json:"rules,omitempty"`
+	Joined []entities.Ship                          `json:"joined,omitempty"`
+	Left   []entities.PlayerID                      `json:"left,omitempty"`
+	Inputs map[entities.PlayerID]rules.InputCommand `json:"inputs,omitempty"`
+}
+
+// StartRecording starts recording the session from its current world and rules,
+// replacing any recording in progress. level and seed are stored in the replay
+// to describe where the world came from.
+// Reset restarts the recording from the reset world.
+func (s *Session) StartRecording(level string, seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recording = &Replay{
+		Version: ReplayVersion,
+		Level:   level,
+		Seed:    seed,
+		Dt:      s.dt,
+		Rules:   s.rulesConfig(),
+		World:   copyWorld(s.world),
+		EndTick: s.world.Tick,
+	}
+}
+
+// Recording returns a copy of the recording so far, or false if the session is not recording.
+func (s *Session) Recording() (Replay, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.recording == nil {
+		return Replay{}, false
+	}
+	return s.recording.copy(), true
+}
+
+// StopRecording ends the recording and returns it, or false if the session was not recording.
+func (s *Session) StopRecording() (Replay, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.recording == nil {
+		return Replay{}, false
+	}
+	replay := *s.recording
+	s.recording = nil
+	return replay, true
+}
+
+// recordJoin records that ship joined before the current tick. Callers hold s.mu.
+func (s *Session) recordJoin(ship entities.Ship) {
+	if s.recording == nil {
+		return
+	}
+	frame := s.frame()
+	frame.Joined = append(frame.Joined, ship)
+}
+
+// recordLeave records that player id left before the current tick. Callers hold s.mu.
+func (s *Session) recordLeave(id entities.PlayerID) {
+	if s.recording == nil {
+		return
+	}
+	frame := s.frame()
+	// A player who joined in the same frame never took part in a step
+	for i, ship := range frame.Joined {
+		if ship.ID == id {
+			frame.Joined = append(frame.Joined[:i], frame.Joined[i+1:]...)
+			return
+		}
+	}
+	frame.Left = append(frame.Left, id)
+}
+
+// recordRules records a rules change before the current tick. Callers hold s.mu.
+func (s *Session) recordRules(cfg rules.Config) {
+	if s.recording == nil {
+		return
+	}
+	s.frame().Rules = &cfg
+}
+
+// restartRecording restarts the recording from the current world. Callers hold s.mu.
+func (s *Session) restartRecording() {
+	if s.recording == nil {
+		return
+	}
+	s.recording = &Replay{
+		Version: ReplayVersion,
+		Level:   s.recording.Level,
+		Seed:    s.recording.Seed,
+		Dt:      s.dt,
+		Rules:   s.rulesConfig(),
+		World:   copyWorld(s.world),
+		EndTick: s.world.Tick,
+	}
+}
+
+// frame returns the frame of the current tick, adding it if needed. Callers hold s.mu.
+func (s *Session) frame() *ReplayFrame {
+	frames := s.recording.Frames
+	if n := len(frames); n > 0 && frames[n-1].Tick == s.world.Tick {
+		return &frames[n-1]
+	}
+	s.recording.Frames = append(frames, ReplayFrame{Tick: s.world.Tick})
+	return &s.recording.Frames[len(s.recording.Frames)-1]
+}
+
+// recordStep records the inputs of the step from tick to the current tick. Callers hold s.mu.
+func (s *Session) recordStep(tick uint32, inputs map[entities.PlayerID]rules.InputCommand) {
+	if s.recording == nil {
+		return
+	}
+
+	var recorded map[entities.PlayerID]rules.InputCommand
+	for id, cmd := range inputs {
+		if cmd == (rules.InputCommand{}) {
+			continue // Same as no input
+		}
+		if recorded == nil {
+			recorded = make(map[entities.PlayerID]rules.InputCommand, len(inputs))
+		}
+		recorded[id] = cmd
+	}
+
+	frames := s.recording.Frames
+	pending := len(frames) > 0 && frames[len(frames)-1].Tick == tick
+	if recorded != nil || pending {
+		if !pending {
+			s.recording.Frames = append(frames, ReplayFrame{Tick: tick})
+		}
+		s.recording.Frames[len(s.recording.Frames)-1].Inputs = recorded
+	}
+	s.recording.EndTick = s.world.Tick
+}
+
+// recordLateInput records the input of player id that a rollback applied at tick,
+// after the world was re-simulated. Callers hold s.mu.
+func (s *Session) recordLateInput(tick uint32, id entities.PlayerID, cmd rules.InputCommand) {
+	if s.recording == nil {
+		return
+	}
+
+	frames := s.recording.Frames
+	if s.world.Done {
+		// The game now ends earlier, the inputs of later ticks were never applied
+		end := sort.Search(len(frames), func(i int) bool { return frames[i].Tick >= s.world.Tick })
+		frames = frames[:end]
+	}
+	if cmd != (rules.InputCommand{}) {
+		i := sort.Search(len(frames), func(i int) bool { return frames[i].Tick >= tick })
+		if i == len(frames) || frames[i].Tick != tick {
+			frames = append(frames, ReplayFrame{})
+			copy(frames[i+1:], frames[i:])
+			frames[i] = ReplayFrame{Tick: tick}
+		}
+		inputs := make(map[entities.PlayerID]rules.InputCommand, len(frames[i].Inputs)+1)
+		for player, input := range frames[i].Inputs {
+			inputs[player] = input
+		}
+		inputs[id] = cmd
+		frames[i].Inputs = inputs
+	}
+	s.recording.Frames = frames
+	s.recording.EndTick = s.world.Tick
+}
+
+// copy returns a copy of the replay that shares no memory with r.
+func (r *Replay) copy() Replay {
+	replay := *r
+	replay.World = copyWorld(r.World)
+	replay.Frames = make([]ReplayFrame, len(r.Frames))
+	for i, frame := range r.Frames {
+		replay.Frames[i] = frame
+		replay.Frames[i].Joined = append([]entities.Ship(nil), frame.Joined...)
+		replay.Frames[i].Left = append([]entities.PlayerID(nil), frame.Left...)
+		if frame.Inputs != nil {
+			replay.Frames[i].Inputs = make(map[entities.PlayerID]rules.InputCommand, len(frame.Inputs))
+			for id, cmd := range frame.Inputs {
+				replay.Frames[i].Inputs[id] = cmd
+			}
+		}
+	}
+	return replay
+}
+
+// Play re-simulates the replay and returns the world at EndTick.
+// Returns an error if the replay has another version, invalid rules, or a frame
+// that cannot be applied (e.g. a player joining twice).
+func (r Replay) Play() (entities.World, error) {
+	player, err := NewReplayPlayer(r, DefaultKeyframeInterval)
+	if err != nil {
+		return entities.World{}, err
+	}
+	for !player.Done() {
+		if err := player.Step(); err != nil {
+			return entities.World{}, err
+		}
+	}
+	return player.World(), nil
+}
+
+// DefaultKeyframeInterval is the default number of ticks between the keyframes
+// of a ReplayPlayer (10 seconds at 30 Hz).
+const DefaultKeyframeInterval = 300
+
+// ReplayPlayer re-simulates a replay one tick at a time. While playing it keeps
+// a keyframe every keyframe interval ticks, so seeking re-simulates at most one
+// interval instead of the whole replay.
+//
+// The world at a tick includes the frame changes made before that tick's step.
+// ReplayPlayer is not safe for concurrent use.
+type ReplayPlayer struct {
+	replay   Replay
+	interval uint32
+	cursor   replayCursor
+	// keyframes are cursors at StartTick + k*interval, in tick order
+	keyframes []replayCursor
+}
+
+// replayCursor is the playback state at one tick.
+type replayCursor struct {
+	world  entities.World
+	cfg    rules.Config
+	inputs map[entities.PlayerID]rules.InputCommand // Inputs of the next step
+	next   int                                      // Index of the next frame to apply
+}
+
+// NewReplayPlayer creates a player positioned at the start of replay.
+// keyframeInterval is the number of ticks between keyframes (DefaultKeyframeInterval if <= 0).
+// Returns an error if the replay has another version, invalid rules or time step,
+// or a first frame that cannot be applied.
+func NewReplayPlayer(replay Replay, keyframeInterval int) (*ReplayPlayer, error) {
+	if replay.Version != ReplayVersion {
+		return nil, fmt.Errorf("unsupported replay version %d (expected %d)", replay.Version, ReplayVersion)
+	}
+	if err := replay.Rules.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid replay rules: %w", err)
+	}
+	if replay.Dt <= 0 {
+		return nil, fmt.Errorf("invalid replay dt: must be > 0, got %g", replay.Dt)
+	}
+	if keyframeInterval <= 0 {
+		keyframeInterval = DefaultKeyframeInterval
+	}
+
+	p := &ReplayPlayer{
+		replay:   replay,
+		interval: uint32(keyframeInterval),
+		cursor:   replayCursor{world: copyWorld(replay.World), cfg: replay.Rules},
+	}
+	if err := p.arrive(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// World returns a copy of the world at the current tick.
+func (p *ReplayPlayer) World() entities.World {
+	return copyWorld(p.cursor.world)
+}
+
+// Tick returns the current tick.
+func (p *ReplayPlayer) Tick() uint32 {
+	return p.cursor.world.Tick
+}
+
+// StartTick returns the first tick of the replay.
+func (p *ReplayPlayer) StartTick() uint32 {
+	return p.replay.World.Tick
+}
+
+// EndTick returns the last tick of the replay.
+func (p *ReplayPlayer) EndTick() uint32 {
+	return p.replay.EndTick
+}
+
+// Done returns true if the player is at the end of the replay (EndTick, or a finished game).
+func (p *ReplayPlayer) Done() bool {
+	return p.cursor.world.Tick >= p.replay.EndTick || p.cursor.world.Done
+}
+
+// Step advances the player by one tick. Stepping a done player does nothing.
+// Returns an error if the next frame cannot be applied.
+func (p *ReplayPlayer) Step() error {
+	if p.Done() {
+		return nil
+	}
+	p.cursor.world = rules.StepPlayers(p.cursor.world, p.cursor.inputs, p.replay.Dt, p.cursor.cfg)
+	return p.arrive()
+}
+
+// Seek moves the player to tick, clamped to [StartTick, EndTick]. It restarts
+// from the closest keyframe at or before tick when that is closer than the
+// current tick, then steps forward. A game that finished before tick stops the
+// player at the finishing tick.
+// Returns an error if a frame cannot be applied.
+func (p *ReplayPlayer) Seek(tick uint32) error {
+	if tick < p.StartTick() {
+		tick = p.StartTick()
+	}
+	if tick > p.EndTick() {
+		tick = p.EndTick()
+	}
+
+	// Keyframe k is at StartTick + k*interval
+	k := int((tick - p.StartTick()) / p.interval)
+	if k >= len(p.keyframes) {
+		k = len(p.keyframes) - 1
+	}
+	if keyframe := p.keyframes[k]; tick < p.Tick() || keyframe.world.Tick > p.Tick() {
+		p.cursor = keyframe.copy()
+	}
+
+	for p.Tick() < tick && !p.Done() {
+		if err := p.Step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arrive applies the frame of the current tick, if any, and keeps a keyframe on
+// keyframe ticks that were not reached before.
+func (p *ReplayPlayer) arrive() error {
+	c := &p.cursor
+	c.inputs = nil
+	if c.next < len(p.replay.Frames) && p.replay.Frames[c.next].Tick == c.world.Tick {
+		frame := p.replay.Frames[c.next]
+		c.next++
+
+		var err error
+		if c.world, err = frame.apply(c.world); err != nil {
+			return err
+		}
+		if frame.Rules != nil {
+			c.cfg = *frame.Rules
+		}
+		c.inputs = frame.Inputs
+	}
+
+	offset := c.world.Tick - p.StartTick()
+	if offset%p.interval == 0 && int(offset/p.interval) == len(p.keyframes) {
+		p.keyframes = append(p.keyframes, c.copy())
+	}
+	return nil
+}
+
+// copy returns a copy of the cursor whose world shares no memory with c.
+// Inputs are never modified, so they are shared.
+func (c replayCursor) copy() replayCursor {
+	c.world = copyWorld(c.world)
+	return c
+}
+
+// apply applies the frame's player changes to world.
+func (f ReplayFrame) apply(world entities.World) (entities.World, error) {
+	for _, id := range f.Left {
+		var ok bool
+		if world, ok = world.RemoveShip(id); !ok {
+			return entities.World{}, fmt.Errorf("invalid replay frame at tick %d: unknown player %q", f.Tick, id)
+		}
+	}
+	for _, ship := range f.Joined {
+		var err error
+		if world, err = world.AddShip(ship); err != nil {
+			return entities.World{}, fmt.Errorf("invalid replay frame at tick %d: %w", f.Tick, err)
+		}
+	}
+	return world, nil
+}
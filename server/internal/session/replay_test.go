@@ -0,0 +1,214 @@
+package session
+
+import (
+	"encoding/json"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Replay", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:replay-recording", "r:high"), func() {
+	const tickInterval = DefaultTickInterval
+
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+	})
+
+	runTicks := func(n int) {
+		for i := 0; i < n; i++ {
+			clock.Advance(tickInterval)
+			ExpectWithOffset(1, session.Run(1)).To(Succeed())
+		}
+	}
+
+	// replayed plays the recording after a JSON round trip
+	replayed := func() entities.World {
+		replay, ok := session.Recording()
+		ExpectWithOffset(1, ok).To(BeTrue())
+		data, err := json.Marshal(replay)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		var decoded Replay
+		ExpectWithOffset(1, json.Unmarshal(data, &decoded)).To(Succeed())
+
+		world, err := decoded.Play()
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return world
+	}
+
+	It("does not record by default", func() {
+		runTicks(3)
+		_, ok := session.Recording()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("reproduces the session's world from the recorded inputs", func() {
+		session.StartRecording("seeded", 7)
+		for seq := uint32(1); seq <= 40; seq++ {
+			Expect(session.EnqueueCommand(seq, rules.InputCommand{Thrust: 1.0, Turn: float32(seq%3) - 1})).To(BeTrue())
+		}
+		runTicks(60)
+
+		Expect(replayed()).To(Equal(session.GetWorld()))
+		replay, _ := session.Recording()
+		Expect(replay.Level).To(Equal("seeded"))
+		Expect(replay.Seed).To(Equal(int64(7)))
+		Expect(replay.EndTick).To(Equal(uint32(60)))
+	})
+
+	It("only stores ticks with inputs", func() {
+		session.StartRecording("seeded", 7)
+		runTicks(10)
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		Expect(session.EnqueueCommand(2, rules.InputCommand{})).To(BeTrue())
+		runTicks(10)
+
+		replay, _ := session.Recording()
+		Expect(replay.Frames).To(HaveLen(1))
+		Expect(replay.Frames[0].Tick).To(Equal(uint32(10)))
+		Expect(replayed()).To(Equal(session.GetWorld()))
+	})
+
+	It("records players joining and leaving and rules changes", func() {
+		session.StartRecording("seeded", 7)
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		runTicks(5)
+
+		Expect(session.AddPlayer(levels.SpawnShip("p2", 1))).To(Succeed())
+		Expect(session.EnqueuePlayerCommand("p2", 1, rules.InputCommand{Thrust: 0.5, Turn: 1.0})).To(BeTrue())
+		cfg := rules.DefaultConfig()
+		cfg.ThrustDrainRate = 3.0
+		Expect(session.SetRulesConfig(cfg)).To(Succeed())
+		runTicks(5)
+
+		Expect(session.RemovePlayer(entities.DefaultPlayerID)).To(BeTrue())
+		runTicks(5)
+		Expect(session.AddPlayer(levels.SpawnShip("p3", 2))).To(Succeed())
+
+		Expect(replayed()).To(Equal(session.GetWorld()))
+	})
+
+	It("restarts the recording when the session is reset", func() {
+		session.StartRecording("seeded", 7)
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		runTicks(5)
+
+		session.Reset(levels.Standard())
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Turn: 1.0})).To(BeTrue())
+		runTicks(5)
+
+		replay, _ := session.Recording()
+		Expect(replay.World.Tick).To(Equal(uint32(0)))
+		Expect(replay.World.Pallets).To(Equal(levels.Standard().Pallets))
+		Expect(replay.Level).To(Equal("seeded"))
+		Expect(replayed()).To(Equal(session.GetWorld()))
+	})
+
+	It("returns the recording when it stops", func() {
+		session.StartRecording("seeded", 7)
+		runTicks(3)
+
+		replay, ok := session.StopRecording()
+		Expect(ok).To(BeTrue())
+		Expect(replay.EndTick).To(Equal(uint32(3)))
+
+		runTicks(3)
+		_, ok = session.Recording()
+		Expect(ok).To(BeFalse())
+		_, ok = session.StopRecording()
+		Expect(ok).To(BeFalse())
+	})
+
+	Describe("ReplayPlayer", func() {
+		var replay Replay
+
+		// worldAt plays the replay from the start up to tick
+		worldAt := func(tick uint32) entities.World {
+			player, err := NewReplayPlayer(replay, 0)
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			for player.Tick() < tick {
+				ExpectWithOffset(1, player.Step()).To(Succeed())
+			}
+			return player.World()
+		}
+
+		BeforeEach(func() {
+			session.StartRecording("seeded", 7)
+			for seq := uint32(1); seq <= 30; seq++ {
+				Expect(session.EnqueueCommand(seq, rules.InputCommand{Thrust: 0.5, Turn: 1.0})).To(BeTrue())
+			}
+			runTicks(10)
+			Expect(session.AddPlayer(levels.SpawnShip("p2", 3))).To(Succeed())
+			runTicks(40)
+			replay, _ = session.Recording()
+		})
+
+		It("steps through the replay to the session's world", func() {
+			player, err := NewReplayPlayer(replay, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(player.StartTick()).To(Equal(uint32(0)))
+			Expect(player.EndTick()).To(Equal(uint32(50)))
+
+			for !player.Done() {
+				Expect(player.Step()).To(Succeed())
+			}
+			Expect(player.World()).To(Equal(session.GetWorld()))
+			Expect(player.Step()).To(Succeed())
+			Expect(player.Tick()).To(Equal(uint32(50)))
+		})
+
+		It("seeks backwards and forwards to the same worlds as playing", func() {
+			player, err := NewReplayPlayer(replay, 8)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, tick := range []uint32{45, 12, 10, 30, 3, 50, 0} {
+				Expect(player.Seek(tick)).To(Succeed())
+				Expect(player.Tick()).To(Equal(tick))
+				Expect(player.World()).To(Equal(worldAt(tick)), "tick %d", tick)
+			}
+		})
+
+		It("restarts seeks from the closest keyframe", func() {
+			player, err := NewReplayPlayer(replay, 8)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(player.Seek(50)).To(Succeed())
+			Expect(player.keyframes).To(HaveLen(7)) // Ticks 0, 8, ..., 48
+
+			// Seeking back to 20 resumes from the keyframe at 16 and adds no keyframes
+			Expect(player.Seek(20)).To(Succeed())
+			Expect(player.World()).To(Equal(worldAt(20)))
+			Expect(player.keyframes).To(HaveLen(7))
+			Expect(player.keyframes[2].world.Tick).To(Equal(uint32(16)))
+		})
+
+		It("clamps seeks to the replay", func() {
+			player, err := NewReplayPlayer(replay, 0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(player.Seek(1000)).To(Succeed())
+			Expect(player.Tick()).To(Equal(uint32(50)))
+			Expect(player.Done()).To(BeTrue())
+		})
+	})
+
+	It("rejects replays of another version or with invalid rules", func() {
+		session.StartRecording("seeded", 7)
+		replay, _ := session.Recording()
+
+		replay.Version = ReplayVersion + 1
+		_, err := replay.Play()
+		Expect(err).To(MatchError(ContainSubstring("unsupported replay version")))
+
+		replay.Version = ReplayVersion
+		replay.Rules.MaxEnergy = 0
+		_, err = replay.Play()
+		Expect(err).To(MatchError(ContainSubstring("invalid replay rules")))
+	})
+})
@@ -3,14 +3,20 @@ package session
 import (
 	"time"
 
+	"github.com/gorbit/orbitalrush/internal/observability"
 	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
 )
 
+// DefaultRollbackWindow is the rollback window the server gives its sessions:
+// 8 ticks (about 270ms at 30Hz) of late input are rolled back.
+const DefaultRollbackWindow = 8
+
 // Snapshot represents a captured state of the game world at a specific point in time.
 type Snapshot struct {
-	World    entities.World
-	Tick     uint32
-	Time     time.Time
+	World entities.World
+	Tick  uint32
+	Time  time.Time
 }
 
 // RollbackHook is an interface for components that need to react to rollback events.
@@ -24,18 +30,88 @@ type RollbackHook interface {
 	AfterRestore(snapshot *Snapshot)
 }
 
+// DefaultSnapshotDepth is the number of snapshots a new SnapshotManager keeps (see SetDepth).
+const DefaultSnapshotDepth = 64
+
+// snapshotKeyframeInterval is the number of snapshots stored as deltas after each keyframe.
+const snapshotKeyframeInterval = 16
+
 // SnapshotManager manages state snapshots for rollback functionality.
+//
+// Snapshots are kept in a ring buffer of bounded depth, and optionally age, in
+// capture order; the oldest are evicted first. Lookups by tick are O(1). Every
+// snapshotKeyframeInterval-th snapshot is a keyframe, a full world copy; the
+// others only store the ships and the pallets that differ from the keyframe, so
+// the pallets of a level are copied once per keyframe rather than every tick.
 type SnapshotManager struct {
-	snapshots map[uint32]*Snapshot
-	hooks     []RollbackHook
+	ring   []storedSnapshot // Circular buffer, oldest at head
+	head   int              // Position of the oldest snapshot
+	count  int              // Number of stored snapshots
+	index  map[uint32]int   // Position in ring of each tick's snapshot
+	maxAge time.Duration    // Snapshots older than the newest by more than maxAge are evicted, 0 for no limit
+
+	keyframe      *entities.World // Keyframe new snapshots are stored against
+	sinceKeyframe int             // Snapshots stored against keyframe
+
+	hooks   []RollbackHook
+	onEvict []func(snapshot *Snapshot)
 }
 
-// NewSnapshotManager creates a new snapshot manager.
+// storedSnapshot is a snapshot as stored by SnapshotManager: a keyframe, or the
+// changes of the world from its keyframe.
+type storedSnapshot struct {
+	tick       uint32
+	time       time.Time
+	base       *entities.World // Keyframe; the snapshot's world if isKeyframe
+	isKeyframe bool
+
+	ships   []entities.Ship
+	pallets []palletChange // Pallets that differ from base
+	sun     entities.Sun
+	world   uint32 // World tick
+	done    bool
+	win     bool
+}
+
+// palletChange is a pallet that differs from the keyframe.
+type palletChange struct {
+	index  int
+	pallet entities.Pallet
+}
+
+// NewSnapshotManager creates a new snapshot manager that keeps DefaultSnapshotDepth snapshots.
 func NewSnapshotManager() *SnapshotManager {
 	return &SnapshotManager{
-		snapshots: make(map[uint32]*Snapshot),
-		hooks:     make([]RollbackHook, 0),
+		ring:  make([]storedSnapshot, DefaultSnapshotDepth),
+		index: make(map[uint32]int),
+		hooks: make([]RollbackHook, 0),
+	}
+}
+
+// SetDepth sets the number of snapshots kept (at least 1), evicting the oldest
+// snapshots beyond it.
+func (sm *SnapshotManager) SetDepth(depth int) {
+	if depth < 1 {
+		depth = 1
+	}
+	for sm.count > depth {
+		sm.evictOldest()
 	}
+
+	ring := make([]storedSnapshot, depth)
+	for i := 0; i < sm.count; i++ {
+		ring[i] = sm.ring[(sm.head+i)%len(sm.ring)]
+		sm.index[ring[i].tick] = i
+	}
+	sm.ring = ring
+	sm.head = 0
+}
+
+// SetMaxAge sets the maximum age of snapshots relative to the newest one (by
+// capture time); older snapshots are evicted on the next capture. 0 disables
+// the age limit (the default).
+func (sm *SnapshotManager) SetMaxAge(age time.Duration) {
+	sm.maxAge = age
 }
 
 // RegisterHook registers a rollback hook that will be called during snapshot operations.
@@ -43,13 +119,20 @@ func (sm *SnapshotManager) RegisterHook(hook RollbackHook) {
 	sm.hooks = append(sm.hooks, hook)
 }
 
+// OnEvict registers a callback called with every snapshot evicted by the depth
+// or age limit or removed by DiscardBefore. ClearSnapshots does not call it.
+func (sm *SnapshotManager) OnEvict(callback func(snapshot *Snapshot)) {
+	sm.onEvict = append(sm.onEvict, callback)
+}
+
 // CaptureSnapshot captures a snapshot of the world state at the current tick.
+// A snapshot already stored for tick is replaced.
 // Returns a snapshot that can be used to restore the world state later.
 func (sm *SnapshotManager) CaptureSnapshot(world entities.World, tick uint32, clock Clock) *Snapshot {
 	snapshot := &Snapshot{
 		World: copyWorld(world),
-		Tick:   tick,
-		Time:   clock.Now(),
+		Tick:  tick,
+		Time:  clock.Now(),
 	}
 
 	// Call hooks before snapshot
@@ -58,7 +141,23 @@ func (sm *SnapshotManager) CaptureSnapshot(world entities.World, tick uint32, cl
 	}
 
 	// Store snapshot
-	sm.snapshots[tick] = snapshot
+	stored := sm.encode(snapshot)
+	if pos, exists := sm.index[tick]; exists {
+		sm.ring[pos] = stored
+	} else {
+		if sm.count == len(sm.ring) {
+			sm.evictOldest()
+		}
+		pos := (sm.head + sm.count) % len(sm.ring)
+		sm.ring[pos] = stored
+		sm.index[tick] = pos
+		sm.count++
+	}
+	if sm.maxAge > 0 {
+		for sm.count > 1 && snapshot.Time.Sub(sm.ring[sm.head].time) > sm.maxAge {
+			sm.evictOldest()
+		}
+	}
 
 	return snapshot
 }
@@ -77,30 +176,319 @@ func (sm *SnapshotManager) RestoreSnapshot(snapshot *Snapshot) entities.World {
 
 // GetSnapshot retrieves a snapshot by tick number.
 // Returns the snapshot and true if found, nil and false otherwise.
+// The snapshot is rebuilt from storage, so modifying it does not affect the manager.
 func (sm *SnapshotManager) GetSnapshot(tick uint32) (*Snapshot, bool) {
-	snapshot, exists := sm.snapshots[tick]
-	return snapshot, exists
+	pos, exists := sm.index[tick]
+	if !exists {
+		return nil, false
+	}
+	return sm.ring[pos].decode(), true
+}
+
+// Len returns the number of stored snapshots.
+func (sm *SnapshotManager) Len() int {
+	return sm.count
+}
+
+// DiscardBefore removes the snapshots of ticks before tick.
+func (sm *SnapshotManager) DiscardBefore(tick uint32) {
+	kept := 0
+	for i := 0; i < sm.count; i++ {
+		stored := sm.ring[(sm.head+i)%len(sm.ring)]
+		if stored.tick < tick {
+			sm.evicted(stored)
+			delete(sm.index, stored.tick)
+			continue
+		}
+		pos := (sm.head + kept) % len(sm.ring)
+		sm.ring[pos] = stored
+		sm.index[stored.tick] = pos
+		kept++
+	}
+	for i := kept; i < sm.count; i++ {
+		sm.ring[(sm.head+i)%len(sm.ring)] = storedSnapshot{} // Release the keyframes
+	}
+	sm.count = kept
 }
 
 // ClearSnapshots removes all stored snapshots.
 func (sm *SnapshotManager) ClearSnapshots() {
-	sm.snapshots = make(map[uint32]*Snapshot)
+	sm.ring = make([]storedSnapshot, len(sm.ring))
+	sm.head = 0
+	sm.count = 0
+	sm.index = make(map[uint32]int)
+	sm.keyframe = nil
+	sm.sinceKeyframe = 0
+}
+
+// evictOldest removes the oldest snapshot.
+func (sm *SnapshotManager) evictOldest() {
+	stored := sm.ring[sm.head]
+	sm.ring[sm.head] = storedSnapshot{} // Release the keyframe
+	delete(sm.index, stored.tick)
+	sm.head = (sm.head + 1) % len(sm.ring)
+	sm.count--
+	sm.evicted(stored)
+}
+
+// evicted calls the eviction callbacks for stored.
+func (sm *SnapshotManager) evicted(stored storedSnapshot) {
+	if len(sm.onEvict) == 0 {
+		return
+	}
+	snapshot := stored.decode()
+	for _, callback := range sm.onEvict {
+		callback(snapshot)
+	}
+}
+
+// encode stores snapshot as a delta against the current keyframe, or as a new
+// keyframe every snapshotKeyframeInterval snapshots or when the pallets were
+// added or removed.
+func (sm *SnapshotManager) encode(snapshot *Snapshot) storedSnapshot {
+	world := snapshot.World
+	stored := storedSnapshot{tick: snapshot.Tick, time: snapshot.Time}
+	if sm.keyframe == nil || sm.sinceKeyframe >= snapshotKeyframeInterval || len(world.Pallets) != len(sm.keyframe.Pallets) {
+		keyframe := copyWorld(world)
+		sm.keyframe = &keyframe
+		sm.sinceKeyframe = 0
+		stored.base = sm.keyframe
+		stored.isKeyframe = true
+		return stored
+	}
+
+	sm.sinceKeyframe++
+	stored.base = sm.keyframe
+	stored.ships = make([]entities.Ship, len(world.Ships))
+	copy(stored.ships, world.Ships)
+	for i, pallet := range world.Pallets {
+		if pallet != sm.keyframe.Pallets[i] {
+			stored.pallets = append(stored.pallets, palletChange{index: i, pallet: pallet})
+		}
+	}
+	stored.sun = world.Sun
+	stored.world = world.Tick
+	stored.done = world.Done
+	stored.win = world.Win
+	return stored
+}
+
+// decode rebuilds the stored snapshot.
+func (s storedSnapshot) decode() *Snapshot {
+	snapshot := &Snapshot{Tick: s.tick, Time: s.time}
+	if s.isKeyframe {
+		snapshot.World = copyWorld(*s.base)
+		return snapshot
+	}
+
+	ships := make([]entities.Ship, len(s.ships))
+	copy(ships, s.ships)
+	pallets := make([]entities.Pallet, len(s.base.Pallets))
+	copy(pallets, s.base.Pallets)
+	for _, change := range s.pallets {
+		pallets[change.index] = change.pallet
+	}
+	snapshot.World = entities.World{
+		Ships:   ships,
+		Sun:     s.sun,
+		Pallets: pallets,
+		Tick:    s.world,
+		Done:    s.done,
+		Win:     s.win,
+	}
+	return snapshot
+}
+
+// tickRecord is what a session applied at one tick of its rollback history.
+type tickRecord struct {
+	tick   uint32
+	inputs map[entities.PlayerID]rules.InputCommand
+	seqs   map[entities.PlayerID]uint32 // Sequence number of each player's input
+}
+
+// SetRollbackWindow sets how many past ticks an input that arrives late can be
+// applied to. The session keeps a snapshot and the inputs of each of the last
+// ticks ticks; when a player's input arrives after later inputs of theirs were
+// applied, it is applied to the earliest tick in between that had no input from
+// the player, and the ticks since are re-simulated. 0 disables rollback (the
+// default): late inputs are rejected.
+//
+// The history starts empty and is cleared whenever players join or leave, the
+// rules change or the session is reset, so rollbacks never cross those.
+func (s *Session) SetRollbackWindow(ticks int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ticks < 0 {
+		ticks = 0
+	}
+	s.rollbackWindow = ticks
+	if ticks > 0 {
+		s.snapshots.SetDepth(ticks)
+	}
+	s.clearHistory()
+}
+
+// RegisterRollbackHook registers hook with the session's snapshots: BeforeSnapshot
+// is called for the snapshot of every tick in the rollback history, including
+// re-simulated ticks, and AfterRestore when a rollback restores a snapshot.
+// Hooks are called with the session lock held.
+func (s *Session) RegisterRollbackHook(hook RollbackHook) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.snapshots.RegisterHook(hook)
+}
+
+// recordHistory adds the inputs applied at tick to the rollback history and drops
+// the ticks that left the window. The snapshots are as deep as the window, so
+// they evict the same ticks.
+func (s *Session) recordHistory(tick uint32, inputs map[entities.PlayerID]rules.InputCommand, seqs map[entities.PlayerID]uint32) {
+	if s.rollbackWindow == 0 {
+		return
+	}
+	s.history = append(s.history, tickRecord{tick: tick, inputs: inputs, seqs: seqs})
+	if excess := len(s.history) - s.rollbackWindow; excess > 0 {
+		for _, record := range s.history[:excess] {
+			for id, seq := range record.seqs {
+				if seq > s.historyFloor[id] {
+					s.historyFloor[id] = seq
+				}
+			}
+		}
+		s.history = s.history[excess:]
+	}
+}
+
+// clearHistory empties the rollback history. Every input applied so far is then
+// before the history, so the floor of each player moves up to its queue.
+func (s *Session) clearHistory() {
+	s.history = nil
+	s.historyFloor = make(map[entities.PlayerID]uint32, len(s.queues))
+	for id, queue := range s.queues {
+		s.historyFloor[id] = queue.NextSequence() - 1
+	}
+	s.snapshots.ClearSnapshots()
+}
+
+// applied reports whether input seq of player id was applied: before the
+// rollback history or at one of its ticks.
+func (s *Session) applied(id entities.PlayerID, seq uint32) bool {
+	if seq <= s.historyFloor[id] {
+		return true
+	}
+	for _, record := range s.history {
+		if applied, ok := record.seqs[id]; ok && applied == seq {
+			return true
+		}
+	}
+	return false
+}
+
+// rollback applies input seq of player id, which arrived after later inputs of the
+// player were applied, to the earliest tick of the history between them without
+// input from the player, and re-simulates from there. Returns false, leaving the
+// session unchanged, if seq was already applied (also before the history) or
+// has no tick left to go to, or if the session froze on an invariant violation
+// (including one found while re-simulating).
+func (s *Session) rollback(id entities.PlayerID, seq uint32, cmd rules.InputCommand) bool {
+	if s.violation != nil || s.applied(id, seq) {
+		return false
+	}
+
+	// Ticks where the player's previous and next applied inputs went
+	prev, next := -1, -1
+	for i, record := range s.history {
+		applied, ok := record.seqs[id]
+		switch {
+		case !ok:
+		case applied < seq:
+			prev = i
+		case next < 0:
+			next = i
+		}
+	}
+	target := -1
+	for i := prev + 1; next >= 0 && i < next; i++ {
+		if _, ok := s.history[i].seqs[id]; !ok {
+			target = i
+			break
+		}
+	}
+	if target < 0 {
+		observability.RecordLateInput("dropped")
+		return false
+	}
+
+	snapshot, ok := s.snapshots.GetSnapshot(s.history[target].tick)
+	if !ok {
+		observability.RecordLateInput("dropped")
+		return false
+	}
+	world := s.snapshots.RestoreSnapshot(snapshot)
+
+	// Re-simulate on a copy of the history, and keep the snapshots of the new
+	// timeline until it is complete, so the session is unchanged on failure
+	history := append([]tickRecord(nil), s.history[target:]...)
+	history[0].inputs = withPlayer(history[0].inputs, id, cmd)
+	history[0].seqs = withPlayer(history[0].seqs, id, seq)
+	resimulated := make([]entities.World, 0, len(history)-1) // World before each tick after the target
+	cfg := s.rulesConfig()
+	for i, record := range history {
+		if i > 0 {
+			resimulated = append(resimulated, copyWorld(world))
+		}
+		if s.invariantMode == InvariantsOff {
+			world = rules.StepPlayers(world, record.inputs, s.dt, cfg)
+		} else {
+			before := copyWorld(world)
+			world = rules.StepPlayers(world, record.inputs, s.dt, cfg)
+			if err := s.checkStep(before, world, record.inputs); err != nil {
+				return false
+			}
+		}
+		if world.Done {
+			history = history[:i+1]
+			break
+		}
+	}
+
+	for i, before := range resimulated {
+		s.snapshots.CaptureSnapshot(before, history[i+1].tick, s.clock)
+	}
+	s.world = world
+	s.history = append(s.history[:target:target], history...)
+	s.recordLateInput(history[0].tick, id, cmd)
+	observability.RecordLateInput("rolled_back")
+	observability.ObserveRollbackTicks(len(history))
+	return true
+}
+
+// withPlayer returns a copy of m with m[id] set to value.
+func withPlayer[V any](m map[entities.PlayerID]V, id entities.PlayerID, value V) map[entities.PlayerID]V {
+	result := make(map[entities.PlayerID]V, len(m)+1)
+	for k, v := range m {
+		result[k] = v
+	}
+	result[id] = value
+	return result
 }
 
 // copyWorld creates a deep copy of a World struct.
 // This ensures that modifying the restored state doesn't affect the snapshot.
 func copyWorld(world entities.World) entities.World {
-	// Copy pallets slice
+	// Copy ships and pallets slices
+	shipsCopy := make([]entities.Ship, len(world.Ships))
+	copy(shipsCopy, world.Ships)
 	palletsCopy := make([]entities.Pallet, len(world.Pallets))
 	copy(palletsCopy, world.Pallets)
 
 	return entities.World{
-		Ship:    world.Ship,    // Ship is a struct, so this is a copy
-		Sun:     world.Sun,     // Sun is a struct, so this is a copy
-		Pallets: palletsCopy,   // Explicitly copy the slice
+		Ships:   shipsCopy,   // Explicitly copy the slice
+		Sun:     world.Sun,   // Sun is a struct, so this is a copy
+		Pallets: palletsCopy, // Explicitly copy the slice
 		Tick:    world.Tick,
 		Done:    world.Done,
 		Win:     world.Win,
 	}
 }
-
@@ -1,10 +1,14 @@
 package session
 
 import (
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -32,7 +36,7 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 			snapshot := manager.CaptureSnapshot(world, 5, clock)
 
 			// Modify world
-			world.Ship.Pos = entities.NewVec2(20.0, 10.0)
+			world.Ships[0].Pos = entities.NewVec2(20.0, 10.0)
 			world.Tick = 10
 
 			// Restore from snapshot
@@ -40,10 +44,10 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 
 			// Verify restored state matches original
 			Expect(restored.Tick).To(Equal(uint32(5)))
-			Expect(restored.Ship.Pos.X).To(Equal(10.0))
-			Expect(restored.Ship.Pos.Y).To(Equal(0.0))
-			Expect(restored.Ship.Vel.X).To(Equal(1.0))
-			Expect(restored.Ship.Energy).To(Equal(float32(100.0)))
+			Expect(restored.Ships[0].Pos.X).To(Equal(10.0))
+			Expect(restored.Ships[0].Pos.Y).To(Equal(0.0))
+			Expect(restored.Ships[0].Vel.X).To(Equal(1.0))
+			Expect(restored.Ships[0].Energy).To(Equal(float32(100.0)))
 		})
 
 		It("snapshot preserves all world state fields", func() {
@@ -72,12 +76,12 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 			Expect(restored.Tick).To(Equal(uint32(42)))
 			Expect(restored.Done).To(BeTrue())
 			Expect(restored.Win).To(BeTrue())
-			Expect(restored.Ship.Pos.X).To(Equal(10.0))
-			Expect(restored.Ship.Pos.Y).To(Equal(5.0))
-			Expect(restored.Ship.Vel.X).To(Equal(1.0))
-			Expect(restored.Ship.Vel.Y).To(Equal(2.0))
-			Expect(restored.Ship.Rot).To(Equal(1.5))
-			Expect(restored.Ship.Energy).To(Equal(float32(75.0)))
+			Expect(restored.Ships[0].Pos.X).To(Equal(10.0))
+			Expect(restored.Ships[0].Pos.Y).To(Equal(5.0))
+			Expect(restored.Ships[0].Vel.X).To(Equal(1.0))
+			Expect(restored.Ships[0].Vel.Y).To(Equal(2.0))
+			Expect(restored.Ships[0].Rot).To(Equal(1.5))
+			Expect(restored.Ships[0].Energy).To(Equal(float32(75.0)))
 			Expect(restored.Sun.Pos.X).To(Equal(0.0))
 			Expect(restored.Sun.Pos.Y).To(Equal(0.0))
 			Expect(restored.Sun.Radius).To(Equal(float32(5.0)))
@@ -105,12 +109,12 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 
 			// Restore and modify
 			restored := manager.RestoreSnapshot(snapshot)
-			restored.Ship.Pos = entities.NewVec2(999.0, 999.0)
+			restored.Ships[0].Pos = entities.NewVec2(999.0, 999.0)
 			restored.Tick = 999
 
 			// Restore again - should still have original values
 			restored2 := manager.RestoreSnapshot(snapshot)
-			Expect(restored2.Ship.Pos.X).To(Equal(10.0))
+			Expect(restored2.Ships[0].Pos.X).To(Equal(10.0))
 			Expect(restored2.Tick).To(Equal(uint32(0)))
 		})
 	})
@@ -135,29 +139,29 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 
 			// Capture snapshot at tick 5
 			world.Tick = 5
-			world.Ship.Pos = entities.NewVec2(15.0, 0.0)
+			world.Ships[0].Pos = entities.NewVec2(15.0, 0.0)
 			snapshot5 := manager.CaptureSnapshot(world, 5, clock)
 
 			// Capture snapshot at tick 10
 			world.Tick = 10
-			world.Ship.Pos = entities.NewVec2(20.0, 0.0)
+			world.Ships[0].Pos = entities.NewVec2(20.0, 0.0)
 			snapshot10 := manager.CaptureSnapshot(world, 10, clock)
 
 			// Retrieve and verify snapshots
 			retrieved0, exists0 := manager.GetSnapshot(0)
 			Expect(exists0).To(BeTrue())
 			Expect(retrieved0.Tick).To(Equal(uint32(0)))
-			Expect(retrieved0.World.Ship.Pos.X).To(Equal(10.0))
+			Expect(retrieved0.World.Ships[0].Pos.X).To(Equal(10.0))
 
 			retrieved5, exists5 := manager.GetSnapshot(5)
 			Expect(exists5).To(BeTrue())
 			Expect(retrieved5.Tick).To(Equal(uint32(5)))
-			Expect(retrieved5.World.Ship.Pos.X).To(Equal(15.0))
+			Expect(retrieved5.World.Ships[0].Pos.X).To(Equal(15.0))
 
 			retrieved10, exists10 := manager.GetSnapshot(10)
 			Expect(exists10).To(BeTrue())
 			Expect(retrieved10.Tick).To(Equal(uint32(10)))
-			Expect(retrieved10.World.Ship.Pos.X).To(Equal(20.0))
+			Expect(retrieved10.World.Ships[0].Pos.X).To(Equal(20.0))
 
 			// Verify snapshots are independent
 			Expect(snapshot0).To(Equal(retrieved0))
@@ -180,19 +184,19 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 
 			// Capture snapshot at tick 5
 			world.Tick = 5
-			world.Ship.Pos = entities.NewVec2(15.0, 0.0)
+			world.Ships[0].Pos = entities.NewVec2(15.0, 0.0)
 			manager.CaptureSnapshot(world, 5, clock)
 
 			// Advance world
 			world.Tick = 10
-			world.Ship.Pos = entities.NewVec2(20.0, 0.0)
+			world.Ships[0].Pos = entities.NewVec2(20.0, 0.0)
 
 			// Restore to tick 5
 			snapshot5, _ := manager.GetSnapshot(5)
 			restored := manager.RestoreSnapshot(snapshot5)
 
 			Expect(restored.Tick).To(Equal(uint32(5)))
-			Expect(restored.Ship.Pos.X).To(Equal(15.0))
+			Expect(restored.Ships[0].Pos.X).To(Equal(15.0))
 		})
 
 		It("GetSnapshot returns false for non-existent snapshot", func() {
@@ -238,6 +242,135 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 		})
 	})
 
+	Describe("DiscardBefore", func() {
+		It("removes only the snapshots of earlier ticks", func() {
+			clock := NewFakeClock()
+			world := entities.NewWorld(entities.NewShip(entities.NewVec2(10.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 100.0), entities.NewSun(entities.NewVec2(0.0, 0.0), 5.0, 1000.0), nil)
+
+			manager := NewSnapshotManager()
+			for tick := uint32(0); tick < 4; tick++ {
+				manager.CaptureSnapshot(world, tick, clock)
+			}
+			manager.DiscardBefore(2)
+
+			for tick := uint32(0); tick < 4; tick++ {
+				_, exists := manager.GetSnapshot(tick)
+				Expect(exists).To(Equal(tick >= 2), "tick %d", tick)
+			}
+		})
+	})
+
+	Describe("Bounded History", func() {
+		var (
+			clock   *FakeClock
+			world   entities.World
+			manager *SnapshotManager
+		)
+
+		BeforeEach(func() {
+			clock = NewFakeClock()
+			world = levels.Seeded(7)
+			manager = NewSnapshotManager()
+		})
+
+		// capture steps the world and captures a snapshot of every tick in [from, to)
+		capture := func(from, to uint32) map[uint32]entities.World {
+			worlds := make(map[uint32]entities.World)
+			for tick := from; tick < to; tick++ {
+				clock.Advance(33 * time.Millisecond)
+				world = rules.StepPlayers(world, map[entities.PlayerID]rules.InputCommand{entities.DefaultPlayerID: {Thrust: 1.0, Turn: 0.3}}, 1.0/30.0, rules.DefaultConfig())
+				// Pick up a pallet every few ticks, so snapshots differ from their keyframe
+				if tick%5 == 0 {
+					world.Pallets[int(tick/5)%len(world.Pallets)].Active = false
+				}
+				manager.CaptureSnapshot(world, tick, clock)
+				worlds[tick] = copyWorld(world)
+			}
+			return worlds
+		}
+
+		It("keeps the newest snapshots up to the depth and reports evictions", func() {
+			manager.SetDepth(10)
+			var evicted []uint32
+			manager.OnEvict(func(snapshot *Snapshot) { evicted = append(evicted, snapshot.Tick) })
+
+			worlds := capture(0, 25)
+			Expect(manager.Len()).To(Equal(10))
+			Expect(evicted).To(HaveLen(15))
+			Expect(evicted[0]).To(Equal(uint32(0)))
+			Expect(evicted[14]).To(Equal(uint32(14)))
+
+			_, exists := manager.GetSnapshot(14)
+			Expect(exists).To(BeFalse())
+			for tick := uint32(15); tick < 25; tick++ {
+				snapshot, exists := manager.GetSnapshot(tick)
+				Expect(exists).To(BeTrue())
+				Expect(snapshot.World).To(Equal(worlds[tick]), "tick %d", tick)
+			}
+		})
+
+		It("evicts snapshots older than the maximum age", func() {
+			manager.SetMaxAge(100 * time.Millisecond)
+			capture(0, 10)
+
+			// 33ms apart: the newest and the three before it are within 100ms
+			Expect(manager.Len()).To(Equal(4))
+			_, exists := manager.GetSnapshot(5)
+			Expect(exists).To(BeFalse())
+			_, exists = manager.GetSnapshot(6)
+			Expect(exists).To(BeTrue())
+		})
+
+		It("stores snapshots as deltas against keyframes and rebuilds them exactly", func() {
+			worlds := capture(0, 40)
+
+			keyframes := 0
+			for i := 0; i < manager.Len(); i++ {
+				stored := manager.ring[(manager.head+i)%len(manager.ring)]
+				if stored.isKeyframe {
+					keyframes++
+				} else {
+					Expect(len(stored.pallets)).To(BeNumerically("<", len(world.Pallets)))
+				}
+			}
+			Expect(keyframes).To(Equal(3)) // Ticks 0, 17 and 34
+
+			for tick, expected := range worlds {
+				snapshot, exists := manager.GetSnapshot(tick)
+				Expect(exists).To(BeTrue())
+				Expect(snapshot.World).To(Equal(expected), "tick %d", tick)
+			}
+		})
+
+		It("replaces recaptured ticks without affecting the other snapshots", func() {
+			worlds := capture(0, 20)
+			replacement := copyWorld(worlds[17])
+			replacement.Ships[0].Energy = 1
+			manager.CaptureSnapshot(replacement, 17, clock)
+
+			Expect(manager.Len()).To(Equal(20))
+			snapshot, _ := manager.GetSnapshot(17)
+			Expect(snapshot.World).To(Equal(replacement))
+			for _, tick := range []uint32{0, 16, 18, 19} {
+				snapshot, _ := manager.GetSnapshot(tick)
+				Expect(snapshot.World).To(Equal(worlds[tick]), "tick %d", tick)
+			}
+		})
+
+		It("keeps the order of snapshots when the depth changes", func() {
+			worlds := capture(0, 12)
+			manager.SetDepth(5)
+			Expect(manager.Len()).To(Equal(5))
+			capture(12, 14)
+
+			_, exists := manager.GetSnapshot(8)
+			Expect(exists).To(BeFalse())
+			snapshot, exists := manager.GetSnapshot(11)
+			Expect(exists).To(BeTrue())
+			Expect(snapshot.World).To(Equal(worlds[11]))
+		})
+	})
+
 	Describe("Rollback Hooks", func() {
 		It("calls BeforeSnapshot hook when capturing snapshot", func() {
 			clock := NewFakeClock()
@@ -367,6 +500,159 @@ var _ = Describe("Rollback Infrastructure", Label("scope:unit", "loop:g3-orch",
 	})
 })
 
+var _ = Describe("Session Rollback", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:rollback-netcode", "r:high"), func() {
+	const tickInterval = DefaultTickInterval
+
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	input := func(seq uint32) rules.InputCommand {
+		return rules.InputCommand{Thrust: 1.0, Turn: float32(seq%3) - 1}
+	}
+
+	runTicks := func(s *Session, c *FakeClock, n int) {
+		for i := 0; i < n; i++ {
+			c.Advance(tickInterval)
+			ExpectWithOffset(1, s.Run(1)).To(Succeed())
+		}
+	}
+
+	// playLateInput applies seqs 1 and 2, skips tick 2, applies seq 4 at tick 3 and
+	// runs to tick 5; seq 3 is then late
+	playLateInput := func() {
+		Expect(session.EnqueueCommand(1, input(1))).To(BeTrue())
+		Expect(session.EnqueueCommand(2, input(2))).To(BeTrue())
+		runTicks(session, clock, 3)
+		Expect(session.EnqueueCommand(4, input(4))).To(BeTrue())
+		runTicks(session, clock, 2)
+	}
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+		session.SetRollbackWindow(DefaultRollbackWindow)
+	})
+
+	It("re-simulates a late input from the tick it missed", func() {
+		session.StartRecording("seeded", 7)
+		playLateInput()
+		Expect(session.EnqueueCommand(3, input(3))).To(BeTrue())
+
+		// The same inputs, each in time
+		inTimeClock := NewFakeClock()
+		inTime := NewSession(inTimeClock, levels.Seeded(7), 100)
+		for seq := uint32(1); seq <= 4; seq++ {
+			Expect(inTime.EnqueueCommand(seq, input(seq))).To(BeTrue())
+		}
+		runTicks(inTime, inTimeClock, 5)
+
+		Expect(session.GetWorld()).To(Equal(inTime.GetWorld()))
+		replay, _ := session.Recording()
+		Expect(replay.Play()).To(Equal(inTime.GetWorld()))
+
+		// Later ticks continue from the corrected world
+		Expect(session.EnqueueCommand(5, input(5))).To(BeTrue())
+		Expect(inTime.EnqueueCommand(5, input(5))).To(BeTrue())
+		runTicks(session, clock, 2)
+		runTicks(inTime, inTimeClock, 2)
+		Expect(session.GetWorld()).To(Equal(inTime.GetWorld()))
+	})
+
+	It("calls the rollback hooks for the restored and re-simulated ticks", func() {
+		var restored, captured []uint32
+		session.RegisterRollbackHook(&testHook{
+			beforeSnapshot: func(snapshot *Snapshot) { captured = append(captured, snapshot.Tick) },
+			afterRestore:   func(snapshot *Snapshot) { restored = append(restored, snapshot.Tick) },
+		})
+		playLateInput()
+		captured = nil
+
+		Expect(session.EnqueueCommand(3, input(3))).To(BeTrue())
+		Expect(restored).To(Equal([]uint32{2}))
+		Expect(captured).To(Equal([]uint32{3, 4}))
+	})
+
+	It("keeps the snapshots when re-simulating violates an invariant", func() {
+		// Seq 4 turns by NaN while invariants are off; re-simulating its tick then fails
+		Expect(session.EnqueueCommand(1, input(1))).To(BeTrue())
+		Expect(session.EnqueueCommand(2, input(2))).To(BeTrue())
+		runTicks(session, clock, 3)
+		Expect(session.EnqueueCommand(4, rules.InputCommand{Turn: float32(math.NaN())})).To(BeTrue())
+		runTicks(session, clock, 2)
+		session.SetInvariantMode(InvariantsFreeze)
+
+		before := map[uint32]*Snapshot{}
+		for tick := uint32(0); tick < 5; tick++ {
+			snapshot, ok := session.snapshots.GetSnapshot(tick)
+			Expect(ok).To(BeTrue())
+			before[tick] = snapshot
+		}
+
+		Expect(session.EnqueueCommand(3, input(3))).To(BeFalse())
+		Expect(session.Violation()).NotTo(BeNil())
+		for tick, snapshot := range before {
+			after, ok := session.snapshots.GetSnapshot(tick)
+			Expect(ok).To(BeTrue())
+			// Compare encodings, since the worlds from tick 4 on hold NaN
+			Expect(fmt.Sprintf("%v", after.World)).To(Equal(fmt.Sprintf("%v", snapshot.World)), "snapshot of tick %d", tick)
+		}
+	})
+
+	It("rejects duplicates and late inputs without a free tick", func() {
+		playLateInput()
+		Expect(session.EnqueueCommand(2, input(2))).To(BeFalse())
+		Expect(session.EnqueueCommand(3, input(3))).To(BeTrue())
+		Expect(session.EnqueueCommand(3, input(3))).To(BeFalse())
+
+		// Seq 7 was applied right after seq 5, so seq 6 has no tick to go to
+		Expect(session.EnqueueCommand(5, input(5))).To(BeTrue())
+		Expect(session.EnqueueCommand(7, input(7))).To(BeTrue())
+		runTicks(session, clock, 2)
+		Expect(session.EnqueueCommand(6, input(6))).To(BeFalse())
+	})
+
+	It("rejects late inputs older than the window", func() {
+		session.SetRollbackWindow(2)
+		playLateInput()
+		Expect(session.EnqueueCommand(3, input(3))).To(BeFalse())
+	})
+
+	It("rejects inputs applied before the window", func() {
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		runTicks(session, clock, 1)
+		runTicks(session, clock, DefaultRollbackWindow)
+		Expect(session.EnqueueCommand(2, rules.InputCommand{})).To(BeTrue())
+		runTicks(session, clock, 1)
+		energy := session.GetWorld().Ships[0].Energy
+
+		// Seq 1 left the history, but seq 2 leaves idle ticks it could go to
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})).To(BeFalse())
+		Expect(session.GetWorld().Ships[0].Energy).To(Equal(energy))
+
+		// The same holds for inputs applied before the history was cleared
+		session.SetRollbackWindow(DefaultRollbackWindow)
+		runTicks(session, clock, 1)
+		Expect(session.EnqueueCommand(4, rules.InputCommand{})).To(BeTrue())
+		runTicks(session, clock, 1)
+		Expect(session.EnqueueCommand(2, rules.InputCommand{})).To(BeFalse())
+		Expect(session.EnqueueCommand(3, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+	})
+
+	It("rejects late inputs when disabled or after players change", func() {
+		session.SetRollbackWindow(0)
+		playLateInput()
+		Expect(session.EnqueueCommand(3, input(3))).To(BeFalse())
+
+		session.SetRollbackWindow(DefaultRollbackWindow)
+		session.Reset(levels.Seeded(7))
+		playLateInput()
+		Expect(session.AddPlayer(levels.SpawnShip("p2", 1))).To(Succeed())
+		Expect(session.EnqueueCommand(3, input(3))).To(BeFalse())
+	})
+})
+
 // testHook is a test implementation of RollbackHook for testing.
 type testHook struct {
 	beforeSnapshot func(*Snapshot)
@@ -0,0 +1,361 @@
+package session
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/observability"
+)
+
+// schedulerMaxTicks is the maxTicks of every scheduled Run, as in the run loops of
+// the transport and rooms.
+const schedulerMaxTicks = 10
+
+// Scheduler steps many sessions from a single timer and a pool of workers,
+// instead of one goroutine and ticker per session. At every tick boundary
+// (wall-clock multiples of the tick interval, so all sessions are stepped
+// together) it hands each session to a worker, which calls Run. A session whose
+// previous step is still running is skipped for that boundary and counted as an
+// overrun, so a slow session never queues up steps; the order sessions are handed
+// out rotates every boundary, so no session is always served last.
+//
+// Sessions keep their own ticker: a boundary runs the ticks due for each session,
+// so sessions at another tick rate than the scheduler's still run at their rate.
+//
+// The boundaries also pace snapshot broadcasts (see ScheduledSession.Subscribe),
+// so connections do not need timers of their own.
+//
+// Scheduler is safe for concurrent use.
+type Scheduler struct {
+	rate     int
+	interval time.Duration
+	workers  int
+	jobs     chan schedulerJob
+
+	mu       sync.Mutex          // Guards sessions and offset
+	sessions []*ScheduledSession // Scheduled sessions, in no particular order
+	offset   int                 // Index of the session handed out first at the next boundary
+
+	rounds   atomic.Uint64
+	missed   atomic.Uint64
+	steps    atomic.Uint64
+	overruns atomic.Uint64
+	lag      atomic.Int64 // Total lag of all steps, in nanoseconds
+	maxLag   atomic.Int64
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// schedulerJob is a session to step for the boundary it is due at.
+type schedulerJob struct {
+	session  *ScheduledSession
+	boundary time.Time
+}
+
+// SchedulerStats are a scheduler's counters since it was created.
+type SchedulerStats struct {
+	Sessions int           // Sessions currently scheduled
+	Rounds   uint64        // Tick boundaries the scheduler stepped sessions at
+	Missed   uint64        // Tick boundaries skipped because the scheduler woke up after the next one
+	Steps    uint64        // Runs of scheduled sessions
+	Overruns uint64        // Boundaries sessions missed because their previous step was still running
+	Lag      time.Duration // Total time between boundaries and the start of their steps
+	MaxLag   time.Duration // Largest time between a boundary and the start of its step
+}
+
+// ScheduledSession is a session stepped by a Scheduler (see Scheduler.Add).
+type ScheduledSession struct {
+	scheduler *Scheduler
+	session   *Session
+	onStop    func(err error)
+	index     int         // Index in scheduler.sessions, -1 once removed; guarded by scheduler.mu
+	removed   atomic.Bool // Set by Remove, so that steps handed out before are skipped
+	busy      atomic.Bool // A worker is stepping the session
+	overruns  atomic.Uint64
+
+	subsMu sync.Mutex          // Guards subs
+	subs   []*stepSubscription // Notified after steps, see Subscribe
+}
+
+// stepSubscription is a subscriber to the steps of a scheduled session.
+type stepSubscription struct {
+	c     chan time.Time
+	every int // Steps between notifications
+	steps int // Steps since the last notification; guarded by subsMu
+}
+
+// NewScheduler creates a scheduler with boundaries at the interval of rate ticks
+// per second (see ValidateTickRate) and workers workers; workers <= 0 means one
+// per CPU (GOMAXPROCS). The scheduler runs once started.
+// Returns an error if the rate is not supported.
+func NewScheduler(rate, workers int) (*Scheduler, error) {
+	if err := ValidateTickRate(rate); err != nil {
+		return nil, err
+	}
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &Scheduler{
+		rate:     rate,
+		interval: (time.Second + time.Duration(rate) - 1) / time.Duration(rate),
+		workers:  workers,
+		jobs:     make(chan schedulerJob, workers),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Rate returns the number of tick boundaries per second.
+func (s *Scheduler) Rate() int {
+	return s.rate
+}
+
+// Interval returns the time between two tick boundaries.
+func (s *Scheduler) Interval() time.Duration {
+	return s.interval
+}
+
+// Workers returns the number of workers stepping sessions.
+func (s *Scheduler) Workers() int {
+	return s.workers
+}
+
+// Add schedules sess: from the next boundary on it is run like a run loop would,
+// with at most 10 ticks per step. When Run returns an error, ErrSessionExpired or
+// the *InvariantViolationError of a frozen session, the session is removed and
+// onStop, if not nil, is called with the error on the worker's goroutine.
+func (s *Scheduler) Add(sess *Session, onStop func(err error)) *ScheduledSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	scheduled := &ScheduledSession{
+		scheduler: s,
+		session:   sess,
+		onStop:    onStop,
+		index:     len(s.sessions),
+	}
+	s.sessions = append(s.sessions, scheduled)
+	observability.UpdateScheduledSessions(len(s.sessions))
+	return scheduled
+}
+
+// Remove stops stepping the session. A step already running completes; steps
+// handed out but not started are skipped. It can be called multiple times.
+func (ss *ScheduledSession) Remove() {
+	s := ss.scheduler
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ss.index < 0 {
+		return
+	}
+	// Move the last session into the gap
+	last := s.sessions[len(s.sessions)-1]
+	s.sessions[ss.index] = last
+	last.index = ss.index
+	s.sessions[len(s.sessions)-1] = nil
+	s.sessions = s.sessions[:len(s.sessions)-1]
+	ss.index = -1
+	ss.removed.Store(true)
+	observability.UpdateScheduledSessions(len(s.sessions))
+}
+
+// Subscribe returns a channel that receives the boundary of the session's steps
+// closest to interval apart (every step for intervals up to the scheduler's),
+// sent once Run returned, and a function that ends the subscription. Like a
+// time.Ticker's, the channel holds one boundary: a subscriber still busy with
+// the previous one misses the next, and steps never wait for subscribers.
+func (ss *ScheduledSession) Subscribe(interval time.Duration) (<-chan time.Time, func()) {
+	step := ss.scheduler.interval
+	every := int((interval + step/2) / step)
+	if every < 1 {
+		every = 1
+	}
+	sub := &stepSubscription{c: make(chan time.Time, 1), every: every}
+
+	ss.subsMu.Lock()
+	ss.subs = append(ss.subs, sub)
+	ss.subsMu.Unlock()
+
+	return sub.c, func() {
+		ss.subsMu.Lock()
+		defer ss.subsMu.Unlock()
+		for i, s := range ss.subs {
+			if s == sub {
+				ss.subs = append(ss.subs[:i], ss.subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// notify sends boundary to the subscribers due after this step.
+func (ss *ScheduledSession) notify(boundary time.Time) {
+	ss.subsMu.Lock()
+	defer ss.subsMu.Unlock()
+
+	for _, sub := range ss.subs {
+		sub.steps++
+		if sub.steps < sub.every {
+			continue
+		}
+		sub.steps = 0
+		select {
+		case sub.c <- boundary:
+		default:
+		}
+	}
+}
+
+// Session returns the scheduled session.
+func (ss *ScheduledSession) Session() *Session {
+	return ss.session
+}
+
+// Overruns returns the number of boundaries the session missed because its
+// previous step was still running.
+func (ss *ScheduledSession) Overruns() uint64 {
+	return ss.overruns.Load()
+}
+
+// Stats returns the scheduler's counters.
+func (s *Scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	sessions := len(s.sessions)
+	s.mu.Unlock()
+
+	return SchedulerStats{
+		Sessions: sessions,
+		Rounds:   s.rounds.Load(),
+		Missed:   s.missed.Load(),
+		Steps:    s.steps.Load(),
+		Overruns: s.overruns.Load(),
+		Lag:      time.Duration(s.lag.Load()),
+		MaxLag:   time.Duration(s.maxLag.Load()),
+	}
+}
+
+// Start starts the scheduler's timer and workers. It can be called multiple times.
+func (s *Scheduler) Start() {
+	s.startOnce.Do(func() {
+		s.wg.Add(s.workers + 1)
+		for i := 0; i < s.workers; i++ {
+			go s.work()
+		}
+		go s.run()
+	})
+}
+
+// Stop stops the scheduler and waits for running steps to complete. Scheduled
+// sessions are no longer stepped. It can be called multiple times.
+func (s *Scheduler) Stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+	s.wg.Wait()
+}
+
+// run wakes up at every boundary and hands the sessions out to the workers.
+func (s *Scheduler) run() {
+	defer s.wg.Done()
+	defer close(s.jobs) // Ends the workers once the last round is handed out
+
+	next := time.Now().Truncate(s.interval).Add(s.interval)
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-timer.C:
+			// Step at the latest boundary; boundaries passed while the previous round
+			// was handed out are skipped
+			boundary := time.Now().Truncate(s.interval)
+			if skipped := boundary.Sub(next) / s.interval; skipped > 0 {
+				s.missed.Add(uint64(skipped))
+			}
+			s.round(boundary)
+			next = boundary.Add(s.interval)
+			timer.Reset(time.Until(next))
+		}
+	}
+}
+
+// round hands every scheduled session that is not still being stepped out to the
+// workers for boundary, starting one session further than the previous round.
+func (s *Scheduler) round(boundary time.Time) {
+	s.rounds.Add(1)
+	for _, scheduled := range s.dispatchOrder() {
+		if !scheduled.busy.CompareAndSwap(false, true) {
+			scheduled.overruns.Add(1)
+			s.overruns.Add(1)
+			observability.RecordSchedulerOverrun()
+			continue
+		}
+		select {
+		case s.jobs <- schedulerJob{session: scheduled, boundary: boundary}:
+		case <-s.done:
+			scheduled.busy.Store(false)
+			return
+		}
+	}
+}
+
+// dispatchOrder returns the scheduled sessions in the order of the next round and
+// rotates the order for the round after.
+func (s *Scheduler) dispatchOrder() []*ScheduledSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.sessions)
+	if n == 0 {
+		return nil
+	}
+	start := s.offset % n
+	order := make([]*ScheduledSession, 0, n)
+	order = append(order, s.sessions[start:]...)
+	order = append(order, s.sessions[:start]...)
+	s.offset = start + 1
+	return order
+}
+
+// work steps the sessions handed out by run until the scheduler stops.
+func (s *Scheduler) work() {
+	defer s.wg.Done()
+	for job := range s.jobs {
+		s.step(job)
+	}
+}
+
+// step runs one session for its boundary and records the lag.
+func (s *Scheduler) step(job schedulerJob) {
+	scheduled := job.session
+	defer scheduled.busy.Store(false)
+	if scheduled.removed.Load() {
+		return
+	}
+
+	lag := time.Since(job.boundary)
+	s.steps.Add(1)
+	s.lag.Add(int64(lag))
+	for {
+		largest := s.maxLag.Load()
+		if int64(lag) <= largest || s.maxLag.CompareAndSwap(largest, int64(lag)) {
+			break
+		}
+	}
+	observability.ObserveSchedulerLag(lag)
+
+	if err := scheduled.session.Run(schedulerMaxTicks); err != nil {
+		scheduled.Remove()
+		if scheduled.onStop != nil {
+			scheduled.onStop(err)
+		}
+		return
+	}
+	scheduled.notify(job.boundary)
+}
@@ -0,0 +1,170 @@
+//go:build unix
+
+package session
+
+import (
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+)
+
+// Benchmarks of the Scheduler against one goroutine and time.Ticker per session
+// (the run loops before the scheduler), at 1k and 10k sessions. One op is one tick
+// interval of wall time; the reported metrics are the CPU time per tick, the mean
+// and largest lag between a tick and the start of its step, and the ticks missed
+// (scheduler overruns, or ticks the ticker dropped). The Snapshots benchmarks add
+// a connection per session reading the world every snapshot interval, paced by
+// ScheduledSession.Subscribe or by a ticker per connection as in the transport.
+// Run with `make bench`.
+
+func BenchmarkScheduler1k(b *testing.B)           { benchmarkScheduler(b, 1000, false) }
+func BenchmarkScheduler10k(b *testing.B)          { benchmarkScheduler(b, 10000, false) }
+func BenchmarkTickers1k(b *testing.B)             { benchmarkTickers(b, 1000, false) }
+func BenchmarkTickers10k(b *testing.B)            { benchmarkTickers(b, 10000, false) }
+func BenchmarkSchedulerSnapshots1k(b *testing.B)  { benchmarkScheduler(b, 1000, true) }
+func BenchmarkSchedulerSnapshots10k(b *testing.B) { benchmarkScheduler(b, 10000, true) }
+func BenchmarkTickersSnapshots1k(b *testing.B)    { benchmarkTickers(b, 1000, true) }
+func BenchmarkTickersSnapshots10k(b *testing.B)   { benchmarkTickers(b, 10000, true) }
+
+// benchSnapshotInterval is the snapshot interval of the transport's connections.
+const benchSnapshotInterval = 100 * time.Millisecond
+
+// readSnapshots reads the world of sess at every value of ticks until done is
+// closed, like a connection's snapshot loop.
+func readSnapshots(wg *sync.WaitGroup, sess *Session, ticks <-chan time.Time, done <-chan struct{}) {
+	defer wg.Done()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticks:
+			_ = sess.GetWorld()
+		}
+	}
+}
+
+// benchSessions creates n sessions of one ship and no pallets, so that the
+// benchmarks measure scheduling rather than physics.
+func benchSessions(n int) []*Session {
+	clock := NewRealClock()
+	ship := entities.NewShip(entities.NewVec2(200.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 100.0)
+	sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+	world := entities.NewWorld(ship, sun, nil)
+	sessions := make([]*Session, n)
+	for i := range sessions {
+		sessions[i] = NewSession(clock, world, 100)
+	}
+	return sessions
+}
+
+// cpuTime returns the user and system CPU time of the process.
+func cpuTime(b *testing.B) time.Duration {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		b.Fatal(err)
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+}
+
+// reportTicks reports the benchmark metrics of b.N ticks.
+func reportTicks(b *testing.B, cpu time.Duration, steps uint64, lag, maxLag time.Duration, missed uint64) {
+	b.ReportMetric(float64(cpu.Milliseconds())/float64(b.N), "cpu-ms/tick")
+	if steps > 0 {
+		b.ReportMetric(float64(lag.Microseconds())/float64(steps), "lag-us/step")
+	}
+	b.ReportMetric(float64(maxLag.Microseconds()), "max-lag-us")
+	b.ReportMetric(float64(missed)/float64(b.N), "missed/tick")
+}
+
+func benchmarkScheduler(b *testing.B, n int, snapshots bool) {
+	scheduler, err := NewScheduler(DefaultTickRate, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for _, sess := range benchSessions(n) {
+		scheduled := scheduler.Add(sess, nil)
+		if snapshots {
+			ticks, _ := scheduled.Subscribe(benchSnapshotInterval)
+			wg.Add(1)
+			go readSnapshots(&wg, sess, ticks, done)
+		}
+	}
+
+	b.ResetTimer()
+	cpu := cpuTime(b)
+	scheduler.Start()
+	time.Sleep(time.Duration(b.N) * scheduler.Interval())
+	scheduler.Stop()
+	close(done)
+	wg.Wait()
+	cpu = cpuTime(b) - cpu
+	b.StopTimer()
+
+	stats := scheduler.Stats()
+	reportTicks(b, cpu, stats.Steps, stats.Lag, stats.MaxLag, stats.Overruns+stats.Missed*uint64(n))
+}
+
+func benchmarkTickers(b *testing.B, n int, snapshots bool) {
+	var (
+		wg     sync.WaitGroup
+		steps  atomic.Uint64
+		lag    atomic.Int64
+		maxLag atomic.Int64
+		missed atomic.Uint64
+	)
+	done := make(chan struct{})
+	sessions := benchSessions(n)
+
+	b.ResetTimer()
+	cpu := cpuTime(b)
+	for _, sess := range sessions {
+		if snapshots {
+			ticker := time.NewTicker(benchSnapshotInterval)
+			defer ticker.Stop()
+			wg.Add(1)
+			go readSnapshots(&wg, sess, ticker.C, done)
+		}
+		wg.Add(1)
+		go func(sess *Session) {
+			defer wg.Done()
+			ticker := time.NewTicker(DefaultTickInterval)
+			defer ticker.Stop()
+			var previous time.Time // Time of the previous tick
+			for {
+				select {
+				case <-done:
+					return
+				case tick := <-ticker.C:
+					late := time.Since(tick)
+					// The ticker drops the ticks of a receiver that is still busy
+					if !previous.IsZero() {
+						missed.Add(uint64((tick.Sub(previous) - DefaultTickInterval/2) / DefaultTickInterval))
+					}
+					previous = tick
+					steps.Add(1)
+					lag.Add(int64(late))
+					for {
+						largest := maxLag.Load()
+						if int64(late) <= largest || maxLag.CompareAndSwap(largest, int64(late)) {
+							break
+						}
+					}
+					_ = sess.Run(schedulerMaxTicks)
+				}
+			}
+		}(sess)
+	}
+	time.Sleep(time.Duration(b.N) * DefaultTickInterval)
+	close(done)
+	wg.Wait()
+	cpu = cpuTime(b) - cpu
+	b.StopTimer()
+
+	reportTicks(b, cpu, steps.Load(), time.Duration(lag.Load()), time.Duration(maxLag.Load()), missed.Load())
+}
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Scheduler", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:tick-scheduler", "r:high"), func() {
+	var (
+		clock     *FakeClock
+		scheduler *Scheduler
+	)
+
+	newSession := func() *Session {
+		return NewSession(clock, levels.Seeded(7), 100)
+	}
+
+	BeforeEach(func() {
+		observability.InitMetrics()
+		clock = NewFakeClock()
+		var err error
+		scheduler, err = NewScheduler(DefaultTickRate, 2)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		scheduler.Stop()
+	})
+
+	It("validates the rate and defaults the workers", func() {
+		_, err := NewScheduler(45, 1)
+		Expect(err).To(MatchError(ContainSubstring("unsupported tick rate")))
+
+		defaulted, err := NewScheduler(TickRate60, 0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(defaulted.Workers()).To(BeNumerically(">", 0))
+		Expect(defaulted.Interval()).To(Equal((time.Second + 59) / 60))
+	})
+
+	It("steps every scheduled session on the tick boundaries", func() {
+		sessions := []*Session{newSession(), newSession(), newSession()}
+		for _, sess := range sessions {
+			scheduler.Add(sess, nil)
+		}
+		scheduler.Start()
+
+		clock.Advance(5 * DefaultTickInterval)
+		for _, sess := range sessions {
+			Eventually(func() uint32 { return sess.GetWorld().Tick }).Should(Equal(uint32(5)))
+		}
+		stats := scheduler.Stats()
+		Expect(stats.Sessions).To(Equal(3))
+		Expect(stats.Rounds).To(BeNumerically(">", 0))
+		Expect(stats.Steps).To(BeNumerically(">=", 3))
+		Expect(stats.MaxLag).To(BeNumerically(">=", 0))
+		Expect(testutil.ToFloat64(observability.GetScheduledSessionsGauge())).To(Equal(3.0))
+	})
+
+	It("stops stepping removed sessions", func() {
+		kept, removed := newSession(), newSession()
+		scheduler.Add(kept, nil)
+		scheduler.Add(removed, nil).Remove()
+		scheduler.Start()
+
+		clock.Advance(DefaultTickInterval)
+		Eventually(func() uint32 { return kept.GetWorld().Tick }).Should(Equal(uint32(1)))
+		Consistently(func() uint32 { return removed.GetWorld().Tick }, 100*time.Millisecond).Should(Equal(uint32(0)))
+		Expect(scheduler.Stats().Sessions).To(Equal(1))
+	})
+
+	It("removes expired sessions and reports them", func() {
+		sess := newSession()
+		sess.SetLifecycle(LifecycleConfig{IdleTimeout: time.Minute})
+		stopped := make(chan error, 2)
+		scheduled := scheduler.Add(sess, func(err error) { stopped <- err })
+		scheduler.Start()
+
+		clock.Advance(time.Minute)
+		Eventually(stopped).Should(Receive(MatchError(ErrSessionExpired)))
+		Consistently(stopped, 100*time.Millisecond).ShouldNot(Receive())
+		Expect(scheduler.Stats().Sessions).To(Equal(0))
+		scheduled.Remove() // Already removed
+	})
+
+	It("removes frozen sessions and reports their violation", func() {
+		// Energy above MaxEnergy violates the world invariants on the first tick
+		ship := entities.NewShip(entities.NewVec2(200.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 150.0)
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		sess := NewSession(clock, entities.NewWorld(ship, sun, nil), 100)
+		sess.SetInvariantMode(InvariantsFreeze)
+		stopped := make(chan error, 2)
+		scheduler.Add(sess, func(err error) { stopped <- err })
+		scheduler.Start()
+
+		clock.Advance(DefaultTickInterval)
+		var violation *InvariantViolationError
+		Eventually(stopped).Should(Receive(BeAssignableToTypeOf(violation)))
+		Consistently(stopped, 100*time.Millisecond).ShouldNot(Receive())
+		Expect(scheduler.Stats().Sessions).To(Equal(0))
+	})
+
+	It("skips sessions whose previous step is still running and counts the overrun", func() {
+		scheduled := scheduler.Add(newSession(), nil)
+		scheduled.busy.Store(true)
+
+		scheduler.round(time.Now())
+		Expect(scheduled.Overruns()).To(Equal(uint64(1)))
+		Expect(scheduler.Stats().Overruns).To(Equal(uint64(1)))
+		Expect(testutil.ToFloat64(observability.GetSchedulerOverrunsCounter())).To(Equal(1.0))
+	})
+
+	It("notifies subscribers after the steps closest to their interval", func() {
+		sess := newSession()
+		scheduled := scheduler.Add(sess, nil)
+		every, stopEvery := scheduled.Subscribe(0)
+		third, stopThird := scheduled.Subscribe(100 * time.Millisecond)
+		defer stopThird()
+
+		boundary := time.Now()
+		for i := 1; i <= 3; i++ {
+			clock.Advance(DefaultTickInterval)
+			scheduler.step(schedulerJob{session: scheduled, boundary: boundary.Add(time.Duration(i) * DefaultTickInterval)})
+			if i < 3 {
+				Expect(third).NotTo(Receive())
+			}
+		}
+		// The tick is stepped before subscribers are notified
+		Expect(third).To(Receive(Equal(boundary.Add(3 * DefaultTickInterval))))
+		Expect(sess.GetWorld().Tick).To(Equal(uint32(3)))
+
+		// every was not read: it holds the first boundary and dropped the others
+		Expect(every).To(Receive(Equal(boundary.Add(DefaultTickInterval))))
+		Expect(every).NotTo(Receive())
+
+		stopEvery()
+		scheduler.step(schedulerJob{session: scheduled, boundary: boundary.Add(4 * DefaultTickInterval)})
+		Expect(every).NotTo(Receive())
+	})
+
+	It("rotates the order sessions are handed out in", func() {
+		first := scheduler.Add(newSession(), nil)
+		second := scheduler.Add(newSession(), nil)
+		third := scheduler.Add(newSession(), nil)
+
+		Expect(scheduler.dispatchOrder()).To(Equal([]*ScheduledSession{first, second, third}))
+		Expect(scheduler.dispatchOrder()).To(Equal([]*ScheduledSession{second, third, first}))
+		Expect(scheduler.dispatchOrder()).To(Equal([]*ScheduledSession{third, first, second}))
+		Expect(scheduler.dispatchOrder()).To(Equal([]*ScheduledSession{first, second, third}))
+	})
+})
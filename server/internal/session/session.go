@@ -1,6 +1,8 @@
 package session
 
 import (
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -9,88 +11,241 @@ import (
 	"github.com/gorbit/orbitalrush/internal/sim/rules"
 )
 
-// Session orchestrates the game loop by combining ticker, command queue, and game rules.
+// Session orchestrates the game loop by combining ticker, command queues, and game rules.
+// The world may hold several ships; each player has their own command queue and
+// every tick applies one queued command per player.
+//
+// Session is safe for concurrent use. Every method takes the session lock, so Run,
+// EnqueueCommand, Reset and GetWorld may be called from different goroutines; Run
+// holds the lock for the whole batch of ticks it processes.
 type Session struct {
+	mu sync.Mutex // Guards every field below
+
 	world        entities.World
-	queue        *CommandQueue
+	queues       map[entities.PlayerID]*CommandQueue // One command queue per player
 	ticker       *Ticker
 	clock        Clock
+	simClock     *scaledClock // Clock of the ticker: clock paused and scaled (see Pause and SetTimeScale)
 	dt           float64
 	G            float64
 	aMax         float64
 	pickupRadius float64
-	running      bool
-	logger       logr.Logger // Optional logger for observability
-	maxQueueSize int         // Maximum queue size for threshold logging
+	rulesCfg     rules.Config // Remaining rule parameters (see SetRulesConfig)
+	logger       logr.Logger  // Optional logger for observability
+	maxQueueSize int          // Maximum size of each player's queue
+
+	invariantMode InvariantMode            // Whether world invariants are checked after every step
+	violation     *InvariantViolationError // Set when the session froze on an invariant violation
+
+	recording *Replay // Replay being recorded, nil if not recording (see StartRecording)
+
+	rollbackWindow int                          // Ticks late inputs can roll back, 0 if disabled (see SetRollbackWindow)
+	snapshots      *SnapshotManager             // World before each tick of history
+	history        []tickRecord                 // Inputs of the last rollbackWindow ticks, oldest first
+	historyFloor   map[entities.PlayerID]uint32 // Highest sequence of each player applied before history
+
+	gapPolicy GapPolicy                           // What jitter buffers play at ticks without input
+	jitter    map[entities.PlayerID]*jitterBuffer // Jitter buffers of players sending tick-targeted commands
+
+	backlogPolicy BacklogPolicy                      // How queued commands beyond maxBacklog are handled
+	maxBacklog    int                                // Commands that may stay queued per player after a tick
+	backlog       map[entities.PlayerID]BacklogStats // Commands dropped or coalesced since TakeBacklogStats
+
+	acks map[entities.PlayerID]InputAck // Last input applied per player (see LastInputAck)
+
+	state           State            // Lifecycle state (see lifecycle.go)
+	stateSince      time.Time        // Clock time the session entered state
+	lastActivity    time.Time        // Clock time of the last input or transition, for the idle timeout
+	lifecycle       LifecycleConfig  // Countdown and timeouts (see SetLifecycle)
+	transitionHooks []TransitionHook // Called after every lifecycle transition
 }
 
 // NewSession creates a new session with the given clock, initial world state, and max queue size.
+// Every ship in world gets a command queue of maxQueueSize commands.
+// The session keeps its own copy of world, so the caller may reuse it (e.g. for restarts).
 func NewSession(clock Clock, world entities.World, maxQueueSize int) *Session {
+	simClock := newScaledClock(clock)
+	now := clock.Now()
 	return &Session{
-		world:        world,
-		queue:        NewCommandQueue(maxQueueSize),
-		ticker:       NewFixedRateTicker(clock),
+		world:        copyWorld(world),
+		queues:       newQueues(world, maxQueueSize),
+		ticker:       NewFixedRateTicker(simClock),
 		clock:        clock,
-		dt:           1.0 / 30.0, // 30Hz tick rate
-		G:            1.0,        // Gravitational constant
-		aMax:         100.0,      // Maximum acceleration
-		pickupRadius: 15.0,       // Pallet pickup radius (about ship length for better gameplay)
-		running:      false,
+		simClock:     simClock,
+		dt:           1.0 / DefaultTickRate, // 30Hz tick rate
+		G:            1.0,                   // Gravitational constant
+		aMax:         100.0,                 // Maximum acceleration
+		pickupRadius: 15.0,                  // Pallet pickup radius (about ship length for better gameplay)
+		rulesCfg:     rules.DefaultConfig(),
 		maxQueueSize: maxQueueSize,
+		snapshots:    NewSnapshotManager(),
+		jitter:       make(map[entities.PlayerID]*jitterBuffer),
+		backlog:      make(map[entities.PlayerID]BacklogStats),
+		acks:         make(map[entities.PlayerID]InputAck),
+		state:        StateRunning,
+		stateSince:   now,
+		lastActivity: now,
+	}
+}
+
+// newQueues creates an empty command queue for every ship in world.
+func newQueues(world entities.World, maxQueueSize int) map[entities.PlayerID]*CommandQueue {
+	queues := make(map[entities.PlayerID]*CommandQueue, len(world.Ships))
+	for _, ship := range world.Ships {
+		queues[ship.ID] = NewCommandQueue(maxQueueSize)
 	}
+	return queues
 }
 
-// EnqueueCommand adds a command to the queue with the specified sequence number.
+// EnqueueCommand adds a command for entities.DefaultPlayerID, the player of
+// single-player worlds, with the specified sequence number.
 // Returns true if the command was successfully enqueued, false otherwise.
 func (s *Session) EnqueueCommand(seq uint32, cmd rules.InputCommand) bool {
-	success := s.queue.Enqueue(seq, cmd)
-	
+	return s.EnqueuePlayerCommand(entities.DefaultPlayerID, seq, cmd)
+}
+
+// EnqueuePlayerCommand adds a command to the queue of player id with the specified sequence number.
+// Sequence numbers are per player.
+// Returns true if the command was successfully enqueued, false otherwise (including unknown players).
+func (s *Session) EnqueuePlayerCommand(id entities.PlayerID, seq uint32, cmd rules.InputCommand) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue, ok := s.queues[id]
+	if !ok {
+		return false
+	}
+
+	success := queue.Enqueue(seq, cmd)
+	if !success && seq < queue.NextSequence() && s.rollbackWindow > 0 {
+		success = s.rollback(id, seq, cmd)
+	}
+	if success {
+		s.touch()
+	}
+	s.observeQueue(id, queue)
+	return success
+}
+
+// observeQueue updates the queue depth metric after a command was queued for
+// player id, and logs if the player's queue is filling up.
+func (s *Session) observeQueue(id entities.PlayerID, queue *CommandQueue) {
 	// Update queue depth metric
-	queueSize := s.queue.Size()
-	observability.UpdateQueueDepth(queueSize)
+	observability.UpdateQueueDepth(s.queueDepth())
 	
-	// Log if queue depth exceeds threshold (50% of max size)
+	// Log if the player's queue depth exceeds threshold (50% of max size)
+	queueSize := queue.Size()
 	const thresholdPercent = 0.5
 	threshold := int(float64(s.maxQueueSize) * thresholdPercent)
 	if queueSize >= threshold && s.logger.Enabled() {
 		s.logger.WithValues(
 			"component", "session",
+			"player_id", id,
 			"queue_depth", queueSize,
 			"max_size", s.maxQueueSize,
 			"threshold", threshold,
 		).Info("Queue depth exceeded threshold")
 	}
-	
-	return success
+}
+
+// AddPlayer adds ship to the world, owned by ship.ID, and gives the player an empty command queue.
+// Returns an error if ship has no ID or the player is already in the session.
+func (s *Session) AddPlayer(ship entities.Ship) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	world, err := s.world.AddShip(ship)
+	if err != nil {
+		return err
+	}
+	s.world = world
+	s.queues[ship.ID] = NewCommandQueue(s.maxQueueSize)
+	s.clearHistory()
+	s.recordJoin(ship)
+	return nil
+}
+
+// RemovePlayer removes the ship and command queue of player id.
+// Returns false if the player is not in the session.
+func (s *Session) RemovePlayer(id entities.PlayerID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	world, ok := s.world.RemoveShip(id)
+	if !ok {
+		return false
+	}
+	s.world = world
+	delete(s.queues, id)
+	delete(s.jitter, id)
+	delete(s.backlog, id)
+	delete(s.acks, id)
+	s.clearHistory()
+	s.recordLeave(id)
+	observability.UpdateQueueDepth(s.queueDepth())
+	return true
+}
+
+// Players returns the IDs of the players in the session, sorted.
+func (s *Session) Players() []entities.PlayerID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]entities.PlayerID, len(s.world.Ships))
+	for i, ship := range s.world.Ships {
+		ids[i] = ship.ID
+	}
+	return ids
+}
+
+// queueDepth returns the number of commands queued across all players.
+func (s *Session) queueDepth() int {
+	depth := 0
+	for _, queue := range s.queues {
+		depth += queue.Size()
+	}
+	return depth
 }
 
 // Run executes the tick loop for up to maxTicks iterations.
-// The loop processes commands and calls rules.Step() at the correct tick rate.
+// The loop processes one command per player and calls rules.StepPlayers() for every
+// tick the ticker made due since the last run. Due ticks beyond maxTicks are
+// dropped, so a session that fell behind does not try to catch up.
 // Returns nil on success, or an error if something goes wrong.
+// When invariant checking is enabled and a step violates a world invariant, the
+// session freezes: that and every later Run returns an *InvariantViolationError
+// without advancing the world.
+// Run also drives the lifecycle: ticks are only processed while the session is
+// running, a done world finishes it, and once a timeout expired the session
+// Run returns ErrSessionExpired.
 func (s *Session) Run(maxTicks int) error {
-	s.running = true
-	defer func() {
-		s.running = false
-	}()
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	ticksProcessed := 0
-	now := s.clock.Now()
-
-	// Calculate how many ticks should occur based on elapsed time
-	// This handles the case where time was advanced by multiple intervals
-	elapsed := now.Sub(s.ticker.lastTick)
-
-	// Calculate total ticks needed - use integer division to get whole ticks
-	totalTicksNeeded := int(elapsed / s.ticker.interval)
-	// Ensure we process at least 1 tick if any time has passed
-	// This handles edge cases where elapsed is slightly less than interval
-	if totalTicksNeeded == 0 && elapsed > 0 {
-		totalTicksNeeded = 1
+	if s.violation != nil {
+		return s.violation
+	}
+	if err := s.advanceLifecycle(); err != nil {
+		return err
+	}
+	if s.state != StateRunning {
+		return nil
 	}
 
-	// Limit to maxTicks (don't process more than requested)
-	if totalTicksNeeded > maxTicks {
-		totalTicksNeeded = maxTicks
+	ticksProcessed := 0
+
+	// Take the ticks due since the last run; beyond maxTicks the session fell behind
+	totalTicksNeeded, dropped := s.ticker.Advance(s.simClock.Now(), maxTicks)
+	if dropped > 0 {
+		observability.RecordDroppedTicks(dropped)
+		if s.logger.Enabled() {
+			s.logger.WithValues(
+				"component", "session",
+				"tick", s.world.Tick,
+				"dropped_ticks", dropped,
+				"max_ticks", maxTicks,
+			).Info("Session fell behind, dropped ticks")
+		}
 	}
 
 	// Process all ticks that should have occurred
@@ -98,23 +253,50 @@ func (s *Session) Run(maxTicks int) error {
 		// Measure tick execution time
 		tickStart := time.Now()
 
-		// Advance ticker - manually update lastTick to simulate time progression
-		s.ticker.lastTick = s.ticker.lastTick.Add(s.ticker.interval)
-
-		// Get next command from queue (or zero command if queue is empty)
-		var input rules.InputCommand
-		if queuedCmd, ok := s.queue.Dequeue(); ok {
-			input = queuedCmd.Command
-		} else {
-			// No command available, use zero command
-			input = rules.InputCommand{Thrust: 0.0, Turn: 0.0}
+		// Get next command from each player's queue
+		// Players with an empty queue are absent from inputs and get the zero command
+		inputs := make(map[entities.PlayerID]rules.InputCommand, len(s.queues))
+		seqs := make(map[entities.PlayerID]uint32, len(s.queues))
+		for id, queue := range s.queues {
+			if buffer, ok := s.jitter[id]; ok {
+				if cmd, seq, ok := s.playout(buffer, queue); ok {
+					inputs[id] = cmd
+					if seq != 0 {
+						seqs[id] = seq
+					}
+				}
+				continue
+			}
+			if queuedCmd, ok := s.dequeue(id, queue); ok {
+				inputs[id] = queuedCmd.Command
+				seqs[id] = queuedCmd.Sequence
+			}
 		}
 		
 		// Update queue depth metric after dequeue
-		observability.UpdateQueueDepth(s.queue.Size())
+		observability.UpdateQueueDepth(s.queueDepth())
 
-		// Call rules.Step() to update world state
-		s.world = rules.Step(s.world, input, s.dt, s.G, s.aMax, s.pickupRadius)
+		// Call rules.StepPlayers() to update world state
+		tick := s.world.Tick
+		cfg := s.rulesConfig()
+		if s.rollbackWindow > 0 {
+			s.snapshots.CaptureSnapshot(s.world, tick, s.clock)
+		}
+		if s.invariantMode == InvariantsOff {
+			s.world = rules.StepPlayers(s.world, inputs, s.dt, cfg)
+		} else {
+			// Step mutates the ships and pallets slices in place, so keep a deep copy to compare against
+			before := copyWorld(s.world)
+			after := rules.StepPlayers(s.world, inputs, s.dt, cfg)
+			if err := s.checkStep(before, after, inputs); err != nil {
+				s.world = before
+				return err
+			}
+			s.world = after
+		}
+		s.recordStep(tick, inputs)
+		s.recordHistory(tick, inputs, seqs)
+		s.recordAcks(tick, seqs)
 
 		ticksProcessed++
 
@@ -150,26 +332,121 @@ func (s *Session) Run(maxTicks int) error {
 		}
 	}
 
+	if s.world.Done {
+		s.transition(StateFinished)
+	}
 	return nil
 }
 
-// GetWorld returns the current world state.
+// GetWorld returns a copy of the current world state.
+// The copy shares no memory with the session, so it stays valid while Run advances the world.
 func (s *Session) GetWorld() entities.World {
-	return s.world
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return copyWorld(s.world)
+}
+
+// SetTickRate sets the number of ticks per second (20, 30 or 60); each tick then
+// simulates 1/rate seconds. The ticker restarts from the current time and the
+// rollback history is cleared. Returns an error for unsupported rates or while
+// recording, since a replay has a single time step.
+func (s *Session) SetTickRate(rate int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ticker, err := NewTicker(s.simClock, rate)
+	if err != nil {
+		return err
+	}
+	if s.recording != nil {
+		return fmt.Errorf("cannot change the tick rate while recording")
+	}
+	s.ticker = ticker
+	s.dt = 1.0 / float64(rate)
+	s.clearHistory()
+	return nil
+}
+
+// TickRate returns the number of ticks per second.
+func (s *Session) TickRate() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ticker.Rate()
+}
+
+// TickInterval returns the tick period (see Ticker.Interval). Run loops call Run
+// at this interval.
+func (s *Session) TickInterval() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ticker.Interval()
 }
 
-// IsRunning returns true if the session is currently running.
-func (s *Session) IsRunning() bool {
-	return s.running
+// InterpolationAlpha returns how far the current time is between the last tick and
+// the next one, in [0, 1] (see Ticker.Alpha).
+func (s *Session) InterpolationAlpha() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ticker.Alpha(s.simClock.Now())
+}
+
+// Reset restarts the session from world: every ship in world gets an empty queue
+// (sequence numbers start again at 1), the ticker restarts from the current time,
+// jitter buffers are dropped, and an invariant violation the session froze on is
+// cleared. Logger, invariant mode, gap and backlog policies, rollback window,
+// tick rate, pause, time scale and lifecycle are kept; a recording restarts from world.
+// A counting down, running or finished session starts again (counting down if the
+// lifecycle has a countdown); waiting and expired sessions keep their state.
+func (s *Session) Reset(world entities.World) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.world = copyWorld(world)
+	s.queues = newQueues(world, s.maxQueueSize)
+	s.jitter = make(map[entities.PlayerID]*jitterBuffer)
+	s.backlog = make(map[entities.PlayerID]BacklogStats)
+	s.acks = make(map[entities.PlayerID]InputAck)
+	s.ticker.Reset()
+	s.violation = nil
+	s.clearHistory()
+	s.restartRecording()
+	if s.state == StateCountdown || s.state == StateRunning || s.state == StateFinished {
+		s.start()
+		s.stateSince = s.clock.Now() // Restart a countdown that was already counting down
+	}
+	s.touch()
+	observability.UpdateQueueDepth(0)
 }
 
-// Stop stops the session (sets running to false).
-func (s *Session) Stop() {
-	s.running = false
+// SetRulesConfig sets the rule parameters used by later ticks, including the
+// physics constants (G, aMax, pickupRadius). Rooms use it to run custom settings.
+// Returns an error, and leaves the session unchanged, if cfg is invalid.
+func (s *Session) SetRulesConfig(cfg rules.Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rulesCfg = cfg
+	s.G = cfg.G
+	s.aMax = cfg.AMax
+	s.pickupRadius = cfg.PickupRadius
+	s.clearHistory()
+	s.recordRules(s.rulesConfig())
+	return nil
 }
 
 // SetLogger sets the logger for this session. This is optional and can be nil.
 // When set, the logger will be used for structured logging of tick performance.
 func (s *Session) SetLogger(logger logr.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	s.logger = logger
 }
@@ -40,8 +40,8 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session := NewSession(clock, world, 100)
 
 			Expect(session.GetWorld().Tick).To(Equal(uint32(0)))
-			Expect(session.GetWorld().Ship.Pos.X).To(Equal(10.0))
-			Expect(session.IsRunning()).To(BeFalse())
+			Expect(session.GetWorld().Ships[0].Pos.X).To(Equal(10.0))
+			Expect(session.State()).To(Equal(StateRunning))
 		})
 
 		It("initializes ticker at 30 Hz", func() {
@@ -59,7 +59,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			// Ticker should be initialized
 			Expect(session.ticker).NotTo(BeNil())
-			Expect(session.ticker.interval).To(Equal(33 * time.Millisecond))
+			Expect(session.ticker.Rate()).To(Equal(DefaultTickRate))
 		})
 
 		It("initializes command queue", func() {
@@ -75,8 +75,8 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			session := NewSession(clock, world, 100)
 
-			Expect(session.queue).NotTo(BeNil())
-			Expect(session.queue.Size()).To(Equal(0))
+			Expect(session.queues[entities.DefaultPlayerID]).NotTo(BeNil())
+			Expect(session.queues[entities.DefaultPlayerID].Size()).To(Equal(0))
 		})
 
 		It("sets game constants correctly", func() {
@@ -116,7 +116,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			success := session.EnqueueCommand(1, cmd)
 
 			Expect(success).To(BeTrue())
-			Expect(session.queue.Size()).To(Equal(1))
+			Expect(session.queues[entities.DefaultPlayerID].Size()).To(Equal(1))
 		})
 
 		It("processes commands in sequence order", func() {
@@ -137,7 +137,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session.EnqueueCommand(3, rules.InputCommand{Thrust: 0.3, Turn: 0.0})
 
 			// Run for 3 ticks
-			clock.Advance(33 * time.Millisecond * 3)
+			clock.Advance(DefaultTickInterval * 3)
 			err := session.Run(3)
 
 			Expect(err).To(BeNil())
@@ -162,7 +162,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			initialTick := session.GetWorld().Tick
 
 			// Advance time by 3 tick intervals
-			clock.Advance(33 * time.Millisecond * 3)
+			clock.Advance(DefaultTickInterval * 3)
 			session.Run(3)
 
 			// Should have processed 3 ticks
@@ -181,17 +181,17 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			world := entities.NewWorld(ship, sun, nil)
 			session := NewSession(clock, world, 100)
 
-			initialPos := session.GetWorld().Ship.Pos
+			initialPos := session.GetWorld().Ships[0].Pos
 
 			// Enqueue a thrust command
 			session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
 
 			// Advance time and run
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session.Run(1)
 
 			// Physics should have updated (ship moved due to thrust and gravity)
-			Expect(session.GetWorld().Ship.Pos).NotTo(Equal(initialPos))
+			Expect(session.GetWorld().Ships[0].Pos).NotTo(Equal(initialPos))
 		})
 
 		It("uses zero command when queue is empty", func() {
@@ -206,15 +206,15 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			world := entities.NewWorld(ship, sun, nil)
 			session := NewSession(clock, world, 100)
 
-			initialPos := session.GetWorld().Ship.Pos
+			initialPos := session.GetWorld().Ships[0].Pos
 
 			// Run without enqueueing any commands
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session.Run(1)
 
 			// Physics should still update (gravity pulls ship), but no thrust
 			// Ship should move due to gravity only
-			Expect(session.GetWorld().Ship.Pos).NotTo(Equal(initialPos))
+			Expect(session.GetWorld().Ships[0].Pos).NotTo(Equal(initialPos))
 			Expect(session.GetWorld().Tick).To(Equal(uint32(1)))
 		})
 
@@ -238,7 +238,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
 
 			// Run for many ticks - should stop when game is done
-			clock.Advance(33 * time.Millisecond * 100)
+			clock.Advance(DefaultTickInterval * 100)
 			session.Run(100)
 
 			// Game should be done (pallet picked up = win)
@@ -270,8 +270,8 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session2.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
 
 			// Advance clocks by same amount
-			clock1.Advance(33 * time.Millisecond * 5)
-			clock2.Advance(33 * time.Millisecond * 5)
+			clock1.Advance(DefaultTickInterval * 5)
+			clock2.Advance(DefaultTickInterval * 5)
 
 			// Run both sessions
 			session1.Run(5)
@@ -282,11 +282,11 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			finalWorld2 := session2.GetWorld()
 
 			Expect(finalWorld1.Tick).To(Equal(finalWorld2.Tick))
-			Expect(finalWorld1.Ship.Pos.X).To(Equal(finalWorld2.Ship.Pos.X))
-			Expect(finalWorld1.Ship.Pos.Y).To(Equal(finalWorld2.Ship.Pos.Y))
-			Expect(finalWorld1.Ship.Vel.X).To(Equal(finalWorld2.Ship.Vel.X))
-			Expect(finalWorld1.Ship.Vel.Y).To(Equal(finalWorld2.Ship.Vel.Y))
-			Expect(finalWorld1.Ship.Energy).To(Equal(finalWorld2.Ship.Energy))
+			Expect(finalWorld1.Ships[0].Pos.X).To(Equal(finalWorld2.Ships[0].Pos.X))
+			Expect(finalWorld1.Ships[0].Pos.Y).To(Equal(finalWorld2.Ships[0].Pos.Y))
+			Expect(finalWorld1.Ships[0].Vel.X).To(Equal(finalWorld2.Ships[0].Vel.X))
+			Expect(finalWorld1.Ships[0].Vel.Y).To(Equal(finalWorld2.Ships[0].Vel.Y))
+			Expect(finalWorld1.Ships[0].Energy).To(Equal(finalWorld2.Ships[0].Energy))
 		})
 	})
 
@@ -304,16 +304,16 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session := NewSession(clock, world, 100)
 
 			initialTick := session.GetWorld().Tick
-			initialPos := session.GetWorld().Ship.Pos
+			initialPos := session.GetWorld().Ships[0].Pos
 
 			// Run for 10 ticks
-			clock.Advance(33 * time.Millisecond * 10)
+			clock.Advance(DefaultTickInterval * 10)
 			session.Run(10)
 
 			// Tick should have incremented
 			Expect(session.GetWorld().Tick).To(Equal(initialTick + 10))
 			// Position should have changed (gravity pulls ship toward sun)
-			Expect(session.GetWorld().Ship.Pos).NotTo(Equal(initialPos))
+			Expect(session.GetWorld().Ships[0].Pos).NotTo(Equal(initialPos))
 		})
 
 		It("increments tick counter correctly", func() {
@@ -330,7 +330,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			Expect(session.GetWorld().Tick).To(Equal(uint32(0)))
 
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session.Run(1)
 
 			Expect(session.GetWorld().Tick).To(Equal(uint32(1)))
@@ -352,24 +352,24 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			// First application
 			session1 := NewSession(clock, initialWorld, 100)
 			session1.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session1.Run(1)
 			state1 := session1.GetWorld()
 
 			// Second application (same initial state, same command)
 			session2 := NewSession(clock, initialWorld, 100)
 			session2.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session2.Run(1)
 			state2 := session2.GetWorld()
 
 			// Verify states are identical
 			Expect(state1.Tick).To(Equal(state2.Tick))
-			Expect(state1.Ship.Pos.X).To(BeNumerically("~", state2.Ship.Pos.X, 0.001))
-			Expect(state1.Ship.Pos.Y).To(BeNumerically("~", state2.Ship.Pos.Y, 0.001))
-			Expect(state1.Ship.Vel.X).To(BeNumerically("~", state2.Ship.Vel.X, 0.001))
-			Expect(state1.Ship.Vel.Y).To(BeNumerically("~", state2.Ship.Vel.Y, 0.001))
-			Expect(state1.Ship.Energy).To(BeNumerically("~", state2.Ship.Energy, 0.001))
+			Expect(state1.Ships[0].Pos.X).To(BeNumerically("~", state2.Ships[0].Pos.X, 0.001))
+			Expect(state1.Ships[0].Pos.Y).To(BeNumerically("~", state2.Ships[0].Pos.Y, 0.001))
+			Expect(state1.Ships[0].Vel.X).To(BeNumerically("~", state2.Ships[0].Vel.X, 0.001))
+			Expect(state1.Ships[0].Vel.Y).To(BeNumerically("~", state2.Ships[0].Vel.Y, 0.001))
+			Expect(state1.Ships[0].Energy).To(BeNumerically("~", state2.Ships[0].Energy, 0.001))
 		})
 
 		It("applying same command multiple times produces identical results", func() {
@@ -389,7 +389,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			for i := 0; i < 3; i++ {
 				session := NewSession(clock, initialWorld, 100)
 				session.EnqueueCommand(1, cmd)
-				clock.Advance(33 * time.Millisecond)
+				clock.Advance(DefaultTickInterval)
 				session.Run(1)
 				states = append(states, session.GetWorld())
 			}
@@ -397,10 +397,10 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			// Verify all three states are identical
 			Expect(states[0].Tick).To(Equal(states[1].Tick))
 			Expect(states[1].Tick).To(Equal(states[2].Tick))
-			Expect(states[0].Ship.Pos.X).To(BeNumerically("~", states[1].Ship.Pos.X, 0.001))
-			Expect(states[1].Ship.Pos.X).To(BeNumerically("~", states[2].Ship.Pos.X, 0.001))
-			Expect(states[0].Ship.Energy).To(BeNumerically("~", states[1].Ship.Energy, 0.001))
-			Expect(states[1].Ship.Energy).To(BeNumerically("~", states[2].Ship.Energy, 0.001))
+			Expect(states[0].Ships[0].Pos.X).To(BeNumerically("~", states[1].Ships[0].Pos.X, 0.001))
+			Expect(states[1].Ships[0].Pos.X).To(BeNumerically("~", states[2].Ships[0].Pos.X, 0.001))
+			Expect(states[0].Ships[0].Energy).To(BeNumerically("~", states[1].Ships[0].Energy, 0.001))
+			Expect(states[1].Ships[0].Energy).To(BeNumerically("~", states[2].Ships[0].Energy, 0.001))
 		})
 
 		It("queue rejects duplicate sequence numbers", func() {
@@ -424,11 +424,11 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			Expect(success2).To(BeFalse())
 
 			// Verify first command is still in queue
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session.Run(1)
 			finalWorld := session.GetWorld()
 			// Ship should have moved due to thrust=1.0, not thrust=0.5
-			Expect(finalWorld.Ship.Pos.X).To(BeNumerically(">", 10.0))
+			Expect(finalWorld.Ships[0].Pos.X).To(BeNumerically(">", 10.0))
 		})
 
 		It("commands are deterministic (not just cached)", func() {
@@ -455,28 +455,28 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			// Apply same command to different initial states
 			session1 := NewSession(clock, world1, 100)
 			session1.EnqueueCommand(1, cmd)
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session1.Run(1)
 			state1 := session1.GetWorld()
 
 			session2 := NewSession(clock, world2, 100)
 			session2.EnqueueCommand(1, cmd)
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session2.Run(1)
 			state2 := session2.GetWorld()
 
 			// States should be different (different initial positions)
-			Expect(state1.Ship.Pos.X).NotTo(BeNumerically("~", state2.Ship.Pos.X, 0.1))
+			Expect(state1.Ships[0].Pos.X).NotTo(BeNumerically("~", state2.Ships[0].Pos.X, 0.1))
 
 			// But applying same command to same initial state should produce same result
 			session3 := NewSession(clock, world1, 100)
 			session3.EnqueueCommand(1, cmd)
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session3.Run(1)
 			state3 := session3.GetWorld()
 
-			Expect(state1.Ship.Pos.X).To(BeNumerically("~", state3.Ship.Pos.X, 0.001))
-			Expect(state1.Ship.Pos.Y).To(BeNumerically("~", state3.Ship.Pos.Y, 0.001))
+			Expect(state1.Ships[0].Pos.X).To(BeNumerically("~", state3.Ships[0].Pos.X, 0.001))
+			Expect(state1.Ships[0].Pos.Y).To(BeNumerically("~", state3.Ships[0].Pos.Y, 0.001))
 		})
 
 		It("idempotency holds across multiple ticks", func() {
@@ -494,7 +494,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session1 := NewSession(clock, initialWorld, 100)
 			session1.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
 			session1.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond * 2)
+			clock.Advance(DefaultTickInterval * 2)
 			session1.Run(2)
 			state1 := session1.GetWorld()
 
@@ -502,14 +502,14 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session2 := NewSession(clock, initialWorld, 100)
 			session2.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
 			session2.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond * 2)
+			clock.Advance(DefaultTickInterval * 2)
 			session2.Run(2)
 			state2 := session2.GetWorld()
 
 			// Verify states are identical
 			Expect(state1.Tick).To(Equal(state2.Tick))
-			Expect(state1.Ship.Pos.X).To(BeNumerically("~", state2.Ship.Pos.X, 0.001))
-			Expect(state1.Ship.Energy).To(BeNumerically("~", state2.Ship.Energy, 0.001))
+			Expect(state1.Ships[0].Pos.X).To(BeNumerically("~", state2.Ships[0].Pos.X, 0.001))
+			Expect(state1.Ships[0].Energy).To(BeNumerically("~", state2.Ships[0].Energy, 0.001))
 		})
 	})
 
@@ -528,41 +528,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			retrievedWorld := session.GetWorld()
 			Expect(retrievedWorld.Tick).To(Equal(uint32(0)))
-			Expect(retrievedWorld.Ship.Pos.X).To(Equal(10.0))
-		})
-
-		It("IsRunning() returns correct state", func() {
-			clock := NewFakeClock()
-			ship := entities.NewShip(
-				entities.NewVec2(10.0, 0.0),
-				entities.NewVec2(0.0, 0.0),
-				0.0,
-				100.0,
-			)
-			sun := entities.NewSun(entities.NewVec2(0.0, 0.0), sunRadius, sunMass)
-			world := entities.NewWorld(ship, sun, nil)
-			session := NewSession(clock, world, 100)
-
-			Expect(session.IsRunning()).To(BeFalse())
-
-			// Note: Run() sets running to true during execution, then false when done
-			// We can't easily test this without making Run() async, which is beyond scope
-		})
-
-		It("Stop() stops the session", func() {
-			clock := NewFakeClock()
-			ship := entities.NewShip(
-				entities.NewVec2(10.0, 0.0),
-				entities.NewVec2(0.0, 0.0),
-				0.0,
-				100.0,
-			)
-			sun := entities.NewSun(entities.NewVec2(0.0, 0.0), sunRadius, sunMass)
-			world := entities.NewWorld(ship, sun, nil)
-			session := NewSession(clock, world, 100)
-
-			session.Stop()
-			Expect(session.IsRunning()).To(BeFalse())
+			Expect(retrievedWorld.Ships[0].Pos.X).To(Equal(10.0))
 		})
 	})
 
@@ -594,8 +560,8 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session2.EnqueueCommand(3, rules.InputCommand{Thrust: 0.0, Turn: 0.3})
 
 			// Advance time identically
-			clock1.Advance(33 * time.Millisecond * 3)
-			clock2.Advance(33 * time.Millisecond * 3)
+			clock1.Advance(DefaultTickInterval * 3)
+			clock2.Advance(DefaultTickInterval * 3)
 
 			// Run both sessions
 			session1.Run(3)
@@ -606,11 +572,11 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			final2 := session2.GetWorld()
 
 			Expect(final1.Tick).To(Equal(final2.Tick))
-			Expect(final1.Ship.Pos.X).To(BeNumerically("~", final2.Ship.Pos.X, 0.001))
-			Expect(final1.Ship.Pos.Y).To(BeNumerically("~", final2.Ship.Pos.Y, 0.001))
-			Expect(final1.Ship.Vel.X).To(BeNumerically("~", final2.Ship.Vel.X, 0.001))
-			Expect(final1.Ship.Vel.Y).To(BeNumerically("~", final2.Ship.Vel.Y, 0.001))
-			Expect(final1.Ship.Energy).To(BeNumerically("~", final2.Ship.Energy, 0.001))
+			Expect(final1.Ships[0].Pos.X).To(BeNumerically("~", final2.Ships[0].Pos.X, 0.001))
+			Expect(final1.Ships[0].Pos.Y).To(BeNumerically("~", final2.Ships[0].Pos.Y, 0.001))
+			Expect(final1.Ships[0].Vel.X).To(BeNumerically("~", final2.Ships[0].Vel.X, 0.001))
+			Expect(final1.Ships[0].Vel.Y).To(BeNumerically("~", final2.Ships[0].Vel.Y, 0.001))
+			Expect(final1.Ships[0].Energy).To(BeNumerically("~", final2.Ships[0].Energy, 0.001))
 		})
 
 		It("end-to-end command ordering with out-of-order enqueue", func() {
@@ -631,7 +597,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
 
 			// Advance time and run
-			clock.Advance(33 * time.Millisecond * 3)
+			clock.Advance(DefaultTickInterval * 3)
 			session.Run(3)
 
 			// Verify commands were applied in order (1, 2, 3)
@@ -639,7 +605,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			final := session.GetWorld()
 			Expect(final.Tick).To(Equal(uint32(3)))
 			// Ship should have moved forward (thrust applied first two ticks)
-			Expect(final.Ship.Pos.X).To(BeNumerically(">", 10.0))
+			Expect(final.Ships[0].Pos.X).To(BeNumerically(">", 10.0))
 		})
 
 		It("end-to-end rollback and replay produces same result", func() {
@@ -657,7 +623,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session1 := NewSession(clock, initialWorld, 100)
 			session1.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
 			session1.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond * 2)
+			clock.Advance(DefaultTickInterval * 2)
 			session1.Run(2)
 
 			// Capture snapshot at tick 2
@@ -666,7 +632,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			// Continue with more commands
 			session1.EnqueueCommand(3, rules.InputCommand{Thrust: 0.0, Turn: 0.3})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session1.Run(1)
 			final1 := session1.GetWorld()
 
@@ -674,15 +640,15 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			restoredWorld := manager.RestoreSnapshot(snapshot)
 			session2 := NewSession(clock, restoredWorld, 100)
 			session2.EnqueueCommand(3, rules.InputCommand{Thrust: 0.0, Turn: 0.3})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session2.Run(1)
 			final2 := session2.GetWorld()
 
 			// Verify final states match
 			Expect(final1.Tick).To(Equal(final2.Tick))
-			Expect(final1.Ship.Pos.X).To(BeNumerically("~", final2.Ship.Pos.X, 0.001))
-			Expect(final1.Ship.Pos.Y).To(BeNumerically("~", final2.Ship.Pos.Y, 0.001))
-			Expect(final1.Ship.Energy).To(BeNumerically("~", final2.Ship.Energy, 0.001))
+			Expect(final1.Ships[0].Pos.X).To(BeNumerically("~", final2.Ships[0].Pos.X, 0.001))
+			Expect(final1.Ships[0].Pos.Y).To(BeNumerically("~", final2.Ships[0].Pos.Y, 0.001))
+			Expect(final1.Ships[0].Energy).To(BeNumerically("~", final2.Ships[0].Energy, 0.001))
 		})
 
 		It("end-to-end ticker queue session integration", func() {
@@ -697,14 +663,14 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			world := entities.NewWorld(ship, sun, nil)
 			session := NewSession(clock, world, 100)
 
-			initialPos := world.Ship.Pos
+			initialPos := world.Ships[0].Pos
 
 			// Enqueue commands
 			session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
 			session.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
 
 			// Advance clock by multiple intervals
-			clock.Advance(33 * time.Millisecond * 5)
+			clock.Advance(DefaultTickInterval * 5)
 
 			// Run session (should process up to 5 ticks, but only 2 commands available)
 			session.Run(5)
@@ -716,9 +682,9 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			// Verify commands were applied and world state changed
 			// (Position may change due to gravity + thrust, but should be different from initial)
-			Expect(final.Ship.Pos.X).NotTo(Equal(initialPos.X))
+			Expect(final.Ships[0].Pos.X).NotTo(Equal(initialPos.X))
 			// Energy should have decreased due to thrust commands
-			Expect(final.Ship.Energy).To(BeNumerically("<", 100.0))
+			Expect(final.Ships[0].Energy).To(BeNumerically("<", 100.0))
 		})
 
 		It("end-to-end rollback hooks with session integration", func() {
@@ -735,7 +701,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			// Apply command and run
 			session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session.Run(1)
 
 			// Create snapshot manager with hook
@@ -763,7 +729,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 
 			// Verify restored state matches
 			Expect(restored.Tick).To(Equal(uint32(1)))
-			Expect(restored.Ship.Pos.X).To(BeNumerically("~", session.GetWorld().Ship.Pos.X, 0.001))
+			Expect(restored.Ships[0].Pos.X).To(BeNumerically("~", session.GetWorld().Ships[0].Pos.X, 0.001))
 		})
 
 		It("end-to-end complex multi-tick scenario with rollback and replay", func() {
@@ -782,17 +748,17 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			// First run: apply commands and capture snapshots
 			session1 := NewSession(clock, initialWorld, 100)
 			session1.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session1.Run(1)
 			snapshot1 := manager.CaptureSnapshot(session1.GetWorld(), 1, clock)
 
 			session1.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session1.Run(1)
 			snapshot2 := manager.CaptureSnapshot(session1.GetWorld(), 2, clock)
 
 			session1.EnqueueCommand(3, rules.InputCommand{Thrust: 0.0, Turn: 0.3})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session1.Run(1)
 			final1 := session1.GetWorld()
 
@@ -800,24 +766,24 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			restoredWorld1 := manager.RestoreSnapshot(snapshot1)
 			session2 := NewSession(clock, restoredWorld1, 100)
 			session2.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session2.Run(1)
 
 			// Verify state matches snapshot2
 			state2 := session2.GetWorld()
 			Expect(state2.Tick).To(Equal(snapshot2.Tick))
-			Expect(state2.Ship.Pos.X).To(BeNumerically("~", snapshot2.World.Ship.Pos.X, 0.001))
+			Expect(state2.Ships[0].Pos.X).To(BeNumerically("~", snapshot2.World.Ships[0].Pos.X, 0.001))
 
 			// Continue replay
 			session2.EnqueueCommand(3, rules.InputCommand{Thrust: 0.0, Turn: 0.3})
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(DefaultTickInterval)
 			session2.Run(1)
 			final2 := session2.GetWorld()
 
 			// Verify final states match
 			Expect(final1.Tick).To(Equal(final2.Tick))
-			Expect(final1.Ship.Pos.X).To(BeNumerically("~", final2.Ship.Pos.X, 0.001))
-			Expect(final1.Ship.Energy).To(BeNumerically("~", final2.Ship.Energy, 0.001))
+			Expect(final1.Ships[0].Pos.X).To(BeNumerically("~", final2.Ships[0].Pos.X, 0.001))
+			Expect(final1.Ships[0].Energy).To(BeNumerically("~", final2.Ships[0].Energy, 0.001))
 		})
 	})
 
@@ -846,7 +812,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			initialCount := initialMetric.Histogram.GetSampleCount()
 
 			// Run session for multiple ticks
-			clock.Advance(33 * time.Millisecond * 5)
+			clock.Advance(DefaultTickInterval * 5)
 			session.Run(5)
 
 			// Verify histogram recorded tick durations
@@ -871,7 +837,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session := NewSession(clock, world, 100)
 
 			// Run session for many ticks to get distribution
-			clock.Advance(33 * time.Millisecond * 100)
+			clock.Advance(DefaultTickInterval * 100)
 			session.Run(100)
 
 			// Verify histogram has buckets configured
@@ -911,8 +877,8 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session2.EnqueueCommand(2, rules.InputCommand{Thrust: 0.5, Turn: 0.0})
 
 			// Advance clocks identically
-			clock1.Advance(33 * time.Millisecond * 5)
-			clock2.Advance(33 * time.Millisecond * 5)
+			clock1.Advance(DefaultTickInterval * 5)
+			clock2.Advance(DefaultTickInterval * 5)
 
 			// Run both sessions (instrumentation should not affect determinism)
 			session1.Run(5)
@@ -923,11 +889,11 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			final2 := session2.GetWorld()
 
 			Expect(final1.Tick).To(Equal(final2.Tick))
-			Expect(final1.Ship.Pos.X).To(BeNumerically("~", final2.Ship.Pos.X, 0.001))
-			Expect(final1.Ship.Pos.Y).To(BeNumerically("~", final2.Ship.Pos.Y, 0.001))
-			Expect(final1.Ship.Vel.X).To(BeNumerically("~", final2.Ship.Vel.X, 0.001))
-			Expect(final1.Ship.Vel.Y).To(BeNumerically("~", final2.Ship.Vel.Y, 0.001))
-			Expect(final1.Ship.Energy).To(BeNumerically("~", final2.Ship.Energy, 0.001))
+			Expect(final1.Ships[0].Pos.X).To(BeNumerically("~", final2.Ships[0].Pos.X, 0.001))
+			Expect(final1.Ships[0].Pos.Y).To(BeNumerically("~", final2.Ships[0].Pos.Y, 0.001))
+			Expect(final1.Ships[0].Vel.X).To(BeNumerically("~", final2.Ships[0].Vel.X, 0.001))
+			Expect(final1.Ships[0].Vel.Y).To(BeNumerically("~", final2.Ships[0].Vel.Y, 0.001))
+			Expect(final1.Ships[0].Energy).To(BeNumerically("~", final2.Ships[0].Energy, 0.001))
 		})
 
 		It("has minimal performance impact", func() {
@@ -943,7 +909,7 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 			session := NewSession(clock, world, 100)
 
 			// Measure time for running many ticks
-			clock.Advance(33 * time.Millisecond * 1000)
+			clock.Advance(DefaultTickInterval * 1000)
 			start := time.Now()
 			session.Run(1000)
 			elapsed := time.Since(start)
@@ -956,6 +922,41 @@ var _ = Describe("Session Tick Loop", Label("scope:unit", "loop:g3-orch", "layer
 	})
 })
 
+var _ = Describe("Session Rules Config", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:rules-config", "r:medium"), func() {
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		ship := entities.NewShip(entities.NewVec2(200.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 50.0)
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		session = NewSession(clock, entities.NewWorld(ship, sun, nil), 10)
+	})
+
+	It("steps the world with the configured rules", func() {
+		cfg := rules.DefaultConfig()
+		cfg.ThrustDrainRate = 5.0
+		cfg.G = 2.0
+		Expect(session.SetRulesConfig(cfg)).To(Succeed())
+		Expect(session.G).To(Equal(2.0))
+
+		session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})
+		clock.Advance(DefaultTickInterval)
+		Expect(session.Run(1)).To(Succeed())
+
+		Expect(session.GetWorld().Ships[0].Energy).To(Equal(float32(45.0)))
+	})
+
+	It("rejects an invalid config and keeps the previous one", func() {
+		cfg := rules.DefaultConfig()
+		cfg.MaxEnergy = 0
+		Expect(session.SetRulesConfig(cfg)).NotTo(Succeed())
+		Expect(session.rulesConfig()).To(Equal(rules.DefaultConfig()))
+	})
+})
+
 // testRollbackHook is a test implementation of RollbackHook for integration tests.
 type testRollbackHook struct {
 	beforeSnapshot func(*Snapshot)
@@ -1,6 +1,8 @@
 package session
 
 import (
+	"fmt"
+	"sync"
 	"time"
 )
 
@@ -11,9 +13,10 @@ type Clock interface {
 }
 
 // FakeClock is a deterministic clock implementation for testing.
-// It allows precise control over time advancement.
+// It allows precise control over time advancement and is safe for concurrent use.
 type FakeClock struct {
-	startTime time.Time
+	mu          sync.Mutex
+	startTime   time.Time
 	currentTime time.Time
 }
 
@@ -28,16 +31,22 @@ func NewFakeClock() *FakeClock {
 
 // Now returns the current fake time.
 func (f *FakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	return f.currentTime
 }
 
 // Advance moves the fake clock forward by the specified duration.
 func (f *FakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.currentTime = f.currentTime.Add(d)
 }
 
 // SetTime sets the fake clock to a specific time.
 func (f *FakeClock) SetTime(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	f.currentTime = t
 }
 
@@ -54,52 +63,139 @@ func (r *RealClock) Now() time.Time {
 	return time.Now()
 }
 
-// Ticker generates ticks at a fixed rate.
-// It uses a clock interface to allow deterministic testing.
+// Supported tick rates, in ticks per second.
+const (
+	TickRate20 = 20
+	TickRate30 = 30
+	TickRate60 = 60
+
+	// DefaultTickRate is the tick rate of new sessions
+	DefaultTickRate = TickRate30
+)
+
+// DefaultTickInterval is the tick period at DefaultTickRate rounded up to a whole
+// nanosecond (33.333334ms), so advancing a clock by it always makes a tick due.
+const DefaultTickInterval = (time.Second + DefaultTickRate - 1) / DefaultTickRate
+
+// ValidateTickRate returns an error unless rate is a supported tick rate (20, 30 or 60).
+func ValidateTickRate(rate int) error {
+	switch rate {
+	case TickRate20, TickRate30, TickRate60:
+		return nil
+	default:
+		return fmt.Errorf("unsupported tick rate: %d (expected 20, 30 or 60)", rate)
+	}
+}
+
+// Ticker is a fixed-timestep accumulator: it makes rate ticks due per second of
+// clock time. Due ticks are computed exactly from the time elapsed since the
+// ticker started, so the fractional remainder of a tick carries over and tick time
+// never drifts from clock time. It uses a clock interface to allow deterministic testing.
 type Ticker struct {
-	clock    Clock
-	interval time.Duration
-	lastTick time.Time
+	clock  Clock
+	rate   int
+	origin time.Time // Reference time of the ticks counted in ticks
+	ticks  int64     // Ticks consumed since origin
 }
 
-// NewTicker creates a new ticker with the specified clock and interval.
-func NewTicker(clock Clock, interval time.Duration) *Ticker {
-	return &Ticker{
-		clock:    clock,
-		interval: interval,
-		lastTick: clock.Now(),
+// NewTicker creates a ticker at rate ticks per second, starting at the current time.
+// Returns an error if the rate is not supported (see ValidateTickRate).
+func NewTicker(clock Clock, rate int) (*Ticker, error) {
+	if err := ValidateTickRate(rate); err != nil {
+		return nil, err
 	}
+	return &Ticker{
+		clock:  clock,
+		rate:   rate,
+		origin: clock.Now(),
+	}, nil
 }
 
-// NewFixedRateTicker creates a new ticker at 30 Hz (33.33ms intervals).
+// NewFixedRateTicker creates a new ticker at DefaultTickRate (30 Hz).
 func NewFixedRateTicker(clock Clock) *Ticker {
-	// 30 Hz = 1/30 seconds = 33.333... milliseconds
-	// Using 33ms is close enough (actual would be 33.333...ms)
-	interval := 33 * time.Millisecond
-	return NewTicker(clock, interval)
+	ticker, _ := NewTicker(clock, DefaultTickRate)
+	return ticker
+}
+
+// Rate returns the number of ticks per second.
+func (t *Ticker) Rate() int {
+	return t.rate
+}
+
+// Interval returns the tick period rounded up to a whole nanosecond.
+func (t *Ticker) Interval() time.Duration {
+	return (time.Second + time.Duration(t.rate) - 1) / time.Duration(t.rate)
+}
+
+// due returns the number of ticks due at now and not consumed yet.
+func (t *Ticker) due(now time.Time) int64 {
+	elapsed := now.Sub(t.origin)
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(elapsed)*int64(t.rate)/int64(time.Second) - t.ticks
 }
 
-// ShouldTick returns true if enough time has passed since the last tick.
+// consume marks n ticks as consumed. Whole seconds are moved into the origin,
+// so the elapsed time the ticker multiplies stays small.
+func (t *Ticker) consume(n int64) {
+	t.ticks += n
+	if seconds := t.ticks / int64(t.rate); seconds > 0 {
+		t.origin = t.origin.Add(time.Duration(seconds) * time.Second)
+		t.ticks -= seconds * int64(t.rate)
+	}
+}
+
+// ShouldTick returns true if a tick is due at now.
 func (t *Ticker) ShouldTick(now time.Time) bool {
-	elapsed := now.Sub(t.lastTick)
-	return elapsed >= t.interval
+	return t.due(now) > 0
 }
 
-// Tick advances the ticker if enough time has passed.
+// Tick consumes one due tick.
 // Returns true if a tick occurred, false otherwise.
 func (t *Ticker) Tick(now time.Time) bool {
 	if !t.ShouldTick(now) {
 		return false
 	}
-
-	// Update lastTick to the current time
-	// This ensures we maintain the correct interval even if time jumps
-	t.lastTick = now
+	t.consume(1)
 	return true
 }
 
-// Reset resets the ticker state, setting lastTick to the current time.
-func (t *Ticker) Reset() {
-	t.lastTick = t.clock.Now()
+// Advance consumes the ticks due at now. It returns the number of ticks to run,
+// at most maxTicks, and the number of ticks dropped because more were due: a
+// ticker that fell behind skips the ticks it cannot run instead of catching up.
+func (t *Ticker) Advance(now time.Time, maxTicks int) (ticks, dropped int) {
+	due := t.due(now)
+	if due <= 0 {
+		return 0, 0
+	}
+	t.consume(due)
+	if maxTicks < 0 {
+		maxTicks = 0
+	}
+	if due > int64(maxTicks) {
+		return maxTicks, int(due - int64(maxTicks))
+	}
+	return int(due), 0
 }
 
+// Alpha returns how far now is between the last consumed tick and the next one,
+// in [0, 1]. Renderers interpolate between the last two ticks by it. It is 1 if
+// ticks are due and not consumed yet.
+func (t *Ticker) Alpha(now time.Time) float64 {
+	elapsed := now.Sub(t.origin)
+	if elapsed <= 0 {
+		return 0
+	}
+	remainder := int64(elapsed)*int64(t.rate) - t.ticks*int64(time.Second)
+	if remainder >= int64(time.Second) {
+		return 1
+	}
+	return float64(remainder) / float64(time.Second)
+}
+
+// Reset restarts the ticker at the current time, dropping any fractional tick.
+func (t *Ticker) Reset() {
+	t.origin = t.clock.Now()
+	t.ticks = 0
+}
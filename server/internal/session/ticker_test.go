@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -14,23 +15,28 @@ func TestTicker(t *testing.T) {
 }
 
 var _ = Describe("Fixed-Rate Ticker", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:tick-determinism", "r:high"), func() {
-	const tickInterval = 33 * time.Millisecond // 30 Hz = 33.33ms
-	const epsilon = 1 * time.Millisecond        // Allow 1ms tolerance for timing checks
+	const tickInterval = DefaultTickInterval
+	const epsilon = 1 * time.Millisecond // Allow 1ms tolerance for timing checks
 
 	Describe("Ticker Creation", func() {
 		It("creates ticker with correct 30 Hz interval", func() {
 			clock := NewFakeClock()
 			ticker := NewFixedRateTicker(clock)
 
-			Expect(ticker.interval).To(Equal(tickInterval))
+			Expect(ticker.Rate()).To(Equal(30))
+			Expect(ticker.Interval()).To(Equal(33333334 * time.Nanosecond))
 		})
 
-		It("creates ticker with custom interval", func() {
+		It("creates tickers at the supported rates only", func() {
 			clock := NewFakeClock()
-			customInterval := 50 * time.Millisecond
-			ticker := NewTicker(clock, customInterval)
+			for _, rate := range []int{TickRate20, TickRate30, TickRate60} {
+				ticker, err := NewTicker(clock, rate)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(ticker.Rate()).To(Equal(rate))
+			}
 
-			Expect(ticker.interval).To(Equal(customInterval))
+			_, err := NewTicker(clock, 50)
+			Expect(err).To(MatchError(ContainSubstring("unsupported tick rate")))
 		})
 	})
 
@@ -187,15 +193,12 @@ var _ = Describe("Fixed-Rate Ticker", Label("scope:unit", "loop:g3-orch", "layer
 			// Jump forward by multiple intervals
 			clock.Advance(tickInterval * 5)
 			now := clock.Now()
-			ticked := ticker.Tick(now)
-
-			// Should tick once (not multiple times in a single call)
-			Expect(ticked).To(BeTrue())
 
-			// After tick, lastTick = now, so calling Tick() again with same now should not tick
-			// This is the expected behavior - Tick() only ticks once per call
-			ticked2 := ticker.Tick(now)
-			Expect(ticked2).To(BeFalse())
+			// Tick() consumes one due tick per call, so every interval of the jump ticks
+			for i := 0; i < 5; i++ {
+				Expect(ticker.Tick(now)).To(BeTrue())
+			}
+			Expect(ticker.Tick(now)).To(BeFalse())
 
 			// Advance time by one more interval and tick should work again
 			clock.Advance(tickInterval)
@@ -224,5 +227,113 @@ var _ = Describe("Fixed-Rate Ticker", Label("scope:unit", "loop:g3-orch", "layer
 			Expect(ticked2).To(BeTrue())
 		})
 	})
+
+	Describe("Accumulator", func() {
+		It("keeps tick time in step with clock time at every rate", func() {
+			for _, rate := range []int{TickRate20, TickRate30, TickRate60} {
+				clock := NewFakeClock()
+				ticker, err := NewTicker(clock, rate)
+				Expect(err).NotTo(HaveOccurred())
+
+				// An hour of 10ms steps, which no rate divides evenly
+				total := 0
+				for i := 0; i < 360000; i++ {
+					clock.Advance(10 * time.Millisecond)
+					ticks, dropped := ticker.Advance(clock.Now(), 10)
+					Expect(dropped).To(BeZero())
+					total += ticks
+				}
+				Expect(total).To(Equal(3600*rate), "rate %d", rate)
+			}
+		})
+
+		It("carries the fractional remainder over to the next advance", func() {
+			clock := NewFakeClock()
+			ticker := NewFixedRateTicker(clock)
+
+			// 50ms is 1.5 ticks at 30 Hz
+			clock.Advance(50 * time.Millisecond)
+			Expect(ticker.Advance(clock.Now(), 10)).To(Equal(1))
+			clock.Advance(50 * time.Millisecond)
+			Expect(ticker.Advance(clock.Now(), 10)).To(Equal(2))
+		})
+
+		It("drops the ticks due beyond the limit", func() {
+			clock := NewFakeClock()
+			ticker := NewFixedRateTicker(clock)
+
+			clock.Advance(tickInterval * 12)
+			ticks, dropped := ticker.Advance(clock.Now(), 10)
+			Expect(ticks).To(Equal(10))
+			Expect(dropped).To(Equal(2))
+
+			// The dropped ticks are not due later
+			Expect(ticker.ShouldTick(clock.Now())).To(BeFalse())
+		})
+
+		It("reports the interpolation alpha between ticks", func() {
+			clock := NewFakeClock()
+			ticker, err := NewTicker(clock, TickRate20)
+			Expect(err).NotTo(HaveOccurred())
+
+			clock.Advance(60 * time.Millisecond)
+			Expect(ticker.Alpha(clock.Now())).To(Equal(1.0)) // A tick is due
+			Expect(ticker.Advance(clock.Now(), 10)).To(Equal(1))
+			Expect(ticker.Alpha(clock.Now())).To(BeNumerically("~", 0.2, 1e-9))
+
+			clock.Advance(25 * time.Millisecond)
+			Expect(ticker.Alpha(clock.Now())).To(BeNumerically("~", 0.7, 1e-9))
+		})
+	})
+})
+
+var _ = Describe("Session Tick Rate", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:tick-determinism", "r:high"), func() {
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+	})
+
+	It("runs rate ticks of 1/rate seconds per second of clock time", func() {
+		Expect(session.SetTickRate(TickRate60)).To(Succeed())
+		Expect(session.TickRate()).To(Equal(60))
+		Expect(session.TickInterval()).To(Equal(16666667 * time.Nanosecond))
+
+		for i := 0; i < 10; i++ {
+			clock.Advance(100 * time.Millisecond)
+			Expect(session.Run(10)).To(Succeed())
+		}
+		Expect(session.GetWorld().Tick).To(Equal(uint32(60)))
+		Expect(session.dt).To(Equal(1.0 / 60))
+	})
+
+	It("drops the ticks due beyond the run limit", func() {
+		clock.Advance(15 * DefaultTickInterval)
+		Expect(session.Run(10)).To(Succeed())
+
+		Expect(session.GetWorld().Tick).To(Equal(uint32(10)))
+
+		// The dropped ticks are not run later
+		clock.Advance(DefaultTickInterval)
+		Expect(session.Run(10)).To(Succeed())
+		Expect(session.GetWorld().Tick).To(Equal(uint32(11)))
+	})
+
+	It("reports the interpolation alpha", func() {
+		clock.Advance(50 * time.Millisecond)
+		Expect(session.Run(10)).To(Succeed())
+		Expect(session.InterpolationAlpha()).To(BeNumerically("~", 0.5, 1e-6))
+	})
+
+	It("rejects unsupported rates and rate changes while recording", func() {
+		Expect(session.SetTickRate(45)).To(MatchError(ContainSubstring("unsupported tick rate")))
+		session.StartRecording("seeded", 7)
+		Expect(session.SetTickRate(TickRate20)).To(MatchError(ContainSubstring("recording")))
+		Expect(session.TickRate()).To(Equal(DefaultTickRate))
+	})
 })
 
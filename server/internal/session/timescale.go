@@ -0,0 +1,107 @@
+package session
+
+import (
+	"fmt"
+	"time"
+)
+
+// Time scale limits (multiples of real time).
+const (
+	MinTimeScale = 0.25
+	MaxTimeScale = 4.0
+)
+
+// scaledClock is a session's simulation clock: it follows the session clock
+// at scale times its rate and stands still while paused. The ticker reads it,
+// so pausing stops ticks and scaling changes how many ticks fall in a second.
+// It is only used with the session lock held.
+type scaledClock struct {
+	clock  Clock
+	base   time.Time // Simulation time at since
+	since  time.Time // Session clock time of the last pause, resume or scale change
+	scale  float64
+	paused bool
+}
+
+// newScaledClock creates a running simulation clock at scale 1, starting at clock's time.
+func newScaledClock(clock Clock) *scaledClock {
+	now := clock.Now()
+	return &scaledClock{clock: clock, base: now, since: now, scale: 1}
+}
+
+// Now returns the simulation time. It is computed from the time since the last
+// change, so scaling never accumulates rounding errors.
+func (c *scaledClock) Now() time.Time {
+	if c.paused {
+		return c.base
+	}
+	return c.base.Add(time.Duration(float64(c.clock.Now().Sub(c.since)) * c.scale))
+}
+
+// set pauses or resumes the clock and sets its scale from now on.
+func (c *scaledClock) set(paused bool, scale float64) {
+	c.base = c.Now()
+	c.since = c.clock.Now()
+	c.paused = paused
+	c.scale = scale
+}
+
+// Pause stops the simulation: Run processes no ticks until Resume. Commands
+// are still queued, and applied from the first tick after Resume.
+func (s *Session) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.simClock.paused {
+		return
+	}
+	s.simClock.set(true, s.simClock.scale)
+	if s.logger.Enabled() {
+		s.logger.Info("Session paused", "component", "session", "tick", s.world.Tick)
+	}
+}
+
+// Resume continues a paused simulation; the time it was paused is not simulated.
+func (s *Session) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.simClock.paused {
+		return
+	}
+	s.simClock.set(false, s.simClock.scale)
+	if s.logger.Enabled() {
+		s.logger.Info("Session resumed", "component", "session", "tick", s.world.Tick)
+	}
+}
+
+// Paused reports whether the session is paused.
+func (s *Session) Paused() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.simClock.paused
+}
+
+// SetTimeScale runs the simulation at scale times real time, in [MinTimeScale,
+// MaxTimeScale]: 0.5 is slow motion, 2 fast-forward. Ticks keep their time step,
+// so more or fewer of them are run per second. Returns an error for scales out of range.
+func (s *Session) SetTimeScale(scale float64) error {
+	if scale < MinTimeScale || scale > MaxTimeScale {
+		return fmt.Errorf("invalid time scale: must be in [%g, %g], got %g", MinTimeScale, MaxTimeScale, scale)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.simClock.set(s.simClock.paused, scale)
+	return nil
+}
+
+// TimeScale returns the time scale of the simulation (1 for real time).
+func (s *Session) TimeScale() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.simClock.scale
+}
@@ -0,0 +1,102 @@
+package session
+
+import (
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Time Control", Label("scope:unit", "loop:g3-orch", "layer:sim", "double:fake-io", "b:time-control", "r:high"), func() {
+	var (
+		clock   *FakeClock
+		session *Session
+	)
+
+	// runFor advances the clock by d and runs the ticks due
+	runFor := func(d time.Duration) {
+		clock.Advance(d)
+		ExpectWithOffset(1, session.Run(100)).To(Succeed())
+	}
+
+	tick := func() uint32 {
+		return session.GetWorld().Tick
+	}
+
+	BeforeEach(func() {
+		clock = NewFakeClock()
+		session = NewSession(clock, levels.Seeded(7), 100)
+	})
+
+	It("runs no ticks while paused and keeps the queued commands", func() {
+		runFor(10 * DefaultTickInterval)
+		Expect(tick()).To(Equal(uint32(10)))
+
+		session.Pause()
+		Expect(session.Paused()).To(BeTrue())
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0})).To(BeTrue())
+		runFor(time.Second)
+		Expect(tick()).To(Equal(uint32(10)))
+		Expect(session.queues[entities.DefaultPlayerID].Size()).To(Equal(1))
+
+		// The paused second is not simulated
+		session.Resume()
+		Expect(session.Paused()).To(BeFalse())
+		runFor(DefaultTickInterval)
+		Expect(tick()).To(Equal(uint32(11)))
+		Expect(session.queues[entities.DefaultPlayerID].Size()).To(Equal(0))
+	})
+
+	It("keeps the interpolation alpha while paused", func() {
+		runFor(50 * time.Millisecond)
+		session.Pause()
+		alpha := session.InterpolationAlpha()
+		clock.Advance(20 * time.Millisecond)
+		Expect(session.InterpolationAlpha()).To(Equal(alpha))
+	})
+
+	It("runs scale times as many ticks per second", func() {
+		Expect(session.SetTimeScale(0.5)).To(Succeed())
+		Expect(session.TimeScale()).To(Equal(0.5))
+		runFor(time.Second)
+		Expect(tick()).To(Equal(uint32(15)))
+
+		Expect(session.SetTimeScale(2)).To(Succeed())
+		for i := 0; i < 10; i++ {
+			runFor(100 * time.Millisecond)
+		}
+		Expect(tick()).To(Equal(uint32(75)))
+	})
+
+	It("produces the same world as real time, only sooner or later", func() {
+		reference := NewSession(NewFakeClock(), levels.Seeded(7), 100)
+		Expect(reference.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.5})).To(BeTrue())
+		reference.clock.(*FakeClock).Advance(30 * DefaultTickInterval)
+		Expect(reference.Run(100)).To(Succeed())
+
+		Expect(session.SetTimeScale(MaxTimeScale)).To(Succeed())
+		Expect(session.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.5})).To(BeTrue())
+		runFor(250 * time.Millisecond)
+		Expect(session.GetWorld()).To(Equal(reference.GetWorld()))
+	})
+
+	It("keeps pause and scale through scale changes and resets", func() {
+		session.Pause()
+		Expect(session.SetTimeScale(2)).To(Succeed())
+		runFor(time.Second)
+		Expect(tick()).To(BeZero())
+
+		session.Reset(levels.Seeded(7))
+		Expect(session.Paused()).To(BeTrue())
+		Expect(session.TimeScale()).To(Equal(2.0))
+	})
+
+	It("rejects time scales out of range", func() {
+		Expect(session.SetTimeScale(0)).To(MatchError(ContainSubstring("invalid time scale")))
+		Expect(session.SetTimeScale(MaxTimeScale * 2)).To(HaveOccurred())
+		Expect(session.TimeScale()).To(Equal(1.0))
+	})
+})
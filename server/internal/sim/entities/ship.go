@@ -1,14 +1,23 @@
 package entities
 
-// Ship represents the player's ship in the game.
+// PlayerID identifies the player that owns a ship.
+type PlayerID string
+
+// DefaultPlayerID is the owner of the ship in single-player worlds created by NewWorld.
+const DefaultPlayerID PlayerID = "p1"
+
+// Ship represents a player's ship in the game.
 type Ship struct {
-	Pos    Vec2    // Position
-	Vel    Vec2    // Velocity
-	Rot    float64 // Rotation angle in radians
-	Energy float32 // Current energy level
+	ID        PlayerID // Owning player (unique within a World)
+	Pos       Vec2     // Position
+	Vel       Vec2     // Velocity
+	Rot       float64  // Rotation angle in radians
+	Energy    float32  // Current energy level
+	Destroyed bool     // Whether the ship has hit the sun (destroyed ships are no longer simulated)
 }
 
 // NewShip creates a new Ship with the given values.
+// The ID is left empty; NewWorld assigns DefaultPlayerID, multiplayer code sets it explicitly.
 func NewShip(pos, vel Vec2, rot float64, energy float32) Ship {
 	return Ship{
 		Pos:    pos,
@@ -1,5 +1,10 @@
 package entities
 
+import (
+	"fmt"
+	"sort"
+)
+
 // Sun represents the sun (gravity source) in the game.
 type Sun struct {
 	Pos    Vec2    // Position
@@ -34,22 +39,37 @@ func NewPallet(id uint32, pos Vec2, active bool) Pallet {
 
 // World represents the complete game world state.
 type World struct {
-	Ship    Ship     // The player's ship
+	Ships   []Ship   // Player ships, sorted by ID
 	Sun     Sun      // The sun (gravity source)
 	Pallets []Pallet // List of energy pallets
 	Tick    uint32   // Current simulation tick
 	Done    bool     // Whether the game is finished
-	Win     bool     // Whether the player won (only valid if Done is true)
+	Win     bool     // Whether the players won (only valid if Done is true)
 }
 
-// NewWorld creates a new World with the given values.
+// NewWorld creates a new single-player World with the given values.
+// If the ship has no ID it is assigned DefaultPlayerID.
 // If pallets is nil, it will be initialized as an empty slice.
 func NewWorld(ship Ship, sun Sun, pallets []Pallet) World {
+	if ship.ID == "" {
+		ship.ID = DefaultPlayerID
+	}
+	return NewMultiplayerWorld([]Ship{ship}, sun, pallets)
+}
+
+// NewMultiplayerWorld creates a new World holding the given ships.
+// Ships are copied and sorted by ID. If pallets is nil, it will be initialized as an empty slice.
+func NewMultiplayerWorld(ships []Ship, sun Sun, pallets []Pallet) World {
 	if pallets == nil {
 		pallets = []Pallet{}
 	}
+	sorted := make([]Ship, len(ships))
+	copy(sorted, ships)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ID < sorted[j].ID
+	})
 	return World{
-		Ship:    ship,
+		Ships:   sorted,
 		Sun:     sun,
 		Pallets: pallets,
 		Tick:    0,
@@ -57,3 +77,60 @@ func NewWorld(ship Ship, sun Sun, pallets []Pallet) World {
 		Win:     false,
 	}
 }
+
+// ShipIndex returns the index of the ship owned by id, or -1 if there is none.
+func (w World) ShipIndex(id PlayerID) int {
+	i := sort.Search(len(w.Ships), func(i int) bool {
+		return w.Ships[i].ID >= id
+	})
+	if i < len(w.Ships) && w.Ships[i].ID == id {
+		return i
+	}
+	return -1
+}
+
+// ShipByID returns the ship owned by id.
+// Returns false if no ship belongs to id.
+func (w World) ShipByID(id PlayerID) (Ship, bool) {
+	i := w.ShipIndex(id)
+	if i < 0 {
+		return Ship{}, false
+	}
+	return w.Ships[i], true
+}
+
+// AddShip returns a copy of w with ship added in ID order.
+// The Ships slice of w is not modified.
+// Returns an error if ship has no ID or the ID is already taken.
+func (w World) AddShip(ship Ship) (World, error) {
+	if ship.ID == "" {
+		return w, fmt.Errorf("ship has no player ID")
+	}
+	if w.ShipIndex(ship.ID) >= 0 {
+		return w, fmt.Errorf("player %q already has a ship", ship.ID)
+	}
+
+	ships := make([]Ship, 0, len(w.Ships)+1)
+	ships = append(ships, w.Ships...)
+	ships = append(ships, ship)
+	sort.Slice(ships, func(i, j int) bool {
+		return ships[i].ID < ships[j].ID
+	})
+	w.Ships = ships
+	return w, nil
+}
+
+// RemoveShip returns a copy of w without the ship owned by id.
+// The Ships slice of w is not modified. Returns false if no ship belongs to id.
+func (w World) RemoveShip(id PlayerID) (World, bool) {
+	i := w.ShipIndex(id)
+	if i < 0 {
+		return w, false
+	}
+
+	ships := make([]Ship, 0, len(w.Ships)-1)
+	ships = append(ships, w.Ships[:i]...)
+	ships = append(ships, w.Ships[i+1:]...)
+	w.Ships = ships
+	return w, true
+}
@@ -115,7 +115,9 @@ var _ = Describe("World", Label("scope:unit", "loop:g1-physics", "layer:sim", "d
 
 			world := NewWorld(ship, sun, pallets)
 
-			Expect(world.Ship).To(Equal(ship))
+			expectedShip := ship
+			expectedShip.ID = DefaultPlayerID
+			Expect(world.Ships).To(Equal([]Ship{expectedShip}))
 			Expect(world.Sun).To(Equal(sun))
 			Expect(world.Pallets).To(HaveLen(2))
 			Expect(world.Pallets[0]).To(Equal(pallets[0]))
@@ -128,7 +130,7 @@ var _ = Describe("World", Label("scope:unit", "loop:g1-physics", "layer:sim", "d
 		It("creates a zero world", func() {
 			world := World{}
 
-			Expect(world.Ship).To(Equal(Ship{}))
+			Expect(world.Ships).To(BeEmpty())
 			Expect(world.Sun).To(Equal(Sun{}))
 			Expect(world.Pallets).To(BeEmpty())
 			Expect(world.Tick).To(Equal(uint32(0)))
@@ -153,7 +155,7 @@ var _ = Describe("World", Label("scope:unit", "loop:g1-physics", "layer:sim", "d
 
 			world := NewWorld(ship, sun, pallets)
 
-			Expect(world.Ship.Energy).To(Equal(float32(75)))
+			Expect(world.Ships[0].Energy).To(Equal(float32(75)))
 			Expect(world.Sun.Mass).To(Equal(500.0))
 			Expect(world.Pallets).To(HaveLen(1))
 			Expect(world.Tick).To(Equal(uint32(0)))
@@ -176,3 +178,84 @@ var _ = Describe("World", Label("scope:unit", "loop:g1-physics", "layer:sim", "d
 	})
 })
 
+
+var _ = Describe("Multiplayer World", Label("scope:unit", "loop:g1-physics", "layer:sim", "dep:none", "b:multiplayer-world", "r:medium"), func() {
+	var sun Sun
+
+	BeforeEach(func() {
+		sun = NewSun(NewVec2(0.0, 0.0), 50.0, 1000.0)
+	})
+
+	shipWithID := func(id PlayerID, x float64) Ship {
+		ship := NewShip(NewVec2(x, 0.0), NewVec2(0.0, 0.0), 0.0, 100.0)
+		ship.ID = id
+		return ship
+	}
+
+	Describe("NewMultiplayerWorld", func() {
+		It("sorts ships by ID without modifying the caller's slice", func() {
+			ships := []Ship{shipWithID("p2", 200), shipWithID("p1", 100)}
+			world := NewMultiplayerWorld(ships, sun, nil)
+
+			Expect(world.Ships[0].ID).To(Equal(PlayerID("p1")))
+			Expect(world.Ships[1].ID).To(Equal(PlayerID("p2")))
+			Expect(ships[0].ID).To(Equal(PlayerID("p2")))
+		})
+	})
+
+	Describe("ShipByID", func() {
+		It("finds ships by ID", func() {
+			world := NewMultiplayerWorld([]Ship{shipWithID("a", 100), shipWithID("b", 200)}, sun, nil)
+
+			ship, ok := world.ShipByID("b")
+			Expect(ok).To(BeTrue())
+			Expect(ship.Pos.X).To(Equal(200.0))
+			Expect(world.ShipIndex("a")).To(Equal(0))
+
+			_, ok = world.ShipByID("c")
+			Expect(ok).To(BeFalse())
+			Expect(world.ShipIndex("c")).To(Equal(-1))
+		})
+	})
+
+	Describe("AddShip", func() {
+		It("inserts the ship in ID order and leaves the original world unchanged", func() {
+			world := NewMultiplayerWorld([]Ship{shipWithID("a", 100), shipWithID("c", 300)}, sun, nil)
+
+			added, err := world.AddShip(shipWithID("b", 200))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(added.Ships).To(HaveLen(3))
+			Expect(added.Ships[1].ID).To(Equal(PlayerID("b")))
+			Expect(world.Ships).To(HaveLen(2))
+		})
+
+		It("rejects empty and duplicate IDs", func() {
+			world := NewMultiplayerWorld([]Ship{shipWithID("a", 100)}, sun, nil)
+
+			_, err := world.AddShip(shipWithID("", 200))
+			Expect(err).To(HaveOccurred())
+			_, err = world.AddShip(shipWithID("a", 200))
+			Expect(err).To(MatchError(ContainSubstring("already")))
+		})
+	})
+
+	Describe("RemoveShip", func() {
+		It("removes the ship and leaves the original world unchanged", func() {
+			world := NewMultiplayerWorld([]Ship{shipWithID("a", 100), shipWithID("b", 200)}, sun, nil)
+
+			removed, ok := world.RemoveShip("a")
+			Expect(ok).To(BeTrue())
+			Expect(removed.Ships).To(HaveLen(1))
+			Expect(removed.Ships[0].ID).To(Equal(PlayerID("b")))
+			Expect(world.Ships).To(HaveLen(2))
+			Expect(world.Ships[0].ID).To(Equal(PlayerID("a")))
+		})
+
+		It("reports unknown IDs", func() {
+			world := NewMultiplayerWorld([]Ship{shipWithID("a", 100)}, sun, nil)
+
+			_, ok := world.RemoveShip("z")
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,122 @@
+package levels
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+)
+
+// Layout constants shared by the built-in levels.
+const (
+	// SunRadius is the radius of the sun in every built-in level
+	SunRadius = float32(50.0)
+	// SunMass is the mass of the sun in every built-in level
+	SunMass = 1000.0
+	// ShipStartDistance is the distance from the sun at which the ship spawns
+	ShipStartDistance = 70.0
+	// ShipStartEnergy is the energy the ship spawns with
+	ShipStartEnergy = float32(100.0)
+	// SeededPalletCount is the number of pallets placed by Seeded
+	SeededPalletCount = 10
+	// SeededMinDistance is the minimum pallet distance from the sun in Seeded levels
+	SeededMinDistance = 75.0
+	// SeededMaxDistance is the maximum pallet distance from the sun in Seeded levels
+	SeededMaxDistance = 160.0
+	// SpawnSlots is the number of evenly spaced spawn points around the sun
+	SpawnSlots = 8
+)
+
+// Standard returns the hand-authored level served to players.
+// Ship at position (70, 0) with zero velocity, 100 energy.
+// Sun at origin (0, 0) with radius 50, mass 1000.
+// Ship starts outside sun radius (70 > 50) to avoid immediate collision.
+// Pallets are positioned around the sun in two rings plus two far pallets.
+func Standard() entities.World {
+	ship := defaultShip()
+	sun := defaultSun()
+
+	// Create initial pallets positioned in a circle around the sun
+	// Position them at various distances and angles for gameplay variety
+	pallets := []entities.Pallet{
+		// First ring - closer to sun
+		entities.NewPallet(1, entities.NewVec2(80.0, 0.0), true),  // Right
+		entities.NewPallet(2, entities.NewVec2(-80.0, 0.0), true), // Left
+		entities.NewPallet(3, entities.NewVec2(0.0, 80.0), true),  // Up
+		entities.NewPallet(4, entities.NewVec2(0.0, -80.0), true), // Down
+
+		// Second ring - further from sun
+		entities.NewPallet(5, entities.NewVec2(120.0, 60.0), true),   // Right-up
+		entities.NewPallet(6, entities.NewVec2(-120.0, 60.0), true),  // Left-up
+		entities.NewPallet(7, entities.NewVec2(120.0, -60.0), true),  // Right-down
+		entities.NewPallet(8, entities.NewVec2(-120.0, -60.0), true), // Left-down
+
+		// Additional pallets at various positions
+		entities.NewPallet(9, entities.NewVec2(150.0, 0.0), true),   // Far right
+		entities.NewPallet(10, entities.NewVec2(-150.0, 0.0), true), // Far left
+	}
+
+	return entities.NewWorld(ship, sun, pallets)
+}
+
+// Seeded returns a procedurally generated level for the given seed.
+// The sun and ship match Standard; SeededPalletCount pallets are scattered at
+// random angles between SeededMinDistance and SeededMaxDistance from the sun.
+// The same seed always produces the same world.
+func Seeded(seed int64) entities.World {
+	rng := rand.New(rand.NewSource(seed))
+
+	pallets := make([]entities.Pallet, SeededPalletCount)
+	for i := range pallets {
+		angle := rng.Float64() * 2 * math.Pi
+		distance := SeededMinDistance + rng.Float64()*(SeededMaxDistance-SeededMinDistance)
+		pos := entities.NewVec2(distance*math.Cos(angle), distance*math.Sin(angle))
+		pallets[i] = entities.NewPallet(uint32(i+1), pos, true)
+	}
+
+	return entities.NewWorld(defaultShip(), defaultSun(), pallets)
+}
+
+// ByName resolves the levels that can be selected by name (e.g. when creating a room).
+// Supported names: "standard" and "seeded" (Seeded(seed)). The seed is ignored by "standard".
+func ByName(name string, seed int64) (entities.World, error) {
+	switch name {
+	case "standard":
+		return Standard(), nil
+	case "seeded":
+		return Seeded(seed), nil
+	default:
+		return entities.World{}, fmt.Errorf("unknown level: %s", name)
+	}
+}
+
+// SpawnShip returns the ship for player id at spawn slot slot.
+// Slots are spaced evenly on a circle of radius ShipStartDistance around the sun,
+// starting at the single-player spawn point (slot 0) and wrapping after SpawnSlots.
+// Ships face away from the sun with zero velocity and full energy.
+func SpawnShip(id entities.PlayerID, slot int) entities.Ship {
+	angle := 2 * math.Pi * float64(slot%SpawnSlots) / SpawnSlots
+	ship := entities.NewShip(
+		entities.NewVec2(ShipStartDistance*math.Cos(angle), ShipStartDistance*math.Sin(angle)),
+		entities.NewVec2(0.0, 0.0),
+		angle,
+		ShipStartEnergy,
+	)
+	ship.ID = id
+	return ship
+}
+
+// defaultShip returns the spawn ship shared by the built-in levels.
+func defaultShip() entities.Ship {
+	return SpawnShip(entities.DefaultPlayerID, 0)
+}
+
+// defaultSun returns the sun shared by the built-in levels.
+func defaultSun() entities.Sun {
+	return entities.NewSun(
+		entities.NewVec2(0.0, 0.0),
+		SunRadius,
+		SunMass,
+	)
+}
@@ -0,0 +1,106 @@
+package levels
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestLevels(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Levels Suite")
+}
+
+var _ = Describe("Levels", Label("scope:unit", "loop:g2-rules", "layer:sim", "dep:none", "b:levels", "r:low"), func() {
+	Describe("Standard", func() {
+		It("places the ship outside the sun", func() {
+			world := Standard()
+			Expect(world.Ships[0].Pos.Length()).To(BeNumerically(">", float64(world.Sun.Radius)))
+			Expect(world.Ships[0].Energy).To(Equal(ShipStartEnergy))
+		})
+
+		It("has ten active pallets with unique IDs", func() {
+			world := Standard()
+			Expect(world.Pallets).To(HaveLen(10))
+			ids := map[uint32]bool{}
+			for _, pallet := range world.Pallets {
+				Expect(pallet.Active).To(BeTrue())
+				ids[pallet.ID] = true
+			}
+			Expect(ids).To(HaveLen(10))
+		})
+
+		It("returns independent pallet slices", func() {
+			a := Standard()
+			b := Standard()
+			a.Pallets[0].Active = false
+			Expect(b.Pallets[0].Active).To(BeTrue())
+		})
+	})
+
+	Describe("Seeded", func() {
+		It("is deterministic for a seed", func() {
+			Expect(Seeded(42)).To(Equal(Seeded(42)))
+		})
+
+		It("differs between seeds", func() {
+			Expect(Seeded(1).Pallets).NotTo(Equal(Seeded(2).Pallets))
+		})
+
+		It("keeps pallets within the configured ring", func() {
+			for seed := int64(0); seed < 20; seed++ {
+				world := Seeded(seed)
+				Expect(world.Pallets).To(HaveLen(SeededPalletCount))
+				for _, pallet := range world.Pallets {
+					distance := pallet.Pos.Sub(world.Sun.Pos).Length()
+					Expect(distance).To(BeNumerically(">=", SeededMinDistance-1e-9))
+					Expect(distance).To(BeNumerically("<=", SeededMaxDistance+1e-9))
+					Expect(pallet.Active).To(BeTrue())
+				}
+			}
+		})
+	})
+
+	Describe("SpawnShip", func() {
+		It("uses the single-player spawn point for slot 0", func() {
+			ship := SpawnShip("p1", 0)
+			Expect(ship).To(Equal(Standard().Ships[0]))
+		})
+
+		It("spaces slots evenly around the sun", func() {
+			seen := map[[2]int64]bool{}
+			for slot := 0; slot < SpawnSlots; slot++ {
+				ship := SpawnShip(entities.PlayerID(fmt.Sprintf("p%d", slot)), slot)
+				Expect(ship.ID).To(Equal(entities.PlayerID(fmt.Sprintf("p%d", slot))))
+				Expect(ship.Pos.Length()).To(BeNumerically("~", ShipStartDistance, 1e-9))
+				seen[[2]int64{int64(math.Round(ship.Pos.X)), int64(math.Round(ship.Pos.Y))}] = true
+			}
+			Expect(seen).To(HaveLen(SpawnSlots))
+		})
+
+		It("wraps slots after SpawnSlots", func() {
+			Expect(SpawnShip("p9", SpawnSlots+1).Pos).To(Equal(SpawnShip("p9", 1).Pos))
+		})
+	})
+	Describe("ByName", func() {
+		It("resolves the built-in levels", func() {
+			standard, err := ByName("standard", 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(standard).To(Equal(Standard()))
+
+			seeded, err := ByName("seeded", 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(seeded).To(Equal(Seeded(7)))
+		})
+
+		It("rejects unknown names", func() {
+			_, err := ByName("maze", 0)
+			Expect(err).To(MatchError(ContainSubstring("unknown level")))
+		})
+	})
+})
@@ -45,24 +45,24 @@ var _ = Describe("Physics Integration", Label("scope:unit", "loop:g1-physics", "
 			numTicks := 50
 			for i := 0; i < numTicks; i++ {
 				// Simulate world1
-				acc := GravityAcceleration(world1.Ship.Pos, world1.Sun.Pos, world1.Sun.Mass, G, aMax)
-				newPos, newVel := SemiImplicitEuler(world1.Ship.Pos, world1.Ship.Vel, acc, dt)
-				world1.Ship.Pos = newPos
-				world1.Ship.Vel = newVel
+				acc := GravityAcceleration(world1.Ships[0].Pos, world1.Sun.Pos, world1.Sun.Mass, G, aMax)
+				newPos, newVel := SemiImplicitEuler(world1.Ships[0].Pos, world1.Ships[0].Vel, acc, dt)
+				world1.Ships[0].Pos = newPos
+				world1.Ships[0].Vel = newVel
 				world1.Tick++
 
 				// Simulate world2 (same initial conditions)
-				acc2 := GravityAcceleration(world2.Ship.Pos, world2.Sun.Pos, world2.Sun.Mass, G, aMax)
-				newPos2, newVel2 := SemiImplicitEuler(world2.Ship.Pos, world2.Ship.Vel, acc2, dt)
-				world2.Ship.Pos = newPos2
-				world2.Ship.Vel = newVel2
+				acc2 := GravityAcceleration(world2.Ships[0].Pos, world2.Sun.Pos, world2.Sun.Mass, G, aMax)
+				newPos2, newVel2 := SemiImplicitEuler(world2.Ships[0].Pos, world2.Ships[0].Vel, acc2, dt)
+				world2.Ships[0].Pos = newPos2
+				world2.Ships[0].Vel = newVel2
 				world2.Tick++
 
 				// Verify states are identical
-				Expect(world1.Ship.Pos.X).To(Equal(world2.Ship.Pos.X))
-				Expect(world1.Ship.Pos.Y).To(Equal(world2.Ship.Pos.Y))
-				Expect(world1.Ship.Vel.X).To(Equal(world2.Ship.Vel.X))
-				Expect(world1.Ship.Vel.Y).To(Equal(world2.Ship.Vel.Y))
+				Expect(world1.Ships[0].Pos.X).To(Equal(world2.Ships[0].Pos.X))
+				Expect(world1.Ships[0].Pos.Y).To(Equal(world2.Ships[0].Pos.Y))
+				Expect(world1.Ships[0].Vel.X).To(Equal(world2.Ships[0].Vel.X))
+				Expect(world1.Ships[0].Vel.Y).To(Equal(world2.Ships[0].Vel.Y))
 				Expect(world1.Tick).To(Equal(world2.Tick))
 			}
 		})
@@ -0,0 +1,73 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+)
+
+// Config holds the tunable parameters of the game rules.
+// The package-level constants describe the shipped balance; Config lets callers
+// (balance tooling, rooms with custom settings) run the same rules with other values.
+type Config struct {
+	// ThrustAcceleration is the acceleration magnitude per unit thrust input (m/s²)
+	ThrustAcceleration float64
+	// TurnRate is the angular velocity per unit turn input (rad/s)
+	TurnRate float64
+	// MaxEnergy is the maximum energy value (energy bar cap)
+	MaxEnergy float32
+	// ThrustDrainRate is the energy drained per tick when thrusting
+	ThrustDrainRate float32
+	// PalletRestoreAmount is the energy restored per pallet pickup
+	PalletRestoreAmount float32
+	// G is the gravitational constant (game-scale)
+	G float64
+	// AMax is the maximum gravity acceleration magnitude
+	AMax float64
+	// PickupRadius is the pallet pickup radius
+	PickupRadius float64
+}
+
+// DefaultConfig returns the shipped rule parameters.
+// The physics values match the ones used by session.Session.
+func DefaultConfig() Config {
+	return Config{
+		ThrustAcceleration:  ThrustAcceleration,
+		TurnRate:            TurnRate,
+		MaxEnergy:           MaxEnergy,
+		ThrustDrainRate:     ThrustDrainRate,
+		PalletRestoreAmount: PalletRestoreAmount,
+		G:                   1.0,
+		AMax:                100.0,
+		PickupRadius:        15.0,
+	}
+}
+
+// Validate checks that the configuration describes a playable rule set.
+// Returns an error describing the first invalid field.
+func (c Config) Validate() error {
+	if c.ThrustAcceleration < 0 {
+		return fmt.Errorf("invalid ThrustAcceleration: must be >= 0, got %f", c.ThrustAcceleration)
+	}
+	if c.TurnRate < 0 {
+		return fmt.Errorf("invalid TurnRate: must be >= 0, got %f", c.TurnRate)
+	}
+	if c.MaxEnergy <= 0 {
+		return fmt.Errorf("invalid MaxEnergy: must be > 0, got %f", c.MaxEnergy)
+	}
+	if c.ThrustDrainRate < 0 {
+		return fmt.Errorf("invalid ThrustDrainRate: must be >= 0, got %f", c.ThrustDrainRate)
+	}
+	if c.PalletRestoreAmount < 0 {
+		return fmt.Errorf("invalid PalletRestoreAmount: must be >= 0, got %f", c.PalletRestoreAmount)
+	}
+	if math.IsNaN(c.G) || math.IsInf(c.G, 0) || c.G <= 0 {
+		return fmt.Errorf("invalid G: must be finite and > 0, got %f", c.G)
+	}
+	if c.AMax <= 0 {
+		return fmt.Errorf("invalid AMax: must be > 0, got %f", c.AMax)
+	}
+	if c.PickupRadius <= 0 {
+		return fmt.Errorf("invalid PickupRadius: must be > 0, got %f", c.PickupRadius)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package rules
+
+import (
+	"math"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rules Config", Label("scope:unit", "loop:g2-rules", "layer:sim", "dep:none", "b:rules-config", "r:medium", "double:fake"), func() {
+	const dt = 1.0 / 30.0
+
+	newWorld := func() entities.World {
+		ship := entities.NewShip(entities.NewVec2(70.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 50.0)
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		pallets := []entities.Pallet{entities.NewPallet(1, entities.NewVec2(70.0, 0.0), true)}
+		return entities.NewWorld(ship, sun, pallets)
+	}
+
+	Describe("DefaultConfig", func() {
+		It("matches the package constants", func() {
+			cfg := DefaultConfig()
+			Expect(cfg.ThrustAcceleration).To(Equal(ThrustAcceleration))
+			Expect(cfg.TurnRate).To(Equal(TurnRate))
+			Expect(cfg.MaxEnergy).To(Equal(MaxEnergy))
+			Expect(cfg.ThrustDrainRate).To(Equal(ThrustDrainRate))
+			Expect(cfg.PalletRestoreAmount).To(Equal(PalletRestoreAmount))
+		})
+
+		It("is valid", func() {
+			Expect(DefaultConfig().Validate()).To(Succeed())
+		})
+	})
+
+	Describe("Validate", func() {
+		It("rejects non-positive MaxEnergy", func() {
+			cfg := DefaultConfig()
+			cfg.MaxEnergy = 0
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("MaxEnergy")))
+		})
+
+		It("rejects negative ThrustDrainRate", func() {
+			cfg := DefaultConfig()
+			cfg.ThrustDrainRate = -1
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("ThrustDrainRate")))
+		})
+
+		It("rejects non-positive and non-finite G", func() {
+			for _, g := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+				cfg := DefaultConfig()
+				cfg.G = g
+				Expect(cfg.Validate()).To(MatchError(ContainSubstring("invalid G")), "G = %v", g)
+			}
+		})
+
+		It("rejects non-positive PickupRadius", func() {
+			cfg := DefaultConfig()
+			cfg.PickupRadius = 0
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("PickupRadius")))
+		})
+	})
+
+	Describe("StepWithConfig", func() {
+		It("matches Step when given the same parameters", func() {
+			cfg := DefaultConfig()
+			input := InputCommand{Thrust: 1.0, Turn: 0.5}
+
+			viaStep := Step(newWorld(), input, dt, cfg.G, cfg.AMax, cfg.PickupRadius)
+			viaConfig := StepWithConfig(newWorld(), input, dt, cfg)
+
+			Expect(viaConfig).To(Equal(viaStep))
+		})
+
+		It("drains energy at the configured rate", func() {
+			cfg := DefaultConfig()
+			cfg.ThrustDrainRate = 2.0
+			cfg.PickupRadius = 1.0 // keep the pallet out of reach
+
+			world := newWorld()
+			world.Pallets = nil
+			world = StepWithConfig(world, InputCommand{Thrust: 1.0}, dt, cfg)
+
+			Expect(world.Ships[0].Energy).To(Equal(float32(48.0)))
+		})
+
+		It("restores energy by the configured amount, capped at MaxEnergy", func() {
+			cfg := DefaultConfig()
+			cfg.PalletRestoreAmount = 10.0
+			cfg.MaxEnergy = 55.0
+
+			world := StepWithConfig(newWorld(), InputCommand{}, dt, cfg)
+
+			Expect(world.Pallets[0].Active).To(BeFalse())
+			Expect(world.Ships[0].Energy).To(Equal(float32(55.0)))
+		})
+
+		It("uses the configured turn rate", func() {
+			cfg := DefaultConfig()
+			cfg.TurnRate = 6.0
+
+			world := StepWithConfig(newWorld(), InputCommand{Turn: 1.0}, dt, cfg)
+
+			Expect(world.Ships[0].Rot).To(BeNumerically("~", 6.0*dt, 1e-9))
+		})
+	})
+})
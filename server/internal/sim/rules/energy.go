@@ -21,11 +21,7 @@ const (
 // Returns:
 //   - New energy value after draining (clamped to [0, MaxEnergy])
 func DrainEnergyOnThrust(currentEnergy float32, isThrusting bool) float32 {
-	if !isThrusting {
-		return currentEnergy
-	}
-	newEnergy := currentEnergy - ThrustDrainRate
-	return ClampEnergy(newEnergy)
+	return drainEnergy(currentEnergy, isThrusting, DefaultConfig())
 }
 
 // RestoreEnergyOnPickup restores energy when a pallet is collected.
@@ -38,8 +34,7 @@ func DrainEnergyOnThrust(currentEnergy float32, isThrusting bool) float32 {
 // Returns:
 //   - New energy value after restoring (clamped to [0, MaxEnergy])
 func RestoreEnergyOnPickup(currentEnergy float32) float32 {
-	newEnergy := currentEnergy + PalletRestoreAmount
-	return ClampEnergy(newEnergy)
+	return restoreEnergy(currentEnergy, DefaultConfig())
 }
 
 // ClampEnergy clamps energy to valid range [0, MaxEnergy].
@@ -50,11 +45,31 @@ func RestoreEnergyOnPickup(currentEnergy float32) float32 {
 // Returns:
 //   - Clamped energy value (0 <= energy <= MaxEnergy)
 func ClampEnergy(energy float32) float32 {
+	return clampEnergy(energy, MaxEnergy)
+}
+
+// drainEnergy drains cfg.ThrustDrainRate when thrusting, clamped to [0, cfg.MaxEnergy].
+func drainEnergy(currentEnergy float32, isThrusting bool, cfg Config) float32 {
+	if !isThrusting {
+		return currentEnergy
+	}
+	newEnergy := currentEnergy - cfg.ThrustDrainRate
+	return clampEnergy(newEnergy, cfg.MaxEnergy)
+}
+
+// restoreEnergy adds cfg.PalletRestoreAmount, clamped to [0, cfg.MaxEnergy].
+func restoreEnergy(currentEnergy float32, cfg Config) float32 {
+	newEnergy := currentEnergy + cfg.PalletRestoreAmount
+	return clampEnergy(newEnergy, cfg.MaxEnergy)
+}
+
+// clampEnergy clamps energy to [0, maxEnergy].
+func clampEnergy(energy float32, maxEnergy float32) float32 {
 	if energy < 0 {
 		return 0
 	}
-	if energy > MaxEnergy {
-		return MaxEnergy
+	if energy > maxEnergy {
+		return maxEnergy
 	}
 	return energy
 }
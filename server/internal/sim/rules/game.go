@@ -31,21 +31,37 @@ func CheckWinCondition(world entities.World) bool {
 }
 
 // CheckLoseCondition checks if the lose condition is met.
-// Lose condition: ship collides with sun (using ShipSunCollision).
+// Lose condition: every ship is destroyed, i.e. has Destroyed set or collides
+// with the sun (using ShipSunCollision). A world without ships cannot be lost.
 //
 // Parameters:
 //   - world: Current world state
 //
 // Returns:
-//   - true if ship collides with sun, false otherwise
+//   - true if there is at least one ship and all ships are destroyed, false otherwise
 func CheckLoseCondition(world entities.World) bool {
-	return physics.ShipSunCollision(world.Ship.Pos, world.Sun.Pos, world.Sun.Radius)
+	if len(world.Ships) == 0 {
+		return false
+	}
+
+	for _, ship := range world.Ships {
+		if !shipHitSun(ship, world.Sun) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// shipHitSun reports whether ship is destroyed or currently collides with the sun.
+func shipHitSun(ship entities.Ship, sun entities.Sun) bool {
+	return ship.Destroyed || physics.ShipSunCollision(ship.Pos, sun.Pos, sun.Radius)
 }
 
 // EvaluateGameState evaluates win/lose conditions and updates World.Done and World.Win flags.
 // Win condition takes precedence over lose condition (if both are true, win is set).
 // Once Done is true, state should not change (idempotent evaluation).
-// Other world fields (Ship, Sun, Pallets, Tick) are not modified.
+// Other world fields (Ships, Sun, Pallets, Tick) are not modified.
 //
 // Parameters:
 //   - world: Current world state
@@ -306,12 +306,12 @@ var _ = Describe("Game State Transitions", Label("scope:unit", "loop:g2-rules",
 			updatedWorld := EvaluateGameState(world)
 
 			// Other fields should be preserved
-			Expect(updatedWorld.Ship.Pos.X).To(Equal(100.0))
-			Expect(updatedWorld.Ship.Pos.Y).To(Equal(100.0))
-			Expect(updatedWorld.Ship.Vel.X).To(Equal(1.0))
-			Expect(updatedWorld.Ship.Vel.Y).To(Equal(2.0))
-			Expect(updatedWorld.Ship.Rot).To(Equal(1.5))
-			Expect(updatedWorld.Ship.Energy).To(Equal(float32(75.0)))
+			Expect(updatedWorld.Ships[0].Pos.X).To(Equal(100.0))
+			Expect(updatedWorld.Ships[0].Pos.Y).To(Equal(100.0))
+			Expect(updatedWorld.Ships[0].Vel.X).To(Equal(1.0))
+			Expect(updatedWorld.Ships[0].Vel.Y).To(Equal(2.0))
+			Expect(updatedWorld.Ships[0].Rot).To(Equal(1.5))
+			Expect(updatedWorld.Ships[0].Energy).To(Equal(float32(75.0)))
 			Expect(updatedWorld.Sun.Pos.X).To(Equal(0.0))
 			Expect(updatedWorld.Sun.Pos.Y).To(Equal(0.0))
 			Expect(updatedWorld.Sun.Radius).To(Equal(float32(50.0)))
@@ -367,7 +367,7 @@ var _ = Describe("Game State Transitions", Label("scope:unit", "loop:g2-rules",
 			Expect(world.Done).To(BeFalse())
 
 			// Move ship to sun
-			world.Ship = entities.NewShip(
+			world.Ships[0] = entities.NewShip(
 				entities.NewVec2(0.0, 0.0), // At sun center
 				entities.NewVec2(0.0, 0.0),
 				0.0,
@@ -65,7 +65,12 @@ func ClampInput(input InputCommand) InputCommand {
 // Returns:
 //   - Updated rotation angle in radians, normalized to [0, 2π)
 func UpdateRotation(currentRot float64, turnInput float64, dt float64) float64 {
-	newRot := currentRot + TurnRate*turnInput*dt
+	return updateRotation(currentRot, turnInput, dt, TurnRate)
+}
+
+// updateRotation advances rotation by turnRate*turnInput*dt and normalizes it.
+func updateRotation(currentRot float64, turnInput float64, dt float64, turnRate float64) float64 {
+	newRot := currentRot + turnRate*turnInput*dt
 	return normalizeRotation(newRot)
 }
 
@@ -90,13 +95,18 @@ func normalizeRotation(rot float64) float64 {
 //   - Acceleration vector in the direction of ship's rotation
 //     Note: Y component is negated to match screen coordinate system where Y increases downward.
 func CalculateThrustAcceleration(rotation float64, thrustInput float32) entities.Vec2 {
+	return thrustAcceleration(rotation, thrustInput, ThrustAcceleration)
+}
+
+// thrustAcceleration scales the rotation direction by thrustInput*acceleration (Y flipped).
+func thrustAcceleration(rotation float64, thrustInput float32, acceleration float64) entities.Vec2 {
 	// Calculate direction vector from rotation angle
 	// In standard math coordinates: x = cos(θ), y = sin(θ)
 	directionX := math.Cos(rotation)
 	directionY := math.Sin(rotation)
 
 	// Scale by thrust input and acceleration constant
-	magnitude := float64(thrustInput) * ThrustAcceleration
+	magnitude := float64(thrustInput) * acceleration
 
 	// Negate Y component to match screen coordinate system (Y-down)
 	// World uses Y-up, but rendering flips Y, so we flip thrust Y to compensate
@@ -114,14 +124,29 @@ func CalculateThrustAcceleration(rotation float64, thrustInput float32) entities
 // Returns:
 //   - Updated ship with new rotation, velocity, and energy
 func ApplyInput(ship entities.Ship, input InputCommand, dt float64) entities.Ship {
+	return ApplyInputWithConfig(ship, input, dt, DefaultConfig())
+}
+
+// ApplyInputWithConfig is ApplyInput using the turn rate, thrust acceleration and
+// energy economy from cfg instead of the package constants.
+//
+// Parameters:
+//   - ship: Current ship state
+//   - input: Input command to apply
+//   - dt: Time step in seconds
+//   - cfg: Rule parameters
+//
+// Returns:
+//   - Updated ship with new rotation, velocity, and energy
+func ApplyInputWithConfig(ship entities.Ship, input InputCommand, dt float64, cfg Config) entities.Ship {
 	// Clamp input values
 	clampedInput := ClampInput(input)
 
 	// Update rotation (always works, regardless of energy)
-	newRot := UpdateRotation(ship.Rot, float64(clampedInput.Turn), dt)
+	newRot := updateRotation(ship.Rot, float64(clampedInput.Turn), dt, cfg.TurnRate)
 
 	// Calculate thrust acceleration
-	thrustAcc := CalculateThrustAcceleration(newRot, clampedInput.Thrust)
+	thrustAcc := thrustAcceleration(newRot, clampedInput.Thrust, cfg.ThrustAcceleration)
 
 	// Determine if thrust should be applied (only when energy > 0)
 	shouldThrust := ship.Energy > MinEnergyForThrust && clampedInput.Thrust > 0.0
@@ -135,13 +160,12 @@ func ApplyInput(ship entities.Ship, input InputCommand, dt float64) entities.Shi
 
 	// Update energy (drain if thrusting)
 	isThrusting := shouldThrust
-	newEnergy := DrainEnergyOnThrust(ship.Energy, isThrusting)
+	newEnergy := drainEnergy(ship.Energy, isThrusting, cfg)
 
 	// Return updated ship
-	return entities.NewShip(
-		ship.Pos, // Position is not updated by input processing (handled by physics step)
-		newVel,
-		newRot,
-		newEnergy,
-	)
+	// Position is not updated by input processing (handled by physics step); ID and Destroyed are kept
+	ship.Vel = newVel
+	ship.Rot = newRot
+	ship.Energy = newEnergy
+	return ship
 }
@@ -0,0 +1,102 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+)
+
+// InvariantError describes every invariant a step broke.
+type InvariantError struct {
+	Tick       uint32   // Tick of the world that broke the invariants
+	Violations []string // One human-readable entry per broken invariant
+}
+
+// Error implements the error interface.
+func (e *InvariantError) Error() string {
+	return fmt.Sprintf("world invariants violated at tick %d: %s", e.Tick, strings.Join(e.Violations, "; "))
+}
+
+// CheckInvariants verifies that next is a valid successor of prev under cfg.
+// It is meant to run after every Step in debug builds and tests; it is not
+// part of the game rules and never modifies either world.
+//
+// Invariants:
+//   - Ship, sun and pallet vectors are finite (no NaN, no Inf)
+//   - Every ship's energy is in [0, cfg.MaxEnergy]
+//   - Tick advances by exactly one
+//   - Ships keep their IDs and order, and a destroyed ship is never revived
+//   - Pallets keep their IDs and order, and a collected pallet never becomes active again
+//
+// Returns nil if every invariant holds, or an *InvariantError listing the violations.
+func CheckInvariants(prev, next entities.World, cfg Config) error {
+	var violations []string
+
+	for _, ship := range next.Ships {
+		name := fmt.Sprintf("ship[%s]", ship.ID)
+		violations = appendVec2Violations(violations, name+".pos", ship.Pos)
+		violations = appendVec2Violations(violations, name+".vel", ship.Vel)
+		if math.IsNaN(ship.Rot) || math.IsInf(ship.Rot, 0) {
+			violations = append(violations, fmt.Sprintf("%s.rot is not finite: %v", name, ship.Rot))
+		}
+		if math.IsNaN(float64(ship.Energy)) || ship.Energy < 0 || ship.Energy > cfg.MaxEnergy {
+			violations = append(violations, fmt.Sprintf("%s.energy %v outside [0, %v]", name, ship.Energy, cfg.MaxEnergy))
+		}
+	}
+	violations = appendVec2Violations(violations, "sun.pos", next.Sun.Pos)
+
+	if next.Tick != prev.Tick+1 {
+		violations = append(violations, fmt.Sprintf("tick went from %d to %d", prev.Tick, next.Tick))
+	}
+
+	if len(next.Ships) != len(prev.Ships) {
+		violations = append(violations, fmt.Sprintf("ship count changed from %d to %d", len(prev.Ships), len(next.Ships)))
+	} else {
+		for i := range next.Ships {
+			before, after := prev.Ships[i], next.Ships[i]
+			if before.ID != after.ID {
+				violations = append(violations, fmt.Sprintf("ship at index %d changed ID from %q to %q", i, before.ID, after.ID))
+				continue
+			}
+			if before.Destroyed && !after.Destroyed {
+				violations = append(violations, fmt.Sprintf("ship[%s] was revived", after.ID))
+			}
+		}
+	}
+
+	if len(next.Pallets) != len(prev.Pallets) {
+		violations = append(violations, fmt.Sprintf("pallet count changed from %d to %d", len(prev.Pallets), len(next.Pallets)))
+	} else {
+		for i := range next.Pallets {
+			before, after := prev.Pallets[i], next.Pallets[i]
+			if before.ID != after.ID {
+				violations = append(violations, fmt.Sprintf("pallet at index %d changed ID from %d to %d", i, before.ID, after.ID))
+				continue
+			}
+			if !before.Active && after.Active {
+				violations = append(violations, fmt.Sprintf("pallet %d was reactivated", after.ID))
+			}
+		}
+	}
+	for _, pallet := range next.Pallets {
+		violations = appendVec2Violations(violations, fmt.Sprintf("pallet %d pos", pallet.ID), pallet.Pos)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &InvariantError{Tick: next.Tick, Violations: violations}
+}
+
+// appendVec2Violations appends a violation for each non-finite component of v.
+func appendVec2Violations(violations []string, name string, v entities.Vec2) []string {
+	if math.IsNaN(v.X) || math.IsInf(v.X, 0) {
+		violations = append(violations, fmt.Sprintf("%s.x is not finite: %v", name, v.X))
+	}
+	if math.IsNaN(v.Y) || math.IsInf(v.Y, 0) {
+		violations = append(violations, fmt.Sprintf("%s.y is not finite: %v", name, v.Y))
+	}
+	return violations
+}
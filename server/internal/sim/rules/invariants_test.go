@@ -0,0 +1,111 @@
+package rules
+
+import (
+	"errors"
+	"math"
+
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CheckInvariants", Label("scope:unit", "loop:g2-rules", "layer:sim", "dep:none", "b:world-invariants", "r:high"), func() {
+	const dt = 1.0 / 30.0
+
+	var (
+		cfg  Config
+		prev entities.World
+		next entities.World
+	)
+
+	BeforeEach(func() {
+		cfg = DefaultConfig()
+		ship := entities.NewShip(entities.NewVec2(200.0, 0.0), entities.NewVec2(0.0, 10.0), 0.0, 50.0)
+		sun := entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+		pallets := []entities.Pallet{
+			entities.NewPallet(1, entities.NewVec2(300.0, 0.0), true),
+			entities.NewPallet(2, entities.NewVec2(-300.0, 0.0), false),
+		}
+		prev = entities.NewWorld(ship, sun, pallets)
+
+		next = prev
+		next.Ships = append([]entities.Ship(nil), prev.Ships...)
+		next.Pallets = append([]entities.Pallet(nil), prev.Pallets...)
+		next.Tick = prev.Tick + 1
+	})
+
+	violations := func(err error) []string {
+		var invErr *InvariantError
+		ExpectWithOffset(1, errors.As(err, &invErr)).To(BeTrue())
+		return invErr.Violations
+	}
+
+	It("accepts a world produced by Step", func() {
+		stepped := StepWithConfig(entities.NewWorld(prev.Ships[0], prev.Sun, append([]entities.Pallet(nil), prev.Pallets...)), InputCommand{Thrust: 1.0, Turn: 1.0}, dt, cfg)
+		Expect(CheckInvariants(prev, stepped, cfg)).To(Succeed())
+	})
+
+	It("rejects non-finite ship vectors", func() {
+		next.Ships[0].Pos.X = math.NaN()
+		next.Ships[0].Vel.Y = math.Inf(1)
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(
+			ContainSubstring("ship[p1].pos.x"),
+			ContainSubstring("ship[p1].vel.y"),
+		))
+	})
+
+	It("rejects a non-finite pallet position", func() {
+		next.Pallets[0].Pos.Y = math.Inf(-1)
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("pallet 1 pos.y")))
+	})
+
+	It("rejects energy outside [0, MaxEnergy]", func() {
+		next.Ships[0].Energy = cfg.MaxEnergy + 1
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("ship[p1].energy")))
+
+		next.Ships[0].Energy = -1
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("ship[p1].energy")))
+	})
+
+	It("uses MaxEnergy from the config", func() {
+		cfg.MaxEnergy = 40
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("ship[p1].energy")))
+	})
+
+	It("rejects ticks that do not advance by one", func() {
+		next.Tick = prev.Tick
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("tick went from 0 to 0")))
+
+		next.Tick = prev.Tick + 2
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("tick went from 0 to 2")))
+	})
+
+	It("rejects a reactivated pallet", func() {
+		next.Pallets[1].Active = true
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("pallet 2 was reactivated")))
+	})
+
+	It("rejects pallets that were added, removed or reordered", func() {
+		next.Pallets = next.Pallets[:1]
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("pallet count changed")))
+
+		next.Pallets = []entities.Pallet{prev.Pallets[1], prev.Pallets[0]}
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(HaveLen(2))
+	})
+
+	It("rejects a revived ship and changes to the ship roster", func() {
+		prev.Ships[0].Destroyed = true
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("ship[p1] was revived")))
+
+		next.Ships = nil
+		Expect(violations(CheckInvariants(prev, next, cfg))).To(ConsistOf(ContainSubstring("ship count changed")))
+	})
+
+	It("reports the tick of the offending world", func() {
+		next.Ships[0].Pos.X = math.NaN()
+		var invErr *InvariantError
+		Expect(errors.As(CheckInvariants(prev, next, cfg), &invErr)).To(BeTrue())
+		Expect(invErr.Tick).To(Equal(next.Tick))
+		Expect(invErr.Error()).To(ContainSubstring("tick 1"))
+	})
+})
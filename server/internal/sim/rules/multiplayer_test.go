@@ -0,0 +1,109 @@
+package rules
+
+import (
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Multiplayer Step", Label("scope:unit", "loop:g2-rules", "layer:sim", "dep:none", "b:multiplayer-step", "r:high"), func() {
+	const dt = 1.0 / 30.0
+
+	var (
+		cfg Config
+		sun entities.Sun
+	)
+
+	BeforeEach(func() {
+		cfg = DefaultConfig()
+		sun = entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0)
+	})
+
+	shipAt := func(id entities.PlayerID, x, y float64) entities.Ship {
+		ship := entities.NewShip(entities.NewVec2(x, y), entities.NewVec2(0.0, 0.0), 0.0, 50.0)
+		ship.ID = id
+		return ship
+	}
+
+	It("applies each player's input to their own ship", func() {
+		world := entities.NewMultiplayerWorld([]entities.Ship{shipAt("a", 200, 0), shipAt("b", -200, 0)}, sun, nil)
+
+		next := StepPlayers(world, map[entities.PlayerID]InputCommand{"a": {Thrust: 1.0}}, dt, cfg)
+
+		Expect(next.Ships[0].Energy).To(BeNumerically("<", 50.0))
+		Expect(next.Ships[1].Energy).To(Equal(float32(50.0)))
+		Expect(next.Tick).To(Equal(uint32(1)))
+	})
+
+	It("matches StepWithConfig for a single player", func() {
+		world := entities.NewWorld(shipAt("", 200, 0), sun, nil)
+		input := InputCommand{Thrust: 1.0, Turn: 0.5}
+
+		single := StepWithConfig(entities.NewWorld(world.Ships[0], sun, nil), input, dt, cfg)
+		multi := StepPlayers(world, map[entities.PlayerID]InputCommand{entities.DefaultPlayerID: input}, dt, cfg)
+
+		Expect(multi).To(Equal(single))
+	})
+
+	It("applies StepWithConfig's input to the first ship only", func() {
+		newWorld := func() entities.World {
+			return entities.NewMultiplayerWorld([]entities.Ship{shipAt("a", 200, 0), shipAt("b", -200, 0)}, sun, nil)
+		}
+		input := InputCommand{Thrust: 1.0}
+
+		viaConfig := StepWithConfig(newWorld(), input, dt, cfg)
+		viaPlayers := StepPlayers(newWorld(), map[entities.PlayerID]InputCommand{"a": input}, dt, cfg)
+
+		Expect(viaConfig).To(Equal(viaPlayers))
+		Expect(viaConfig.Ships[1].Energy).To(Equal(float32(50.0)))
+	})
+
+	It("gives a contested pallet to the ship with the lower ID", func() {
+		pallet := entities.NewPallet(1, entities.NewVec2(200.0, 0.0), true)
+		world := entities.NewMultiplayerWorld([]entities.Ship{shipAt("b", 200, 0), shipAt("a", 200, 0)}, sun, []entities.Pallet{pallet, entities.NewPallet(2, entities.NewVec2(-300.0, 0.0), true)})
+
+		next := StepPlayers(world, nil, dt, cfg)
+
+		Expect(next.Pallets[0].Active).To(BeFalse())
+		Expect(next.Ships[0].ID).To(Equal(entities.PlayerID("a")))
+		Expect(next.Ships[0].Energy).To(BeNumerically(">", 50.0))
+		Expect(next.Ships[1].Energy).To(Equal(float32(50.0)))
+	})
+
+	It("destroys a ship that hits the sun and stops simulating it", func() {
+		pallets := []entities.Pallet{entities.NewPallet(1, entities.NewVec2(-300.0, 0.0), true)}
+		world := entities.NewMultiplayerWorld([]entities.Ship{shipAt("a", 10, 0), shipAt("b", 200, 0)}, sun, pallets)
+
+		next := StepPlayers(world, nil, dt, cfg)
+		Expect(next.Ships[0].Destroyed).To(BeTrue())
+		Expect(next.Ships[1].Destroyed).To(BeFalse())
+		Expect(next.Done).To(BeFalse())
+
+		wreck := next.Ships[0]
+		next = StepPlayers(next, map[entities.PlayerID]InputCommand{"a": {Thrust: 1.0, Turn: 1.0}}, dt, cfg)
+		Expect(next.Ships[0]).To(Equal(wreck))
+	})
+
+	It("ends in a loss only when every ship has been destroyed", func() {
+		pallets := []entities.Pallet{entities.NewPallet(1, entities.NewVec2(-300.0, 0.0), true)}
+		world := entities.NewMultiplayerWorld([]entities.Ship{shipAt("a", 10, 0), shipAt("b", 0, 10)}, sun, pallets)
+
+		next := StepPlayers(world, nil, dt, cfg)
+
+		Expect(next.Done).To(BeTrue())
+		Expect(next.Win).To(BeFalse())
+	})
+
+	It("wins once the players have collected every pallet between them", func() {
+		pallets := []entities.Pallet{
+			entities.NewPallet(1, entities.NewVec2(200.0, 0.0), true),
+			entities.NewPallet(2, entities.NewVec2(-200.0, 0.0), true),
+		}
+		world := entities.NewMultiplayerWorld([]entities.Ship{shipAt("a", 200, 0), shipAt("b", -200, 0)}, sun, pallets)
+
+		next := StepPlayers(world, nil, dt, cfg)
+
+		Expect(next.Done).To(BeTrue())
+		Expect(next.Win).To(BeTrue())
+	})
+})
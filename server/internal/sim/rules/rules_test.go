@@ -495,18 +495,18 @@ var _ = Describe("Rules Integration", Label("scope:unit", "loop:g2-rules", "laye
 			// Simulate game loop
 			for i := 0; i < 200 && !world.Done; i++ {
 				// Apply input
-				world.Ship = ApplyInput(world.Ship, input, dt)
+				world.Ships[0] = ApplyInput(world.Ships[0], input, dt)
 
 				// Update physics (gravity)
-				acc := physics.GravityAcceleration(world.Ship.Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
-				newPos, newVel := physics.SemiImplicitEuler(world.Ship.Pos, world.Ship.Vel, acc, dt)
-				world.Ship.Pos = newPos
-				world.Ship.Vel = newVel
+				acc := physics.GravityAcceleration(world.Ships[0].Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
+				newPos, newVel := physics.SemiImplicitEuler(world.Ships[0].Pos, world.Ships[0].Vel, acc, dt)
+				world.Ships[0].Pos = newPos
+				world.Ships[0].Vel = newVel
 
 				// Check pallet pickups
 				for j := range world.Pallets {
-					if world.Pallets[j].Active && physics.ShipPalletCollision(world.Ship.Pos, world.Pallets[j].Pos, pickupRadius) {
-						world.Ship.Energy = RestoreEnergyOnPickup(world.Ship.Energy)
+					if world.Pallets[j].Active && physics.ShipPalletCollision(world.Ships[0].Pos, world.Pallets[j].Pos, pickupRadius) {
+						world.Ships[0].Energy = RestoreEnergyOnPickup(world.Ships[0].Energy)
 						world.Pallets[j].Active = false
 					}
 				}
@@ -542,13 +542,13 @@ var _ = Describe("Rules Integration", Label("scope:unit", "loop:g2-rules", "laye
 			// Simulate until collision or max ticks
 			for i := 0; i < 200 && !world.Done; i++ {
 				// Apply input (none in this case)
-				world.Ship = ApplyInput(world.Ship, input, dt)
+				world.Ships[0] = ApplyInput(world.Ships[0], input, dt)
 
 				// Update physics (gravity pulls toward sun)
-				acc := physics.GravityAcceleration(world.Ship.Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
-				newPos, newVel := physics.SemiImplicitEuler(world.Ship.Pos, world.Ship.Vel, acc, dt)
-				world.Ship.Pos = newPos
-				world.Ship.Vel = newVel
+				acc := physics.GravityAcceleration(world.Ships[0].Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
+				newPos, newVel := physics.SemiImplicitEuler(world.Ships[0].Pos, world.Ships[0].Vel, acc, dt)
+				world.Ships[0].Pos = newPos
+				world.Ships[0].Vel = newVel
 
 				// Evaluate game state
 				world = EvaluateGameState(world)
@@ -576,23 +576,23 @@ var _ = Describe("Rules Integration", Label("scope:unit", "loop:g2-rules", "laye
 			world := entities.NewWorld(ship, sun, pallets)
 			input := InputCommand{Thrust: 1.0, Turn: 0.0}
 
-			initialEnergy := world.Ship.Energy
+			initialEnergy := world.Ships[0].Energy
 
 			// Simulate until energy depleted or pallet picked up
 			for i := 0; i < 50 && !world.Done; i++ {
 				// Apply input
-				world.Ship = ApplyInput(world.Ship, input, dt)
+				world.Ships[0] = ApplyInput(world.Ships[0], input, dt)
 
 				// Update physics
-				acc := physics.GravityAcceleration(world.Ship.Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
-				newPos, newVel := physics.SemiImplicitEuler(world.Ship.Pos, world.Ship.Vel, acc, dt)
-				world.Ship.Pos = newPos
-				world.Ship.Vel = newVel
+				acc := physics.GravityAcceleration(world.Ships[0].Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
+				newPos, newVel := physics.SemiImplicitEuler(world.Ships[0].Pos, world.Ships[0].Vel, acc, dt)
+				world.Ships[0].Pos = newPos
+				world.Ships[0].Vel = newVel
 
 				// Check pallet pickup
 				for j := range world.Pallets {
-					if world.Pallets[j].Active && physics.ShipPalletCollision(world.Ship.Pos, world.Pallets[j].Pos, pickupRadius) {
-						world.Ship.Energy = RestoreEnergyOnPickup(world.Ship.Energy)
+					if world.Pallets[j].Active && physics.ShipPalletCollision(world.Ships[0].Pos, world.Pallets[j].Pos, pickupRadius) {
+						world.Ships[0].Energy = RestoreEnergyOnPickup(world.Ships[0].Energy)
 						world.Pallets[j].Active = false
 					}
 				}
@@ -604,7 +604,7 @@ var _ = Describe("Rules Integration", Label("scope:unit", "loop:g2-rules", "laye
 
 			// Energy should be restored after pickup
 			if !world.Pallets[0].Active {
-				Expect(world.Ship.Energy).To(BeNumerically(">", initialEnergy-ThrustDrainRate*10.0))
+				Expect(world.Ships[0].Energy).To(BeNumerically(">", initialEnergy-ThrustDrainRate*10.0))
 			}
 		})
 
@@ -627,18 +627,18 @@ var _ = Describe("Rules Integration", Label("scope:unit", "loop:g2-rules", "laye
 			// Simulate game loop
 			for i := 0; i < 300 && !world.Done; i++ {
 				// Apply input
-				world.Ship = ApplyInput(world.Ship, input, dt)
+				world.Ships[0] = ApplyInput(world.Ships[0], input, dt)
 
 				// Update physics
-				acc := physics.GravityAcceleration(world.Ship.Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
-				newPos, newVel := physics.SemiImplicitEuler(world.Ship.Pos, world.Ship.Vel, acc, dt)
-				world.Ship.Pos = newPos
-				world.Ship.Vel = newVel
+				acc := physics.GravityAcceleration(world.Ships[0].Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
+				newPos, newVel := physics.SemiImplicitEuler(world.Ships[0].Pos, world.Ships[0].Vel, acc, dt)
+				world.Ships[0].Pos = newPos
+				world.Ships[0].Vel = newVel
 
 				// Check pallet pickups
 				for j := range world.Pallets {
-					if world.Pallets[j].Active && physics.ShipPalletCollision(world.Ship.Pos, world.Pallets[j].Pos, pickupRadius) {
-						world.Ship.Energy = RestoreEnergyOnPickup(world.Ship.Energy)
+					if world.Pallets[j].Active && physics.ShipPalletCollision(world.Ships[0].Pos, world.Pallets[j].Pos, pickupRadius) {
+						world.Ships[0].Energy = RestoreEnergyOnPickup(world.Ships[0].Energy)
 						world.Pallets[j].Active = false
 					}
 				}
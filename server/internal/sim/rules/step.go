@@ -6,9 +6,9 @@ import (
 )
 
 // Step performs one complete game loop step, applying all rules in the correct order:
-// 1. Input → Apply player input (thrust, turn)
-// 2. Physics → Update position and velocity (gravity + integrator)
-// 3. Collisions → Process pallet pickups (deactivate pallet, restore energy)
+// 1. Input → Apply player input (thrust, turn) to the first ship
+// 2. Physics → Update position and velocity of each ship (gravity + integrator)
+// 3. Collisions → Process pallet pickups (deactivate pallet, restore energy) and mark ships that hit the sun as destroyed
 // 4. Rules → Evaluate win/lose conditions (update Done/Win flags)
 // 5. State → Increment tick counter
 //
@@ -26,31 +26,103 @@ import (
 // Returns:
 //   - Updated world state after one game loop step
 func Step(world entities.World, input InputCommand, dt float64, G float64, aMax float64, pickupRadius float64) entities.World {
+	cfg := DefaultConfig()
+	cfg.G = G
+	cfg.AMax = aMax
+	cfg.PickupRadius = pickupRadius
+	return StepWithConfig(world, input, dt, cfg)
+}
+
+// StepWithConfig performs one game loop step like Step, taking every rule and
+// physics parameter from cfg. This is what balance tooling and configurable
+// sessions use to run the simulation with non-default constants.
+// input controls the first ship of world; any other ships get the zero command,
+// as in StepPlayers. Use StepPlayers to give each player their own input.
+//
+// Parameters:
+//   - world: Current world state
+//   - input: Player input command (thrust, turn)
+//   - dt: Time step in seconds
+//   - cfg: Rule and physics parameters
+//
+// Returns:
+//   - Updated world state after one game loop step
+func StepWithConfig(world entities.World, input InputCommand, dt float64, cfg Config) entities.World {
+	var first entities.PlayerID
+	if len(world.Ships) > 0 {
+		first = world.Ships[0].ID
+	}
+	return step(world, func(id entities.PlayerID) InputCommand {
+		if id == first {
+			return input
+		}
+		return InputCommand{}
+	}, dt, cfg)
+}
+
+// StepPlayers performs one game loop step for a multiplayer world, applying each
+// player's input to their own ship. Ships without an entry in inputs get the
+// zero command (no thrust, no turn).
+//
+// Ships are processed in ID order, so results are deterministic: when two ships
+// reach the same pallet on the same tick, the ship with the lower ID collects it.
+// A ship that hits the sun is marked Destroyed and is no longer simulated.
+//
+// Parameters:
+//   - world: Current world state
+//   - inputs: Input command per player
+//   - dt: Time step in seconds
+//   - cfg: Rule and physics parameters
+//
+// Returns:
+//   - Updated world state after one game loop step
+func StepPlayers(world entities.World, inputs map[entities.PlayerID]InputCommand, dt float64, cfg Config) entities.World {
+	return step(world, func(id entities.PlayerID) InputCommand { return inputs[id] }, dt, cfg)
+}
+
+// step runs the game loop step shared by StepWithConfig and StepPlayers.
+// Note that the Ships and Pallets slices of world are updated in place.
+func step(world entities.World, inputFor func(entities.PlayerID) InputCommand, dt float64, cfg Config) entities.World {
 	// If game is already done, skip processing and only increment tick
 	if world.Done {
 		world.Tick++
 		return world
 	}
 
-	// Step 1: Apply Input
-	// Process player input (thrust, turn) - updates rotation, velocity, and energy
-	world.Ship = ApplyInput(world.Ship, input, dt)
+	for i := range world.Ships {
+		ship := &world.Ships[i]
+		if ship.Destroyed {
+			continue
+		}
+
+		// Step 1: Apply Input
+		// Process player input (thrust, turn) - updates rotation, velocity, and energy
+		*ship = ApplyInputWithConfig(*ship, inputFor(ship.ID), dt, cfg)
 
-	// Step 2: Update Physics
-	// Calculate gravity acceleration and integrate position and velocity
-	acc := physics.GravityAcceleration(world.Ship.Pos, world.Sun.Pos, world.Sun.Mass, G, aMax)
-	newPos, newVel := physics.SemiImplicitEuler(world.Ship.Pos, world.Ship.Vel, acc, dt)
-	world.Ship.Pos = newPos
-	world.Ship.Vel = newVel
+		// Step 2: Update Physics
+		// Calculate gravity acceleration and integrate position and velocity
+		acc := physics.GravityAcceleration(ship.Pos, world.Sun.Pos, world.Sun.Mass, cfg.G, cfg.AMax)
+		ship.Pos, ship.Vel = physics.SemiImplicitEuler(ship.Pos, ship.Vel, acc, dt)
+	}
 
 	// Step 3: Process Collisions
 	// Check for pallet pickups and process them (deactivate pallet, restore energy)
 	for i := range world.Pallets {
-		if world.Pallets[i].Active && physics.ShipPalletCollision(world.Ship.Pos, world.Pallets[i].Pos, pickupRadius) {
-			// Deactivate pallet
-			world.Pallets[i].Active = false
-			// Restore energy
-			world.Ship.Energy = RestoreEnergyOnPickup(world.Ship.Energy)
+		for j := range world.Ships {
+			ship := &world.Ships[j]
+			if world.Pallets[i].Active && !ship.Destroyed && physics.ShipPalletCollision(ship.Pos, world.Pallets[i].Pos, cfg.PickupRadius) {
+				// Deactivate pallet
+				world.Pallets[i].Active = false
+				// Restore energy
+				ship.Energy = restoreEnergy(ship.Energy, cfg)
+			}
+		}
+	}
+
+	// Ships that hit the sun are destroyed
+	for i := range world.Ships {
+		if shipHitSun(world.Ships[i], world.Sun) {
+			world.Ships[i].Destroyed = true
 		}
 	}
 
@@ -64,4 +136,3 @@ func Step(world entities.World, input InputCommand, dt float64, G float64, aMax
 
 	return world
 }
-
@@ -32,13 +32,13 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, nil)
 			input := InputCommand{Thrust: 0.0, Turn: 0.0}
 
-			initialPos := world.Ship.Pos
+			initialPos := world.Ships[0].Pos
 			initialTick := world.Tick
 
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Physics should update (gravity pulls ship toward sun)
-			Expect(world.Ship.Pos).NotTo(Equal(initialPos))
+			Expect(world.Ships[0].Pos).NotTo(Equal(initialPos))
 			// Tick should increment
 			Expect(world.Tick).To(Equal(initialTick + 1))
 		})
@@ -54,15 +54,15 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, nil)
 			input := InputCommand{Thrust: 1.0, Turn: 0.0}
 
-			initialVel := world.Ship.Vel
-			initialEnergy := world.Ship.Energy
+			initialVel := world.Ships[0].Vel
+			initialEnergy := world.Ships[0].Energy
 
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Velocity should increase (thrust applied)
-			Expect(world.Ship.Vel.Length()).To(BeNumerically(">", initialVel.Length()))
+			Expect(world.Ships[0].Vel.Length()).To(BeNumerically(">", initialVel.Length()))
 			// Energy should decrease (drained by thrust)
-			Expect(world.Ship.Energy).To(BeNumerically("<", initialEnergy))
+			Expect(world.Ships[0].Energy).To(BeNumerically("<", initialEnergy))
 		})
 
 		It("step applies input correctly (turn)", func() {
@@ -76,12 +76,12 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, nil)
 			input := InputCommand{Thrust: 0.0, Turn: 1.0}
 
-			initialRot := world.Ship.Rot
+			initialRot := world.Ships[0].Rot
 
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Rotation should change
-			Expect(world.Ship.Rot).To(BeNumerically(">", initialRot))
+			Expect(world.Ships[0].Rot).To(BeNumerically(">", initialRot))
 		})
 
 		It("step updates physics correctly (gravity + integrator)", func() {
@@ -95,15 +95,15 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, nil)
 			input := InputCommand{Thrust: 0.0, Turn: 0.0}
 
-			initialPos := world.Ship.Pos
-			initialVel := world.Ship.Vel
+			initialPos := world.Ships[0].Pos
+			initialVel := world.Ships[0].Vel
 
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Position should change (gravity pulls toward sun)
-			Expect(world.Ship.Pos).NotTo(Equal(initialPos))
+			Expect(world.Ships[0].Pos).NotTo(Equal(initialPos))
 			// Velocity should change (gravity accelerates)
-			Expect(world.Ship.Vel).NotTo(Equal(initialVel))
+			Expect(world.Ships[0].Vel).NotTo(Equal(initialVel))
 		})
 
 		It("step processes pallet pickup correctly (deactivate pallet, restore energy)", func() {
@@ -120,14 +120,14 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, pallets)
 			input := InputCommand{Thrust: 0.0, Turn: 0.0}
 
-			initialEnergy := world.Ship.Energy
+			initialEnergy := world.Ships[0].Energy
 
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Pallet should be deactivated
 			Expect(world.Pallets[0].Active).To(BeFalse())
 			// Energy should be restored
-			Expect(world.Ship.Energy).To(BeNumerically("~", initialEnergy+PalletRestoreAmount, epsilon))
+			Expect(world.Ships[0].Energy).To(BeNumerically("~", initialEnergy+PalletRestoreAmount, epsilon))
 		})
 
 		It("step processes multiple pallet pickups in one step", func() {
@@ -146,7 +146,7 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, pallets)
 			input := InputCommand{Thrust: 0.0, Turn: 0.0}
 
-			initialEnergy := world.Ship.Energy
+			initialEnergy := world.Ships[0].Energy
 
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
@@ -158,7 +158,7 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			if expectedEnergy > MaxEnergy {
 				expectedEnergy = MaxEnergy
 			}
-			Expect(world.Ship.Energy).To(BeNumerically("~", expectedEnergy, epsilon))
+			Expect(world.Ships[0].Energy).To(BeNumerically("~", expectedEnergy, epsilon))
 		})
 
 		It("step evaluates win condition correctly (all pallets collected)", func() {
@@ -257,18 +257,18 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world.Tick = 10
 			input := InputCommand{Thrust: 1.0, Turn: 1.0}
 
-			initialPos := world.Ship.Pos
-			initialVel := world.Ship.Vel
-			initialRot := world.Ship.Rot
-			initialEnergy := world.Ship.Energy
+			initialPos := world.Ships[0].Pos
+			initialVel := world.Ships[0].Vel
+			initialRot := world.Ships[0].Rot
+			initialEnergy := world.Ships[0].Energy
 
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// State should be unchanged (except tick)
-			Expect(world.Ship.Pos).To(Equal(initialPos))
-			Expect(world.Ship.Vel).To(Equal(initialVel))
-			Expect(world.Ship.Rot).To(Equal(initialRot))
-			Expect(world.Ship.Energy).To(Equal(initialEnergy))
+			Expect(world.Ships[0].Pos).To(Equal(initialPos))
+			Expect(world.Ships[0].Vel).To(Equal(initialVel))
+			Expect(world.Ships[0].Rot).To(Equal(initialRot))
+			Expect(world.Ships[0].Energy).To(Equal(initialEnergy))
 			Expect(world.Done).To(BeTrue())
 			Expect(world.Win).To(BeTrue())
 			// Tick should increment
@@ -345,7 +345,7 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, pallets)
 			input := InputCommand{Thrust: 1.0, Turn: 0.0}
 
-			initialEnergy := world.Ship.Energy
+			initialEnergy := world.Ships[0].Energy
 
 			// Simulate until energy depleted or pallet picked up
 			for i := 0; i < 50 && !world.Done; i++ {
@@ -354,7 +354,7 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 
 			// Energy should be restored after pickup
 			if !world.Pallets[0].Active {
-				Expect(world.Ship.Energy).To(BeNumerically(">", initialEnergy-ThrustDrainRate*10.0))
+				Expect(world.Ships[0].Energy).To(BeNumerically(">", initialEnergy-ThrustDrainRate*10.0))
 			}
 		})
 
@@ -413,12 +413,12 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			}
 
 			// States should be identical
-			Expect(world1.Ship.Pos.X).To(Equal(world2.Ship.Pos.X))
-			Expect(world1.Ship.Pos.Y).To(Equal(world2.Ship.Pos.Y))
-			Expect(world1.Ship.Vel.X).To(Equal(world2.Ship.Vel.X))
-			Expect(world1.Ship.Vel.Y).To(Equal(world2.Ship.Vel.Y))
-			Expect(world1.Ship.Rot).To(Equal(world2.Ship.Rot))
-			Expect(world1.Ship.Energy).To(Equal(world2.Ship.Energy))
+			Expect(world1.Ships[0].Pos.X).To(Equal(world2.Ships[0].Pos.X))
+			Expect(world1.Ships[0].Pos.Y).To(Equal(world2.Ships[0].Pos.Y))
+			Expect(world1.Ships[0].Vel.X).To(Equal(world2.Ships[0].Vel.X))
+			Expect(world1.Ships[0].Vel.Y).To(Equal(world2.Ships[0].Vel.Y))
+			Expect(world1.Ships[0].Rot).To(Equal(world2.Ships[0].Rot))
+			Expect(world1.Ships[0].Energy).To(Equal(world2.Ships[0].Energy))
 			Expect(world1.Tick).To(Equal(world2.Tick))
 		})
 	})
@@ -452,11 +452,11 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, nil)
 			input := InputCommand{Thrust: 0.0, Turn: 0.0}
 
-			initialEnergy := world.Ship.Energy
+			initialEnergy := world.Ships[0].Energy
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Energy should not change (no thrust)
-			Expect(world.Ship.Energy).To(Equal(initialEnergy))
+			Expect(world.Ships[0].Energy).To(Equal(initialEnergy))
 		})
 
 		It("step works correctly when energy is zero (no thrust)", func() {
@@ -470,11 +470,11 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world := entities.NewWorld(ship, sun, nil)
 			input := InputCommand{Thrust: 1.0, Turn: 0.0}
 
-			initialVel := world.Ship.Vel
+			initialVel := world.Ships[0].Vel
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Velocity should not change (no thrust without energy)
-			Expect(world.Ship.Vel.Length()).To(BeNumerically("~", initialVel.Length(), epsilon))
+			Expect(world.Ships[0].Vel.Length()).To(BeNumerically("~", initialVel.Length(), epsilon))
 		})
 
 		It("step works correctly when energy is at maximum (clamping)", func() {
@@ -494,8 +494,8 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// Energy should be clamped to MaxEnergy
-			Expect(world.Ship.Energy).To(BeNumerically("<=", MaxEnergy, epsilon))
-			Expect(world.Ship.Energy).To(Equal(MaxEnergy))
+			Expect(world.Ships[0].Energy).To(BeNumerically("<=", MaxEnergy, epsilon))
+			Expect(world.Ships[0].Energy).To(Equal(MaxEnergy))
 		})
 
 		It("step works correctly over many consecutive steps", func() {
@@ -519,8 +519,8 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			// Tick should increment correctly
 			Expect(world.Tick).To(Equal(initialTick + 100))
 			// State should be consistent
-			Expect(world.Ship.Energy).To(BeNumerically(">=", 0.0))
-			Expect(world.Ship.Energy).To(BeNumerically("<=", MaxEnergy))
+			Expect(world.Ships[0].Energy).To(BeNumerically(">=", 0.0))
+			Expect(world.Ships[0].Energy).To(BeNumerically("<=", MaxEnergy))
 		})
 
 		It("step maintains world state consistency", func() {
@@ -540,8 +540,8 @@ var _ = Describe("Game Loop Step", Label("scope:unit", "loop:g2-rules", "layer:s
 			world = Step(world, input, dt, G, aMax, pickupRadius)
 
 			// All fields should be updated correctly
-			Expect(world.Ship.Pos).NotTo(Equal(entities.NewVec2(0.0, 0.0))) // Position changed
-			Expect(world.Ship.Rot).NotTo(Equal(0.0))                        // Rotation changed
+			Expect(world.Ships[0].Pos).NotTo(Equal(entities.NewVec2(0.0, 0.0))) // Position changed
+			Expect(world.Ships[0].Rot).NotTo(Equal(0.0))                        // Rotation changed
 			Expect(world.Tick).To(Equal(uint32(1)))                        // Tick incremented
 			Expect(world.Sun).To(Equal(sun))                                // Sun unchanged
 		})
@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// These specs are meant to be run with the race detector (make test-race).
+var _ = Describe("Transport Concurrency", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "b:concurrent-access", "r:high"), func() {
+	var (
+		testServer *httptest.Server
+		clientConn *websocket.Conn
+		serverConn chan *websocket.Conn
+	)
+
+	BeforeEach(func() {
+		serverConn = make(chan *websocket.Conn, 1)
+		testServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			conn, err := UpgradeConnection(w, r)
+			if err != nil {
+				return
+			}
+			serverConn <- conn
+		}))
+
+		var err error
+		clientConn, _, err = websocket.DefaultDialer.Dial("ws"+testServer.URL[4:], nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Drain everything the server sends so its writes never block
+		go func() {
+			for {
+				if _, _, err := clientConn.ReadMessage(); err != nil {
+					return
+				}
+			}
+		}()
+	})
+
+	AfterEach(func() {
+		clientConn.Close()
+		testServer.Close()
+	})
+
+	acceptConnection := func() *Connection {
+		var conn *websocket.Conn
+		Eventually(serverConn).Should(Receive(&conn))
+		return NewConnection(conn)
+	}
+
+	Describe("Connection", func() {
+		It("does not panic when WriteMessage races with Close", func() {
+			connection := acceptConnection()
+
+			var wg sync.WaitGroup
+			for i := 0; i < 4; i++ {
+				wg.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					for j := 0; j < 500; j++ {
+						_ = connection.WriteMessage([]byte(`{"t":"snapshot"}`))
+					}
+				}()
+			}
+
+			wg.Add(2)
+			for i := 0; i < 2; i++ {
+				go func() {
+					defer GinkgoRecover()
+					defer wg.Done()
+					time.Sleep(time.Millisecond)
+					_ = connection.Close()
+				}()
+			}
+			wg.Wait()
+
+			Expect(connection.WriteMessage([]byte(`{}`))).To(MatchError(ContainSubstring("connection closed")))
+		})
+	})
+
+	Describe("SessionHandler", func() {
+		It("handles input and restart while the run and snapshot loops are active", func() {
+			connection := acceptConnection()
+			defer connection.Close()
+
+			handler := NewSessionHandler(connection, session.NewRealClock(), NewInitialWorld(), DefaultOptions(), logr.Discard())
+			handler.Start()
+			defer handler.Stop()
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for seq := uint32(1); seq <= 300; seq++ {
+					_ = handler.HandleInput(&proto.InputMessage{Type: "input", Seq: seq, Thrust: 1.0, Turn: 0.5})
+					time.Sleep(100 * time.Microsecond)
+				}
+			}()
+
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				for i := 0; i < 20; i++ {
+					Expect(handler.HandleRestart(&proto.RestartMessage{Type: "restart"})).To(Succeed())
+					time.Sleep(2 * time.Millisecond)
+				}
+			}()
+
+			wg.Wait()
+		})
+
+		It("restarts from an unmodified initial world", func() {
+			connection := acceptConnection()
+			defer connection.Close()
+
+			clock := session.NewFakeClock()
+			initialWorld := NewInitialWorld()
+			// Put the ship on top of the first pallet so the first tick collects it
+			initialWorld.Ships[0].Pos = initialWorld.Pallets[0].Pos
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
+
+			clock.Advance(session.DefaultTickInterval)
+			Expect(handler.session.Run(1)).To(Succeed())
+			Expect(handler.session.GetWorld().Pallets[0].Active).To(BeFalse())
+
+			Expect(handler.HandleRestart(&proto.RestartMessage{Type: "restart"})).To(Succeed())
+
+			world := handler.session.GetWorld()
+			Expect(world.Tick).To(Equal(uint32(0)))
+			Expect(world.Pallets).To(HaveEach(HaveField("Active", BeTrue())))
+			Expect(initialWorld.Pallets).To(HaveEach(HaveField("Active", BeTrue())))
+		})
+	})
+})
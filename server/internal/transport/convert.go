@@ -2,6 +2,7 @@ package transport
 
 import (
 	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/session"
 	"github.com/gorbit/orbitalrush/internal/sim/entities"
 )
 
@@ -16,10 +17,12 @@ func Vec2ToSnapshot(v entities.Vec2) proto.Vec2Snapshot {
 // ShipToSnapshot converts an entities.Ship to a proto.ShipSnapshot.
 func ShipToSnapshot(s entities.Ship) proto.ShipSnapshot {
 	return proto.ShipSnapshot{
-		Pos:    Vec2ToSnapshot(s.Pos),
-		Vel:    Vec2ToSnapshot(s.Vel),
-		Rot:    s.Rot,
-		Energy: s.Energy,
+		ID:        string(s.ID),
+		Pos:       Vec2ToSnapshot(s.Pos),
+		Vel:       Vec2ToSnapshot(s.Vel),
+		Rot:       s.Rot,
+		Energy:    s.Energy,
+		Destroyed: s.Destroyed,
 	}
 }
 
@@ -42,25 +45,57 @@ func PalletToSnapshot(p entities.Pallet) proto.PalletSnapshot {
 	}
 }
 
+// InputAckToSnapshot converts a session.InputAck to a proto.InputAckSnapshot.
+func InputAckToSnapshot(ack session.InputAck) *proto.InputAckSnapshot {
+	return &proto.InputAckSnapshot{
+		Seq:  ack.Sequence,
+		Tick: ack.Tick,
+	}
+}
+
 // WorldToSnapshot converts an entities.World to a proto.SnapshotMessage.
 // This function bridges the simulation layer with the protocol layer,
 // enabling the server to broadcast game state to clients.
+// The snapshot is not addressed to a player: Ship is the first ship (if any)
+// and You is empty. Use WorldToPlayerSnapshot for a player's view.
 func WorldToSnapshot(w entities.World) proto.SnapshotMessage {
+	var ship entities.Ship
+	if len(w.Ships) > 0 {
+		ship = w.Ships[0]
+	}
+	return worldToSnapshot(w, ship, "")
+}
+
+// WorldToPlayerSnapshot converts an entities.World to the snapshot sent to player you:
+// Ship is their own ship and You is their ID. If you has no ship, Ship is left zero.
+// The world carries no input state: callers set Ack from the session (see InputAckToSnapshot).
+func WorldToPlayerSnapshot(w entities.World, you entities.PlayerID) proto.SnapshotMessage {
+	ship, _ := w.ShipByID(you)
+	return worldToSnapshot(w, ship, you)
+}
+
+// worldToSnapshot builds a snapshot of w with the given own ship and receiver ID.
+func worldToSnapshot(w entities.World, ship entities.Ship, you entities.PlayerID) proto.SnapshotMessage {
 	// Convert pallets slice, ensuring empty slice produces empty array (not nil)
 	pallets := make([]proto.PalletSnapshot, len(w.Pallets))
 	for i, pallet := range w.Pallets {
 		pallets[i] = PalletToSnapshot(pallet)
 	}
 
+	ships := make([]proto.ShipSnapshot, len(w.Ships))
+	for i, s := range w.Ships {
+		ships[i] = ShipToSnapshot(s)
+	}
+
 	return proto.SnapshotMessage{
 		Type:    "snapshot",
 		Tick:    w.Tick,
-		Ship:    ShipToSnapshot(w.Ship),
+		Ship:    ShipToSnapshot(ship),
+		Ships:   ships,
+		You:     string(you),
 		Sun:     SunToSnapshot(w.Sun),
 		Pallets: pallets,
 		Done:    w.Done,
 		Win:     w.Win,
 	}
 }
-
-
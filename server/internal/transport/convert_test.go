@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/session"
 	"github.com/gorbit/orbitalrush/internal/sim/entities"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -246,6 +247,13 @@ var _ = Describe("Entity-to-Protocol Conversion", Label("scope:unit", "loop:g5-a
 		})
 	})
 
+	Describe("InputAckToSnapshot", func() {
+		It("converts the sequence number and tick", func() {
+			result := InputAckToSnapshot(session.InputAck{Sequence: 7, Tick: 41})
+			Expect(result).To(Equal(&proto.InputAckSnapshot{Seq: 7, Tick: 41}))
+		})
+	})
+
 	Describe("WorldToSnapshot", func() {
 		It("converts complete world with all entities correctly", func() {
 			ship := entities.NewShip(
@@ -386,7 +394,7 @@ var _ = Describe("Entity-to-Protocol Conversion", Label("scope:unit", "loop:g5-a
 			result := WorldToSnapshot(world)
 
 			// Verify Ship conversion
-			shipSnapshot := ShipToSnapshot(ship)
+			shipSnapshot := ShipToSnapshot(world.Ships[0])
 			Expect(result.Ship).To(Equal(shipSnapshot))
 
 			// Verify Sun conversion
@@ -462,6 +470,45 @@ var _ = Describe("Entity-to-Protocol Conversion", Label("scope:unit", "loop:g5-a
 			}
 		})
 	})
-})
+	Describe("WorldToPlayerSnapshot", func() {
+		var world entities.World
+
+		BeforeEach(func() {
+			p1 := entities.NewShip(entities.NewVec2(10.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 100.0)
+			p1.ID = "p1"
+			p2 := entities.NewShip(entities.NewVec2(-10.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 80.0)
+			p2.ID = "p2"
+			p2.Destroyed = true
+			world = entities.NewMultiplayerWorld(
+				[]entities.Ship{p2, p1},
+				entities.NewSun(entities.NewVec2(0.0, 0.0), 5.0, 1000.0),
+				[]entities.Pallet{},
+			)
+		})
 
+		It("addresses the snapshot to the given player", func() {
+			result := WorldToPlayerSnapshot(world, "p2")
+
+			Expect(result.You).To(Equal("p2"))
+			Expect(result.Ship.ID).To(Equal("p2"))
+			Expect(result.Ship.Energy).To(Equal(float32(80.0)))
+			Expect(result.Ship.Destroyed).To(BeTrue())
+		})
 
+		It("includes every ship in ID order", func() {
+			result := WorldToPlayerSnapshot(world, "p1")
+
+			Expect(result.Ships).To(HaveLen(2))
+			Expect(result.Ships[0].ID).To(Equal("p1"))
+			Expect(result.Ships[1].ID).To(Equal("p2"))
+			Expect(proto.ValidateSnapshotMessage(&result)).To(Succeed())
+		})
+
+		It("leaves Ship zero for a player without a ship", func() {
+			result := WorldToPlayerSnapshot(world, "p3")
+
+			Expect(result.You).To(Equal("p3"))
+			Expect(result.Ship).To(Equal(proto.ShipSnapshot{}))
+		})
+	})
+})
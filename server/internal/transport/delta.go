@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"sync"
+
+	"github.com/gorbit/orbitalrush/internal/proto"
+)
+
+// deltaHistorySize is the number of snapshots sent to a connection that it can
+// acknowledge as the base of later deltas (3.2 seconds at 10 Hz). A client whose
+// last acknowledged snapshot is older, e.g. after losing acknowledgements, gets
+// full snapshots until it acknowledges one of them.
+const deltaHistorySize = 32
+
+// snapshotBaselines keeps the snapshots recently sent to a connection and the one
+// the client last acknowledged, and turns new snapshots into deltas against it.
+// The zero value is ready to use; its methods may be called from any goroutine.
+type snapshotBaselines struct {
+	mu     sync.Mutex
+	sent   []proto.SnapshotMessage // Snapshots sent, oldest first, at most deltaHistorySize
+	acked  uint32                  // Tick of the acknowledged snapshot, valid if hasAck
+	hasAck bool
+}
+
+// next records snapshot as sent and returns the message to send for it: a delta
+// against the acknowledged snapshot, or snapshot itself (a keyframe) if there is
+// none or it is no longer kept.
+func (b *snapshotBaselines) next(snapshot proto.SnapshotMessage) any {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Ticks only go back when the session restarts; earlier snapshots are stale
+	if n := len(b.sent); n > 0 && snapshot.Tick < b.sent[n-1].Tick {
+		b.sent = b.sent[:0]
+		b.hasAck = false
+	}
+
+	var msg any = snapshot
+	if b.hasAck {
+		if base, ok := b.find(b.acked); ok {
+			msg = proto.DiffSnapshot(base, snapshot)
+		} else {
+			b.hasAck = false
+		}
+	}
+
+	// Snapshots of the same tick (e.g. while paused) replace each other
+	if n := len(b.sent); n > 0 && b.sent[n-1].Tick == snapshot.Tick {
+		b.sent[n-1] = snapshot
+	} else {
+		if len(b.sent) == deltaHistorySize {
+			copy(b.sent, b.sent[1:])
+			b.sent = b.sent[:deltaHistorySize-1]
+		}
+		b.sent = append(b.sent, snapshot)
+	}
+	return msg
+}
+
+// ack makes the sent snapshot of tick the base of the next deltas, unless an
+// acknowledged snapshot is newer. Acknowledgements of snapshots no longer kept
+// are ignored.
+func (b *snapshotBaselines) ack(tick uint32) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.hasAck && tick <= b.acked {
+		return
+	}
+	if _, ok := b.find(tick); ok {
+		b.acked = tick
+		b.hasAck = true
+	}
+}
+
+// requestKeyframe forgets the acknowledged snapshot, so that the next snapshot is
+// sent in full.
+func (b *snapshotBaselines) requestKeyframe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hasAck = false
+}
+
+// reset forgets the sent snapshots, e.g. when a new connection takes over.
+func (b *snapshotBaselines) reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = b.sent[:0]
+	b.hasAck = false
+}
+
+// find returns the sent snapshot of tick. Callers hold b.mu.
+func (b *snapshotBaselines) find(tick uint32) (proto.SnapshotMessage, bool) {
+	for i := len(b.sent) - 1; i >= 0; i-- {
+		if b.sent[i].Tick == tick {
+			return b.sent[i], true
+		}
+	}
+	return proto.SnapshotMessage{}, false
+}
@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"github.com/gorbit/orbitalrush/internal/proto"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// baselineSnapshot returns a snapshot of tick with one ship at x.
+func baselineSnapshot(tick uint32, x float64) proto.SnapshotMessage {
+	return proto.SnapshotMessage{
+		Type:    "snapshot",
+		Tick:    tick,
+		Ship:    proto.ShipSnapshot{Pos: proto.Vec2Snapshot{X: x}, Energy: 100},
+		Sun:     proto.SunSnapshot{Radius: 50},
+		Pallets: []proto.PalletSnapshot{{ID: 1, Active: true}},
+	}
+}
+
+var _ = Describe("Snapshot Baselines", Label("scope:unit", "loop:g5-adapter", "layer:server", "b:delta-snapshots", "r:medium"), func() {
+	var baselines *snapshotBaselines
+
+	BeforeEach(func() {
+		baselines = &snapshotBaselines{}
+	})
+
+	It("sends full snapshots until one is acknowledged", func() {
+		Expect(baselines.next(baselineSnapshot(1, 10))).To(BeAssignableToTypeOf(proto.SnapshotMessage{}))
+		Expect(baselines.next(baselineSnapshot(2, 20))).To(BeAssignableToTypeOf(proto.SnapshotMessage{}))
+	})
+
+	It("sends deltas against the acknowledged snapshot", func() {
+		baselines.next(baselineSnapshot(1, 10))
+		baselines.next(baselineSnapshot(2, 20))
+		baselines.ack(1)
+
+		msg := baselines.next(baselineSnapshot(3, 30))
+		delta, ok := msg.(proto.DeltaSnapshotMessage)
+		Expect(ok).To(BeTrue())
+		Expect(delta.Base).To(Equal(uint32(1)))
+		Expect(delta.Ship.Pos).To(Equal(&proto.Vec2Snapshot{X: 30}))
+		Expect(delta.Sun).To(BeNil())
+		Expect(delta.Pallets).To(BeEmpty())
+
+		got, err := proto.ApplyDelta(baselineSnapshot(1, 10), delta)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal(baselineSnapshot(3, 30)))
+	})
+
+	It("ignores acknowledgements older than the current one or of unknown snapshots", func() {
+		baselines.next(baselineSnapshot(1, 10))
+		baselines.next(baselineSnapshot(2, 20))
+		baselines.ack(2)
+		baselines.ack(1)
+		baselines.ack(7)
+
+		delta := baselines.next(baselineSnapshot(3, 30)).(proto.DeltaSnapshotMessage)
+		Expect(delta.Base).To(Equal(uint32(2)))
+	})
+
+	It("sends a keyframe on request", func() {
+		baselines.next(baselineSnapshot(1, 10))
+		baselines.ack(1)
+		baselines.requestKeyframe()
+
+		Expect(baselines.next(baselineSnapshot(2, 20))).To(Equal(baselineSnapshot(2, 20)))
+	})
+
+	It("sends keyframes once the acknowledged snapshot is no longer kept", func() {
+		baselines.next(baselineSnapshot(1, 10))
+		baselines.ack(1)
+		for tick := uint32(2); tick <= deltaHistorySize; tick++ {
+			Expect(baselines.next(baselineSnapshot(tick, 10))).To(BeAssignableToTypeOf(proto.DeltaSnapshotMessage{}))
+		}
+
+		// Snapshot 1 dropped out of the history when snapshot 33 was recorded
+		baselines.next(baselineSnapshot(deltaHistorySize+1, 10))
+		Expect(baselines.next(baselineSnapshot(deltaHistorySize+2, 10))).To(BeAssignableToTypeOf(proto.SnapshotMessage{}))
+	})
+
+	It("forgets snapshots when the tick goes back after a restart", func() {
+		baselines.next(baselineSnapshot(5, 10))
+		baselines.ack(5)
+
+		Expect(baselines.next(baselineSnapshot(0, 0))).To(BeAssignableToTypeOf(proto.SnapshotMessage{}))
+		baselines.ack(5)
+		Expect(baselines.next(baselineSnapshot(1, 0))).To(BeAssignableToTypeOf(proto.SnapshotMessage{}))
+	})
+
+	It("sends deltas against the latest snapshot of a tick while paused", func() {
+		paused := baselineSnapshot(4, 10)
+		paused.Paused = true
+		baselines.next(baselineSnapshot(4, 10))
+		baselines.next(paused)
+		baselines.ack(4)
+
+		delta := baselines.next(paused).(proto.DeltaSnapshotMessage)
+		Expect(delta).To(Equal(proto.DeltaSnapshotMessage{Type: "delta", Tick: 4, Base: 4, Paused: true}))
+	})
+})
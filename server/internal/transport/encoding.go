@@ -0,0 +1,139 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket subprotocols a client can request (Sec-WebSocket-Protocol) to choose
+// the encoding of its connection. Connections without a subprotocol use JSON.
+const (
+	// SubprotocolBinary selects the binary encoding (see proto.MarshalBinary)
+	SubprotocolBinary = "orbitalrush.binary"
+	// SubprotocolJSON selects the JSON encoding
+	SubprotocolJSON = "orbitalrush.json"
+)
+
+// Encoding is the message encoding of a connection.
+type Encoding int
+
+const (
+	// EncodingJSON sends and accepts JSON messages in text frames
+	EncodingJSON Encoding = iota
+	// EncodingBinary sends and accepts binary messages in binary frames
+	EncodingBinary
+)
+
+// String returns the encoding name.
+func (e Encoding) String() string {
+	if e == EncodingBinary {
+		return "binary"
+	}
+	return "json"
+}
+
+// encodingForSubprotocol returns the encoding selected by a negotiated subprotocol.
+func encodingForSubprotocol(subprotocol string) Encoding {
+	if subprotocol == SubprotocolBinary {
+		return EncodingBinary
+	}
+	return EncodingJSON
+}
+
+// frameType returns the WebSocket frame type carrying messages of the encoding.
+func (e Encoding) frameType() int {
+	if e == EncodingBinary {
+		return websocket.BinaryMessage
+	}
+	return websocket.TextMessage
+}
+
+// Encode serializes msg, one of the proto message structs, in the encoding.
+func (e Encoding) Encode(msg any) ([]byte, error) {
+	if e == EncodingBinary {
+		return proto.MarshalBinary(msg)
+	}
+	return json.Marshal(msg)
+}
+
+// Parse parses a message in the encoding (see ParseMessage and ParseBinaryMessage).
+func (e Encoding) Parse(data []byte) (interface{}, error) {
+	if e == EncodingBinary {
+		return ParseBinaryMessage(data)
+	}
+	return ParseMessage(data)
+}
+
+// Encoding returns the encoding negotiated for the connection.
+func (c *Connection) Encoding() Encoding {
+	return c.encoding
+}
+
+// Send serializes msg in the connection's encoding and enqueues it like WriteMessage.
+func (c *Connection) Send(msg any) error {
+	data, err := c.encoding.Encode(msg)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(data)
+}
+
+// ParseBinaryMessage parses a binary message and returns a typed message, like
+// ParseMessage does for JSON. Returns an error if the message is malformed,
+// invalid, or not a client message.
+func ParseBinaryMessage(data []byte) (interface{}, error) {
+	msg, err := proto.UnmarshalBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse binary message: %w", err)
+	}
+
+	switch m := msg.(type) {
+	case *proto.InputMessage:
+		if err := proto.ValidateInputMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid InputMessage: %w", err)
+		}
+	case *proto.InputsMessage:
+		if err := proto.ValidateInputsMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid InputsMessage: %w", err)
+		}
+	case *proto.RestartMessage:
+		if err := proto.ValidateRestartMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid RestartMessage: %w", err)
+		}
+	case *proto.SessionControlMessage:
+		if err := proto.ValidateSessionControlMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid SessionControlMessage: %w", err)
+		}
+	case *proto.SnapshotAckMessage:
+		if err := proto.ValidateSnapshotAckMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid SnapshotAckMessage: %w", err)
+		}
+	case *proto.KeyframeRequestMessage:
+		if err := proto.ValidateKeyframeRequestMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid KeyframeRequestMessage: %w", err)
+		}
+	case *proto.ReplayControlMessage:
+		if err := proto.ValidateReplayControlMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid ReplayControlMessage: %w", err)
+		}
+	case *proto.HelloMessage:
+		if err := proto.ValidateHelloMessage(m); err != nil {
+			return nil, fmt.Errorf("invalid HelloMessage: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unexpected message type: %T", msg)
+	}
+	return msg, nil
+}
+
+// RouteBinaryMessage is RouteMessage for a binary message.
+func RouteBinaryMessage(data []byte, inputHandler InputMessageHandler, restartHandler RestartMessageHandler, controlHandler SessionControlHandler) error {
+	msg, err := ParseBinaryMessage(data)
+	if err != nil {
+		return err
+	}
+	return routeParsedMessage(msg, inputHandler, restartHandler, controlHandler)
+}
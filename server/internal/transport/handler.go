@@ -4,22 +4,167 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/room"
 	"github.com/gorbit/orbitalrush/internal/session"
 )
 
-// WebSocketHandler handles WebSocket upgrade requests at the /ws endpoint.
-// It upgrades the HTTP connection to WebSocket, creates a session handler,
-// and manages the connection lifecycle.
-func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+// WebSocketHandler returns the handler of WebSocket upgrade requests at the /ws
+// endpoint. It upgrades the HTTP connection to WebSocket, creates a session
+// handler with a private session set up with opts, and manages the connection
+// lifecycle.
+//
+// If opts.Resume is set, every session is resumable: the client first receives a
+// "session" message with a resume token, and when the connection drops the session
+// keeps running for the resume grace period (see NewResumeStore). Reconnecting
+// with /ws?resume=<token> within it reattaches the client to the same session. An
+// unknown or expired token starts a new session, which the session message
+// reports with resumed=false.
+func WebSocketHandler(opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serveResumable(w, r, opts)
+	}
+}
+
+// serveResumable serves a /ws request for a private session (see WebSocketHandler).
+func serveResumable(w http.ResponseWriter, r *http.Request, opts Options) {
+	connLogger := newConnectionLogger(r)
+	resumeToken := r.URL.Query().Get("resume")
+
+	if opts.Resume == nil {
+		serveWebSocket(w, r, connLogger, func(wsConn *Connection, sessionLogger logr.Logger) {
+			// Create session handler with real clock and initial world
+			sessionHandler := NewSessionHandler(wsConn, session.NewRealClock(), NewInitialWorld(), opts, sessionLogger)
+			serveSession(wsConn, sessionHandler, connLogger, wsConn.ReadMessage)
+		})
+		return
+	}
+
+	resumableSessions := opts.Resume
+	serveWebSocket(w, r, connLogger, func(wsConn *Connection, sessionLogger logr.Logger) {
+		sessionHandler, resumed := resumableSessions.take(resumeToken)
+		if !resumed {
+			token, err := newResumeToken()
+			if err != nil {
+				connLogger.Error(err, "Failed to create resume token", "message_type", "resume_error")
+				return
+			}
+			resumeToken = token
+			// Create session handler with real clock and initial world
+			sessionHandler = NewSessionHandler(wsConn, session.NewRealClock(), NewInitialWorld(), opts, sessionLogger)
+		}
+		if err := wsConn.Send(proto.SessionMessage{Type: "session", Token: resumeToken, Resumed: resumed}); err != nil {
+			if resumed {
+				resumableSessions.park(resumeToken, sessionHandler)
+			} else {
+				sessionHandler.Stop()
+			}
+			return
+		}
+
+		if resumed {
+			connLogger.Info("Session resumed", "message_type", "resume")
+			sessionHandler.Attach(wsConn)
+		} else {
+			resumableSessions.register(resumeToken, sessionHandler)
+			sessionHandler.Start()
+		}
+
+		routeMessages(wsConn, sessionHandler, connLogger, wsConn.ReadMessage)
+
+		// Keep the session running until the client resumes it or the grace period ends
+		sessionHandler.Detach()
+		resumableSessions.park(resumeToken, sessionHandler)
+	})
+}
+
+// NewWebSocketHandler returns the handler for the /ws endpoint backed by rooms.
+// Requests with a room query parameter (/ws?room=<id>) join that room as a new
+// player and leave it on disconnect; other requests get a private session, as with
+// WebSocketHandler with opts. Joining fails before the upgrade with 404 if the room
+// does not exist and 409 if it is full.
+//
+// With spectate=true (/ws?room=<id>&spectate=true) the client watches the room
+// read-only instead of joining it (see NewSpectatorSessionHandler).
+func NewWebSocketHandler(rooms *room.Manager, opts Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		roomID := r.URL.Query().Get("room")
+		spectate := false
+		if value := r.URL.Query().Get("spectate"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				writeRoomError(w, http.StatusBadRequest, fmt.Errorf("invalid spectate: %q", value))
+				return
+			}
+			spectate = parsed
+		}
+		if spectate && roomID == "" {
+			writeRoomError(w, http.StatusBadRequest, fmt.Errorf("spectating requires a room"))
+			return
+		}
+		if roomID == "" {
+			serveResumable(w, r, opts)
+			return
+		}
+
+		connLogger := newConnectionLogger(r).WithValues("room_id", roomID)
+
+		if spectate {
+			serveSpectator(w, r, rooms, roomID, connLogger)
+			return
+		}
+
+		rm, playerID, err := rooms.Join(roomID)
+		if err != nil {
+			connLogger.Error(err, "Failed to join room", "message_type", "join_error")
+			writeRoomError(w, roomErrorStatus(err), err)
+			return
+		}
+		defer rooms.Leave(roomID, playerID)
+
+		connLogger = connLogger.WithValues("player_id", playerID)
+		serveWebSocket(w, r, connLogger, func(wsConn *Connection, sessionLogger logr.Logger) {
+			sessionHandler := NewRoomSessionHandler(wsConn, rm, playerID, sessionLogger)
+			serveSession(wsConn, sessionHandler, connLogger, wsConn.ReadMessage)
+		})
+	}
+}
+
+// serveSpectator attaches the client to room roomID as a spectator until it disconnects.
+// Fails before the upgrade with 404 if the room does not exist.
+func serveSpectator(w http.ResponseWriter, r *http.Request, rooms *room.Manager, roomID string, connLogger logr.Logger) {
+	rm, err := rooms.Spectate(roomID)
+	if err != nil {
+		connLogger.Error(err, "Failed to spectate room", "message_type", "spectate_error")
+		writeRoomError(w, roomErrorStatus(err), err)
+		return
+	}
+	defer rooms.StopSpectating(roomID)
+
+	connLogger = connLogger.WithValues("spectator", true)
+	serveWebSocket(w, r, connLogger, func(wsConn *Connection, sessionLogger logr.Logger) {
+		sessionHandler := NewSpectatorSessionHandler(wsConn, rm, sessionLogger)
+		serveSession(wsConn, sessionHandler, connLogger, wsConn.ReadMessage)
+	})
+}
+
+// newConnectionLogger returns a logger for a WebSocket request, tagged with a
+// connection ID generated from the remote address and timestamp.
+func newConnectionLogger(r *http.Request) logr.Logger {
 	logger := observability.NewLogger().WithValues("component", "transport", "handler", "websocket")
-	
-	// Generate a simple connection ID from remote address and timestamp
 	connectionID := fmt.Sprintf("%s-%d", r.RemoteAddr, time.Now().UnixNano())
-	connLogger := logger.WithValues("connection_id", connectionID)
+	return logger.WithValues("connection_id", connectionID)
+}
 
+// serveWebSocket upgrades the request, calls serve with the connection and a
+// session logger, and records connection metrics. The connection is closed when
+// serve returns.
+func serveWebSocket(w http.ResponseWriter, r *http.Request, connLogger logr.Logger, serve func(wsConn *Connection, sessionLogger logr.Logger)) {
 	// Upgrade HTTP connection to WebSocket
 	conn, err := UpgradeConnection(w, r)
 	if err != nil {
@@ -67,23 +212,29 @@ func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 		activeGauge.Inc()
 	}
 	
-	// Create session handler with real clock and initial world
-	clock := session.NewRealClock()
-	initialWorld := NewInitialWorld()
-	// Create session logger with connection context
-	sessionLogger := connLogger.WithValues("component", "session")
-	sessionHandler := NewSessionHandler(wsConn, clock, initialWorld, sessionLogger)
-
 	connLogger.Info("WebSocket connection established", "message_type", "connect", "remote_addr", r.RemoteAddr)
 
+	// Create session logger with connection context
+	serve(wsConn, connLogger.WithValues("component", "session"))
+}
+
+// serveSession runs sessionHandler until read fails, routing every message read
+// from the client to it. Routing errors are reported to the client.
+func serveSession(wsConn *Connection, sessionHandler *SessionHandler, connLogger logr.Logger, read func() ([]byte, error)) {
 	// Start session handler (runs session loop and snapshot broadcasting)
 	sessionHandler.Start()
 	defer sessionHandler.Stop()
 
+	routeMessages(wsConn, sessionHandler, connLogger, read)
+}
+
+// routeMessages routes every message read from the client to sessionHandler until
+// read fails. Routing errors are reported to the client.
+func routeMessages(wsConn *Connection, sessionHandler *SessionHandler, connLogger logr.Logger, read func() ([]byte, error)) {
 	// Handle incoming messages in a loop
 	for {
 		// Read message from WebSocket
-		data, err := wsConn.ReadMessage()
+		data, err := read()
 		if err != nil {
 			// Connection closed or error reading
 			// This is normal when client disconnects
@@ -92,7 +243,11 @@ func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 		}
 
 		// Route message to session handler
-		err = RouteMessage(data, sessionHandler, sessionHandler)
+		if wsConn.Encoding() == EncodingBinary {
+			err = RouteBinaryMessage(data, sessionHandler, sessionHandler, sessionHandler)
+		} else {
+			err = RouteMessage(data, sessionHandler, sessionHandler, sessionHandler)
+		}
 		if err != nil {
 			// Record error event
 			if eventsCounter := observability.GetConnectionEventsCounter(); eventsCounter != nil {
@@ -100,8 +255,7 @@ func WebSocketHandler(w http.ResponseWriter, r *http.Request) {
 			}
 			// Send error response to client
 			connLogger.Error(err, "Failed to route message", "message_type", "route_error")
-			errorMsg := NewErrorMessage(err)
-			if writeErr := wsConn.WriteMessage(errorMsg); writeErr != nil {
+			if writeErr := wsConn.Send(newErrorMessage(err)); writeErr != nil {
 				// Failed to write error, connection likely closed
 				if eventsCounter := observability.GetConnectionEventsCounter(); eventsCounter != nil {
 					eventsCounter.WithLabelValues("error").Inc()
@@ -22,11 +22,14 @@ func TestHandler(t *testing.T) {
 var _ = Describe("HTTP Route Handlers", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "b:http-routes", "r:medium"), func() {
 	var testServer *httptest.Server
 	var serverURL string
+	var resumableSessions *ResumeStore
 
 	BeforeEach(func() {
 		// Create test HTTP server with handlers
 		mux := http.NewServeMux()
-		mux.HandleFunc("/ws", WebSocketHandler)
+		opts := DefaultOptions()
+		resumableSessions = opts.Resume
+		mux.HandleFunc("/ws", WebSocketHandler(opts))
 		mux.HandleFunc("/healthz", HealthzHandler)
 
 		testServer = httptest.NewServer(mux)
@@ -37,6 +40,8 @@ var _ = Describe("HTTP Route Handlers", Label("scope:integration", "loop:g5-adap
 		if testServer != nil {
 			testServer.Close()
 		}
+		// Stop the sessions parked by the test's connections
+		resumableSessions.drain()
 	})
 
 	Describe("WebSocketHandler", func() {
@@ -56,6 +61,7 @@ var _ = Describe("HTTP Route Handlers", Label("scope:integration", "loop:g5-adap
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Wait a bit to ensure session handler is started
@@ -96,6 +102,7 @@ var _ = Describe("HTTP Route Handlers", Label("scope:integration", "loop:g5-adap
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 
 			// Close connection
 			err = conn.Close()
@@ -415,6 +422,7 @@ var _ = Describe("HTTP Route Handlers", Label("scope:integration", "loop:g5-adap
 var _ = Describe("Connection Metrics", Label("scope:integration", "loop:g7-ops", "layer:server", "dep:ws", "b:connection-metrics", "r:high"), func() {
 	var testServer *httptest.Server
 	var serverURL string
+	var resumableSessions *ResumeStore
 
 	BeforeEach(func() {
 		// Initialize metrics before each test
@@ -422,7 +430,9 @@ var _ = Describe("Connection Metrics", Label("scope:integration", "loop:g7-ops",
 
 		// Create test HTTP server with handlers
 		mux := http.NewServeMux()
-		mux.HandleFunc("/ws", WebSocketHandler)
+		opts := DefaultOptions()
+		resumableSessions = opts.Resume
+		mux.HandleFunc("/ws", WebSocketHandler(opts))
 		mux.HandleFunc("/healthz", HealthzHandler)
 		mux.HandleFunc("/metrics", observability.MetricsHandler)
 
@@ -434,6 +444,8 @@ var _ = Describe("Connection Metrics", Label("scope:integration", "loop:g7-ops",
 		if testServer != nil {
 			testServer.Close()
 		}
+		// Stop the sessions parked by the test's connections
+		resumableSessions.drain()
 	})
 
 	Describe("Connection Events Counter", func() {
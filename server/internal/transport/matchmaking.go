@@ -0,0 +1,126 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/matchmaking"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/room"
+)
+
+// errConnectionClosed is returned by the reads of a matchmaking connection once
+// the client has disconnected.
+var errConnectionClosed = errors.New("connection closed")
+
+// NewMatchmakingHandler returns the handler for the /ws/match endpoint.
+// The client is queued with the rating query parameter (/ws/match?rating=<n>,
+// matchmaking.DefaultRating if absent) and receives a "queued" message. Once
+// matched it receives a "match" message naming its room and player ID, and the
+// connection continues as a player of that room, as with /ws?room=. Messages sent
+// while queued are ignored. Disconnecting while queued cancels the ticket.
+// An invalid rating fails before the upgrade with 400.
+func NewMatchmakingHandler(matchmaker *matchmaking.Matchmaker, rooms *room.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rating := matchmaking.DefaultRating
+		if value := r.URL.Query().Get("rating"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				writeRoomError(w, http.StatusBadRequest, fmt.Errorf("invalid rating: %q", value))
+				return
+			}
+			rating = parsed
+		}
+
+		connLogger := newConnectionLogger(r).WithValues("rating", rating)
+
+		serveWebSocket(w, r, connLogger, func(wsConn *Connection, sessionLogger logr.Logger) {
+			// Read in the background so a disconnect is noticed while queued
+			msgs, stopReading := readInBackground(wsConn)
+			defer stopReading()
+
+			if err := wsConn.Send(proto.QueuedMessage{Type: "queued", Rating: rating}); err != nil {
+				return
+			}
+			ticket := matchmaker.Enqueue(rating)
+
+			assignment, ok := awaitAssignment(matchmaker, rooms, ticket, msgs)
+			if !ok {
+				connLogger.Info("Client left the matchmaking queue", "message_type", "queue_cancel")
+				return
+			}
+			defer rooms.Leave(assignment.Room.ID(), assignment.PlayerID)
+
+			connLogger = connLogger.WithValues("room_id", assignment.Room.ID(), "player_id", assignment.PlayerID)
+			connLogger.Info("Client matched", "message_type", "match", "waited_seconds", assignment.Waited.Seconds())
+
+			match := proto.MatchMessage{
+				Type:    "match",
+				Room:    assignment.Room.ID(),
+				You:     string(assignment.PlayerID),
+				Players: assignment.Players,
+			}
+			if err := wsConn.Send(match); err != nil {
+				return
+			}
+
+			sessionHandler := NewRoomSessionHandler(wsConn, assignment.Room, assignment.PlayerID, sessionLogger)
+			serveSession(wsConn, sessionHandler, connLogger, func() ([]byte, error) {
+				data, ok := <-msgs
+				if !ok {
+					return nil, errConnectionClosed
+				}
+				return data, nil
+			})
+		})
+	}
+}
+
+// awaitAssignment waits until ticket is matched or msgs is closed by a disconnect,
+// discarding the messages received meanwhile. On disconnect the ticket is
+// cancelled; if it was matched in the meantime, the player leaves the room again.
+// Returns false if the client disconnected.
+func awaitAssignment(matchmaker *matchmaking.Matchmaker, rooms *room.Manager, ticket *matchmaking.Ticket, msgs <-chan []byte) (matchmaking.Assignment, bool) {
+	for {
+		select {
+		case assignment := <-ticket.Assigned():
+			return assignment, true
+		case _, ok := <-msgs:
+			if ok {
+				continue
+			}
+			if !matchmaker.Cancel(ticket) {
+				// Matched concurrently with the disconnect; the assignment is already sent
+				assignment := <-ticket.Assigned()
+				rooms.Leave(assignment.Room.ID(), assignment.PlayerID)
+			}
+			return matchmaking.Assignment{}, false
+		}
+	}
+}
+
+// readInBackground reads messages from wsConn on a new goroutine and delivers them
+// on the returned channel, which is closed when reading fails (e.g. on disconnect).
+// Call stop when the messages are no longer consumed.
+func readInBackground(wsConn *Connection) (msgs <-chan []byte, stop func()) {
+	out := make(chan []byte)
+	done := make(chan struct{})
+	go func() {
+		defer close(out)
+		for {
+			data, err := wsConn.ReadMessage()
+			if err != nil {
+				return
+			}
+			select {
+			case out <- data:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return out, func() { close(done) }
+}
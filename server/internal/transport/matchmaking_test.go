@@ -0,0 +1,110 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/matchmaking"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/room"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Matchmaking Endpoint", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "b:matchmaking", "r:high"), func() {
+	var (
+		rooms      *room.Manager
+		matchmaker *matchmaking.Matchmaker
+		testServer *httptest.Server
+	)
+
+	BeforeEach(func() {
+		rooms = room.NewManager(session.NewRealClock(), 10, session.DefaultOptions(), logr.Discard())
+		var err error
+		matchmaker, err = matchmaking.NewMatchmaker(session.NewRealClock(), rooms, matchmaking.DefaultConfig(), logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ws/match", NewMatchmakingHandler(matchmaker, rooms))
+		testServer = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+		for _, info := range rooms.List() {
+			rooms.Close(info.ID)
+		}
+	})
+
+	dialMatch := func(query string) (*websocket.Conn, *http.Response, error) {
+		return websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+"/ws/match"+query, nil)
+	}
+
+	readJSON := func(conn *websocket.Conn, v interface{}) {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, json.Unmarshal(data, v)).To(Succeed())
+	}
+
+	It("queues players and hands each their match before streaming the room", func() {
+		first, _, err := dialMatch("?rating=1500")
+		Expect(err).NotTo(HaveOccurred())
+		defer first.Close()
+		second, _, err := dialMatch("?rating=1550")
+		Expect(err).NotTo(HaveOccurred())
+		defer second.Close()
+
+		var queued proto.QueuedMessage
+		readJSON(first, &queued)
+		Expect(proto.ValidateQueuedMessage(&queued)).To(Succeed())
+		Expect(queued.Rating).To(Equal(1500))
+		readJSON(second, &queued)
+		Expect(queued.Rating).To(Equal(1550))
+
+		Eventually(matchmaker.QueueSize).Should(Equal(2))
+		Expect(matchmaker.Match()).To(Equal(1))
+
+		var firstMatch, secondMatch proto.MatchMessage
+		readJSON(first, &firstMatch)
+		readJSON(second, &secondMatch)
+		Expect(proto.ValidateMatchMessage(&firstMatch)).To(Succeed())
+		Expect(firstMatch.Room).To(Equal(secondMatch.Room))
+		Expect(firstMatch.Players).To(Equal(2))
+		Expect(firstMatch.You).NotTo(Equal(secondMatch.You))
+
+		var snapshot proto.SnapshotMessage
+		readJSON(first, &snapshot)
+		Expect(snapshot.You).To(Equal(firstMatch.You))
+		Expect(snapshot.Ships).To(HaveLen(2))
+
+		first.Close()
+		second.Close()
+		Eventually(rooms.List, 2*time.Second).Should(BeEmpty())
+	})
+
+	It("uses the default rating and cancels the ticket on disconnect", func() {
+		conn, _, err := dialMatch("")
+		Expect(err).NotTo(HaveOccurred())
+
+		var queued proto.QueuedMessage
+		readJSON(conn, &queued)
+		Expect(queued.Rating).To(Equal(matchmaking.DefaultRating))
+		Eventually(matchmaker.QueueSize).Should(Equal(1))
+
+		conn.Close()
+		Eventually(matchmaker.QueueSize, 2*time.Second).Should(Equal(0))
+	})
+
+	It("rejects invalid ratings before upgrading", func() {
+		_, resp, err := dialMatch("?rating=high")
+		Expect(err).To(HaveOccurred())
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(matchmaker.QueueSize()).To(Equal(0))
+	})
+})
@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/persistence"
+	"github.com/gorbit/orbitalrush/internal/session"
+)
+
+// DefaultCheckpointInterval is how often resumable sessions are checkpointed.
+const DefaultCheckpointInterval = 10 * time.Second
+
+// CheckpointSessions saves the checkpoint of every session of resume, connected or
+// parked, under its resume token, and deletes the checkpoints of sessions that
+// ended. Returns the number of sessions saved; failures are joined into the error.
+func CheckpointSessions(store *persistence.Store, resume *ResumeStore) (int, error) {
+	handlers := resume.handlers()
+
+	var errs []error
+	saved := 0
+	for token, handler := range handlers {
+		if err := store.Save(token, handler.Checkpoint()); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		saved++
+	}
+
+	ids, err := store.IDs()
+	if err != nil {
+		return saved, errors.Join(append(errs, err)...)
+	}
+	for _, id := range ids {
+		if _, ok := handlers[id]; !ok {
+			if err := store.Delete(id); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return saved, errors.Join(errs...)
+}
+
+// RestoreSessions recreates the sessions checkpointed in store with opts and parks
+// them in opts.Resume under their resume tokens, so that clients can resume them
+// within the grace period. Restored sessions run from the current time. Returns
+// the number of sessions restored; checkpoints that cannot be restored are
+// skipped and reported in the error.
+func RestoreSessions(store *persistence.Store, opts Options, logger logr.Logger) (int, error) {
+	if opts.Resume == nil {
+		return 0, errors.New("restoring sessions requires a resume store")
+	}
+
+	checkpoints, loadErr := store.LoadAll()
+	errs := []error{loadErr}
+
+	restored := 0
+	for token, cp := range checkpoints {
+		sess, err := session.RestoreSession(session.NewRealClock(), cp, 100) // maxQueueSize = 100
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore session %s: %w", token, err))
+			continue
+		}
+		handler := newRestoredSessionHandler(sess, opts, logger.WithValues("component", "session"))
+		handler.startRunLoop()
+		opts.Resume.park(token, handler)
+		restored++
+	}
+	return restored, errors.Join(errs...)
+}
+
+// StartCheckpointing checkpoints the sessions of resume to store every interval
+// until ctx is cancelled. Failures are logged.
+func StartCheckpointing(ctx context.Context, store *persistence.Store, resume *ResumeStore, interval time.Duration, logger logr.Logger) {
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := CheckpointSessions(store, resume); err != nil {
+					logger.Error(err, "Failed to checkpoint sessions", "dir", store.Dir())
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,148 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/persistence"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Persistence", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "dep:fs", "b:session-persistence", "r:high"), func() {
+	var (
+		testServer        *httptest.Server
+		store             *persistence.Store
+		opts              Options
+		resumableSessions *ResumeStore
+	)
+
+	BeforeEach(func() {
+		opts = DefaultOptions()
+		opts.Resume = NewResumeStore(2 * time.Second)
+		resumableSessions = opts.Resume
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ws", WebSocketHandler(opts))
+		testServer = httptest.NewServer(mux)
+
+		var err error
+		store, err = persistence.NewStore(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	dial := func(query string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+"/ws"+query, nil)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return conn
+	}
+
+	// readMessage reads the next message of type t, skipping other messages
+	readMessage := func(conn *websocket.Conn, t string, v interface{}) {
+		for {
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			_, data, err := conn.ReadMessage()
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			var header struct {
+				Type string `json:"t"`
+			}
+			ExpectWithOffset(1, json.Unmarshal(data, &header)).To(Succeed())
+			if header.Type == t {
+				ExpectWithOffset(1, json.Unmarshal(data, v)).To(Succeed())
+				return
+			}
+		}
+	}
+
+	sendInput := func(conn *websocket.Conn, seq uint32) {
+		msg, _ := json.Marshal(proto.InputMessage{Type: "input", Seq: seq, Thrust: 1.0})
+		ExpectWithOffset(1, conn.WriteMessage(websocket.TextMessage, msg)).To(Succeed())
+	}
+
+	// stopSession simulates the server going down: the session is dropped without a trace
+	stopSession := func(token string) {
+		Eventually(func() bool { return resumableSessions.isParked(token) }).Should(BeTrue())
+		handler, ok := resumableSessions.take(token)
+		Expect(ok).To(BeTrue())
+		resumableSessions.mu.Lock()
+		delete(resumableSessions.active, token)
+		resumableSessions.mu.Unlock()
+		handler.Stop()
+	}
+
+	It("checkpoints connected sessions and restores them for resume tokens", func() {
+		conn := dial("?resumable=true")
+		var sessionMsg proto.SessionMessage
+		readMessage(conn, "session", &sessionMsg)
+		for seq := uint32(1); seq <= 3; seq++ {
+			sendInput(conn, seq)
+		}
+		var before proto.SnapshotMessage
+		Eventually(func() float32 {
+			readMessage(conn, "snapshot", &before)
+			return before.Ship.Energy
+		}).Should(BeNumerically("<", 100))
+
+		// Checkpoint once all three inputs were applied
+		var cp session.Checkpoint
+		Eventually(func() map[entities.PlayerID]uint32 {
+			_, err := CheckpointSessions(store, resumableSessions)
+			Expect(err).NotTo(HaveOccurred())
+			cp, err = store.Load(sessionMsg.Token)
+			Expect(err).NotTo(HaveOccurred())
+			return cp.NextSequences
+		}).Should(HaveKeyWithValue(BeEquivalentTo("p1"), uint32(4)))
+		Expect(cp.World.Tick).To(BeNumerically(">=", before.Tick))
+
+		conn.Close()
+		stopSession(sessionMsg.Token)
+
+		restored, err := RestoreSessions(store, opts, logr.Discard())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(restored).To(BeNumerically(">=", 1))
+		Expect(resumableSessions.isParked(sessionMsg.Token)).To(BeTrue())
+
+		resumed := dial("?resume=" + sessionMsg.Token)
+		defer resumed.Close()
+		var resumedMsg proto.SessionMessage
+		readMessage(resumed, "session", &resumedMsg)
+		Expect(resumedMsg.Resumed).To(BeTrue())
+
+		var after proto.SnapshotMessage
+		readMessage(resumed, "snapshot", &after)
+		Expect(after.Tick).To(BeNumerically(">=", cp.World.Tick))
+
+		sendInput(resumed, 3)
+		var errorMsg ErrorMessage
+		readMessage(resumed, "error", &errorMsg)
+		Expect(errorMsg.Message).To(ContainSubstring("seq 3"))
+	})
+
+	It("deletes the checkpoints of sessions that ended", func() {
+		Expect(store.Save("ended", session.Checkpoint{World: NewInitialWorld()})).To(Succeed())
+
+		_, err := CheckpointSessions(store, resumableSessions)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.IDs()).NotTo(ContainElement("ended"))
+	})
+
+	It("skips checkpoints that cannot be restored", func() {
+		cp := session.Checkpoint{World: NewInitialWorld()} // Zero rules are invalid
+		Expect(store.Save("invalid", cp)).To(Succeed())
+
+		restored, err := RestoreSessions(store, opts, logr.Discard())
+		Expect(err).To(MatchError(ContainSubstring("failed to restore session invalid")))
+		Expect(restored).To(Equal(0))
+		Expect(resumableSessions.isParked("invalid")).To(BeFalse())
+	})
+})
@@ -0,0 +1,227 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/persistence"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/session"
+)
+
+// replaySnapshotInterval is how often a playing replay connection receives a snapshot
+// (10 Hz, like session connections).
+const replaySnapshotInterval = 100 * time.Millisecond
+
+// errReplayNotFound is reported for replay IDs that are not in the replay store.
+var errReplayNotFound = errors.New("replay not found")
+
+// NewReplayHandler returns the handler for the /replay/{id} endpoint, which
+// re-simulates replay id from store and streams the standard snapshots, so any
+// client that renders snapshots can watch it.
+//
+// The connection first receives a "replay" state message. Playback starts at the
+// beginning at the speed query parameter (default 1, see proto.MaxReplaySpeed),
+// paused if paused=true, and pauses at the end. "replay_control" messages play,
+// pause, change the speed or seek; each is answered with a state message.
+//
+// Unknown replays fail before the upgrade with 404, invalid IDs and query parameters with 400.
+func NewReplayHandler(store *persistence.ReplayStore) http.Handler {
+	logger := observability.NewLogger().WithValues("component", "transport", "handler", "replay")
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /replay/{id}", func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+
+		speed := 1.0
+		if value := r.URL.Query().Get("speed"); value != "" {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil || parsed < proto.MinReplaySpeed || parsed > proto.MaxReplaySpeed {
+				writeRoomError(w, http.StatusBadRequest, fmt.Errorf("invalid speed: %q", value))
+				return
+			}
+			speed = parsed
+		}
+		paused := false
+		if value := r.URL.Query().Get("paused"); value != "" {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				writeRoomError(w, http.StatusBadRequest, fmt.Errorf("invalid paused: %q", value))
+				return
+			}
+			paused = parsed
+		}
+
+		replay, err := store.Load(id)
+		if errors.Is(err, persistence.ErrInvalidReplayID) {
+			writeRoomError(w, http.StatusBadRequest, persistence.ErrInvalidReplayID)
+			return
+		}
+		if errors.Is(err, os.ErrNotExist) {
+			writeRoomError(w, http.StatusNotFound, errReplayNotFound)
+			return
+		}
+		var player *session.ReplayPlayer
+		if err == nil {
+			player, err = session.NewReplayPlayer(replay, session.DefaultKeyframeInterval)
+		}
+		if err != nil {
+			logger.Error(err, "Failed to load replay", "replay_id", id, "message_type", "replay_error")
+			writeRoomError(w, http.StatusInternalServerError, errors.New("failed to load replay"))
+			return
+		}
+
+		connLogger := newConnectionLogger(r).WithValues("replay_id", id)
+		serveWebSocket(w, r, connLogger, func(wsConn *Connection, _ logr.Logger) {
+			msgs, stopReading := readInBackground(wsConn)
+			defer stopReading()
+
+			stream := &replayStream{
+				id:     id,
+				conn:   wsConn,
+				player: player,
+				speed:  speed,
+				paused: paused,
+				// Replay ticks advanced per snapshot at speed 1
+				ticksPerSnapshot: replaySnapshotInterval.Seconds() / replay.Dt,
+				logger:           connLogger,
+			}
+			stream.run(msgs)
+		})
+	})
+
+	return mux
+}
+
+// replayStream plays a replay to one connection. It is only used by the goroutine running it.
+type replayStream struct {
+	id               string
+	conn             *Connection
+	player           *session.ReplayPlayer
+	speed            float64
+	paused           bool
+	ticksPerSnapshot float64
+	pending          float64 // Fraction of a tick carried over to the next snapshot
+	logger           logr.Logger
+}
+
+// run streams the replay until msgs is closed or a write fails.
+func (s *replayStream) run(msgs <-chan []byte) {
+	ticker := time.NewTicker(replaySnapshotInterval)
+	defer ticker.Stop()
+
+	if s.writeState() != nil || s.writeSnapshot() != nil {
+		return
+	}
+
+	for {
+		select {
+		case data, ok := <-msgs:
+			if !ok {
+				return
+			}
+			if err := s.handle(data); err != nil {
+				return
+			}
+
+		case <-ticker.C:
+			if s.paused || s.player.Done() {
+				continue
+			}
+			if err := s.advance(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// advance plays the ticks due for one snapshot and writes the snapshot.
+// Reaching the end pauses playback and writes the state.
+func (s *replayStream) advance() error {
+	s.pending += s.speed * s.ticksPerSnapshot
+	for ; s.pending >= 1 && !s.player.Done(); s.pending-- {
+		if err := s.player.Step(); err != nil {
+			s.logger.Error(err, "Failed to play replay", "message_type", "replay_error")
+			s.conn.Send(newErrorMessage(err))
+			return err
+		}
+	}
+	if err := s.writeSnapshot(); err != nil {
+		return err
+	}
+
+	if s.player.Done() {
+		s.paused = true
+		s.pending = 0
+		return s.writeState()
+	}
+	return nil
+}
+
+// handle applies a control message and writes the new state. Invalid messages are
+// answered with an error message. Returns an error if a write fails.
+func (s *replayStream) handle(data []byte) error {
+	msg, err := s.conn.Encoding().Parse(data)
+	if err == nil {
+		control, ok := msg.(*proto.ReplayControlMessage)
+		if !ok {
+			err = fmt.Errorf("unexpected message type: %T", msg)
+		} else {
+			err = s.apply(control)
+		}
+	}
+	if err != nil {
+		s.logger.Error(err, "Failed to handle replay control", "message_type", "route_error")
+		return s.conn.Send(newErrorMessage(err))
+	}
+	return s.writeState()
+}
+
+// apply applies a validated control message. Seeking writes the snapshot of the new tick.
+func (s *replayStream) apply(msg *proto.ReplayControlMessage) error {
+	switch msg.Action {
+	case "play":
+		if s.player.Done() {
+			// Play again from the start
+			if err := s.player.Seek(s.player.StartTick()); err != nil {
+				return err
+			}
+		}
+		s.paused = false
+	case "pause":
+		s.paused = true
+	case "speed":
+		s.speed = msg.Speed
+	case "seek":
+		if err := s.player.Seek(msg.Tick); err != nil {
+			return err
+		}
+		s.pending = 0
+		return s.writeSnapshot()
+	}
+	return nil
+}
+
+// writeState writes the playback state.
+func (s *replayStream) writeState() error {
+	return s.conn.Send(proto.ReplayStateMessage{
+		Type:      "replay",
+		ID:        s.id,
+		Tick:      s.player.Tick(),
+		StartTick: s.player.StartTick(),
+		EndTick:   s.player.EndTick(),
+		Paused:    s.paused,
+		Speed:     s.speed,
+	})
+}
+
+// writeSnapshot writes the snapshot of the current tick, addressed to no player.
+func (s *replayStream) writeSnapshot() error {
+	return s.conn.Send(WorldToSnapshot(s.player.World()))
+}
@@ -0,0 +1,172 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/persistence"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replay Playback", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "dep:fs", "b:replay-playback", "r:medium"), func() {
+	const endTick = 90
+
+	var (
+		testServer *httptest.Server
+		replay     session.Replay
+	)
+
+	// worldAt plays the replay up to tick
+	worldAt := func(tick uint32) entities.World {
+		player, err := session.NewReplayPlayer(replay, 0)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		ExpectWithOffset(1, player.Seek(tick)).To(Succeed())
+		return player.World()
+	}
+
+	BeforeEach(func() {
+		clock := session.NewFakeClock()
+		sess := session.NewSession(clock, NewInitialWorld(), 100)
+		sess.StartRecording("standard", 0)
+		for seq := uint32(1); seq <= 60; seq++ {
+			sess.EnqueueCommand(seq, rules.InputCommand{Thrust: 0.3, Turn: 0.5})
+		}
+		for i := 0; i < endTick; i++ {
+			clock.Advance(session.DefaultTickInterval)
+			Expect(sess.Run(1)).To(Succeed())
+		}
+		replay, _ = sess.StopRecording()
+
+		store, err := persistence.NewReplayStore(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(store.Save("abc123", replay)).To(Succeed())
+
+		mux := http.NewServeMux()
+		mux.Handle("/replay/", NewReplayHandler(store))
+		testServer = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+	})
+
+	dial := func(path string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+path, nil)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return conn
+	}
+
+	// readMessage reads the next message of type t, skipping other messages
+	readMessage := func(conn *websocket.Conn, t string, v interface{}) {
+		for {
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			_, data, err := conn.ReadMessage()
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			var header struct {
+				Type string `json:"t"`
+			}
+			ExpectWithOffset(1, json.Unmarshal(data, &header)).To(Succeed())
+			if header.Type == t {
+				ExpectWithOffset(1, json.Unmarshal(data, v)).To(Succeed())
+				return
+			}
+		}
+	}
+
+	sendControl := func(conn *websocket.Conn, msg proto.ReplayControlMessage) {
+		msg.Type = "replay_control"
+		data, _ := json.Marshal(msg)
+		ExpectWithOffset(1, conn.WriteMessage(websocket.TextMessage, data)).To(Succeed())
+	}
+
+	It("rejects unknown replays and invalid parameters before upgrading", func() {
+		resp, err := http.Get(testServer.URL + "/replay/missing")
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+		resp, err = http.Get(testServer.URL + "/replay/a.b")
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+		resp, err = http.Get(testServer.URL + "/replay/abc123?speed=100")
+		Expect(err).NotTo(HaveOccurred())
+		resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+
+	It("streams snapshots of the replay and pauses at the end", func() {
+		conn := dial("/replay/abc123?speed=16")
+		defer conn.Close()
+
+		var state proto.ReplayStateMessage
+		readMessage(conn, "replay", &state)
+		Expect(proto.ValidateReplayStateMessage(&state)).To(Succeed())
+		Expect(state).To(Equal(proto.ReplayStateMessage{Type: "replay", ID: "abc123", Tick: 0, EndTick: endTick, Speed: 16}))
+
+		var snapshot proto.SnapshotMessage
+		readMessage(conn, "snapshot", &snapshot)
+		Expect(snapshot).To(Equal(WorldToSnapshot(worldAt(0))))
+
+		readMessage(conn, "replay", &state)
+		Expect(state.Paused).To(BeTrue())
+		Expect(state.Tick).To(Equal(uint32(endTick)))
+	})
+
+	It("seeks, changes speed and pauses on control messages", func() {
+		conn := dial("/replay/abc123?paused=true")
+		defer conn.Close()
+
+		var state proto.ReplayStateMessage
+		readMessage(conn, "replay", &state)
+		Expect(state.Paused).To(BeTrue())
+		var snapshot proto.SnapshotMessage
+		readMessage(conn, "snapshot", &snapshot)
+		Expect(snapshot.Tick).To(Equal(uint32(0)))
+
+		sendControl(conn, proto.ReplayControlMessage{Action: "seek", Tick: 45})
+		readMessage(conn, "snapshot", &snapshot)
+		Expect(snapshot.Tick).To(Equal(uint32(45)))
+		Expect(snapshot).To(Equal(WorldToSnapshot(worldAt(45))))
+		readMessage(conn, "replay", &state)
+		Expect(state.Tick).To(Equal(uint32(45)))
+
+		sendControl(conn, proto.ReplayControlMessage{Action: "speed", Speed: 4})
+		readMessage(conn, "replay", &state)
+		Expect(state.Speed).To(Equal(4.0))
+
+		sendControl(conn, proto.ReplayControlMessage{Action: "play"})
+		readMessage(conn, "replay", &state)
+		Expect(state.Paused).To(BeFalse())
+		readMessage(conn, "snapshot", &snapshot)
+		Expect(snapshot.Tick).To(BeNumerically(">", 45))
+
+		sendControl(conn, proto.ReplayControlMessage{Action: "pause"})
+		readMessage(conn, "replay", &state)
+		Expect(state.Paused).To(BeTrue())
+	})
+
+	It("answers invalid messages with an error", func() {
+		conn := dial("/replay/abc123?paused=true")
+		defer conn.Close()
+
+		sendControl(conn, proto.ReplayControlMessage{Action: "speed", Speed: 100})
+		var errorMsg ErrorMessage
+		readMessage(conn, "error", &errorMsg)
+		Expect(errorMsg.Message).To(ContainSubstring("invalid speed"))
+
+		input, _ := json.Marshal(proto.InputMessage{Type: "input", Seq: 1, Thrust: 1.0})
+		Expect(conn.WriteMessage(websocket.TextMessage, input)).To(Succeed())
+		readMessage(conn, "error", &errorMsg)
+		Expect(errorMsg.Message).To(ContainSubstring("unexpected message type"))
+	})
+})
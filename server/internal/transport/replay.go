@@ -0,0 +1,45 @@
+package transport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/persistence"
+	"github.com/gorbit/orbitalrush/internal/room"
+	"github.com/gorbit/orbitalrush/internal/session"
+)
+
+// RoomReplaySink returns the room.ReplaySink that saves the replays of closed
+// rooms to store under new replay IDs. Failures are logged to logger.
+func RoomReplaySink(store *persistence.ReplayStore, logger logr.Logger) room.ReplaySink {
+	return func(roomID string, replay session.Replay) {
+		saveReplay(store, logger, replay, "room_id", roomID)
+	}
+}
+
+// saveReplay saves replay to store under a new replay ID and logs the ID with keysAndValues.
+func saveReplay(store *persistence.ReplayStore, logger logr.Logger, replay session.Replay, keysAndValues ...interface{}) {
+	id, err := newReplayID()
+	if err == nil {
+		err = store.Save(id, replay)
+	}
+	if err != nil {
+		if logger.Enabled() {
+			logger.Error(err, "Failed to save replay", keysAndValues...)
+		}
+		return
+	}
+	if logger.Enabled() {
+		logger.Info("Replay saved", append([]interface{}{"replay_id", id, "end_tick", replay.EndTick}, keysAndValues...)...)
+	}
+}
+
+// newReplayID returns a random 64-bit replay ID.
+func newReplayID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
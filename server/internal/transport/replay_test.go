@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/persistence"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/session"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replay Recording", Label("scope:unit", "loop:g5-adapter", "layer:server", "double:fake-io", "dep:fs", "b:replay-recording", "r:medium"), func() {
+	var (
+		clock *session.FakeClock
+		store *persistence.ReplayStore
+	)
+
+	BeforeEach(func() {
+		clock = session.NewFakeClock()
+		var err error
+		store, err = persistence.NewReplayStore(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("saves the replay of a private session when it stops", func() {
+		opts := DefaultOptions()
+		opts.Replays = store
+		handler := NewSessionHandler(nil, clock, NewInitialWorld(), opts, logr.Discard())
+		for seq := uint32(1); seq <= 5; seq++ {
+			Expect(handler.HandleInput(&proto.InputMessage{Type: "input", Seq: seq, Thrust: 1.0, Turn: -1.0})).To(Succeed())
+		}
+		clock.Advance(10 * session.DefaultTickInterval)
+		Expect(handler.session.Run(10)).To(Succeed())
+		world := handler.session.GetWorld()
+
+		handler.Stop()
+		ids, err := store.IDs()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ids).To(HaveLen(1))
+
+		replay, err := store.Load(ids[0])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replay.Level).To(Equal("standard"))
+		Expect(replay.Play()).To(Equal(world))
+	})
+
+	It("saves room replays through RoomReplaySink", func() {
+		sess := session.NewSession(clock, NewInitialWorld(), 10)
+		sess.StartRecording("standard", 0)
+		replay, _ := sess.StopRecording()
+
+		RoomReplaySink(store, logr.Discard())("room1", replay)
+		Expect(store.IDs()).To(HaveLen(1))
+	})
+
+	It("does not record without a replay store", func() {
+		handler := NewSessionHandler(nil, clock, NewInitialWorld(), DefaultOptions(), logr.Discard())
+		_, ok := handler.session.Recording()
+		Expect(ok).To(BeFalse())
+		handler.Stop()
+	})
+})
@@ -0,0 +1,150 @@
+package transport
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// DefaultResumeGrace is how long a private session outlives its connection by default.
+const DefaultResumeGrace = 30 * time.Second
+
+// ResumeStore tracks the handlers of resumable private sessions by resume token.
+// Handlers of connected clients are active; detached handlers are parked until
+// they are resumed or their grace period ends, when they are stopped.
+//
+// ResumeStore is safe for concurrent use.
+type ResumeStore struct {
+	mu     sync.Mutex
+	grace  time.Duration
+	active map[string]*SessionHandler
+	parked map[string]*parkedSession
+}
+
+// parkedSession is a detached session handler waiting for a reconnect.
+type parkedSession struct {
+	handler *SessionHandler
+	timer   *time.Timer // Stops the handler when the grace period ends
+}
+
+// NewResumeStore creates a store that keeps the sessions of dropped connections for
+// grace. A grace of 0 stops sessions on disconnect.
+func NewResumeStore(grace time.Duration) *ResumeStore {
+	return &ResumeStore{
+		grace:  grace,
+		active: make(map[string]*SessionHandler),
+		parked: make(map[string]*parkedSession),
+	}
+}
+
+// register records handler as the active handler of token.
+func (s *ResumeStore) register(token string, handler *SessionHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.active[token] = handler
+}
+
+// park keeps the detached handler under token for the grace period.
+// The handler is stopped if it is not taken back in time.
+func (s *ResumeStore) park(token string, handler *SessionHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.active, token)
+	if s.grace <= 0 {
+		handler.Stop()
+		return
+	}
+
+	if previous, ok := s.parked[token]; ok && previous.handler != handler {
+		// Replaced, e.g. by a restored checkpoint; its timer no longer matches
+		previous.timer.Stop()
+		previous.handler.Stop()
+	}
+
+	entry := &parkedSession{handler: handler}
+	entry.timer = time.AfterFunc(s.grace, func() {
+		s.expire(token, entry)
+	})
+	s.parked[token] = entry
+}
+
+// take removes the handler parked under token, makes it active and returns it.
+// Returns false if no handler is parked under token, e.g. because its grace period ended.
+func (s *ResumeStore) take(token string) (*SessionHandler, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.parked[token]
+	if !ok {
+		return nil, false
+	}
+	delete(s.parked, token)
+	entry.timer.Stop()
+	s.active[token] = entry.handler
+	return entry.handler, true
+}
+
+// expire stops the handler of entry unless it has been taken back meanwhile.
+func (s *ResumeStore) expire(token string, entry *parkedSession) {
+	s.mu.Lock()
+	if s.parked[token] != entry {
+		// Resumed before the timer fired
+		s.mu.Unlock()
+		return
+	}
+	delete(s.parked, token)
+	s.mu.Unlock()
+
+	entry.handler.Stop()
+}
+
+// drain stops the parked handlers and makes the store stop handlers parked later
+// right away, as with a grace of 0. Active handlers stop once their client disconnects.
+func (s *ResumeStore) drain() {
+	s.mu.Lock()
+	s.grace = 0
+	parked := s.parked
+	s.parked = make(map[string]*parkedSession)
+	s.mu.Unlock()
+
+	for _, entry := range parked {
+		entry.timer.Stop()
+		entry.handler.Stop()
+	}
+}
+
+// isParked reports whether a session is parked under token.
+func (s *ResumeStore) isParked(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.parked[token]
+	return ok
+}
+
+// handlers returns the active and parked handlers by token.
+func (s *ResumeStore) handlers() map[string]*SessionHandler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	handlers := make(map[string]*SessionHandler, len(s.active)+len(s.parked))
+	for token, handler := range s.active {
+		handlers[token] = handler
+	}
+	for token, entry := range s.parked {
+		handlers[token] = entry.handler
+	}
+	return handlers
+}
+
+// newResumeToken returns a random 128-bit resume token.
+func newResumeToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,194 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Session Resume", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "b:session-resume", "r:high"), func() {
+	const grace = 500 * time.Millisecond
+
+	var (
+		testServer        *httptest.Server
+		resumableSessions *ResumeStore
+	)
+
+	// serve starts the test server with sessions resumable for resumeGrace
+	serve := func(resumeGrace time.Duration) {
+		opts := DefaultOptions()
+		opts.Resume = NewResumeStore(resumeGrace)
+		resumableSessions = opts.Resume
+		mux := http.NewServeMux()
+		mux.HandleFunc("/ws", WebSocketHandler(opts))
+		testServer = httptest.NewServer(mux)
+	}
+
+	BeforeEach(func() {
+		serve(grace)
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+		resumableSessions.drain()
+	})
+
+	dial := func(query string) *websocket.Conn {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+"/ws"+query, nil)
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		return conn
+	}
+
+	// readMessage reads the next message of type t, skipping snapshots
+	readMessage := func(conn *websocket.Conn, t string, v interface{}) {
+		for {
+			conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			_, data, err := conn.ReadMessage()
+			ExpectWithOffset(1, err).NotTo(HaveOccurred())
+			var header struct {
+				Type string `json:"t"`
+			}
+			ExpectWithOffset(1, json.Unmarshal(data, &header)).To(Succeed())
+			if header.Type == t {
+				ExpectWithOffset(1, json.Unmarshal(data, v)).To(Succeed())
+				return
+			}
+		}
+	}
+
+	isParked := func(token string) func() bool {
+		return func() bool { return resumableSessions.isParked(token) }
+	}
+
+	sendInput := func(conn *websocket.Conn, seq uint32) {
+		msg, _ := json.Marshal(proto.InputMessage{Type: "input", Seq: seq, Thrust: 1.0})
+		ExpectWithOffset(1, conn.WriteMessage(websocket.TextMessage, msg)).To(Succeed())
+	}
+
+	It("hands out a resume token on every connection", func() {
+		conn := dial("")
+		defer conn.Close()
+
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var sessionMsg proto.SessionMessage
+		Expect(conn.ReadJSON(&sessionMsg)).To(Succeed(), "the session message comes first")
+		Expect(proto.ValidateSessionMessage(&sessionMsg)).To(Succeed())
+		Expect(sessionMsg.Resumed).To(BeFalse())
+
+		other := dial("")
+		defer other.Close()
+		var otherMsg proto.SessionMessage
+		readMessage(other, "session", &otherMsg)
+		Expect(otherMsg.Token).NotTo(Equal(sessionMsg.Token))
+	})
+
+	It("hands out no resume token without a resume store", func() {
+		opts := DefaultOptions()
+		opts.Resume = nil
+		plainServer := httptest.NewServer(WebSocketHandler(opts))
+		defer plainServer.Close()
+
+		plain, _, err := websocket.DefaultDialer.Dial("ws"+plainServer.URL[4:], nil)
+		Expect(err).NotTo(HaveOccurred())
+		defer plain.Close()
+		plain.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var first map[string]interface{}
+		Expect(plain.ReadJSON(&first)).To(Succeed())
+		Expect(first["t"]).To(Equal("snapshot"))
+	})
+
+	It("reattaches a reconnecting client to its session with its sequence state", func() {
+		conn := dial("")
+		var sessionMsg proto.SessionMessage
+		readMessage(conn, "session", &sessionMsg)
+
+		for seq := uint32(1); seq <= 3; seq++ {
+			sendInput(conn, seq)
+		}
+		var before proto.SnapshotMessage
+		Eventually(func() float32 {
+			readMessage(conn, "snapshot", &before)
+			return before.Ship.Energy
+		}).Should(BeNumerically("<", 100))
+		conn.Close()
+		Eventually(isParked(sessionMsg.Token)).Should(BeTrue())
+
+		resumed := dial("?resume=" + sessionMsg.Token)
+		defer resumed.Close()
+		var resumedMsg proto.SessionMessage
+		readMessage(resumed, "session", &resumedMsg)
+		Expect(resumedMsg.Resumed).To(BeTrue())
+		Expect(resumedMsg.Token).To(Equal(sessionMsg.Token))
+		Expect(resumableSessions.isParked(sessionMsg.Token)).To(BeFalse())
+
+		var after proto.SnapshotMessage
+		readMessage(resumed, "snapshot", &after)
+		Expect(after.Tick).To(BeNumerically(">", before.Tick))
+		Expect(after.Ship.Energy).To(BeNumerically("<=", before.Ship.Energy))
+
+		// Sequence numbers already processed are still rejected
+		sendInput(resumed, 3)
+		var errorMsg ErrorMessage
+		readMessage(resumed, "error", &errorMsg)
+		Expect(errorMsg.Message).To(ContainSubstring("seq 3"))
+	})
+
+	It("stops the session when the grace period ends", func() {
+		conn := dial("")
+		var sessionMsg proto.SessionMessage
+		readMessage(conn, "session", &sessionMsg)
+		conn.Close()
+
+		Eventually(isParked(sessionMsg.Token)).Should(BeTrue())
+		Eventually(isParked(sessionMsg.Token), 2*grace).Should(BeFalse())
+
+		fresh := dial("?resume=" + sessionMsg.Token)
+		defer fresh.Close()
+		var freshMsg proto.SessionMessage
+		readMessage(fresh, "session", &freshMsg)
+		Expect(freshMsg.Resumed).To(BeFalse())
+		Expect(freshMsg.Token).NotTo(Equal(sessionMsg.Token))
+	})
+
+	It("does not attach a second connection to a connected session", func() {
+		conn := dial("")
+		defer conn.Close()
+		var sessionMsg proto.SessionMessage
+		readMessage(conn, "session", &sessionMsg)
+
+		other := dial("?resume=" + sessionMsg.Token)
+		defer other.Close()
+		var otherMsg proto.SessionMessage
+		readMessage(other, "session", &otherMsg)
+		Expect(otherMsg.Resumed).To(BeFalse())
+		Expect(otherMsg.Token).NotTo(Equal(sessionMsg.Token))
+	})
+
+	It("stops sessions on disconnect when the grace period is 0", func() {
+		testServer.Close()
+		serve(0)
+		conn := dial("")
+		var sessionMsg proto.SessionMessage
+		readMessage(conn, "session", &sessionMsg)
+		conn.Close()
+
+		Consistently(isParked(sessionMsg.Token), 200*time.Millisecond).Should(BeFalse())
+	})
+})
+
+// skipSessionMessage reads the "session" message a resumable private session
+// starts with, before its snapshots.
+func skipSessionMessage(conn *websocket.Conn) {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	messageType, data, err := conn.ReadMessage()
+	ExpectWithOffset(1, err).NotTo(HaveOccurred())
+	if messageType == websocket.TextMessage {
+		ExpectWithOffset(1, string(data)).To(ContainSubstring(`"t":"session"`))
+	}
+}
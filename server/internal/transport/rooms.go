@@ -0,0 +1,97 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/room"
+)
+
+// maxRoomRequestBytes limits the size of room creation requests.
+const maxRoomRequestBytes = 64 * 1024
+
+// RoomListResponse is the response body of GET /api/rooms.
+type RoomListResponse struct {
+	Rooms []room.Info `json:"rooms"`
+}
+
+// RoomErrorResponse is the response body of a failed rooms API request.
+type RoomErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewRoomsHandler returns the handler for the rooms API under /api/rooms:
+//   - GET /api/rooms lists open rooms with their player counts
+//   - POST /api/rooms creates a room from a JSON room.Config and returns its room.Info
+//   - GET /api/rooms/{id} returns the room.Info of one room
+//
+// Fields missing from a creation request keep their room.DefaultConfig values,
+// including individual rule parameters. Players join rooms over /ws?room=<id>
+// (see NewWebSocketHandler).
+func NewRoomsHandler(rooms *room.Manager) http.Handler {
+	logger := observability.NewLogger().WithValues("component", "transport", "handler", "rooms")
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/rooms", func(w http.ResponseWriter, r *http.Request) {
+		writeRoomJSON(w, http.StatusOK, RoomListResponse{Rooms: rooms.List()})
+	})
+
+	mux.HandleFunc("POST /api/rooms", func(w http.ResponseWriter, r *http.Request) {
+		cfg := room.DefaultConfig()
+		decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRoomRequestBytes))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			writeRoomError(w, http.StatusBadRequest, fmt.Errorf("invalid room config: %w", err))
+			return
+		}
+
+		rm, err := rooms.Create(cfg)
+		if err != nil {
+			logger.Error(err, "Failed to create room", "message_type", "create_error")
+			writeRoomError(w, roomErrorStatus(err), err)
+			return
+		}
+		writeRoomJSON(w, http.StatusCreated, rm.Info())
+	})
+
+	mux.HandleFunc("GET /api/rooms/{id}", func(w http.ResponseWriter, r *http.Request) {
+		rm, ok := rooms.Get(r.PathValue("id"))
+		if !ok {
+			writeRoomError(w, http.StatusNotFound, room.ErrRoomNotFound)
+			return
+		}
+		writeRoomJSON(w, http.StatusOK, rm.Info())
+	})
+
+	return mux
+}
+
+// roomErrorStatus maps room manager errors to HTTP status codes.
+// Errors other than the room sentinel errors are invalid requests.
+func roomErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, room.ErrRoomNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, room.ErrRoomFull):
+		return http.StatusConflict
+	case errors.Is(err, room.ErrTooManyRooms):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// writeRoomJSON writes body as a JSON response with the given status code.
+func writeRoomJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeRoomError writes err as a JSON error response with the given status code.
+func writeRoomError(w http.ResponseWriter, status int, err error) {
+	writeRoomJSON(w, status, RoomErrorResponse{Error: err.Error()})
+}
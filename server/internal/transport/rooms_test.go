@@ -0,0 +1,297 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/room"
+	"github.com/gorbit/orbitalrush/internal/session"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rooms API", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "b:rooms", "r:high"), func() {
+	var (
+		rooms             *room.Manager
+		testServer        *httptest.Server
+		resumableSessions *ResumeStore
+	)
+
+	BeforeEach(func() {
+		rooms = room.NewManager(session.NewRealClock(), 2, session.DefaultOptions(), logr.Discard())
+
+		mux := http.NewServeMux()
+		opts := DefaultOptions()
+		resumableSessions = opts.Resume
+		mux.HandleFunc("/ws", NewWebSocketHandler(rooms, opts))
+		roomsHandler := NewRoomsHandler(rooms)
+		mux.Handle("/api/rooms", roomsHandler)
+		mux.Handle("/api/rooms/", roomsHandler)
+		testServer = httptest.NewServer(mux)
+	})
+
+	AfterEach(func() {
+		testServer.Close()
+		resumableSessions.drain()
+		for _, info := range rooms.List() {
+			rooms.Close(info.ID)
+		}
+	})
+
+	createRoom := func(body string) (*http.Response, room.Info) {
+		resp, err := http.Post(testServer.URL+"/api/rooms", "application/json", strings.NewReader(body))
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		var info room.Info
+		_ = json.NewDecoder(resp.Body).Decode(&info)
+		return resp, info
+	}
+
+	listRooms := func() RoomListResponse {
+		resp, err := http.Get(testServer.URL + "/api/rooms")
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		ExpectWithOffset(1, resp.StatusCode).To(Equal(http.StatusOK))
+		var list RoomListResponse
+		ExpectWithOffset(1, json.NewDecoder(resp.Body).Decode(&list)).To(Succeed())
+		return list
+	}
+
+	dialRoom := func(id string) (*websocket.Conn, *http.Response, error) {
+		return websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+"/ws?room="+id, nil)
+	}
+
+	readSnapshot := func(conn *websocket.Conn) proto.SnapshotMessage {
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		_, data, err := conn.ReadMessage()
+		ExpectWithOffset(1, err).NotTo(HaveOccurred())
+		var snapshot proto.SnapshotMessage
+		ExpectWithOffset(1, json.Unmarshal(data, &snapshot)).To(Succeed())
+		return snapshot
+	}
+
+	Describe("POST /api/rooms", func() {
+		It("creates a room with defaults for missing fields", func() {
+			resp, info := createRoom(`{"name":"lobby","level":"seeded","seed":3,"rules":{"ThrustDrainRate":2}}`)
+
+			Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+			Expect(info.ID).NotTo(BeEmpty())
+			Expect(info.Name).To(Equal("lobby"))
+			Expect(info.Level).To(Equal("seeded"))
+			Expect(info.MaxPlayers).To(Equal(room.DefaultMaxPlayers))
+
+			created, ok := rooms.Get(info.ID)
+			Expect(ok).To(BeTrue())
+			Expect(created.Config().Rules.ThrustDrainRate).To(Equal(float32(2)))
+			Expect(created.Config().Rules.MaxEnergy).To(Equal(room.DefaultConfig().Rules.MaxEnergy))
+		})
+
+		It("rejects malformed and invalid configs", func() {
+			resp, _ := createRoom(`{"name":`)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+			resp, _ = createRoom(`{"colour":"red"}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+			resp, _ = createRoom(`{"max_players":99}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("returns 503 when the room limit is reached", func() {
+			createRoom(`{}`)
+			createRoom(`{}`)
+			resp, _ := createRoom(`{}`)
+			Expect(resp.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		})
+	})
+
+	Describe("GET /api/rooms", func() {
+		It("lists open rooms", func() {
+			Expect(listRooms().Rooms).To(BeEmpty())
+
+			_, info := createRoom(`{"name":"lobby"}`)
+
+			list := listRooms()
+			Expect(list.Rooms).To(HaveLen(1))
+			Expect(list.Rooms[0].ID).To(Equal(info.ID))
+			Expect(list.Rooms[0].Players).To(Equal(0))
+		})
+	})
+
+	Describe("GET /api/rooms/{id}", func() {
+		It("returns the room or 404", func() {
+			_, info := createRoom(`{}`)
+
+			resp, err := http.Get(testServer.URL + "/api/rooms/" + info.ID)
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			resp, err = http.Get(testServer.URL + "/api/rooms/missing")
+			Expect(err).NotTo(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("/ws?room=", func() {
+		It("lets two players share a room and closes it when both leave", func() {
+			_, info := createRoom(`{"max_players":2}`)
+
+			first, _, err := dialRoom(info.ID)
+			Expect(err).NotTo(HaveOccurred())
+			second, _, err := dialRoom(info.ID)
+			Expect(err).NotTo(HaveOccurred())
+
+			Eventually(func() int { return listRooms().Rooms[0].Players }).Should(Equal(2))
+
+			snapshot := readSnapshot(first)
+			Expect(snapshot.You).To(Equal("p1"))
+			Expect(snapshot.Ship.ID).To(Equal("p1"))
+			Expect(snapshot.Ships).To(HaveLen(2))
+			Expect(readSnapshot(second).You).To(Equal("p2"))
+
+			first.Close()
+			second.Close()
+			Eventually(func() []room.Info { return listRooms().Rooms }, 2*time.Second).Should(BeEmpty())
+		})
+
+		It("rejects unknown and full rooms before upgrading", func() {
+			_, resp, err := dialRoom("missing")
+			Expect(err).To(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+			_, info := createRoom(`{"max_players":1}`)
+			conn, _, err := dialRoom(info.ID)
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+
+			_, resp, err = dialRoom(info.ID)
+			Expect(err).To(HaveOccurred())
+			Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+		})
+
+		It("lets only the room's host control the session", func() {
+			_, info := createRoom(`{"max_players":2}`)
+			host, _, err := dialRoom(info.ID)
+			Expect(err).NotTo(HaveOccurred())
+			defer host.Close()
+			Eventually(func() int { return listRooms().Rooms[0].Players }).Should(Equal(1))
+			guest, _, err := dialRoom(info.ID)
+			Expect(err).NotTo(HaveOccurred())
+			defer guest.Close()
+
+			Expect(guest.WriteMessage(websocket.TextMessage, []byte(`{"t":"session_control","action":"pause"}`))).To(Succeed())
+			Eventually(func() string {
+				guest.SetReadDeadline(time.Now().Add(2 * time.Second))
+				_, data, err := guest.ReadMessage()
+				Expect(err).NotTo(HaveOccurred())
+				var errorMsg ErrorMessage
+				_ = json.Unmarshal(data, &errorMsg)
+				return errorMsg.Message
+			}).Should(Equal(ErrNotRoomHost.Error()))
+			rm, _ := rooms.Get(info.ID)
+			Expect(rm.Session().Paused()).To(BeFalse())
+
+			Expect(host.WriteMessage(websocket.TextMessage, []byte(`{"t":"session_control","action":"pause"}`))).To(Succeed())
+			Eventually(func() bool { return readSnapshot(guest).Paused }).Should(BeTrue())
+		})
+
+		Describe("spectate", func() {
+			dialSpectator := func(id string) (*websocket.Conn, *http.Response, error) {
+				return websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+"/ws?room="+id+"&spectate=true", nil)
+			}
+
+			It("streams snapshots without joining the room", func() {
+				_, info := createRoom(`{"max_players":1}`)
+				player, _, err := dialRoom(info.ID)
+				Expect(err).NotTo(HaveOccurred())
+				defer player.Close()
+
+				spectator, _, err := dialSpectator(info.ID)
+				Expect(err).NotTo(HaveOccurred())
+				defer spectator.Close()
+
+				Eventually(func() int { return listRooms().Rooms[0].Spectators }).Should(Equal(1))
+				Expect(listRooms().Rooms[0].Players).To(Equal(1))
+
+				snapshot := readSnapshot(spectator)
+				Expect(snapshot.You).To(BeEmpty())
+				Expect(snapshot.Ships).To(HaveLen(1))
+				Expect(snapshot.Ships[0].ID).To(Equal("p1"))
+			})
+
+			It("rejects input and restart messages", func() {
+				_, info := createRoom(`{}`)
+				spectator, _, err := dialSpectator(info.ID)
+				Expect(err).NotTo(HaveOccurred())
+				defer spectator.Close()
+
+				for _, msg := range []string{`{"t":"input","seq":1,"thrust":1,"turn":0}`, `{"t":"restart"}`} {
+					Expect(spectator.WriteMessage(websocket.TextMessage, []byte(msg))).To(Succeed())
+					Eventually(func() string {
+						spectator.SetReadDeadline(time.Now().Add(2 * time.Second))
+						_, data, err := spectator.ReadMessage()
+						Expect(err).NotTo(HaveOccurred())
+						var errorMsg ErrorMessage
+						_ = json.Unmarshal(data, &errorMsg)
+						return errorMsg.Message
+					}).Should(Equal(ErrSpectatorReadOnly.Error()))
+				}
+			})
+
+			It("delays snapshots by the room's spectator delay", func() {
+				_, info := createRoom(`{"spectator_delay_ms":500}`)
+				start := time.Now()
+				spectator, _, err := dialSpectator(info.ID)
+				Expect(err).NotTo(HaveOccurred())
+				defer spectator.Close()
+
+				readSnapshot(spectator)
+				Expect(time.Since(start)).To(BeNumerically(">=", 500*time.Millisecond))
+			})
+
+			It("disconnects spectators when the room closes", func() {
+				_, info := createRoom(`{}`)
+				spectator, _, err := dialSpectator(info.ID)
+				Expect(err).NotTo(HaveOccurred())
+				defer spectator.Close()
+				readSnapshot(spectator)
+
+				rooms.Close(info.ID)
+				Eventually(func() error {
+					spectator.SetReadDeadline(time.Now().Add(2 * time.Second))
+					_, _, err := spectator.ReadMessage()
+					return err
+				}, 2*time.Second).Should(HaveOccurred())
+			})
+
+			It("requires an existing room", func() {
+				_, resp, err := dialSpectator("missing")
+				Expect(err).To(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+				_, resp, err = websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+"/ws?spectate=true", nil)
+				Expect(err).To(HaveOccurred())
+				Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+			})
+		})
+
+		It("keeps serving private sessions without a room", func() {
+			conn, _, err := websocket.DefaultDialer.Dial("ws"+testServer.URL[4:]+"/ws", nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer conn.Close()
+			skipSessionMessage(conn)
+
+			snapshot := readSnapshot(conn)
+			Expect(snapshot.You).To(Equal("p1"))
+			Expect(listRooms().Rooms).To(BeEmpty())
+		})
+	})
+})
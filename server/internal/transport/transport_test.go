@@ -20,11 +20,14 @@ func TestTransport(t *testing.T) {
 var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "b:transport-e2e", "r:high"), func() {
 	var testServer *httptest.Server
 	var serverURL string
+	var resumableSessions *ResumeStore
 
 	BeforeEach(func() {
 		// Create test HTTP server with WebSocket handler
 		mux := http.NewServeMux()
-		mux.HandleFunc("/ws", WebSocketHandler)
+		opts := DefaultOptions()
+		resumableSessions = opts.Resume
+		mux.HandleFunc("/ws", WebSocketHandler(opts))
 		mux.HandleFunc("/healthz", HealthzHandler)
 
 		testServer = httptest.NewServer(mux)
@@ -35,6 +38,8 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 		if testServer != nil {
 			testServer.Close()
 		}
+		// Stop the sessions parked by the test's connections
+		resumableSessions.drain()
 	})
 
 	Describe("Complete WebSocket Handler Integration", func() {
@@ -43,6 +48,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			conn, resp, err := dialer.Dial(serverURL, nil)
 
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			Expect(resp.StatusCode).To(Equal(http.StatusSwitchingProtocols))
 			Expect(conn).NotTo(BeNil())
 			defer conn.Close()
@@ -62,6 +68,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 
 			// Connection should be open
 			Expect(conn).NotTo(BeNil())
@@ -80,6 +87,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Read initial snapshot to ensure connection is ready
@@ -114,6 +122,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Read initial snapshot
@@ -152,6 +161,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Read initial snapshot
@@ -217,6 +227,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Read initial snapshot
@@ -280,6 +291,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Collect snapshots for 1 second
@@ -308,6 +320,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Read snapshot
@@ -339,11 +352,193 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 		})
 	})
 
+	Describe("Binary Encoding", func() {
+		It("negotiates the binary subprotocol and sends binary snapshots", func() {
+			dialer := websocket.Dialer{Subprotocols: []string{SubprotocolBinary}}
+			conn, resp, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+			Expect(resp.Header.Get("Sec-WebSocket-Protocol")).To(Equal(SubprotocolBinary))
+
+			input, err := proto.MarshalBinary(proto.InputMessage{Type: "input", Seq: 1, Thrust: 1.0})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conn.WriteMessage(websocket.BinaryMessage, input)).To(Succeed())
+
+			conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			messageType, data, err := conn.ReadMessage()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(messageType).To(Equal(websocket.BinaryMessage))
+			msg, err := proto.UnmarshalBinary(data)
+			Expect(err).NotTo(HaveOccurred())
+			snapshot, ok := msg.(*proto.SnapshotMessage)
+			Expect(ok).To(BeTrue())
+			Expect(snapshot.Sun.Radius).To(Equal(float32(50.0)))
+		})
+
+		It("answers invalid binary messages with a binary error", func() {
+			dialer := websocket.Dialer{Subprotocols: []string{SubprotocolBinary}}
+			conn, _, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+
+			// Input with seq 0
+			Expect(conn.WriteMessage(websocket.BinaryMessage, []byte{0x01, 0x00, 0, 0, 0, 0, 0, 0, 0, 0, 0x00})).To(Succeed())
+
+			conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			for {
+				_, data, err := conn.ReadMessage()
+				Expect(err).NotTo(HaveOccurred())
+				msg, err := proto.UnmarshalBinary(data)
+				Expect(err).NotTo(HaveOccurred())
+				if errorMsg, ok := msg.(*proto.ErrorMessage); ok {
+					Expect(errorMsg.Message).To(ContainSubstring("seq"))
+					return
+				}
+			}
+		})
+
+		It("keeps JSON for clients requesting the JSON subprotocol", func() {
+			dialer := websocket.Dialer{Subprotocols: []string{SubprotocolJSON}}
+			conn, resp, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+			Expect(resp.Header.Get("Sec-WebSocket-Protocol")).To(Equal(SubprotocolJSON))
+
+			conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			var snapshot proto.SnapshotMessage
+			Expect(conn.ReadJSON(&snapshot)).To(Succeed())
+			Expect(snapshot.Type).To(Equal("snapshot"))
+		})
+	})
+
+	Describe("Delta Snapshots", func() {
+		// readMessage reads the next message of conn as a generic map
+		readMessage := func(conn *websocket.Conn) map[string]interface{} {
+			conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			var msg map[string]interface{}
+			ExpectWithOffset(1, conn.ReadJSON(&msg)).To(Succeed())
+			return msg
+		}
+
+		It("sends deltas against the acknowledged snapshot and keyframes on request", func() {
+			dialer := websocket.Dialer{}
+			conn, _, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+
+			first := readMessage(conn)
+			Expect(first["t"]).To(Equal("snapshot"))
+			Expect(conn.WriteJSON(map[string]interface{}{"t": "snapshot_ack", "tick": first["tick"]})).To(Succeed())
+
+			Eventually(func() interface{} {
+				msg := readMessage(conn)
+				if msg["t"] != "delta" {
+					return nil
+				}
+				return msg["base"]
+			}, 2*time.Second).Should(Equal(first["tick"]))
+
+			Expect(conn.WriteJSON(map[string]interface{}{"t": "keyframe_request"})).To(Succeed())
+			Eventually(func() interface{} {
+				return readMessage(conn)["t"]
+			}, 2*time.Second).Should(Equal("snapshot"))
+		})
+	})
+
+	Describe("Version Handshake", func() {
+		// readUntil reads messages of conn until one of type t
+		readUntil := func(conn *websocket.Conn, t string) map[string]interface{} {
+			conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			for {
+				var msg map[string]interface{}
+				ExpectWithOffset(1, conn.ReadJSON(&msg)).To(Succeed())
+				if msg["t"] == t {
+					return msg
+				}
+			}
+		}
+
+		It("welcomes a client announcing a supported version", func() {
+			dialer := websocket.Dialer{}
+			conn, _, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+
+			Expect(conn.WriteJSON(map[string]interface{}{"t": "hello", "versions": []string{"v2", "v1"}})).To(Succeed())
+			Expect(readUntil(conn, "welcome")["version"]).To(Equal("v1"))
+			Expect(readUntil(conn, "snapshot")["t"]).To(Equal("snapshot"))
+		})
+
+		It("rejects a client announcing no supported version and closes the connection", func() {
+			dialer := websocket.Dialer{}
+			conn, _, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+
+			Expect(conn.WriteJSON(map[string]interface{}{"t": "hello", "versions": []string{"v9"}})).To(Succeed())
+			errorMsg := readUntil(conn, "error")
+			Expect(errorMsg["code"]).To(Equal(CloseReasonUnsupportedVersion))
+			Expect(errorMsg["versions"]).To(Equal([]interface{}{"v1"}))
+
+			// Snapshots enqueued before the error may still arrive
+			for err == nil {
+				_, _, err = conn.ReadMessage()
+			}
+			closeErr, ok := err.(*websocket.CloseError)
+			Expect(ok).To(BeTrue(), "read error %v", err)
+			Expect(closeErr.Code).To(Equal(websocket.CloseProtocolError))
+			Expect(closeErr.Text).To(Equal(CloseReasonUnsupportedVersion))
+		})
+
+		It("answers a hello message after other messages with an error", func() {
+			dialer := websocket.Dialer{}
+			conn, _, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+
+			Expect(conn.WriteJSON(map[string]interface{}{"t": "input", "seq": 1, "thrust": 0.0, "turn": 0.0})).To(Succeed())
+			Expect(conn.WriteJSON(map[string]interface{}{"t": "hello", "versions": []string{"v1"}})).To(Succeed())
+			Expect(readUntil(conn, "error")["message"]).To(ContainSubstring("hello must be the first message"))
+		})
+
+		It("negotiates the version over the binary encoding", func() {
+			dialer := websocket.Dialer{Subprotocols: []string{SubprotocolBinary}}
+			conn, _, err := dialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
+			defer conn.Close()
+
+			hello, err := proto.MarshalBinary(proto.HelloMessage{Type: "hello", Versions: []proto.ProtocolVersion{proto.ProtocolVersionV1}})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(conn.WriteMessage(websocket.BinaryMessage, hello)).To(Succeed())
+
+			conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			for {
+				_, data, err := conn.ReadMessage()
+				Expect(err).NotTo(HaveOccurred())
+				msg, err := proto.UnmarshalBinary(data)
+				Expect(err).NotTo(HaveOccurred())
+				if welcome, ok := msg.(*proto.WelcomeMessage); ok {
+					Expect(welcome.Version).To(Equal(proto.ProtocolVersionV1))
+					return
+				}
+			}
+		})
+	})
+
 	Describe("Error Handling", func() {
 		It("handles malformed JSON messages gracefully", func() {
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Send malformed JSON
@@ -382,6 +577,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Send message with unknown type
@@ -404,6 +600,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Send input message with invalid seq (0)
@@ -429,6 +626,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Send input message with invalid thrust (> 1.0)
@@ -456,6 +654,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Send multiple messages rapidly
@@ -484,6 +683,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Start reading snapshots in background
@@ -527,6 +727,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Collect multiple snapshots
@@ -556,6 +757,7 @@ var _ = Describe("WebSocket Transport End-to-End", Label("scope:integration", "l
 			dialer := websocket.Dialer{}
 			conn, _, err := dialer.Dial(serverURL, nil)
 			Expect(err).NotTo(HaveOccurred())
+			skipSessionMessage(conn)
 			defer conn.Close()
 
 			// Get baseline snapshot
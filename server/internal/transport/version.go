@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorilla/websocket"
+)
+
+// CloseReasonUnsupportedVersion is the close frame reason, and the error code of
+// the error message sent before it, of connections whose hello message announced
+// no version the server supports.
+const CloseReasonUnsupportedVersion = "unsupported_version"
+
+// ErrUnsupportedVersion is returned by ReadMessage after rejecting a hello message
+// announcing no supported version.
+var ErrUnsupportedVersion = errors.New("no supported protocol version")
+
+// Version returns the protocol version negotiated with the client's hello message,
+// or proto.ProtocolVersionV1 if the client did not send one.
+func (c *Connection) Version() proto.ProtocolVersion {
+	if version, ok := c.version.Load().(proto.ProtocolVersion); ok {
+		return version
+	}
+	return proto.ProtocolVersionV1
+}
+
+// handshake answers the client's first message if it is a hello message: with a
+// welcome message for the negotiated version, or with an "unsupported_version"
+// error, after which the connection is closed and ErrUnsupportedVersion returned.
+// Returns false for other messages, which are routed as usual.
+func (c *Connection) handshake(data []byte) (bool, error) {
+	msg, err := c.encoding.Parse(data)
+	hello, ok := msg.(*proto.HelloMessage)
+	if err != nil || !ok {
+		return false, nil
+	}
+
+	supported := proto.SupportedVersions()
+	version, ok := proto.NegotiateVersion(hello.Versions, supported)
+	if !ok {
+		_ = c.Send(proto.ErrorMessage{
+			Type:     "error",
+			Message:  fmt.Sprintf("none of the protocol versions %v is supported", hello.Versions),
+			Code:     CloseReasonUnsupportedVersion,
+			Versions: supported,
+		})
+		c.closeAfterPending(websocket.CloseProtocolError, CloseReasonUnsupportedVersion)
+		return true, ErrUnsupportedVersion
+	}
+
+	c.version.Store(version)
+	return true, c.Send(proto.WelcomeMessage{Type: "welcome", Version: version})
+}
+
+// closeAfterPending closes the connection like CloseWithReason once the messages
+// already enqueued are written, and waits for it (at most WriteDeadline).
+func (c *Connection) closeAfterPending(code int, reason string) {
+	select {
+	case c.closeRequest <- websocket.FormatCloseMessage(code, reason):
+	case <-c.done:
+		return
+	}
+	select {
+	case <-c.done:
+	case <-time.After(WriteDeadline):
+	}
+}
@@ -2,15 +2,21 @@ package transport
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/gorbit/orbitalrush/internal/observability"
+	"github.com/gorbit/orbitalrush/internal/persistence"
 	"github.com/gorbit/orbitalrush/internal/proto"
+	"github.com/gorbit/orbitalrush/internal/room"
 	"github.com/gorbit/orbitalrush/internal/session"
 	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
 	"github.com/gorbit/orbitalrush/internal/sim/rules"
 	"github.com/gorilla/websocket"
 )
@@ -31,6 +37,7 @@ var (
 	upgrader = websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
+		Subprotocols:    []string{SubprotocolBinary, SubprotocolJSON},
 		CheckOrigin: func(r *http.Request) bool {
 			// For now, allow all origins. In production, this should validate
 			// the origin against a whitelist.
@@ -44,17 +51,27 @@ var (
 type Connection struct {
 	conn      *websocket.Conn
 	done      chan struct{}
+	closeOnce sync.Once
 	writeChan chan []byte
 	startTime time.Time
+	encoding  Encoding // Negotiated with the client's subprotocol
+
+	version      atomic.Value // proto.ProtocolVersion negotiated with a hello message, unset without one
+	greeted      bool         // Whether the first message was read; only used by the reading goroutine
+	closeRequest chan []byte  // Close frame payload to send once pending messages are written
 }
 
 // NewConnection creates a new Connection wrapper around a WebSocket connection.
+// The connection's encoding is the one selected by the negotiated subprotocol.
 func NewConnection(conn *websocket.Conn) *Connection {
 	c := &Connection{
 		conn:      conn,
 		done:      make(chan struct{}),
 		writeChan: make(chan []byte, 256),
 		startTime: time.Now(),
+		encoding:  encodingForSubprotocol(conn.Subprotocol()),
+
+		closeRequest: make(chan []byte),
 	}
 
 	// Set read deadline and pong handler
@@ -75,7 +92,8 @@ func (c *Connection) GetStartTime() time.Time {
 	return c.startTime
 }
 
-// UpgradeConnection upgrades an HTTP connection to a WebSocket connection.
+// UpgradeConnection upgrades an HTTP connection to a WebSocket connection,
+// negotiating SubprotocolBinary or SubprotocolJSON if the client requests one.
 // Returns the WebSocket connection or an error if the upgrade fails.
 func UpgradeConnection(w http.ResponseWriter, r *http.Request) (*websocket.Conn, error) {
 	conn, err := upgrader.Upgrade(w, r, nil)
@@ -86,33 +104,46 @@ func UpgradeConnection(w http.ResponseWriter, r *http.Request) (*websocket.Conn,
 	return conn, nil
 }
 
-// ReadMessage reads a JSON text message from the WebSocket connection.
+// ReadMessage reads a message in the connection's encoding from the WebSocket connection.
+// A hello message sent first is answered here and not returned (see Version).
 // Returns the message bytes or an error if the read fails.
 func (c *Connection) ReadMessage() ([]byte, error) {
-	messageType, data, err := c.conn.ReadMessage()
-	if err != nil {
-		return nil, err
-	}
+	for {
+		messageType, data, err := c.conn.ReadMessage()
+		if err != nil {
+			return nil, err
+		}
 
-	// Only accept text messages (JSON)
-	if messageType != websocket.TextMessage {
-		return nil, websocket.ErrBadHandshake
-	}
+		// Only accept frames of the connection's encoding (text for JSON)
+		if messageType != c.encoding.frameType() {
+			return nil, websocket.ErrBadHandshake
+		}
 
-	// Record bytes in and message count
-	if len(data) > 0 {
-		if bytesCounter := observability.GetConnectionBytesCounter(); bytesCounter != nil {
-			bytesCounter.WithLabelValues("in").Add(float64(len(data)))
+		// Record bytes in and message count
+		if len(data) > 0 {
+			if bytesCounter := observability.GetConnectionBytesCounter(); bytesCounter != nil {
+				bytesCounter.WithLabelValues("in").Add(float64(len(data)))
+			}
+			if msgCounter := observability.GetMessagesCounter(); msgCounter != nil {
+				msgCounter.WithLabelValues("in").Inc()
+			}
 		}
-		if msgCounter := observability.GetMessagesCounter(); msgCounter != nil {
-			msgCounter.WithLabelValues("in").Inc()
+
+		if !c.greeted {
+			c.greeted = true
+			if handled, err := c.handshake(data); handled {
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
 		}
-	}
 
-	return data, nil
+		return data, nil
+	}
 }
 
-// WriteMessage enqueues a JSON text message to be written to the WebSocket connection.
+// WriteMessage enqueues a message in the connection's encoding to be written to the WebSocket connection.
 // Returns an error if the connection is closed or the message cannot be enqueued.
 func (c *Connection) WriteMessage(data []byte) error {
 	select {
@@ -124,21 +155,30 @@ func (c *Connection) WriteMessage(data []byte) error {
 }
 
 // Close gracefully closes the WebSocket connection.
-// It can be called multiple times safely.
-// Closing c.done signals writePump to exit, then the underlying connection is closed.
+// It can be called multiple times, from any goroutine.
+// Closing c.done signals writePump to exit, a close frame is sent, and the underlying
+// connection is closed. writeChan is never closed: WriteMessage may be racing with
+// Close, and sending on a closed channel would panic.
 func (c *Connection) Close() error {
-	select {
-	case <-c.done:
-		// Already closed
-		return nil
-	default:
+	return c.closeWithPayload([]byte{})
+}
+
+// CloseWithReason closes the connection like Close, sending the client code and
+// reason in the close frame. Messages still queued are not sent.
+func (c *Connection) CloseWithReason(code int, reason string) error {
+	return c.closeWithPayload(websocket.FormatCloseMessage(code, reason))
+}
+
+// closeWithPayload closes the connection once, sending payload in the close frame.
+func (c *Connection) closeWithPayload(payload []byte) error {
+	var err error
+	c.closeOnce.Do(func() {
 		close(c.done)
-		// Close writeChan to signal writePump to exit
-		// This is safe because writePump will see c.done is closed and exit,
-		// and WriteMessage checks c.done before sending, so no new sends will occur.
-		close(c.writeChan)
-		return c.conn.Close()
-	}
+		// WriteControl and Close may be called concurrently with writePump's writes
+		_ = c.conn.WriteControl(websocket.CloseMessage, payload, time.Now().Add(WriteDeadline))
+		err = c.conn.Close()
+	})
+	return err
 }
 
 // writePump handles all writes to the WebSocket connection.
@@ -154,26 +194,32 @@ func (c *Connection) writePump() {
 		case <-c.done:
 			return
 
-		case data, ok := <-c.writeChan:
-			if !ok {
-				_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+		case data := <-c.writeChan:
+			if err := c.writeMessage(c.encoding.frameType(), data); err != nil {
 				return
 			}
 
-			if err := c.writeMessage(websocket.TextMessage, data); err != nil {
-				return
+		case payload := <-c.closeRequest:
+			// Write the messages enqueued before the request, then close
+		flush:
+			for {
+				select {
+				case data := <-c.writeChan:
+					if err := c.writeMessage(c.encoding.frameType(), data); err != nil {
+						break flush
+					}
+				default:
+					break flush
+				}
 			}
+			_ = c.closeWithPayload(payload)
+			return
 
 		case <-pingTicker.C:
 			// Before sending a ping, check if there is a message ready.
 			select {
-			case data, ok := <-c.writeChan:
-				if !ok {
-					_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-					return
-				}
-
-				if err := c.writeMessage(websocket.TextMessage, data); err != nil {
+			case data := <-c.writeChan:
+				if err := c.writeMessage(c.encoding.frameType(), data); err != nil {
 					return
 				}
 			default:
@@ -190,13 +236,8 @@ func (c *Connection) writePump() {
 			select {
 			case <-c.done:
 				return
-			case data, ok := <-c.writeChan:
-				if !ok {
-					_ = c.conn.WriteMessage(websocket.CloseMessage, []byte{})
-					return
-				}
-
-				if err := c.writeMessage(websocket.TextMessage, data); err != nil {
+			case data := <-c.writeChan:
+				if err := c.writeMessage(c.encoding.frameType(), data); err != nil {
 					return
 				}
 			default:
@@ -213,7 +254,7 @@ func (c *Connection) writeMessage(messageType int, data []byte) error {
 		return err
 	}
 
-	if messageType == websocket.TextMessage && len(data) > 0 {
+	if messageType != websocket.PingMessage && len(data) > 0 {
 		c.recordMetrics(data)
 	}
 
@@ -237,12 +278,32 @@ type InputMessageHandler interface {
 	HandleInput(msg *proto.InputMessage) error
 }
 
+// InputsMessageHandler handles InputsMessage messages. RouteMessage passes them to
+// input handlers that implement it.
+type InputsMessageHandler interface {
+	HandleInputs(msg *proto.InputsMessage) error
+}
+
 // RestartMessageHandler handles RestartMessage messages.
 type RestartMessageHandler interface {
 	HandleRestart(msg *proto.RestartMessage) error
 }
 
-// ParseMessage parses a JSON message and returns a typed message (InputMessage or RestartMessage).
+// SessionControlHandler handles SessionControlMessage messages.
+type SessionControlHandler interface {
+	HandleSessionControl(msg *proto.SessionControlMessage) error
+}
+
+// SnapshotAckHandler handles SnapshotAckMessage and KeyframeRequestMessage messages.
+// RouteMessage passes them to session control handlers that implement it.
+type SnapshotAckHandler interface {
+	HandleSnapshotAck(msg *proto.SnapshotAckMessage) error
+	HandleKeyframeRequest(msg *proto.KeyframeRequestMessage) error
+}
+
+// ParseMessage parses a JSON message and returns a typed message (InputMessage,
+// InputsMessage, RestartMessage, SessionControlMessage, SnapshotAckMessage,
+// KeyframeRequestMessage, ReplayControlMessage or HelloMessage).
 // Returns an error if the message is malformed, invalid, or of unknown type.
 func ParseMessage(data []byte) (interface{}, error) {
 	if len(data) == 0 {
@@ -278,6 +339,16 @@ func ParseMessage(data []byte) (interface{}, error) {
 		}
 		return &msg, nil
 
+	case "inputs":
+		var msg proto.InputsMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse InputsMessage: %w", err)
+		}
+		if err := proto.ValidateInputsMessage(&msg); err != nil {
+			return nil, fmt.Errorf("invalid InputsMessage: %w", err)
+		}
+		return &msg, nil
+
 	case "restart":
 		var msg proto.RestartMessage
 		if err := json.Unmarshal(data, &msg); err != nil {
@@ -288,6 +359,56 @@ func ParseMessage(data []byte) (interface{}, error) {
 		}
 		return &msg, nil
 
+	case "session_control":
+		var msg proto.SessionControlMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse SessionControlMessage: %w", err)
+		}
+		if err := proto.ValidateSessionControlMessage(&msg); err != nil {
+			return nil, fmt.Errorf("invalid SessionControlMessage: %w", err)
+		}
+		return &msg, nil
+
+	case "snapshot_ack":
+		var msg proto.SnapshotAckMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse SnapshotAckMessage: %w", err)
+		}
+		if err := proto.ValidateSnapshotAckMessage(&msg); err != nil {
+			return nil, fmt.Errorf("invalid SnapshotAckMessage: %w", err)
+		}
+		return &msg, nil
+
+	case "keyframe_request":
+		var msg proto.KeyframeRequestMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse KeyframeRequestMessage: %w", err)
+		}
+		if err := proto.ValidateKeyframeRequestMessage(&msg); err != nil {
+			return nil, fmt.Errorf("invalid KeyframeRequestMessage: %w", err)
+		}
+		return &msg, nil
+
+	case "hello":
+		var msg proto.HelloMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse HelloMessage: %w", err)
+		}
+		if err := proto.ValidateHelloMessage(&msg); err != nil {
+			return nil, fmt.Errorf("invalid HelloMessage: %w", err)
+		}
+		return &msg, nil
+
+	case "replay_control":
+		var msg proto.ReplayControlMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to parse ReplayControlMessage: %w", err)
+		}
+		if err := proto.ValidateReplayControlMessage(&msg); err != nil {
+			return nil, fmt.Errorf("invalid ReplayControlMessage: %w", err)
+		}
+		return &msg, nil
+
 	default:
 		return nil, fmt.Errorf("unknown message type: %s", typeStr)
 	}
@@ -295,12 +416,16 @@ func ParseMessage(data []byte) (interface{}, error) {
 
 // RouteMessage parses a JSON message, validates it, and routes it to the appropriate handler.
 // Returns an error if parsing, validation, or handler execution fails.
-func RouteMessage(data []byte, inputHandler InputMessageHandler, restartHandler RestartMessageHandler) error {
+func RouteMessage(data []byte, inputHandler InputMessageHandler, restartHandler RestartMessageHandler, controlHandler SessionControlHandler) error {
 	msg, err := ParseMessage(data)
 	if err != nil {
 		return err
 	}
+	return routeParsedMessage(msg, inputHandler, restartHandler, controlHandler)
+}
 
+// routeParsedMessage routes a parsed message to the appropriate handler.
+func routeParsedMessage(msg interface{}, inputHandler InputMessageHandler, restartHandler RestartMessageHandler, controlHandler SessionControlHandler) error {
 	// Route to appropriate handler
 	switch m := msg.(type) {
 	case *proto.InputMessage:
@@ -309,113 +434,243 @@ func RouteMessage(data []byte, inputHandler InputMessageHandler, restartHandler
 		}
 		return inputHandler.HandleInput(m)
 
+	case *proto.InputsMessage:
+		handler, ok := inputHandler.(InputsMessageHandler)
+		if !ok {
+			return fmt.Errorf("InputMessageHandler does not handle InputsMessage")
+		}
+		return handler.HandleInputs(m)
+
 	case *proto.RestartMessage:
 		if restartHandler == nil {
 			return fmt.Errorf("RestartMessageHandler is nil")
 		}
 		return restartHandler.HandleRestart(m)
 
+	case *proto.SessionControlMessage:
+		if controlHandler == nil {
+			return fmt.Errorf("SessionControlHandler is nil")
+		}
+		return controlHandler.HandleSessionControl(m)
+
+	case *proto.SnapshotAckMessage:
+		handler, ok := controlHandler.(SnapshotAckHandler)
+		if !ok {
+			return fmt.Errorf("SessionControlHandler does not handle SnapshotAckMessage")
+		}
+		return handler.HandleSnapshotAck(m)
+
+	case *proto.KeyframeRequestMessage:
+		handler, ok := controlHandler.(SnapshotAckHandler)
+		if !ok {
+			return fmt.Errorf("SessionControlHandler does not handle KeyframeRequestMessage")
+		}
+		return handler.HandleKeyframeRequest(m)
+
+	case *proto.HelloMessage:
+		// Connection.ReadMessage answers the hello message a client sends first
+		return fmt.Errorf("hello must be the first message")
+
 	default:
 		return fmt.Errorf("unexpected message type: %T", msg)
 	}
 }
 
 // ErrorMessage represents an error response message.
-type ErrorMessage struct {
-	Type    string `json:"t"`
-	Message string `json:"message"`
-}
+type ErrorMessage = proto.ErrorMessage
 
 // NewErrorMessage creates a JSON error response message.
 func NewErrorMessage(err error) []byte {
-	errorMsg := ErrorMessage{
+	data, _ := json.Marshal(newErrorMessage(err))
+	return data
+}
+
+// newErrorMessage returns the error response message for err.
+func newErrorMessage(err error) ErrorMessage {
+	return ErrorMessage{
 		Type:    "error",
 		Message: err.Error(),
 	}
-	data, _ := json.Marshal(errorMsg)
-	return data
 }
 
+// Options configure the private sessions of WebSocket connections. Start from
+// DefaultOptions so that unset fields get usable values.
+type Options struct {
+	// Session are the settings of private sessions; they must be valid (see
+	// session.Options.Validate). With a scheduler, private sessions are stepped by
+	// it instead of a run loop goroutine of their own; the caller starts and stops it.
+	// Connections whose session expired are closed with reason CloseReasonSessionExpired.
+	Session session.Options
+	// Resume keeps the private sessions of dropped connections for a reconnect, nil
+	// to stop them on disconnect (see WebSocketHandler)
+	Resume *ResumeStore
+	// Replays records private sessions and saves their replay when they end, nil to
+	// not record them. Rooms are recorded through RoomReplaySink.
+	Replays *persistence.ReplayStore
+}
+
+// DefaultOptions returns the options of private sessions with the session
+// defaults (see session.DefaultOptions), resumable for DefaultResumeGrace and not
+// recorded. Every call returns a new resume store.
+func DefaultOptions() Options {
+	return Options{
+		Session: session.DefaultOptions(),
+		Resume:  NewResumeStore(DefaultResumeGrace),
+	}
+}
+
+// CloseReasonSessionExpired is the close frame reason of connections whose
+// session expired for being idle or left finished.
+const CloseReasonSessionExpired = "session_expired"
+
+// CloseReasonSessionFailed is the close frame reason of connections whose session
+// stopped on an error, such as an invariant violation in InvariantsFreeze mode.
+const CloseReasonSessionFailed = "session_failed"
+
 // NewInitialWorld creates a default initial world state for new sessions.
-// Ship at position (70, 0) with zero velocity, 100 energy.
-// Sun at origin (0, 0) with radius 50, mass 1000.
-// Ship starts outside sun radius (70 > 50) to avoid immediate collision.
-// Initial pallets positioned around the world in a circular pattern.
+// The layout is the built-in default level (see levels.Standard).
 func NewInitialWorld() entities.World {
-	ship := entities.NewShip(
-		entities.NewVec2(70.0, 0.0),
-		entities.NewVec2(0.0, 0.0),
-		0.0,
-		100.0,
-	)
-	sun := entities.NewSun(
-		entities.NewVec2(0.0, 0.0),
-		50.0,
-		1000.0,
-	)
-
-	// Create initial pallets positioned in a circle around the sun
-	// Position them at various distances and angles for gameplay variety
-	pallets := []entities.Pallet{
-		// First ring - closer to sun
-		entities.NewPallet(1, entities.NewVec2(80.0, 0.0), true),  // Right
-		entities.NewPallet(2, entities.NewVec2(-80.0, 0.0), true), // Left
-		entities.NewPallet(3, entities.NewVec2(0.0, 80.0), true),  // Up
-		entities.NewPallet(4, entities.NewVec2(0.0, -80.0), true), // Down
-
-		// Second ring - further from sun
-		entities.NewPallet(5, entities.NewVec2(120.0, 60.0), true),   // Right-up
-		entities.NewPallet(6, entities.NewVec2(-120.0, 60.0), true),  // Left-up
-		entities.NewPallet(7, entities.NewVec2(120.0, -60.0), true),  // Right-down
-		entities.NewPallet(8, entities.NewVec2(-120.0, -60.0), true), // Left-down
-
-		// Additional pallets at various positions
-		entities.NewPallet(9, entities.NewVec2(150.0, 0.0), true),   // Far right
-		entities.NewPallet(10, entities.NewVec2(-150.0, 0.0), true), // Far left
-	}
-
-	return entities.NewWorld(ship, sun, pallets)
+	return levels.Standard()
 }
 
+// ErrSpectatorReadOnly is returned for input and restart messages sent by spectators.
+var ErrSpectatorReadOnly = errors.New("spectators cannot send input or restart messages")
+
+// ErrNotRoomHost is returned for session control messages sent by room players
+// other than the room's host.
+var ErrNotRoomHost = errors.New("only the room host can control the session")
+
 // SessionHandler manages a session for a WebSocket connection.
+// The session is either private to the connection or shared through a room.
 // It implements InputMessageHandler and RestartMessageHandler interfaces.
 type SessionHandler struct {
-	session        *session.Session
-	conn           *Connection
-	clock          session.Clock
-	initialWorld   entities.World
-	done           chan struct{}
-	snapshotTicker *time.Ticker
+	session      *session.Session
+	room         *room.Room        // Room sharing the session, nil for a private session
+	playerID     entities.PlayerID // Player controlled by this connection, empty for spectators
+	spectator    bool              // Read-only connection: receives snapshots, rejects input and restart
+	delay        time.Duration     // Delay of the snapshots sent to a spectator
+	clock        session.Clock
+	initialWorld entities.World
+	logger       logr.Logger
+	done         chan struct{}
+	opts         Options                   // Options of a private session
+	stopped      chan struct{}             // Closed when the run loop of a private session stopped on runErr, nil for room sessions
+	runErr       error                     // Error of Run that stopped the run loop, set before stopped is closed
+	scheduled    *session.ScheduledSession // Scheduler entry of a private session, nil if it has a run loop
+	baselines    snapshotBaselines         // Snapshots sent to conn, for deltas against the one it acknowledged
+
+	mu            sync.Mutex    // Guards conn and broadcastDone
+	conn          *Connection   // Connection receiving snapshots
+	broadcastDone chan struct{} // Closed to end the snapshot loop of conn, nil while not broadcasting
 }
 
-// NewSessionHandler creates a new SessionHandler with a new session.
+// NewSessionHandler creates a new SessionHandler with a new session set up with opts.
+// The connection controls the entities.DefaultPlayerID ship of initialWorld.
 // The logger parameter is optional. If provided and enabled, it will be injected into the session for tick time logging.
-func NewSessionHandler(conn *Connection, clock session.Clock, initialWorld entities.World, logger logr.Logger) *SessionHandler {
-	sess := session.NewSession(clock, initialWorld, 100) // maxQueueSize = 100
-	// Set logger if it's enabled (zero logger will return false)
+func NewSessionHandler(conn *Connection, clock session.Clock, initialWorld entities.World, opts Options, logger logr.Logger) *SessionHandler {
+	h := &SessionHandler{
+		playerID:     entities.DefaultPlayerID,
+		conn:         conn,
+		clock:        clock,
+		initialWorld: initialWorld,
+		logger:       logger,
+		opts:         opts,
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+	h.session = h.newSession()
+	return h
+}
+
+// newRestoredSessionHandler creates a detached SessionHandler for a private session
+// restored from a checkpoint. Restarts reset it to NewInitialWorld. The caller
+// starts its run loop; Attach connects it to a client.
+func newRestoredSessionHandler(sess *session.Session, opts Options, logger logr.Logger) *SessionHandler {
 	if logger.Enabled() {
 		sess.SetLogger(logger)
 	}
-	snapshotInterval := 100 * time.Millisecond // 10 Hz (100ms = 10 snapshots per second)
+	setUpSession(sess, opts)
+	return &SessionHandler{
+		session:      sess,
+		playerID:     entities.DefaultPlayerID,
+		initialWorld: NewInitialWorld(),
+		logger:       logger,
+		opts:         opts,
+		done:         make(chan struct{}),
+		stopped:      make(chan struct{}),
+	}
+}
 
+// Checkpoint returns the checkpoint of the handler's session.
+func (h *SessionHandler) Checkpoint() session.Checkpoint {
+	return h.session.Checkpoint()
+}
+
+// NewRoomSessionHandler creates a SessionHandler for player playerID of room r.
+// The room runs the session's tick loop, so the handler only broadcasts snapshots
+// and routes messages; restart messages restart the whole room.
+func NewRoomSessionHandler(conn *Connection, r *room.Room, playerID entities.PlayerID, logger logr.Logger) *SessionHandler {
 	return &SessionHandler{
-		session:        sess,
-		conn:           conn,
-		clock:          clock,
-		initialWorld:   initialWorld,
-		done:           make(chan struct{}),
-		snapshotTicker: time.NewTicker(snapshotInterval),
+		session:  r.Session(),
+		room:     r,
+		playerID: playerID,
+		conn:     conn,
+		logger:   logger,
+		done:     make(chan struct{}),
+	}
+}
+
+// NewSpectatorSessionHandler creates a read-only SessionHandler watching room r.
+// The connection receives the room's snapshots, delayed by the room's spectator
+// delay, and every input or restart message is rejected with ErrSpectatorReadOnly.
+func NewSpectatorSessionHandler(conn *Connection, r *room.Room, logger logr.Logger) *SessionHandler {
+	h := NewRoomSessionHandler(conn, r, "", logger)
+	h.spectator = true
+	h.delay = r.SpectatorDelay()
+	return h
+}
+
+// newSession creates a session from the initial world with the handler's logger
+// and options (see setUpSession).
+func (h *SessionHandler) newSession() *session.Session {
+	sess := session.NewSession(h.clock, h.initialWorld, 100) // maxQueueSize = 100
+	// Set logger if it's enabled (zero logger will return false)
+	if h.logger.Enabled() {
+		sess.SetLogger(h.logger)
+	}
+	setUpSession(sess, h.opts)
+	return sess
+}
+
+// setUpSession applies the session options of opts and the default rollback window
+// to the private session sess, and records it if opts has a replay store.
+func setUpSession(sess *session.Session, opts Options) {
+	_ = opts.Session.Apply(sess) // Valid options are a precondition of Options
+	sess.SetRollbackWindow(session.DefaultRollbackWindow)
+	if opts.Replays != nil {
+		sess.StartRecording("standard", 0) // Private sessions play the standard level (see NewInitialWorld)
 	}
 }
 
-// HandleInput enqueues an input command to the session.
+// PlayerID returns the ID of the player controlled by this connection.
+func (h *SessionHandler) PlayerID() entities.PlayerID {
+	return h.playerID
+}
+
+// HandleInput enqueues an input command to the player's queue in the session.
+// Inputs with a tick go through the player's jitter buffer (see
+// session.Session.EnqueuePlayerCommandAt). Spectators cannot send input.
 func (h *SessionHandler) HandleInput(msg *proto.InputMessage) error {
+	if h.spectator {
+		return ErrSpectatorReadOnly
+	}
+
 	cmd := rules.InputCommand{
 		Thrust: msg.Thrust,
 		Turn:   msg.Turn,
 	}
 
-	success := h.session.EnqueueCommand(msg.Seq, cmd)
+	success := h.session.EnqueuePlayerCommandAt(h.playerID, msg.Seq, msg.Tick, cmd)
 	if !success {
 		return fmt.Errorf("failed to enqueue command with seq %d", msg.Seq)
 	}
@@ -423,62 +678,325 @@ func (h *SessionHandler) HandleInput(msg *proto.InputMessage) error {
 	return nil
 }
 
+// HandleInputs enqueues the inputs of an InputsMessage to the player's queue in the
+// session: the newest input and the redundant copies of earlier ones the session
+// did not receive yet (see session.Session.EnqueuePlayerCommands). Returns an error
+// if the newest input was not enqueued. Spectators cannot send input.
+func (h *SessionHandler) HandleInputs(msg *proto.InputsMessage) error {
+	if h.spectator {
+		return ErrSpectatorReadOnly
+	}
+
+	cmds := make([]session.QueuedCommand, len(msg.Inputs))
+	for i, input := range msg.Inputs {
+		cmds[i] = session.QueuedCommand{
+			Sequence: input.Seq,
+			Command:  rules.InputCommand{Thrust: input.Thrust, Turn: input.Turn},
+		}
+	}
+
+	if added, _ := h.session.EnqueuePlayerCommands(h.playerID, cmds); !added {
+		return fmt.Errorf("failed to enqueue command with seq %d", cmds[len(cmds)-1].Sequence)
+	}
+
+	return nil
+}
+
 // HandleRestart resets the session to the initial world state.
+// The session is reset in place, so the run and snapshot loops keep using the same session.
+// In a room, the room is restarted for every player. Spectators cannot restart.
 func (h *SessionHandler) HandleRestart(msg *proto.RestartMessage) error {
-	// Stop current session
-	h.session.Stop()
+	if h.spectator {
+		return ErrSpectatorReadOnly
+	}
+	if h.room != nil {
+		h.room.Restart()
+		return nil
+	}
+	h.session.Reset(h.initialWorld)
 
-	// Create new session with initial world state
-	h.session = session.NewSession(h.clock, h.initialWorld, 100)
+	return nil
+}
 
+// HandleSessionControl pauses, resumes or changes the time scale of the session.
+// In a room, it applies to the room's session for every player, so only the room's
+// host (see room.Room.Host) can control it; other players get ErrNotRoomHost.
+// Spectators cannot control the session. Returns an error for time scales the
+// session rejects.
+func (h *SessionHandler) HandleSessionControl(msg *proto.SessionControlMessage) error {
+	if h.spectator {
+		return ErrSpectatorReadOnly
+	}
+	if h.room != nil {
+		if host, _ := h.room.Host(); host != h.playerID {
+			return ErrNotRoomHost
+		}
+	}
+
+	switch msg.Action {
+	case "pause":
+		h.session.Pause()
+	case "resume":
+		h.session.Resume()
+	case "scale":
+		return h.session.SetTimeScale(msg.Scale)
+	}
+	return nil
+}
+
+// HandleSnapshotAck makes the acknowledged snapshot the base of the deltas sent
+// next (see proto.DeltaSnapshotMessage). Connections receiving delayed snapshots
+// always get full snapshots.
+func (h *SessionHandler) HandleSnapshotAck(msg *proto.SnapshotAckMessage) error {
+	h.baselines.ack(msg.Tick)
 	return nil
 }
 
+// HandleKeyframeRequest sends the next snapshot in full. Deltas resume once the
+// client acknowledges a snapshot again.
+func (h *SessionHandler) HandleKeyframeRequest(msg *proto.KeyframeRequestMessage) error {
+	h.baselines.requestKeyframe()
+	return nil
+}
+
+// snapshotInterval is the time between two snapshots sent to a connection (10 Hz).
+const snapshotInterval = 100 * time.Millisecond
+
+// delayedSnapshot is a serialized snapshot held back until due.
+type delayedSnapshot struct {
+	due  time.Time
+	data []byte
+}
+
 // Start starts the session run loop and snapshot broadcasting.
+// A room session is run by its room, so only snapshot broadcasting is started;
+// the connection is closed when the room closes.
 func (h *SessionHandler) Start() {
-	// Start session run loop (30Hz = ~33ms per tick)
-	sessionTicker := time.NewTicker(33 * time.Millisecond)
+	if h.room == nil {
+		h.startRunLoop()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.startBroadcastLocked()
+}
+
+// Detach stops broadcasting snapshots to the handler's connection. The session
+// keeps running, so that Attach can continue it on another connection.
+func (h *SessionHandler) Detach() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopBroadcastLocked()
+}
+
+// Attach broadcasts snapshots to conn instead of the handler's current connection.
+// It is used to resume a detached handler on a new connection.
+func (h *SessionHandler) Attach(conn *Connection) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.stopBroadcastLocked()
+	h.conn = conn
+	h.baselines.reset()
+	h.startBroadcastLocked()
+}
+
+// startBroadcastLocked starts the snapshot loop of h.conn. Callers hold h.mu.
+func (h *SessionHandler) startBroadcastLocked() {
+	conn := h.conn
+	stop := make(chan struct{})
+	h.broadcastDone = stop
+
+	var roomDone <-chan struct{}
+	if h.room != nil {
+		roomDone = h.room.Done()
+	}
+	ticks, stopTicks := h.snapshotTicks()
+
+	// Start snapshot broadcasting loop (~10 Hz = 100ms per snapshot)
 	go func() {
-		defer sessionTicker.Stop()
+		defer stopTicks()
+		var pending []delayedSnapshot // Spectator snapshots not yet due, oldest first
+		var lastWarning time.Time     // When the last backlog warning was checked
 		for {
 			select {
 			case <-h.done:
 				return
-			case <-sessionTicker.C:
-				// Run session to process ticks (limit to 10 ticks per call to prevent lag)
-				h.session.Run(10)
+			case <-stop:
+				return
+			case <-roomDone:
+				// End the connection so that its read loop returns
+				if err := h.session.Violation(); err != nil {
+					closeStopped(conn, err)
+				} else if h.session.State() == session.StateExpired {
+					closeStopped(conn, session.ErrSessionExpired)
+				}
+				_ = conn.Close()
+				return
+			case <-h.stopped:
+				closeStopped(conn, h.runErr)
+				return
+			case now := <-ticks:
+				// Get world state and broadcast this connection's view of it
+				world := h.session.GetWorld()
+				snapshot := WorldToPlayerSnapshot(world, h.playerID)
+				if h.spectator {
+					snapshot = WorldToSnapshot(world)
+				}
+				if ack, ok := h.session.LastInputAck(h.playerID); ok && !h.spectator {
+					snapshot.Ack = InputAckToSnapshot(ack)
+				}
+				snapshot.Paused = h.session.Paused()
+				if scale := h.session.TimeScale(); scale != 1 {
+					snapshot.TimeScale = scale
+				}
+
+				// Serialize and send snapshot, as a delta once the client acknowledged one
+				var msg any = snapshot
+				if h.delay == 0 {
+					msg = h.baselines.next(snapshot)
+				}
+				data, err := conn.Encoding().Encode(msg)
+				if err != nil {
+					// Skip this snapshot; a world that cannot be encoded is a bug worth seeing
+					if h.logger.Enabled() {
+						h.logger.Error(err, "Failed to encode snapshot", "message_type", "encode_error", "tick", snapshot.Tick)
+					}
+					continue
+				}
+
+				if h.delay > 0 {
+					pending = append(pending, delayedSnapshot{due: now.Add(h.delay), data: data})
+					sent := 0
+					for sent < len(pending) && !pending[sent].due.After(now) {
+						_ = conn.WriteMessage(pending[sent].data)
+						sent++
+					}
+					pending = pending[sent:]
+					continue
+				}
+
+				// Write snapshot (ignore errors - connection may be closed)
+				_ = conn.WriteMessage(data)
+
+				// Warn the player about inputs lost to backlog control, at most once per interval
+				if now.Sub(lastWarning) >= backlogWarningInterval {
+					lastWarning = now
+					if warning, ok := backlogWarning(h.session.TakeBacklogStats(h.playerID)); ok {
+						_ = conn.Send(warning)
+					}
+				}
 			}
 		}
 	}()
+}
 
-	// Start snapshot broadcasting loop (~10 Hz = 100ms per snapshot)
+// backlogWarningInterval is the minimum interval between two "input_backlog" warnings to a player.
+const backlogWarningInterval = time.Second
+
+// backlogWarning returns the "input_backlog" warning for stats,
+// or false if no command was dropped or coalesced.
+func backlogWarning(stats session.BacklogStats) (proto.WarningMessage, bool) {
+	if stats == (session.BacklogStats{}) {
+		return proto.WarningMessage{}, false
+	}
+	return proto.WarningMessage{
+		Type:      "warning",
+		Code:      "input_backlog",
+		Message:   fmt.Sprintf("inputs arrive faster than they are applied: %d dropped, %d coalesced", stats.Dropped, stats.Coalesced),
+		Dropped:   stats.Dropped,
+		Coalesced: stats.Coalesced,
+	}, true
+}
+
+// snapshotTicks returns the channel pacing the snapshot loop and a function that
+// releases it. Snapshots of a scheduled session follow its steps, right after the
+// ticks they show, so connections share the scheduler's timer; a session with a
+// run loop of its own gets a ticker per connection.
+func (h *SessionHandler) snapshotTicks() (<-chan time.Time, func()) {
+	scheduled := h.scheduled
+	if h.room != nil {
+		scheduled = h.room.Scheduled()
+	}
+	if scheduled != nil {
+		return scheduled.Subscribe(snapshotInterval)
+	}
+	ticker := time.NewTicker(snapshotInterval)
+	return ticker.C, ticker.Stop
+}
+
+// stopBroadcastLocked ends the snapshot loop, if one is running. Callers hold h.mu.
+func (h *SessionHandler) stopBroadcastLocked() {
+	if h.broadcastDone != nil {
+		close(h.broadcastDone)
+		h.broadcastDone = nil
+	}
+}
+
+// startRunLoop starts the run loop of a private session, or adds the session to
+// the scheduler if one is set. The loop ends when Run returns an error: the session
+// expired or froze on an invariant violation. That ends the snapshot loop and
+// closes the connection (see closeStopped).
+func (h *SessionHandler) startRunLoop() {
+	if sched := h.opts.Session.Scheduler; sched != nil {
+		h.scheduled = sched.Add(h.session, h.stopRunLoop)
+		return
+	}
+
+	// Start session run loop at the session's tick rate (30Hz = ~33.3ms per tick by default)
+	sessionTicker := time.NewTicker(h.session.TickInterval())
 	go func() {
+		defer sessionTicker.Stop()
 		for {
 			select {
 			case <-h.done:
 				return
-			case <-h.snapshotTicker.C:
-				// Get world state and broadcast snapshot
-				world := h.session.GetWorld()
-				snapshot := WorldToSnapshot(world)
-
-				// Serialize and send snapshot
-				data, err := json.Marshal(snapshot)
-				if err != nil {
-					// Log error but continue
-					continue
+			case <-sessionTicker.C:
+				// Run session to process ticks (limit to 10 ticks per call to prevent lag)
+				if err := h.session.Run(10); err != nil {
+					h.stopRunLoop(err)
+					return
 				}
-
-				// Write snapshot (ignore errors - connection may be closed)
-				_ = h.conn.WriteMessage(data)
 			}
 		}
 	}()
 }
 
-// Stop stops the session handler and cleans up resources.
+// stopRunLoop records the error of Run that stopped the run loop and ends the
+// snapshot loop. Errors other than expiry are logged.
+func (h *SessionHandler) stopRunLoop(err error) {
+	if !errors.Is(err, session.ErrSessionExpired) && h.logger.Enabled() {
+		h.logger.Error(err, "Session stopped", "tick", h.session.GetWorld().Tick)
+	}
+	h.runErr = err
+	close(h.stopped)
+}
+
+// closeStopped closes conn after its session stopped on err: with reason
+// CloseReasonSessionExpired if the session expired, otherwise after an error
+// message with code CloseReasonSessionFailed, with that reason.
+func closeStopped(conn *Connection, err error) {
+	if errors.Is(err, session.ErrSessionExpired) {
+		_ = conn.CloseWithReason(websocket.CloseNormalClosure, CloseReasonSessionExpired)
+		return
+	}
+	msg := newErrorMessage(err)
+	msg.Code = CloseReasonSessionFailed
+	_ = conn.Send(msg)
+	conn.closeAfterPending(websocket.CloseInternalServerErr, CloseReasonSessionFailed)
+}
+
+// Stop stops the session handler and cleans up resources; a recorded private session
+// saves its replay. A room session keeps running for the other players; leaving the
+// room is up to the caller.
 func (h *SessionHandler) Stop() {
 	close(h.done)
-	h.snapshotTicker.Stop()
-	h.session.Stop()
+	h.Detach()
+	if h.scheduled != nil {
+		h.scheduled.Remove()
+	}
+	if h.room == nil {
+		if replay, ok := h.session.StopRecording(); ok && h.opts.Replays != nil {
+			saveReplay(h.opts.Replays, h.logger, replay)
+		}
+	}
 }
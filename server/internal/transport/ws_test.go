@@ -12,6 +12,8 @@ import (
 	"github.com/gorbit/orbitalrush/internal/proto"
 	"github.com/gorbit/orbitalrush/internal/session"
 	"github.com/gorbit/orbitalrush/internal/sim/entities"
+	"github.com/gorbit/orbitalrush/internal/sim/levels"
+	"github.com/gorbit/orbitalrush/internal/sim/rules"
 	"github.com/gorilla/websocket"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -151,6 +153,27 @@ var _ = Describe("WebSocket Connection Handler", Label("scope:integration", "loo
 			Expect(received["data"]).To(Equal("hello"))
 		})
 
+		It("answers a hello message sent first and returns the next message", func() {
+			Eventually(func() bool {
+				return conn != nil
+			}).Should(BeTrue())
+
+			Expect(clientConn.WriteJSON(map[string]interface{}{"t": "hello", "versions": []string{"v2", "v1"}})).To(Succeed())
+			Expect(clientConn.WriteJSON(map[string]interface{}{"t": "restart"})).To(Succeed())
+
+			connection := NewConnection(conn)
+			defer connection.Close()
+			data, err := connection.ReadMessage()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(MatchJSON(`{"t":"restart"}`))
+			Expect(connection.Version()).To(Equal(proto.ProtocolVersionV1))
+
+			clientConn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+			var welcome proto.WelcomeMessage
+			Expect(clientConn.ReadJSON(&welcome)).To(Succeed())
+			Expect(welcome).To(Equal(proto.WelcomeMessage{Type: "welcome", Version: proto.ProtocolVersionV1}))
+		})
+
 		It("handles connection close gracefully", func() {
 			Eventually(func() bool {
 				return conn != nil
@@ -400,6 +423,15 @@ func (h *mockRestartHandler) HandleRestart(msg *proto.RestartMessage) error {
 	return nil
 }
 
+type mockSessionControlHandler struct {
+	lastMessage *proto.SessionControlMessage
+}
+
+func (h *mockSessionControlHandler) HandleSessionControl(msg *proto.SessionControlMessage) error {
+	h.lastMessage = msg
+	return nil
+}
+
 var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop:g5-adapter", "layer:server", "dep:ws", "b:message-routing", "r:high"), func() {
 
 	Describe("ParseMessage", func() {
@@ -519,15 +551,17 @@ var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop
 	Describe("RouteMessage", func() {
 		var inputHandler *mockInputHandler
 		var restartHandler *mockRestartHandler
+		var controlHandler *mockSessionControlHandler
 
 		BeforeEach(func() {
 			inputHandler = &mockInputHandler{}
 			restartHandler = &mockRestartHandler{}
+			controlHandler = &mockSessionControlHandler{}
 		})
 
 		It("successfully routes valid InputMessage to InputMessageHandler", func() {
 			jsonData := []byte(`{"t":"input","seq":42,"thrust":0.75,"turn":-0.5}`)
-			err := RouteMessage(jsonData, inputHandler, restartHandler)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(inputHandler.lastMessage).NotTo(BeNil())
@@ -539,7 +573,7 @@ var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop
 
 		It("successfully routes valid RestartMessage to RestartMessageHandler", func() {
 			jsonData := []byte(`{"t":"restart"}`)
-			err := RouteMessage(jsonData, inputHandler, restartHandler)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
 
 			Expect(err).NotTo(HaveOccurred())
 			Expect(restartHandler.lastMessage).NotTo(BeNil())
@@ -547,10 +581,26 @@ var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop
 			Expect(inputHandler.lastMessage).To(BeNil())
 		})
 
+		It("routes valid SessionControlMessage to SessionControlHandler", func() {
+			jsonData := []byte(`{"t":"session_control","action":"scale","scale":0.5}`)
+			Expect(RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)).To(Succeed())
+
+			Expect(controlHandler.lastMessage).To(Equal(&proto.SessionControlMessage{Type: "session_control", Action: "scale", Scale: 0.5}))
+			Expect(inputHandler.lastMessage).To(BeNil())
+			Expect(restartHandler.lastMessage).To(BeNil())
+		})
+
+		It("routes InputsMessage only to input handlers that handle it", func() {
+			jsonData := []byte(`{"t":"inputs","inputs":[{"seq":1,"thrust":0.5,"turn":0},{"seq":2,"thrust":1,"turn":0}]}`)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
+			Expect(err).To(MatchError(ContainSubstring("does not handle InputsMessage")))
+			Expect(RouteMessage([]byte(`{"t":"inputs","inputs":[]}`), inputHandler, restartHandler, controlHandler)).To(MatchError(ContainSubstring("invalid InputsMessage")))
+		})
+
 		It("returns handler error if InputMessageHandler fails", func() {
 			inputHandler.shouldError = true
 			jsonData := []byte(`{"t":"input","seq":1,"thrust":0.5,"turn":0.0}`)
-			err := RouteMessage(jsonData, inputHandler, restartHandler)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("handler error"))
@@ -559,7 +609,7 @@ var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop
 		It("returns handler error if RestartMessageHandler fails", func() {
 			restartHandler.shouldError = true
 			jsonData := []byte(`{"t":"restart"}`)
-			err := RouteMessage(jsonData, inputHandler, restartHandler)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
 
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(ContainSubstring("handler error"))
@@ -567,7 +617,7 @@ var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop
 
 		It("returns error for malformed messages", func() {
 			jsonData := []byte(`{"t":"input","seq":invalid}`)
-			err := RouteMessage(jsonData, inputHandler, restartHandler)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
 
 			Expect(err).To(HaveOccurred())
 			Expect(inputHandler.lastMessage).To(BeNil())
@@ -576,7 +626,7 @@ var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop
 
 		It("returns error for validation failures", func() {
 			jsonData := []byte(`{"t":"input","seq":0,"thrust":0.5,"turn":0.0}`)
-			err := RouteMessage(jsonData, inputHandler, restartHandler)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
 
 			Expect(err).To(HaveOccurred())
 			Expect(inputHandler.lastMessage).To(BeNil())
@@ -584,7 +634,7 @@ var _ = Describe("Message Parsing and Routing", Label("scope:integration", "loop
 
 		It("returns error for unknown message types", func() {
 			jsonData := []byte(`{"t":"unknown"}`)
-			err := RouteMessage(jsonData, inputHandler, restartHandler)
+			err := RouteMessage(jsonData, inputHandler, restartHandler, controlHandler)
 
 			Expect(err).To(HaveOccurred())
 			Expect(inputHandler.lastMessage).To(BeNil())
@@ -718,7 +768,7 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 
 			Expect(handler).NotTo(BeNil())
 			Expect(handler.session).NotTo(BeNil())
@@ -756,7 +806,7 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 
 			// Enqueue input command
 			inputMsg := &proto.InputMessage{
@@ -770,7 +820,7 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			Expect(err).NotTo(HaveOccurred())
 
 			// Verify command was enqueued by running session
-			clock.Advance(33 * time.Millisecond)
+			clock.Advance(session.DefaultTickInterval)
 			handler.session.Run(1)
 
 			// World should have progressed (tick should increment)
@@ -778,6 +828,83 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			Expect(world.Tick).To(BeNumerically(">", uint32(0)))
 		})
 
+		It("enqueues the newest input and the redundant ones the session did not receive", func() {
+			// A ship outside the sun, so that the world runs for three ticks
+			ship := entities.NewShip(entities.NewVec2(200.0, 0.0), entities.NewVec2(0.0, 0.0), 0.0, 100.0)
+			world := entities.NewWorld(ship, entities.NewSun(entities.NewVec2(0.0, 0.0), 50.0, 1000.0), nil)
+			handler := NewSessionHandler(nil, clock, world, DefaultOptions(), logr.Discard())
+			defer handler.Stop()
+
+			Expect(handler.HandleInputs(&proto.InputsMessage{Type: "inputs", Inputs: []proto.InputEntry{{Seq: 1, Thrust: 1.0}}})).To(Succeed())
+			// The message with sequence 2 was lost
+			Expect(handler.HandleInputs(&proto.InputsMessage{Type: "inputs", Inputs: []proto.InputEntry{
+				{Seq: 1, Thrust: 1.0}, {Seq: 2, Thrust: 1.0, Turn: 0.5}, {Seq: 3, Thrust: 1.0},
+			}})).To(Succeed())
+			err := handler.HandleInputs(&proto.InputsMessage{Type: "inputs", Inputs: []proto.InputEntry{{Seq: 2, Thrust: 1.0}, {Seq: 3, Thrust: 1.0}}})
+			Expect(err).To(MatchError(ContainSubstring("failed to enqueue command with seq 3")))
+
+			// Sequence 2 was applied between 1 and 3
+			clock.Advance(2 * session.DefaultTickInterval)
+			Expect(handler.session.Run(2)).To(Succeed())
+			ack, _ := handler.session.LastInputAck(handler.PlayerID())
+			Expect(ack).To(Equal(session.InputAck{Sequence: 2, Tick: 1}))
+			clock.Advance(session.DefaultTickInterval)
+			Expect(handler.session.Run(1)).To(Succeed())
+			ack, _ = handler.session.LastInputAck(handler.PlayerID())
+			Expect(ack).To(Equal(session.InputAck{Sequence: 3, Tick: 2}))
+		})
+
+		It("plays tick-targeted inputs through the jitter buffer with the configured gap policy", func() {
+			opts := DefaultOptions()
+			opts.Session.GapPolicy = session.GapRepeatLast
+			handler := NewSessionHandler(nil, clock, newInitialWorld(), opts, logr.Discard())
+			defer handler.Stop()
+
+			Expect(handler.HandleInput(&proto.InputMessage{Type: "input", Seq: 1, Thrust: 1.0, Turn: 0.5, Tick: 1})).To(Succeed())
+			err := handler.HandleInput(&proto.InputMessage{Type: "input", Seq: 2, Thrust: 1.0, Tick: 10 + session.MaxJitterBufferTicks})
+			Expect(err).To(MatchError(ContainSubstring("failed to enqueue")))
+			clock.Advance(4 * session.DefaultTickInterval)
+			Expect(handler.session.Run(4)).To(Succeed())
+
+			// Played at tick 2 (JitterBufferTicks later) and repeated at tick 3
+			referenceClock := session.NewFakeClock()
+			reference := session.NewSession(referenceClock, newInitialWorld(), 100)
+			referenceClock.Advance(4 * session.DefaultTickInterval)
+			Expect(reference.Run(2)).To(Succeed())
+			Expect(reference.EnqueueCommand(1, rules.InputCommand{Thrust: 1.0, Turn: 0.5})).To(BeTrue())
+			Expect(reference.EnqueueCommand(2, rules.InputCommand{Thrust: 1.0, Turn: 0.5})).To(BeTrue())
+			Expect(reference.Run(2)).To(Succeed())
+			Expect(handler.session.GetWorld()).To(Equal(reference.GetWorld()))
+		})
+
+		It("creates sessions at the configured tick rate", func() {
+			opts := DefaultOptions()
+			opts.Session.TickRate = session.TickRate20
+			handler := NewSessionHandler(nil, clock, newInitialWorld(), opts, logr.Discard())
+			defer handler.Stop()
+
+			Expect(handler.session.TickRate()).To(Equal(20))
+			Expect(handler.session.TickInterval()).To(Equal(50 * time.Millisecond))
+		})
+
+		It("steps private sessions through the configured scheduler", Label("b:tick-scheduler"), func() {
+			sched, err := session.NewScheduler(session.DefaultTickRate, 1)
+			Expect(err).NotTo(HaveOccurred())
+			sched.Start()
+			defer sched.Stop()
+			opts := DefaultOptions()
+			opts.Session.Scheduler = sched
+			handler := NewSessionHandler(nil, clock, newInitialWorld(), opts, logr.Discard())
+			handler.startRunLoop()
+			Expect(sched.Stats().Sessions).To(Equal(1))
+
+			clock.Advance(session.DefaultTickInterval)
+			Eventually(func() uint32 { return handler.session.GetWorld().Tick }).Should(Equal(uint32(1)))
+
+			handler.Stop()
+			Expect(sched.Stats().Sessions).To(Equal(0))
+		})
+
 		It("successfully resets session world state on restart", func() {
 			var conn *websocket.Conn
 			var clientConn *websocket.Conn
@@ -809,10 +936,10 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 
 			// Advance session to tick 10
-			clock.Advance(10 * 33 * time.Millisecond)
+			clock.Advance(10 * session.DefaultTickInterval)
 			handler.session.Run(10)
 			world := handler.session.GetWorld()
 			// Session may process fewer ticks if time hasn't advanced enough
@@ -827,12 +954,256 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			// Verify world is reset
 			world = handler.session.GetWorld()
 			Expect(world.Tick).To(Equal(uint32(0)))
-			Expect(world.Ship.Pos.X).To(Equal(10.0))
-			Expect(world.Ship.Pos.Y).To(Equal(0.0))
+			Expect(world.Ships[0].Pos.X).To(Equal(10.0))
+			Expect(world.Ships[0].Pos.Y).To(Equal(0.0))
 		})
 	})
 
 	Describe("Session Run Loop and Snapshot Broadcasting", func() {
+		It("reports the paused state and time scale set by session control messages in snapshots", func() {
+			var conn *websocket.Conn
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+				conn, _ = UpgradeConnection(w, r)
+			})
+			testServer = httptest.NewServer(mux)
+			serverURL = "ws" + testServer.URL[4:] + "/ws"
+
+			clientConn, _, err := websocket.DefaultDialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer clientConn.Close()
+			Eventually(func() bool {
+				return conn != nil
+			}).Should(BeTrue())
+			connection := NewConnection(conn)
+			defer connection.Close()
+
+			handler := NewSessionHandler(connection, clock, newInitialWorld(), DefaultOptions(), logr.Discard())
+			Expect(handler.HandleSessionControl(&proto.SessionControlMessage{Type: "session_control", Action: "pause"})).To(Succeed())
+			Expect(handler.HandleSessionControl(&proto.SessionControlMessage{Type: "session_control", Action: "scale", Scale: 0.5})).To(Succeed())
+			err = handler.HandleSessionControl(&proto.SessionControlMessage{Type: "session_control", Action: "scale", Scale: 10})
+			Expect(err).To(MatchError(ContainSubstring("invalid time scale")))
+			handler.Start()
+			defer handler.Stop()
+
+			var snapshot proto.SnapshotMessage
+			clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			Expect(clientConn.ReadJSON(&snapshot)).To(Succeed())
+			Expect(proto.ValidateSnapshotMessage(&snapshot)).To(Succeed())
+			Expect(snapshot.Paused).To(BeTrue())
+			Expect(snapshot.TimeScale).To(Equal(0.5))
+
+			Expect(handler.HandleSessionControl(&proto.SessionControlMessage{Type: "session_control", Action: "resume"})).To(Succeed())
+			Expect(handler.session.Paused()).To(BeFalse())
+		})
+
+		It("sends the snapshots of scheduled sessions after the scheduler's steps", Label("b:tick-scheduler"), func() {
+			sched, err := session.NewScheduler(session.DefaultTickRate, 1)
+			Expect(err).NotTo(HaveOccurred())
+			sched.Start()
+			defer sched.Stop()
+			opts := DefaultOptions()
+			opts.Session.Scheduler = sched
+
+			var conn *websocket.Conn
+			mux := http.NewServeMux()
+			mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+				conn, _ = UpgradeConnection(w, r)
+			})
+			testServer = httptest.NewServer(mux)
+			serverURL = "ws" + testServer.URL[4:] + "/ws"
+
+			clientConn, _, err := websocket.DefaultDialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer clientConn.Close()
+			Eventually(func() bool {
+				return conn != nil
+			}).Should(BeTrue())
+			connection := NewConnection(conn)
+			defer connection.Close()
+
+			// A level, so that the game is not over after the first tick
+			handler := NewSessionHandler(connection, clock, levels.Seeded(7), opts, logr.Discard())
+			clock.Advance(3 * session.DefaultTickInterval)
+			handler.Start()
+			defer handler.Stop()
+
+			// The first step runs the 3 due ticks; the snapshot follows the third step
+			var snapshot proto.SnapshotMessage
+			clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			Expect(clientConn.ReadJSON(&snapshot)).To(Succeed())
+			Expect(snapshot.Tick).To(Equal(uint32(3)))
+			Expect(sched.Stats().Steps).To(BeNumerically(">=", 3))
+		})
+
+		It("acknowledges the player's last applied input in snapshots", func() {
+			var conn *websocket.Conn
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+				conn, _ = UpgradeConnection(w, r)
+			})
+			testServer = httptest.NewServer(mux)
+			serverURL = "ws" + testServer.URL[4:] + "/ws"
+
+			clientConn, _, err := websocket.DefaultDialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer clientConn.Close()
+			Eventually(func() bool {
+				return conn != nil
+			}).Should(BeTrue())
+			connection := NewConnection(conn)
+			defer connection.Close()
+
+			handler := NewSessionHandler(connection, clock, newInitialWorld(), DefaultOptions(), logr.Discard())
+			Expect(handler.HandleInput(&proto.InputMessage{Type: "input", Seq: 5, Thrust: 1.0})).To(Succeed())
+			Expect(handler.HandleInput(&proto.InputMessage{Type: "input", Seq: 6, Thrust: 1.0})).To(Succeed())
+			clock.Advance(session.DefaultTickInterval)
+			Expect(handler.session.Run(1)).To(Succeed())
+			handler.Start()
+			defer handler.Stop()
+
+			var snapshot proto.SnapshotMessage
+			clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			Expect(clientConn.ReadJSON(&snapshot)).To(Succeed())
+			Expect(snapshot.Ack).To(Equal(&proto.InputAckSnapshot{Seq: 5, Tick: 0}))
+		})
+
+		It("warns players whose inputs were dropped by the backlog policy", func() {
+			var conn *websocket.Conn
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+				conn, _ = UpgradeConnection(w, r)
+			})
+			testServer = httptest.NewServer(mux)
+			serverURL = "ws" + testServer.URL[4:] + "/ws"
+
+			clientConn, _, err := websocket.DefaultDialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer clientConn.Close()
+			Eventually(func() bool {
+				return conn != nil
+			}).Should(BeTrue())
+			connection := NewConnection(conn)
+			defer connection.Close()
+
+			opts := DefaultOptions()
+			opts.Session.BacklogPolicy = session.BacklogDropOldest
+			handler := NewSessionHandler(connection, clock, newInitialWorld(), opts, logr.Discard())
+			for seq := uint32(1); seq <= 10; seq++ {
+				Expect(handler.HandleInput(&proto.InputMessage{Type: "input", Seq: seq, Thrust: 1.0})).To(Succeed())
+			}
+			clock.Advance(session.DefaultTickInterval)
+			Expect(handler.session.Run(1)).To(Succeed())
+
+			handler.Start()
+			defer handler.Stop()
+
+			// Skip snapshots until the warning
+			var warning proto.WarningMessage
+			for warning.Type != "warning" {
+				clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+				Expect(clientConn.ReadJSON(&warning)).To(Succeed())
+			}
+			Expect(proto.ValidateWarningMessage(&warning)).To(Succeed())
+			Expect(warning.Code).To(Equal("input_backlog"))
+			Expect(warning.Dropped).To(Equal(10 - 1 - session.DefaultMaxBacklog))
+		})
+
+		It("closes connections whose session expired with the reason", func() {
+			var conn *websocket.Conn
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+				conn, _ = UpgradeConnection(w, r)
+			})
+			testServer = httptest.NewServer(mux)
+			serverURL = "ws" + testServer.URL[4:] + "/ws"
+
+			clientConn, _, err := websocket.DefaultDialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer clientConn.Close()
+			Eventually(func() bool {
+				return conn != nil
+			}).Should(BeTrue())
+			connection := NewConnection(conn)
+			defer connection.Close()
+
+			opts := DefaultOptions()
+			opts.Session.Lifecycle = session.LifecycleConfig{IdleTimeout: time.Minute}
+			handler := NewSessionHandler(connection, clock, newInitialWorld(), opts, logr.Discard())
+			handler.Start()
+			defer handler.Stop()
+
+			clock.Advance(time.Minute)
+
+			// Skip snapshots until the connection is closed
+			for err == nil {
+				clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+				_, _, err = clientConn.ReadMessage()
+			}
+			var closeErr *websocket.CloseError
+			Expect(errors.As(err, &closeErr)).To(BeTrue())
+			Expect(closeErr.Code).To(Equal(websocket.CloseNormalClosure))
+			Expect(closeErr.Text).To(Equal(CloseReasonSessionExpired))
+			Expect(handler.session.State()).To(Equal(session.StateExpired))
+		})
+
+		It("stops the run loop and closes connections whose session froze", func() {
+			var conn *websocket.Conn
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+				conn, _ = UpgradeConnection(w, r)
+			})
+			testServer = httptest.NewServer(mux)
+			serverURL = "ws" + testServer.URL[4:] + "/ws"
+
+			clientConn, _, err := websocket.DefaultDialer.Dial(serverURL, nil)
+			Expect(err).NotTo(HaveOccurred())
+			defer clientConn.Close()
+			Eventually(func() bool {
+				return conn != nil
+			}).Should(BeTrue())
+			connection := NewConnection(conn)
+			defer connection.Close()
+
+			// Energy above MaxEnergy violates the world invariants on the first tick
+			world := levels.Seeded(7)
+			world.Ships[0].Energy = 2 * rules.DefaultConfig().MaxEnergy
+			opts := DefaultOptions()
+			opts.Session.InvariantMode = session.InvariantsFreeze
+			handler := NewSessionHandler(connection, clock, world, opts, logr.Discard())
+			handler.Start()
+			defer handler.Stop()
+
+			clock.Advance(session.DefaultTickInterval)
+			Eventually(handler.stopped).Should(BeClosed())
+
+			// Skip snapshots until the error message and the close frame
+			var errorMsg ErrorMessage
+			for err == nil {
+				clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+				var data []byte
+				_, data, err = clientConn.ReadMessage()
+				if err == nil && json.Unmarshal(data, &errorMsg) == nil && errorMsg.Type == "error" {
+					break
+				}
+			}
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errorMsg.Code).To(Equal(CloseReasonSessionFailed))
+			Expect(errorMsg.Message).To(ContainSubstring("session frozen"))
+
+			clientConn.SetReadDeadline(time.Now().Add(2 * time.Second))
+			_, _, err = clientConn.ReadMessage()
+			var closeErr *websocket.CloseError
+			Expect(errors.As(err, &closeErr)).To(BeTrue())
+			Expect(closeErr.Code).To(Equal(websocket.CloseInternalServerErr))
+			Expect(closeErr.Text).To(Equal(CloseReasonSessionFailed))
+		})
+
 		It("broadcasts snapshots at approximately 10-15 Hz rate", func() {
 			var conn *websocket.Conn
 			var clientConn *websocket.Conn
@@ -865,7 +1236,7 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 			handler.Start()
 			defer handler.Stop()
 
@@ -931,7 +1302,7 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 			handler.Start()
 			defer handler.Stop()
 
@@ -991,7 +1362,7 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 			handler.Start()
 			defer handler.Stop()
 
@@ -1049,12 +1420,12 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 			handler.Start()
 			defer handler.Stop()
 
 			// Advance session to tick 5
-			clock.Advance(5 * 33 * time.Millisecond)
+			clock.Advance(5 * session.DefaultTickInterval)
 
 			// Send restart message
 			restartMsg := map[string]interface{}{
@@ -1110,7 +1481,7 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			defer connection.Close()
 
 			initialWorld := newInitialWorld()
-			handler := NewSessionHandler(connection, clock, initialWorld, logr.Discard())
+			handler := NewSessionHandler(connection, clock, initialWorld, DefaultOptions(), logr.Discard())
 			handler.Start()
 
 			// Advance time a bit to let session start running
@@ -1123,10 +1494,13 @@ var _ = Describe("Session-WebSocket Integration", Label("scope:integration", "lo
 			// Stop handler
 			handler.Stop()
 
-			// Verify handler stopped
-			Eventually(func() bool {
-				return !handler.session.IsRunning()
-			}).Should(BeTrue())
+			// Verify the session is no longer run, once a Run in flight returned
+			time.Sleep(2 * session.DefaultTickInterval)
+			tick := handler.session.GetWorld().Tick
+			clock.Advance(time.Second)
+			Consistently(func() uint32 {
+				return handler.session.GetWorld().Tick
+			}, 200*time.Millisecond).Should(Equal(tick))
 		})
 	})
 })